@@ -116,6 +116,9 @@ func runDryRun(repoArg string) error {
 	fmt.Println("  • Contributor information")
 	fmt.Println("  • Bus factor and risk assessment")
 	fmt.Println("  • Repository maturity score and level")
+	fmt.Println("  • Code quality scores, complexity, code smells and README quality")
+	fmt.Println("  • Project license and the permissions it grants")
+	fmt.Println("  • Dockerfile best practices and base image inventory")
 	fmt.Println("  • Recruiter summary with key insights")
 	fmt.Println()
 	fmt.Println("💡 This dry run does not consume API rate limits or perform actual computations.")
@@ -216,6 +219,29 @@ var analyzeCmd = &cobra.Command{
 				false, // Assuming no releases check for simplicity
 			)
 
+		// Code quality, license and Dockerfile analysis need the file tree;
+		// a failure here only skips those sections
+		var codeQuality *analyzer.CodeQualityMetrics
+		var license *analyzer.LicenseAnalysis
+		var containers *analyzer.ContainerAnalysis
+		fileTree, treeErr := client.GetFileTree(owner, repo, repoInfo.DefaultBranch)
+		if treeErr == nil {
+			sourceOpts := analyzer.SourceMetricsOptions{}
+			if !client.HasToken() {
+				sourceOpts.MaxFiles = 20
+			}
+			source := analyzer.AnalyzeSourceMetrics(client, owner, repo, fileTree, sourceOpts)
+			codeQuality = analyzer.AnalyzeCodeQualityWithSource(repoInfo, fileTree, langs, source)
+			license, _ = analyzer.AnalyzeLicense(client, owner, repo, fileTree)
+			containers = analyzer.AnalyzeContainers(client, owner, repo, fileTree)
+
+			codeOwners, _ := analyzer.AnalyzeCodeOwners(client, owner, repo, fileTree, commits)
+			codeQuality.ApplyCodeOwners(codeOwners)
+			codeQuality.ApplyDocumentation(analyzer.AnalyzeDocumentation(client, owner, repo, fileTree, commits))
+			codeQuality.ApplyCommitConventions(analyzer.AnalyzeCommitConventions(commits))
+			busFactor, busRisk = analyzer.AdjustBusRiskForOwnership(busFactor, busRisk, codeOwners)
+		}
+
 		// Track analysis duration
 		duration := time.Since(startTime)
 
@@ -231,6 +257,8 @@ var analyzeCmd = &cobra.Command{
 				Contributors:    len(contributors),
 				Duration:        duration,
 				Languages:       langs,
+				CodeQuality:     codeQuality,
+				License:         license,
 			})
 		}
 
@@ -255,6 +283,18 @@ var analyzeCmd = &cobra.Command{
 		output.PrintLanguages(langs)
 		output.PrintCommitActivity(activity, 14)
 		output.PrintHealth(score)
+		if treeErr != nil {
+			fmt.Println(output.WarningStyle.Render(fmt.Sprintf("⚠️ Skipping code quality, license and Dockerfile analysis: %v", treeErr)))
+		}
+		if codeQuality != nil {
+			output.PrintCodeQuality(codeQuality)
+		}
+		if license != nil {
+			output.PrintLicense(license)
+		}
+		if containers != nil && len(containers.Dockerfiles) > 0 {
+			output.PrintContainers(containers)
+		}
 		output.PrintGitHubAPIStatus(client)
 		output.PrintRecruiterSummary(summary)
 
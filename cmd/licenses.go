@@ -0,0 +1,90 @@
+// Package cmd provides command-line interface commands for the Repo-lyzer application.
+// It includes the dependency license compliance check.
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+	"github.com/agnivo988/Repo-lyzer/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// licensesCmd defines the "licenses" command.
+// It resolves the license of every dependency and checks it against a policy,
+// exiting with an error when a dependency uses a denied license.
+// Usage example:
+//
+//	repo-lyzer licenses octocat/Hello-World --policy policy.yml
+var licensesCmd = &cobra.Command{
+	Use:   "licenses owner/repo",
+	Short: "Check dependency licenses against a license policy",
+	Long: `Resolve the license of every dependency and check it against a policy of
+allowed, flagged and denied SPDX identifiers.
+
+Without --policy, ~/.repo-lyzer/license-policy.yml is used when it exists;
+otherwise permissive licenses are allowed and copyleft licenses are flagged.
+
+Example policy:
+  allow: [MIT, Apache-2.0, BSD-*, ISC]
+  flag: [LGPL-2.1, MPL-2.0]
+  deny: [GPL-*, AGPL-*]
+  unknown: flag
+  packages:
+    some-gpl-tool: allow`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		policyPath, _ := cmd.Flags().GetString("policy")
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		owner, repo, err := validateRepoURL(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid repository URL: %w", err)
+		}
+
+		policy, err := analyzer.LoadLicensePolicy(policyPath)
+		if err != nil {
+			return err
+		}
+
+		client := github.NewClient()
+		repoInfo, err := client.GetRepo(owner, repo)
+		if err != nil {
+			return err
+		}
+		fileTree, err := client.GetFileTree(owner, repo, repoInfo.DefaultBranch)
+		if err != nil {
+			return fmt.Errorf("failed to get file tree: %w", err)
+		}
+		deps, err := analyzer.AnalyzeDependencies(client, owner, repo, repoInfo.DefaultBranch, fileTree)
+		if err != nil {
+			return fmt.Errorf("failed to analyze dependencies: %w", err)
+		}
+
+		report := analyzer.CheckLicenseCompliance(deps, policy)
+		if asJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(report); err != nil {
+				return err
+			}
+		} else {
+			output.PrintLicenseCompliance(report)
+		}
+
+		if !report.Compliant {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("license policy violated by %d dependencies", report.Denied)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(licensesCmd)
+	licensesCmd.Flags().String("policy", "", "License policy file (default ~/.repo-lyzer/license-policy.yml)")
+	licensesCmd.Flags().Bool("json", false, "Output the compliance report as JSON")
+}
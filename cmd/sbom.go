@@ -0,0 +1,97 @@
+// Package cmd provides command-line interface commands for the Repo-lyzer application.
+// It includes the software bill of materials (SBOM) export.
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+	"github.com/spf13/cobra"
+)
+
+// sbomCmd defines the "sbom" command.
+// It generates an SBOM of the repository's dependencies, including their
+// licenses and known vulnerabilities.
+// Usage example:
+//
+//	repo-lyzer sbom octocat/Hello-World --format spdx --output sbom.spdx.json
+var sbomCmd = &cobra.Command{
+	Use:   "sbom owner/repo",
+	Short: "Generate a software bill of materials for a repository",
+	Long: `Generate a software bill of materials (SBOM) from the repository's manifests,
+lock files and Dockerfile base images.
+
+Supported formats:
+  cyclonedx  CycloneDX 1.5 JSON, with vulnerabilities as VEX
+  spdx       SPDX 2.3 JSON
+  spdx-tv    SPDX 2.3 tag-value
+
+Vulnerabilities are looked up on osv.dev, or in the local database with
+--offline. The SBOM is written to stdout unless --output is given.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		outputPath, _ := cmd.Flags().GetString("output")
+		offline, _ := cmd.Flags().GetBool("offline")
+
+		if _, ok := analyzer.SBOMFormats[format]; !ok {
+			return fmt.Errorf("unsupported SBOM format '%s'. Supported formats are: cyclonedx, spdx, spdx-tv", format)
+		}
+
+		owner, repo, err := validateRepoURL(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid repository URL: %w", err)
+		}
+
+		client := github.NewClient()
+		repoInfo, err := client.GetRepo(owner, repo)
+		if err != nil {
+			return err
+		}
+		fileTree, err := client.GetFileTree(owner, repo, repoInfo.DefaultBranch)
+		if err != nil {
+			return fmt.Errorf("failed to get file tree: %w", err)
+		}
+		deps, err := analyzer.AnalyzeDependencies(client, owner, repo, repoInfo.DefaultBranch, fileTree)
+		if err != nil {
+			return fmt.Errorf("failed to analyze dependencies: %w", err)
+		}
+		analyzer.AddBaseImageDependencies(deps, analyzer.AnalyzeContainers(client, owner, repo, fileTree))
+
+		security, err := analyzer.ScanDependenciesWithOptions(deps, analyzer.ScanOptions{Offline: offline})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: vulnerability scan failed: %v\n", err)
+			security = nil
+		}
+		licenses := analyzer.CheckLicenseCompliance(deps, analyzer.DefaultLicensePolicy())
+
+		subject := analyzer.SBOMSubject{
+			Name:    repoInfo.FullName,
+			Version: repoInfo.DefaultBranch,
+			URL:     repoInfo.HTMLURL,
+		}
+		content, err := analyzer.NewSBOM(subject, deps, security, licenses).Render(format)
+		if err != nil {
+			return err
+		}
+
+		if outputPath == "" {
+			_, err = os.Stdout.Write(content)
+			return err
+		}
+		if err := os.WriteFile(outputPath, content, 0644); err != nil {
+			return fmt.Errorf("failed to write SBOM: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "SBOM written to %s\n", outputPath)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(sbomCmd)
+	sbomCmd.Flags().StringP("format", "f", "cyclonedx", "SBOM format: cyclonedx, spdx or spdx-tv")
+	sbomCmd.Flags().StringP("output", "o", "", "Write the SBOM to a file instead of stdout")
+	sbomCmd.Flags().Bool("offline", false, "Look up vulnerabilities in the local database instead of osv.dev")
+}
@@ -0,0 +1,91 @@
+// Package cmd provides command-line interface commands for the Repo-lyzer application.
+// It includes the OpenSSF Scorecard-style security check suite.
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+	"github.com/agnivo988/Repo-lyzer/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// scorecardCmd defines the "scorecard" command.
+// It runs Scorecard-style security checks and prints each score with the
+// risk-weighted aggregate.
+// Usage example:
+//
+//	repo-lyzer scorecard octocat/Hello-World --max-prs 30
+var scorecardCmd = &cobra.Command{
+	Use:   "scorecard owner/repo",
+	Short: "Run OpenSSF Scorecard-style security checks",
+	Long: `Score the repository's security practices from 0 to 10 on the checks of
+OpenSSF Scorecard: Maintained, Code-Review, Branch-Protection,
+Pinned-Dependencies, Dangerous-Workflow, Security-Policy, Signed-Releases,
+License, CI-Tests, Fuzzing, SAST and Dependency-Update-Tool.
+
+The aggregate weighs each check by its risk. Checks that cannot be
+evaluated, such as Signed-Releases on a repository without releases, are
+shown with "?" and left out of the aggregate. Branch protection details
+need a token with admin access to the repository.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		maxPRs, _ := cmd.Flags().GetInt("max-prs")
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		owner, repo, err := validateRepoURL(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid repository URL: %w", err)
+		}
+
+		client := github.NewClient()
+		repoInfo, err := client.GetRepo(owner, repo)
+		if err != nil {
+			return err
+		}
+		commits, err := client.GetCommits(owner, repo, 90)
+		if err != nil {
+			return fmt.Errorf("failed to get commits: %w", err)
+		}
+		fileTree, err := client.GetFileTree(owner, repo, repoInfo.DefaultBranch)
+		if err != nil {
+			return fmt.Errorf("failed to get file tree: %w", err)
+		}
+		langs, err := client.GetLanguages(owner, repo)
+		if err != nil {
+			return fmt.Errorf("failed to get languages: %w", err)
+		}
+
+		deps, _ := analyzer.AnalyzeDependencies(client, owner, repo, repoInfo.DefaultBranch, fileTree)
+		analyzer.AddBaseImageDependencies(deps, analyzer.AnalyzeContainers(client, owner, repo, fileTree))
+		license, _ := analyzer.AnalyzeLicense(client, owner, repo, fileTree)
+		in := &analyzer.ScorecardInputs{
+			Repo:         repoInfo,
+			Commits:      commits,
+			FileTree:     fileTree,
+			Workflows:    analyzer.AuditWorkflows(client, owner, repo, fileTree, !repoInfo.Private),
+			Dependencies: deps,
+			License:      license,
+			CodeQuality:  analyzer.AnalyzeCodeQuality(repoInfo, fileTree, langs),
+		}
+		analyzer.FetchScorecardData(client, owner, repo, in, maxPRs)
+		result := analyzer.ComputeScorecard(in)
+
+		if asJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(result)
+		}
+		output.PrintScorecard(result)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(scorecardCmd)
+	scorecardCmd.Flags().Int("max-prs", 20, "Maximum number of merged pull requests to check for reviews")
+	scorecardCmd.Flags().Bool("json", false, "Output the checks as JSON")
+}
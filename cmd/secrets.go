@@ -0,0 +1,91 @@
+// Package cmd provides command-line interface commands for the Repo-lyzer application.
+// It includes the secret scan of repository contents.
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+	"github.com/agnivo988/Repo-lyzer/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// secretsCmd defines the "secrets" command.
+// It scans repository files for leaked credentials, exiting with an error
+// when any are found.
+// Usage example:
+//
+//	repo-lyzer secrets octocat/Hello-World --max-files 200
+var secretsCmd = &cobra.Command{
+	Use:   "secrets owner/repo",
+	Short: "Scan repository files for leaked credentials",
+	Long: `Fetch text files from the repository and look for leaked credentials: AWS
+keys, GitHub tokens, Slack webhooks and tokens, private keys, and
+high-entropy values assigned to names like "api_key" or "password".
+
+Binary, vendored and oversized files are skipped, and configuration files
+are scanned first. Findings are reported with the secret masked.
+
+Without --allowlist, ~/.repo-lyzer/secrets-allowlist is used when it exists.
+A .repo-lyzer-secrets-allowlist file in the repository root is applied too.
+Each line holds a path pattern, a rule, a finding fingerprint, or a file line:
+  testdata/**
+  rule:generic-secret
+  3f2a9c0d5e6b7a81
+  docs/setup.md:42`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		allowlistPath, _ := cmd.Flags().GetString("allowlist")
+		maxFiles, _ := cmd.Flags().GetInt("max-files")
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		owner, repo, err := validateRepoURL(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid repository URL: %w", err)
+		}
+
+		client := github.NewClient()
+		repoInfo, err := client.GetRepo(owner, repo)
+		if err != nil {
+			return err
+		}
+		fileTree, err := client.GetFileTree(owner, repo, repoInfo.DefaultBranch)
+		if err != nil {
+			return fmt.Errorf("failed to get file tree: %w", err)
+		}
+
+		result, err := analyzer.ScanSecrets(client, owner, repo, fileTree, analyzer.SecretScanOptions{
+			MaxFiles:      maxFiles,
+			AllowlistPath: allowlistPath,
+		})
+		if err != nil {
+			return err
+		}
+
+		if asJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(result); err != nil {
+				return err
+			}
+		} else {
+			output.PrintSecretFindings(result)
+		}
+
+		if len(result.Findings) > 0 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("%d leaked credentials found", len(result.Findings))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(secretsCmd)
+	secretsCmd.Flags().String("allowlist", "", "Allowlist file (default ~/.repo-lyzer/secrets-allowlist)")
+	secretsCmd.Flags().Int("max-files", 100, "Maximum number of files to fetch and scan")
+	secretsCmd.Flags().Bool("json", false, "Output the findings as JSON")
+}
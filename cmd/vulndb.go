@@ -0,0 +1,92 @@
+// Package cmd provides command-line interface commands for the Repo-lyzer application.
+// It includes commands for managing the offline vulnerability database.
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
+	"github.com/spf13/cobra"
+)
+
+// vulndbCmd defines the "vulndb" command group for the offline vulnerability database.
+// Usage example:
+//
+//	repo-lyzer vulndb update --from ./osv-exports
+var vulndbCmd = &cobra.Command{
+	Use:   "vulndb",
+	Short: "Manage the offline vulnerability database",
+	Long:  "Manage the local vulnerability database used for dependency scanning on machines without network access.",
+}
+
+// vulndbUpdateCmd defines the "vulndb update" subcommand.
+// It imports OSV's per-ecosystem zip exports (e.g. npm/all.zip, PyPI/all.zip
+// from https://osv-vulnerabilities.storage.googleapis.com) into ~/.repo-lyzer/vulndb.
+var vulndbUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Import OSV zip exports into the offline database",
+	Long: `Import OSV vulnerability exports into the local database.
+
+Download the per-ecosystem exports on a connected machine, for example:
+  https://osv-vulnerabilities.storage.googleapis.com/npm/all.zip
+  https://osv-vulnerabilities.storage.googleapis.com/PyPI/all.zip
+
+then copy them into a directory and run:
+  repo-lyzer vulndb update --from <dir>
+
+All .zip files under the directory are imported and replace the previous index.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, _ := cmd.Flags().GetString("from")
+		dbDir, _ := cmd.Flags().GetString("db")
+
+		fmt.Printf("📦 Importing OSV exports from %s...\n", from)
+		index, err := analyzer.ImportOSVExports(from, dbDir)
+		if err != nil {
+			return fmt.Errorf("failed to import vulnerability database: %w", err)
+		}
+
+		db, err := analyzer.OpenVulnDB(dbDir)
+		if err != nil {
+			return err
+		}
+		for _, eco := range db.Ecosystems() {
+			e := index.Ecosystems[eco]
+			fmt.Printf("   • %-12s %6d vulnerabilities across %d packages\n", eco, e.Vulnerabilities, e.Packages)
+		}
+		fmt.Println("✅ Offline vulnerability database updated")
+		return nil
+	},
+}
+
+// vulndbStatusCmd defines the "vulndb status" subcommand.
+var vulndbStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the contents of the offline database",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dbDir, _ := cmd.Flags().GetString("db")
+
+		db, err := analyzer.OpenVulnDB(dbDir)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("📊 Offline vulnerability database\n")
+		fmt.Printf("   • Updated: %s\n", db.Index.UpdatedAt.Format("2006-01-02 15:04"))
+		fmt.Printf("   • Source:  %s\n", db.Index.Source)
+		for _, eco := range db.Ecosystems() {
+			e := db.Index.Ecosystems[eco]
+			fmt.Printf("   • %-12s %6d vulnerabilities across %d packages\n", eco, e.Vulnerabilities, e.Packages)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(vulndbCmd)
+	vulndbCmd.AddCommand(vulndbUpdateCmd)
+	vulndbCmd.AddCommand(vulndbStatusCmd)
+
+	vulndbCmd.PersistentFlags().String("db", "", "Database directory (default ~/.repo-lyzer/vulndb)")
+	vulndbUpdateCmd.Flags().String("from", "", "Directory containing OSV zip exports")
+	vulndbUpdateCmd.MarkFlagRequired("from")
+}
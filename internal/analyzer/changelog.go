@@ -0,0 +1,426 @@
+package analyzer
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// ChangelogEntry is one list item of a changelog version
+type ChangelogEntry struct {
+	Category string `json:"category"` // Keep a Changelog category, or the section title as written
+	Text     string `json:"text"`
+}
+
+// ChangelogVersion is one version section of a changelog
+type ChangelogVersion struct {
+	Version    string           `json:"version"` // empty for the Unreleased section
+	Date       string           `json:"date,omitempty"`
+	Unreleased bool             `json:"unreleased"`
+	Line       int              `json:"line"`
+	Entries    []ChangelogEntry `json:"entries"`
+}
+
+// ChangelogAnalysis is a parsed changelog cross-checked against the
+// repository's tags and releases
+type ChangelogAnalysis struct {
+	File                 string             `json:"file"`   // empty when the repository has no changelog
+	Format               string             `json:"format"` // keep-a-changelog or free-form
+	Versions             []ChangelogVersion `json:"versions"`
+	Categories           map[string]int     `json:"categories"` // entries per category
+	UnreleasedEntries    int                `json:"unreleased_entries"`
+	AvgEntriesPerRelease float64            `json:"avg_entries_per_release"`
+	LatestVersion        string             `json:"latest_version,omitempty"` // newest released version in the changelog
+	LatestRelease        string             `json:"latest_release,omitempty"` // newest tag or release
+	Releases             int                `json:"releases"`                 // published releases looked at
+	ReleasesWithoutNotes int                `json:"releases_without_notes"`
+	MissingVersions      []string           `json:"missing_versions"`  // tagged or released, but not in the changelog
+	UntaggedVersions     []string           `json:"untagged_versions"` // in the changelog, but never tagged
+	Recommendations      []string           `json:"recommendations"`
+}
+
+const (
+	// FormatKeepAChangelog is a changelog following keepachangelog.com
+	FormatKeepAChangelog = "keep-a-changelog"
+	// FormatFreeForm is any other changelog with version headings
+	FormatFreeForm = "free-form"
+
+	// minReleaseNotesLength is the shortest release description counted as notes
+	minReleaseNotesLength = 20
+	// maxChangelogTags caps how many tags are compared with the changelog
+	maxChangelogTags = 100
+)
+
+// changelogNames are the base names, without extension, that changelogs use
+var changelogNames = []string{"changelog", "changes", "history", "news", "releases", "release-notes", "release_notes"}
+
+// changelogExts are the extensions a changelog may have besides none, so
+// that source or data files such as history.py and releases.json are skipped
+var changelogExts = []string{".md", ".markdown", ".rst", ".txt", ".adoc"}
+
+// keepAChangelogCategories maps section titles to the Keep a Changelog
+// category they correspond to
+var keepAChangelogCategories = map[string]string{
+	"added":            "Added",
+	"new":              "Added",
+	"features":         "Added",
+	"new features":     "Added",
+	"changed":          "Changed",
+	"changes":          "Changed",
+	"improvements":     "Changed",
+	"enhancements":     "Changed",
+	"deprecated":       "Deprecated",
+	"deprecations":     "Deprecated",
+	"removed":          "Removed",
+	"fixed":            "Fixed",
+	"fixes":            "Fixed",
+	"bug fixes":        "Fixed",
+	"bugfixes":         "Fixed",
+	"security":         "Security",
+	"breaking":         "Changed",
+	"breaking changes": "Changed",
+}
+
+var (
+	changelogVersionPattern    = regexp.MustCompile(`(?i)^\[?(?:(?:version|release)\s+|[\w./-]+@)?v?(\d+(?:\.\d+)+(?:-[0-9a-z.]+)?)\]?`)
+	changelogUnreleasedPattern = regexp.MustCompile(`(?i)^\[?unreleased\]?`)
+	changelogISODatePattern    = regexp.MustCompile(`\d{4}-\d{2}-\d{2}`)
+	changelogLongDatePattern   = regexp.MustCompile(`(?i)(jan|feb|mar|apr|may|jun|jul|aug|sep|oct|nov|dec)[a-z]*\.? \d{1,2},? \d{4}`)
+	changelogEntryPattern      = regexp.MustCompile(`^ {0,3}[-*+] +(.+)`)
+	changelogListItemPattern   = regexp.MustCompile(`^( *)[-*+] +`)
+	changelogBoldTitlePattern  = regexp.MustCompile(`^\*\*([^*]+)\*\*:?\s*$`)
+	changelogColonTitlePattern = regexp.MustCompile(`^([A-Z][A-Za-z ]{2,30}):\s*$`)
+)
+
+// AnalyzeChangelog fetches the changelog and the repository's tags and
+// compares them, along with the given releases
+func AnalyzeChangelog(client *github.Client, owner, repo string, fileTree []github.TreeEntry, releases []github.Release) *ChangelogAnalysis {
+	cl := &ChangelogAnalysis{}
+	if file := findChangelog(fileTree); file != "" {
+		if content, ok := contentFetcher(client, owner, repo)(file); ok {
+			cl = ParseChangelog(file, content)
+		}
+	}
+	tags, _ := client.GetTags(owner, repo, maxChangelogTags)
+	crossCheckChangelog(cl, releases, tags)
+	return cl
+}
+
+// findChangelog returns the changelog at the root or in docs/, preferring
+// Markdown
+func findChangelog(fileTree []github.TreeEntry) string {
+	best, bestRank := "", 4
+	for _, entry := range fileTree {
+		if entry.Type != "blob" {
+			continue
+		}
+		dir := path.Dir(entry.Path)
+		if dir != "." && dir != "docs" {
+			continue
+		}
+		base := strings.ToLower(path.Base(entry.Path))
+		ext := path.Ext(base)
+		if !contains(changelogNames, strings.TrimSuffix(base, ext)) || ext != "" && !contains(changelogExts, ext) {
+			continue
+		}
+		rank := 0
+		if dir != "." {
+			rank += 2
+		}
+		if !isMarkdownFile(entry.Path) {
+			rank++
+		}
+		if rank < bestRank || rank == bestRank && entry.Path < best {
+			best, bestRank = entry.Path, rank
+		}
+	}
+	return best
+}
+
+// ParseChangelog splits a changelog into versions with categorized
+// entries. Versions are headings that start with a version number, with
+// or without brackets, a "v" or a "name@" prefix; lower headings and bold
+// lines inside a version start a category.
+func ParseChangelog(file, content string) *ChangelogAnalysis {
+	cl := &ChangelogAnalysis{
+		File:             file,
+		Format:           FormatFreeForm,
+		Versions:         []ChangelogVersion{},
+		Categories:       make(map[string]int),
+		MissingVersions:  []string{},
+		UntaggedVersions: []string{},
+	}
+	if strings.Contains(strings.ToLower(content), "keepachangelog.com") {
+		cl.Format = FormatKeepAChangelog
+	}
+
+	var current *ChangelogVersion
+	versionLevel, category, fence, prev := 0, "", false, ""
+	// listIndent is the indentation of the current list's top-level items;
+	// deeper items are details of an entry rather than entries
+	listIndent := -1
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		line = strings.TrimRight(line, "\r")
+		if mdFencePattern.MatchString(line) {
+			fence = !fence
+			prev = ""
+			continue
+		}
+		if fence {
+			continue
+		}
+
+		heading, level := "", 0
+		if m := mdATXHeadingPattern.FindStringSubmatch(line); m != nil {
+			heading, level = strings.TrimSpace(m[2]), len(m[1])
+		} else if m := mdSetextPattern.FindStringSubmatch(line); m != nil && strings.TrimSpace(prev) != "" && !changelogEntryPattern.MatchString(prev) {
+			heading, level = strings.TrimSpace(prev), 1
+			if strings.HasPrefix(m[1], "-") {
+				level = 2
+			}
+			// The title line was already read as text; drop an entry it added
+			if current != nil && len(current.Entries) > 0 && current.Entries[len(current.Entries)-1].Text == heading {
+				current.Entries = current.Entries[:len(current.Entries)-1]
+			}
+		}
+		prev = line
+
+		if heading != "" {
+			listIndent = -1
+			if v, ok := parseChangelogHeading(heading, i+1); ok {
+				cl.Versions = append(cl.Versions, v)
+				current = &cl.Versions[len(cl.Versions)-1]
+				versionLevel, category = level, ""
+				if strings.HasPrefix(heading, "[") && !v.Unreleased {
+					cl.Format = FormatKeepAChangelog
+				}
+				continue
+			}
+			if current != nil && level > versionLevel {
+				category = changelogCategory(heading)
+			} else {
+				current = nil
+			}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if m := changelogBoldTitlePattern.FindStringSubmatch(trimmed); m != nil {
+			category, listIndent = changelogCategory(m[1]), -1
+			continue
+		}
+		if m := changelogColonTitlePattern.FindStringSubmatch(trimmed); m != nil {
+			category, listIndent = changelogCategory(m[1]), -1
+			continue
+		}
+		item := changelogListItemPattern.FindStringSubmatch(line)
+		switch {
+		case item == nil:
+			// A paragraph at the margin ends the list
+			if trimmed != "" && !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") {
+				listIndent = -1
+			}
+			continue
+		case listIndent >= 0 && len(item[1]) > listIndent:
+			continue
+		}
+		if m := changelogEntryPattern.FindStringSubmatch(line); m != nil {
+			listIndent = len(item[1])
+			cat := category
+			if cat == "" {
+				cat = "Other"
+			}
+			current.Entries = append(current.Entries, ChangelogEntry{Category: cat, Text: strings.TrimSpace(m[1])})
+		}
+	}
+
+	released, entries := 0, 0
+	for _, v := range cl.Versions {
+		for _, e := range v.Entries {
+			cl.Categories[e.Category]++
+		}
+		if v.Unreleased {
+			cl.UnreleasedEntries += len(v.Entries)
+			continue
+		}
+		if cl.LatestVersion == "" {
+			cl.LatestVersion = v.Version
+		}
+		released++
+		entries += len(v.Entries)
+	}
+	if released > 0 {
+		cl.AvgEntriesPerRelease = float64(entries) / float64(released)
+	}
+	return cl
+}
+
+// parseChangelogHeading reads a version heading such as "[1.2.0] - 2024-05-01",
+// "v1.2.0 (May 1, 2024)" or "Unreleased"
+func parseChangelogHeading(heading string, line int) (ChangelogVersion, bool) {
+	v := ChangelogVersion{Line: line, Entries: []ChangelogEntry{}}
+	switch {
+	case changelogUnreleasedPattern.MatchString(heading):
+		v.Unreleased = true
+	default:
+		m := changelogVersionPattern.FindStringSubmatch(heading)
+		if m == nil {
+			return v, false
+		}
+		v.Version = m[1]
+	}
+
+	if d := changelogISODatePattern.FindString(heading); d != "" {
+		v.Date = d
+	} else if d := changelogLongDatePattern.FindString(heading); d != "" {
+		for _, layout := range []string{"Jan 2, 2006", "January 2, 2006", "Jan 2 2006", "January 2 2006", "Jan. 2, 2006"} {
+			if t, err := time.Parse(layout, d); err == nil {
+				v.Date = t.Format("2006-01-02")
+				break
+			}
+		}
+	}
+	return v, true
+}
+
+// changelogCategory maps a section title to its Keep a Changelog category
+func changelogCategory(title string) string {
+	title = strings.Trim(strings.TrimSpace(title), ":*_")
+	key := strings.ToLower(title)
+	// Drop a leading emoji or symbol, as in "🐛 Bug Fixes"
+	if i := strings.IndexFunc(key, func(r rune) bool { return r >= 'a' && r <= 'z' }); i > 0 {
+		key, title = key[i:], title[i:]
+	}
+	if cat, ok := keepAChangelogCategories[key]; ok {
+		return cat
+	}
+	return title
+}
+
+// normalizeReleaseVersion turns a tag such as v1.2.0, release-1.2.0 or
+// pkg@1.2.0 into the bare version, or returns "" if it has none
+func normalizeReleaseVersion(tag string) string {
+	tag = strings.TrimPrefix(tag, "refs/tags/")
+	if i := strings.LastIndex(tag, "@"); i >= 0 {
+		tag = tag[i+1:]
+	}
+	for _, prefix := range []string{"release-", "release/", "version-"} {
+		tag = strings.TrimPrefix(tag, prefix)
+	}
+	tag = strings.TrimPrefix(strings.TrimPrefix(tag, "v"), "V")
+	if m := semverPattern.FindStringSubmatch(tag); m == nil || !strings.Contains(m[1], ".") {
+		return ""
+	}
+	return tag
+}
+
+// crossCheckChangelog compares the changelog versions with tags and
+// published releases, and fills in the recommendations
+func crossCheckChangelog(cl *ChangelogAnalysis, releases []github.Release, tags []github.Tag) {
+	if cl.MissingVersions == nil {
+		cl.MissingVersions = []string{}
+	}
+	if cl.UntaggedVersions == nil {
+		cl.UntaggedVersions = []string{}
+	}
+
+	// Versions are matched in canonical form, so a "1.2" heading documents
+	// the v1.2.0 tag; each is reported the way it was written
+	released := make(map[string]string) // canonical version -> tagged version
+	addReleased := func(v string) {
+		if v == "" {
+			return
+		}
+		if _, ok := released[canonicalSemver(v)]; !ok {
+			released[canonicalSemver(v)] = v
+		}
+	}
+	for _, r := range releases {
+		if r.Draft {
+			continue
+		}
+		cl.Releases++
+		if len(strings.TrimSpace(r.Body)) < minReleaseNotesLength {
+			cl.ReleasesWithoutNotes++
+		}
+		addReleased(normalizeReleaseVersion(r.TagName))
+	}
+	for _, t := range tags {
+		addReleased(normalizeReleaseVersion(t.Name))
+	}
+
+	var versions []string
+	for _, v := range released {
+		versions = append(versions, v)
+	}
+	sort.Slice(versions, func(i, j int) bool { return compareSemver(versions[i], versions[j]) > 0 })
+	if len(versions) > 0 {
+		cl.LatestRelease = versions[0]
+	}
+
+	documented := make(map[string]bool)
+	oldestDocumented := ""
+	for _, v := range cl.Versions {
+		if v.Unreleased {
+			continue
+		}
+		documented[canonicalSemver(v.Version)] = true
+		if oldestDocumented == "" || compareSemver(v.Version, oldestDocumented) < 0 {
+			oldestDocumented = v.Version
+		}
+	}
+
+	// Only versions from when the changelog was started can be missing from
+	// it, and pre-releases are often left out on purpose
+	if cl.File != "" {
+		for _, v := range versions {
+			if !documented[canonicalSemver(v)] && !strings.Contains(v, "-") && (oldestDocumented == "" || compareSemver(v, oldestDocumented) >= 0) {
+				cl.MissingVersions = append(cl.MissingVersions, v)
+			}
+		}
+	}
+	// Tags beyond the fetched page may cover older changelog versions
+	if len(versions) > 0 {
+		oldestTag := versions[len(versions)-1]
+		for _, v := range cl.Versions {
+			if _, ok := released[canonicalSemver(v.Version)]; !v.Unreleased && !ok && compareSemver(v.Version, oldestTag) >= 0 {
+				cl.UntaggedVersions = append(cl.UntaggedVersions, v.Version)
+			}
+		}
+	}
+
+	cl.Recommendations = changelogRecommendations(cl)
+}
+
+// changelogRecommendations lists the changelog and release notes fixes
+func changelogRecommendations(cl *ChangelogAnalysis) []string {
+	recs := []string{}
+	if cl.File == "" {
+		if cl.Releases > 0 || cl.LatestRelease != "" {
+			recs = append(recs, "📋 Add a CHANGELOG.md following keepachangelog.com")
+		}
+	} else {
+		if n := len(cl.MissingVersions); n > 0 {
+			recs = append(recs, fmt.Sprintf("📝 Document %d released version(s) missing from %s, starting with %s", n, cl.File, cl.MissingVersions[0]))
+		}
+		if cl.UnreleasedEntries >= 10 {
+			recs = append(recs, fmt.Sprintf("🚢 Cut a release: %d changelog entries are unreleased", cl.UnreleasedEntries))
+		}
+		if len(cl.Versions) > 0 && cl.Format == FormatFreeForm && len(cl.Categories) <= 1 {
+			recs = append(recs, "🗂️ Group changelog entries under Added, Changed and Fixed headings")
+		}
+	}
+	if cl.ReleasesWithoutNotes > 0 {
+		recs = append(recs, fmt.Sprintf("🗒️ Write release notes for %d of %d releases with an empty description", cl.ReleasesWithoutNotes, cl.Releases))
+	}
+	return recs
+}
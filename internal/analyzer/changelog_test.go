@@ -0,0 +1,180 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+const keepAChangelog = `# Changelog
+All notable changes to this project will be documented in this file.
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/).
+
+## [Unreleased]
+### Added
+- Dark mode
+
+## [1.1.0] - 2026-03-02
+### Added
+- Export to CSV
+- Export to PDF
+### Fixed
+- Crash on empty input
+
+` + "```" + `
+- not an entry
+` + "```" + `
+
+## [1.0.0] - 2026-01-15
+### Changed
+- First stable release
+
+[Unreleased]: https://github.com/acme/widget/compare/v1.1.0...HEAD
+[1.1.0]: https://github.com/acme/widget/compare/v1.0.0...v1.1.0
+`
+
+const freeFormChangelog = `2.0.0 / March 4, 2026
+=====================
+
+**🐛 Bug Fixes**
+* Handle timeouts
+* Retry on 502
+
+Features:
+* Streaming responses
+
+v1.9.0 (2026-01-10)
+-------------------
+* Misc cleanups
+`
+
+func TestParseChangelog(t *testing.T) {
+	cl := ParseChangelog("CHANGELOG.md", keepAChangelog)
+
+	if cl.Format != FormatKeepAChangelog || len(cl.Versions) != 3 {
+		t.Fatalf("Format, Versions = %s, %+v", cl.Format, cl.Versions)
+	}
+	v := cl.Versions[1]
+	if v.Version != "1.1.0" || v.Date != "2026-03-02" || len(v.Entries) != 3 || v.Entries[2].Category != "Fixed" {
+		t.Errorf("1.1.0 = %+v", v)
+	}
+	if !cl.Versions[0].Unreleased || cl.UnreleasedEntries != 1 || cl.LatestVersion != "1.1.0" {
+		t.Errorf("Unreleased, UnreleasedEntries, LatestVersion = %v, %d, %s", cl.Versions[0].Unreleased, cl.UnreleasedEntries, cl.LatestVersion)
+	}
+	if cl.AvgEntriesPerRelease != 2 || cl.Categories["Added"] != 3 {
+		t.Errorf("AvgEntriesPerRelease, Categories = %.1f, %v", cl.AvgEntriesPerRelease, cl.Categories)
+	}
+
+	free := ParseChangelog("History.md", freeFormChangelog)
+	if free.Format != FormatFreeForm || len(free.Versions) != 2 {
+		t.Fatalf("free-form Format, Versions = %s, %+v", free.Format, free.Versions)
+	}
+	if v := free.Versions[0]; v.Version != "2.0.0" || v.Date != "2026-03-04" || len(v.Entries) != 3 {
+		t.Errorf("2.0.0 = %+v", v)
+	}
+	if free.Categories["Fixed"] != 2 || free.Categories["Added"] != 1 || free.Versions[1].Version != "1.9.0" {
+		t.Errorf("Categories = %v, 1.9.0 = %+v", free.Categories, free.Versions[1])
+	}
+}
+
+func TestParseChangelog_NestedBullets(t *testing.T) {
+	cl := ParseChangelog("CHANGELOG.md", `## 1.0.0
+### Added
+- Export to CSV
+  - with a header row
+  - and quoted fields
+    continued on the next line
+- Export to PDF
+	- tab-indented detail
+
+Notes:
+  - Imports
+    * nested under imports
+`)
+	if len(cl.Versions) != 1 {
+		t.Fatalf("Versions = %+v", cl.Versions)
+	}
+	var texts []string
+	for _, e := range cl.Versions[0].Entries {
+		texts = append(texts, e.Text)
+	}
+	if got := strings.Join(texts, ","); got != "Export to CSV,Export to PDF,Imports" {
+		t.Errorf("entries = %q, want only the top-level items", got)
+	}
+}
+
+func TestNormalizeReleaseVersion(t *testing.T) {
+	for tag, want := range map[string]string{
+		"v1.2.0":         "1.2.0",
+		"release-2.0":    "2.0",
+		"@acme/ui@3.1.4": "3.1.4",
+		"v2.0.0-rc.1":    "2.0.0-rc.1",
+		"nightly":        "",
+		"v1":             "",
+	} {
+		if got := normalizeReleaseVersion(tag); got != want {
+			t.Errorf("normalizeReleaseVersion(%q) = %q, want %q", tag, got, want)
+		}
+	}
+}
+
+func TestCrossCheckChangelog(t *testing.T) {
+	cl := ParseChangelog("CHANGELOG.md", keepAChangelog)
+	releases := []github.Release{
+		{TagName: "v1.2.0", Body: ""},
+		{TagName: "v1.1.0", Body: "Adds CSV and PDF exports, and fixes a crash."},
+		{TagName: "v1.2.0-beta.1", Prerelease: true},
+		{TagName: "v2.0.0", Draft: true},
+	}
+	tags := []github.Tag{{Name: "v1.2.0"}, {Name: "v1.1.0"}, {Name: "v0.9.0"}, {Name: "nightly"}}
+
+	crossCheckChangelog(cl, releases, tags)
+
+	if cl.Releases != 3 || cl.ReleasesWithoutNotes != 2 || cl.LatestRelease != "1.2.0" {
+		t.Errorf("Releases, WithoutNotes, LatestRelease = %d, %d, %s", cl.Releases, cl.ReleasesWithoutNotes, cl.LatestRelease)
+	}
+	if got := strings.Join(cl.MissingVersions, ","); got != "1.2.0" {
+		t.Errorf("MissingVersions = %q, want 1.2.0 only", got)
+	}
+	if got := strings.Join(cl.UntaggedVersions, ","); got != "1.0.0" {
+		t.Errorf("UntaggedVersions = %q, want 1.0.0", got)
+	}
+	if len(cl.Recommendations) != 2 || !strings.Contains(cl.Recommendations[0], "starting with 1.2.0") {
+		t.Errorf("Recommendations = %v", cl.Recommendations)
+	}
+
+	short := ParseChangelog("CHANGELOG.md", "## 1.2\n- Short heading\n\n## v1.1.0\n- Prefixed heading\n")
+	crossCheckChangelog(short, nil, []github.Tag{{Name: "v1.2.0"}, {Name: "1.1"}})
+	if len(short.MissingVersions) != 0 || len(short.UntaggedVersions) != 0 {
+		t.Errorf("MissingVersions, UntaggedVersions = %v, %v; want 1.2 to match v1.2.0 and v1.1.0 to match 1.1", short.MissingVersions, short.UntaggedVersions)
+	}
+
+	none := &ChangelogAnalysis{}
+	crossCheckChangelog(none, releases, nil)
+	if len(none.Recommendations) == 0 || !strings.Contains(none.Recommendations[0], "CHANGELOG.md") {
+		t.Errorf("without a changelog: %v", none.Recommendations)
+	}
+}
+
+func TestFindChangelog(t *testing.T) {
+	tree := []github.TreeEntry{
+		{Path: "docs/CHANGELOG.md", Type: "blob"},
+		{Path: "HISTORY.rst", Type: "blob"},
+		{Path: "CHANGES.md", Type: "blob"},
+		{Path: "pkg/CHANGELOG.md", Type: "blob"},
+	}
+	if got := findChangelog(tree); got != "CHANGES.md" {
+		t.Errorf("findChangelog() = %q, want CHANGES.md", got)
+	}
+
+	code := []github.TreeEntry{
+		{Path: "history.py", Type: "blob"},
+		{Path: "news.go", Type: "blob"},
+		{Path: "releases.json", Type: "blob"},
+		{Path: "docs/NEWS", Type: "blob"},
+	}
+	if got := findChangelog(code); got != "docs/NEWS" {
+		t.Errorf("findChangelog() = %q, want docs/NEWS", got)
+	}
+}
@@ -3,7 +3,9 @@
 package analyzer
 
 import (
+	"fmt"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/agnivo988/Repo-lyzer/internal/github"
@@ -27,11 +29,17 @@ type CodeQualityMetrics struct {
 	HasDocker         bool                   `json:"has_docker"`
 	HasEditorConfig   bool                   `json:"has_editorconfig"`
 	HasGitignore      bool                   `json:"has_gitignore"`
+	HasCodeOwners     bool                   `json:"has_codeowners"`
 	TestFrameworks    []string               `json:"test_frameworks"`
 	CIProviders       []string               `json:"ci_providers"`
 	FileStats         FileStatistics         `json:"file_stats"`
 	CodeSmells        []CodeSmell            `json:"code_smells"`
 	Recommendations   []string               `json:"recommendations"`
+	Source            *SourceMetrics         `json:"source,omitempty"`       // LOC and complexity, when sources were read
+	TestMapping       *TestMapping           `json:"test_mapping,omitempty"` // source files paired with their tests by convention
+	CodeOwners        *CodeOwnersAnalysis    `json:"codeowners,omitempty"`   // set by ApplyCodeOwners
+	Documentation     *DocumentationAnalysis `json:"documentation,omitempty"` // set by ApplyDocumentation
+	CommitConventions *CommitConventionAnalysis `json:"commit_conventions,omitempty"` // set by ApplyCommitConventions
 }
 
 // FileStatistics contains file-related metrics
@@ -44,7 +52,8 @@ type FileStatistics struct {
 	TestRatio        float64        `json:"test_ratio"`        // test files / source files
 	AvgPathDepth     float64        `json:"avg_path_depth"`    // average directory depth
 	FilesByExtension map[string]int `json:"files_by_extension"`
-	LargestFiles     []string       `json:"largest_files"`     // files with deep paths (potential complexity)
+	LargestFiles     []string       `json:"largest_files"`     // source files with the most bytes
+	DeepestPaths     []string       `json:"deepest_paths"`     // files nested more than 5 directories deep
 }
 
 // CodeSmell represents a potential code quality issue
@@ -57,7 +66,15 @@ type CodeSmell struct {
 
 // AnalyzeCodeQuality performs comprehensive code quality analysis
 func AnalyzeCodeQuality(repo *github.Repo, fileTree []github.TreeEntry, languages map[string]int) *CodeQualityMetrics {
+	return AnalyzeCodeQualityWithSource(repo, fileTree, languages, nil)
+}
+
+// AnalyzeCodeQualityWithSource performs code quality analysis including
+// source-level metrics from AnalyzeSourceMetrics, which add complexity and
+// file size checks to the structure score and code smells. source may be nil.
+func AnalyzeCodeQualityWithSource(repo *github.Repo, fileTree []github.TreeEntry, languages map[string]int, source *SourceMetrics) *CodeQualityMetrics {
 	metrics := &CodeQualityMetrics{
+		Source: source,
 		FileStats: FileStatistics{
 			FilesByExtension: make(map[string]int),
 		},
@@ -74,6 +91,7 @@ func AnalyzeCodeQuality(repo *github.Repo, fileTree []github.TreeEntry, language
 
 	// Analyze file tree
 	analyzeFileTree(metrics, fileTree)
+	metrics.TestMapping = MapTestsToSources(fileTree)
 
 	// Check for important files
 	checkImportantFiles(metrics, fileTree)
@@ -98,6 +116,7 @@ func AnalyzeCodeQuality(repo *github.Repo, fileTree []github.TreeEntry, language
 
 func analyzeFileTree(metrics *CodeQualityMetrics, fileTree []github.TreeEntry) {
 	var totalDepth int
+	var sources []github.TreeEntry
 	
 	for _, entry := range fileTree {
 		if entry.Type != "blob" {
@@ -112,7 +131,7 @@ func analyzeFileTree(metrics *CodeQualityMetrics, fileTree []github.TreeEntry) {
 
 		// Track deep paths (potential complexity)
 		if depth > 5 {
-			metrics.FileStats.LargestFiles = append(metrics.FileStats.LargestFiles, entry.Path)
+			metrics.FileStats.DeepestPaths = append(metrics.FileStats.DeepestPaths, entry.Path)
 		}
 
 		// Get extension
@@ -126,6 +145,7 @@ func analyzeFileTree(metrics *CodeQualityMetrics, fileTree []github.TreeEntry) {
 		
 		if isSourceFile(lowerPath) {
 			metrics.FileStats.SourceFiles++
+			sources = append(sources, entry)
 		}
 		if isTestFile(lowerPath) {
 			metrics.FileStats.TestFiles++
@@ -146,9 +166,15 @@ func analyzeFileTree(metrics *CodeQualityMetrics, fileTree []github.TreeEntry) {
 		metrics.FileStats.TestRatio = float64(metrics.FileStats.TestFiles) / float64(metrics.FileStats.SourceFiles)
 	}
 
-	// Limit largest files list
-	if len(metrics.FileStats.LargestFiles) > 10 {
-		metrics.FileStats.LargestFiles = metrics.FileStats.LargestFiles[:10]
+	// Largest source files by blob size
+	sort.SliceStable(sources, func(i, j int) bool { return sources[i].Size > sources[j].Size })
+	for i := 0; i < len(sources) && i < 10 && sources[i].Size > 0; i++ {
+		metrics.FileStats.LargestFiles = append(metrics.FileStats.LargestFiles, sources[i].Path)
+	}
+
+	// Limit deepest paths list
+	if len(metrics.FileStats.DeepestPaths) > 10 {
+		metrics.FileStats.DeepestPaths = metrics.FileStats.DeepestPaths[:10]
 	}
 }
 
@@ -192,6 +218,11 @@ func checkImportantFiles(metrics *CodeQualityMetrics, fileTree []github.TreeEntr
 			metrics.HasEditorConfig = true
 		}
 
+		// CODEOWNERS
+		if contains(CodeOwnersLocations, entry.Path) {
+			metrics.HasCodeOwners = true
+		}
+
 		// Docker
 		if strings.Contains(lowerPath, "dockerfile") || baseName == "docker-compose.yml" || baseName == "docker-compose.yaml" {
 			metrics.HasDocker = true
@@ -428,6 +459,43 @@ func detectCodeSmells(metrics *CodeQualityMetrics, fileTree []github.TreeEntry,
 		})
 	}
 
+	// Source-level checks
+	if src := metrics.Source; src != nil {
+		complexCount := 0
+		for _, fn := range src.ComplexFunctions {
+			if fn.Complexity > highComplexityThreshold {
+				complexCount++
+			}
+		}
+		if complexCount > 0 {
+			worst := src.ComplexFunctions[0]
+			severity := "Medium"
+			if worst.Complexity > 2*highComplexityThreshold {
+				severity = "High"
+			}
+			metrics.CodeSmells = append(metrics.CodeSmells, CodeSmell{
+				Type:     "Complex Functions",
+				Severity: severity,
+				Description: fmt.Sprintf("%d functions with cyclomatic complexity above %d, up to %d in %s",
+					complexCount, highComplexityThreshold, worst.Complexity, worst.Name),
+				Location: fmt.Sprintf("%s:%d", worst.Path, worst.Line),
+			})
+		}
+		if len(src.LargeFiles) > 0 {
+			largest := src.LargeFiles[0]
+			description := fmt.Sprintf("%d lines of code", largest.Code)
+			if n := src.LargeFileCount; n > 1 {
+				description += fmt.Sprintf("; %d files exceed %d lines", n, largeFileThreshold)
+			}
+			metrics.CodeSmells = append(metrics.CodeSmells, CodeSmell{
+				Type:        "Large File",
+				Severity:    "Low",
+				Description: description,
+				Location:    largest.Path,
+			})
+		}
+	}
+
 	// Check for stale repo
 	if repo != nil && repo.OpenIssues > 100 {
 		metrics.CodeSmells = append(metrics.CodeSmells, CodeSmell{
@@ -494,6 +562,9 @@ func calculateScores(metrics *CodeQualityMetrics, repo *github.Repo) {
 	} else if metrics.FileStats.AvgPathDepth > 6 {
 		structScore -= 10
 	}
+	if src := metrics.Source; src != nil && src.Functions > 0 {
+		structScore += sourceStructureAdjustment(src)
+	}
 	metrics.StructureScore = max(0, min(structScore, 100))
 
 	// Maintenance Score (0-100)
@@ -516,6 +587,11 @@ func calculateScores(metrics *CodeQualityMetrics, repo *github.Repo) {
 	}
 	metrics.MaintenanceScore = max(0, min(maintScore, 100))
 
+	calculateQualityGrade(metrics)
+}
+
+// calculateQualityGrade weights the category scores and assigns the grade
+func calculateQualityGrade(metrics *CodeQualityMetrics) {
 	// Overall Score (weighted average)
 	metrics.OverallScore = (metrics.DocumentationScore*25 +
 		metrics.TestingScore*30 +
@@ -537,6 +613,71 @@ func calculateScores(metrics *CodeQualityMetrics, repo *github.Repo) {
 	}
 }
 
+// sourceStructureAdjustment rewards simple functions and commented code,
+// and penalizes complex functions and oversized files
+func sourceStructureAdjustment(src *SourceMetrics) int {
+	adjust := 0
+	switch {
+	case src.AvgComplexity <= 5:
+		adjust += 10
+	case src.AvgComplexity > 10:
+		adjust -= 15
+	case src.AvgComplexity > 7:
+		adjust -= 5
+	}
+	if src.MaxComplexity > 2*highComplexityThreshold {
+		adjust -= 10
+	}
+	if src.CommentRatio >= 0.1 {
+		adjust += 5
+	}
+	if len(src.LargeFiles) > 0 {
+		adjust -= 5
+	}
+	return adjust
+}
+
+// ApplyCodeOwners attaches a CODEOWNERS analysis and regenerates the
+// recommendations to include ownership gaps
+func (metrics *CodeQualityMetrics) ApplyCodeOwners(co *CodeOwnersAnalysis) {
+	if co == nil || metrics.Grade == "N/A" {
+		return
+	}
+	metrics.CodeOwners = co
+	metrics.Recommendations = nil
+	generateQualityRecommendations(metrics)
+}
+
+// ApplyCommitConventions attaches a commit message analysis, adjusts the
+// maintenance score for commit hygiene and regenerates the recommendations.
+// It is meant to be called once per analysis.
+func (metrics *CodeQualityMetrics) ApplyCommitConventions(cc *CommitConventionAnalysis) {
+	if cc == nil || metrics.Grade == "N/A" || metrics.CommitConventions != nil {
+		return
+	}
+	metrics.CommitConventions = cc
+	metrics.MaintenanceScore = max(0, min(metrics.MaintenanceScore+commitHygieneAdjustment(cc), 100))
+	calculateQualityGrade(metrics)
+	metrics.Recommendations = nil
+	generateQualityRecommendations(metrics)
+}
+
+// ApplyDocumentation attaches a README and docs analysis, rescores the
+// documentation from the README's content and regenerates the
+// recommendations to include its most important fixes
+func (metrics *CodeQualityMetrics) ApplyDocumentation(doc *DocumentationAnalysis) {
+	if doc == nil || metrics.Grade == "N/A" {
+		return
+	}
+	metrics.Documentation = doc
+	if doc.Readme != "" {
+		metrics.DocumentationScore = documentationScore(metrics, doc)
+		calculateQualityGrade(metrics)
+	}
+	metrics.Recommendations = nil
+	generateQualityRecommendations(metrics)
+}
+
 func generateQualityRecommendations(metrics *CodeQualityMetrics) {
 	// Documentation recommendations
 	if !metrics.HasReadme {
@@ -551,6 +692,15 @@ func generateQualityRecommendations(metrics *CodeQualityMetrics) {
 	if !metrics.HasChangelog {
 		metrics.Recommendations = append(metrics.Recommendations, "📋 Add CHANGELOG.md to track version changes")
 	}
+	if doc := metrics.Documentation; doc != nil {
+		// Only the top README fixes, so the other categories still get a say
+		for i, rec := range doc.Recommendations {
+			if i == 2 {
+				break
+			}
+			metrics.Recommendations = append(metrics.Recommendations, rec)
+		}
+	}
 
 	// Testing recommendations
 	if !metrics.HasTests {
@@ -558,6 +708,10 @@ func generateQualityRecommendations(metrics *CodeQualityMetrics) {
 	} else if metrics.FileStats.TestRatio < 0.2 {
 		metrics.Recommendations = append(metrics.Recommendations, "📈 Increase test coverage (currently low)")
 	}
+	if tm := metrics.TestMapping; metrics.HasTests && tm != nil && len(tm.UntestedPackages) > 0 && tm.Coverage < 0.5 {
+		metrics.Recommendations = append(metrics.Recommendations,
+			fmt.Sprintf("🗂️ Add tests for %s, the largest directory without any", tm.UntestedPackages[0].Dir))
+	}
 
 	// CI/CD recommendations
 	if !metrics.HasCI {
@@ -571,6 +725,26 @@ func generateQualityRecommendations(metrics *CodeQualityMetrics) {
 	if !metrics.HasEditorConfig {
 		metrics.Recommendations = append(metrics.Recommendations, "⚙️ Add .editorconfig for consistent formatting")
 	}
+	if src := metrics.Source; src != nil && src.MaxComplexity > highComplexityThreshold {
+		metrics.Recommendations = append(metrics.Recommendations, "🧩 Split up functions with high cyclomatic complexity")
+	}
+
+	// Ownership recommendations
+	if co := metrics.CodeOwners; co != nil && co.File != "" {
+		if co.Coverage < 0.8 && len(co.UnownedDirectories) > 0 {
+			metrics.Recommendations = append(metrics.Recommendations,
+				fmt.Sprintf("👥 Assign code owners for %s (%.0f%% of files have owners)", co.UnownedDirectories[0].Dir, co.Coverage*100))
+		}
+		if len(co.InactiveOwners) > 0 {
+			metrics.Recommendations = append(metrics.Recommendations,
+				fmt.Sprintf("👤 Replace inactive code owners: %s", strings.Join(co.InactiveOwners, ", ")))
+		}
+	} else if !metrics.HasCodeOwners && metrics.FileStats.SourceFiles > 20 {
+		metrics.Recommendations = append(metrics.Recommendations, "👥 Add a CODEOWNERS file to route reviews to maintainers")
+	}
+
+	// Commit message recommendations
+	metrics.Recommendations = append(metrics.Recommendations, commitConventionRecommendations(metrics.CommitConventions)...)
 
 	// Limit recommendations
 	if len(metrics.Recommendations) > 5 {
@@ -0,0 +1,362 @@
+package analyzer
+
+import (
+	"encoding/base64"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// CodeOwnersLocations are the paths GitHub reads CODEOWNERS from, in the
+// order it checks them; only the first one found is used
+var CodeOwnersLocations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// CodeOwnersRule is one pattern line of a CODEOWNERS file. A rule without
+// owners explicitly leaves matching files unowned.
+type CodeOwnersRule struct {
+	Line    int            `json:"line"`
+	Pattern string         `json:"pattern"`
+	Owners  []string       `json:"owners"`
+	re      *regexp.Regexp // compiled pattern
+}
+
+// CodeOwnerStats counts the files assigned to one owner
+type CodeOwnerStats struct {
+	Owner  string `json:"owner"`
+	Files  int    `json:"files"`
+	Team   bool   `json:"team"`   // @org/team owners can't be checked for activity
+	Active bool   `json:"active"` // committed in the analyzed period
+}
+
+// UnownedDirectory is a directory in which no file has an owner
+type UnownedDirectory struct {
+	Dir   string `json:"dir"`
+	Files int    `json:"files"`
+}
+
+// CodeOwnersAnalysis holds the parsed CODEOWNERS file and how much of the
+// tree it covers
+type CodeOwnersAnalysis struct {
+	File               string             `json:"file"` // empty when the repository has no CODEOWNERS
+	Rules              []CodeOwnersRule   `json:"rules"`
+	Errors             []string           `json:"errors,omitempty"` // lines GitHub ignores
+	TotalFiles         int                `json:"total_files"`
+	OwnedFiles         int                `json:"owned_files"`
+	Coverage           float64            `json:"coverage"` // owned files / total files, 0-1
+	Owners             []CodeOwnerStats   `json:"owners"`
+	UnownedDirectories []UnownedDirectory `json:"unowned_directories"`
+	InactiveOwners     []string           `json:"inactive_owners"`
+	OrphanedFiles      int                `json:"orphaned_files"` // files whose every owner is inactive
+}
+
+// maxUnownedDirectories caps the unowned directory list
+const maxUnownedDirectories = 10
+
+// AnalyzeCodeOwners reads the repository's CODEOWNERS file and matches it
+// against the file tree. Owners are checked for activity against commits;
+// pass nil commits to skip that check.
+func AnalyzeCodeOwners(client *github.Client, owner, repo string, fileTree []github.TreeEntry, commits []github.Commit) (*CodeOwnersAnalysis, error) {
+	file := findCodeOwnersFile(fileTree)
+	if file == "" {
+		return analyzeCodeOwners("", "", fileTree, commits), nil
+	}
+
+	content, err := client.GetFileContent(owner, repo, file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", file, err)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", file, err)
+	}
+	return analyzeCodeOwners(file, string(decoded), fileTree, commits), nil
+}
+
+// findCodeOwnersFile returns the CODEOWNERS path GitHub would use
+func findCodeOwnersFile(fileTree []github.TreeEntry) string {
+	present := make(map[string]bool)
+	for _, entry := range fileTree {
+		if entry.Type == "blob" {
+			present[entry.Path] = true
+		}
+	}
+	for _, loc := range CodeOwnersLocations {
+		if present[loc] {
+			return loc
+		}
+	}
+	return ""
+}
+
+func analyzeCodeOwners(file, content string, fileTree []github.TreeEntry, commits []github.Commit) *CodeOwnersAnalysis {
+	analysis := &CodeOwnersAnalysis{
+		File:               file,
+		Rules:              []CodeOwnersRule{},
+		Owners:             []CodeOwnerStats{},
+		UnownedDirectories: []UnownedDirectory{},
+		InactiveOwners:     []string{},
+	}
+	if file != "" {
+		analysis.Rules, analysis.Errors = ParseCodeOwners(content)
+	}
+
+	active, known := activeCommitters(commits)
+	ownerFiles := make(map[string]int)
+	type dirCount struct{ files, owned int }
+	dirs := make(map[string]*dirCount)
+
+	for _, entry := range fileTree {
+		if entry.Type != "blob" {
+			continue
+		}
+		analysis.TotalFiles++
+		owners := MatchCodeOwners(analysis.Rules, entry.Path)
+		if len(owners) > 0 {
+			analysis.OwnedFiles++
+			orphaned := known
+			for _, o := range owners {
+				ownerFiles[o]++
+				if !known || isTeamOwner(o) || active[ownerKey(o)] {
+					orphaned = false
+				}
+			}
+			if orphaned {
+				analysis.OrphanedFiles++
+			}
+		}
+
+		// Count the file in every ancestor directory
+		for dir := path.Dir(entry.Path); dir != "."; dir = path.Dir(dir) {
+			d, ok := dirs[dir]
+			if !ok {
+				d = &dirCount{}
+				dirs[dir] = d
+			}
+			d.files++
+			if len(owners) > 0 {
+				d.owned++
+			}
+		}
+	}
+	if analysis.TotalFiles > 0 {
+		analysis.Coverage = float64(analysis.OwnedFiles) / float64(analysis.TotalFiles)
+	}
+
+	for o, n := range ownerFiles {
+		stats := CodeOwnerStats{Owner: o, Files: n, Team: isTeamOwner(o), Active: true}
+		if known && !stats.Team && !active[ownerKey(o)] {
+			stats.Active = false
+			analysis.InactiveOwners = append(analysis.InactiveOwners, o)
+		}
+		analysis.Owners = append(analysis.Owners, stats)
+	}
+	sort.Slice(analysis.Owners, func(i, j int) bool {
+		a, b := analysis.Owners[i], analysis.Owners[j]
+		if a.Files != b.Files {
+			return a.Files > b.Files
+		}
+		return a.Owner < b.Owner
+	})
+	sort.Strings(analysis.InactiveOwners)
+
+	// Report only the top-most unowned directories: a/b is implied by a
+	if file != "" {
+		for dir, d := range dirs {
+			if d.owned > 0 {
+				continue
+			}
+			if parent := path.Dir(dir); parent != "." && dirs[parent].owned == 0 {
+				continue
+			}
+			analysis.UnownedDirectories = append(analysis.UnownedDirectories, UnownedDirectory{Dir: dir, Files: d.files})
+		}
+		sort.Slice(analysis.UnownedDirectories, func(i, j int) bool {
+			a, b := analysis.UnownedDirectories[i], analysis.UnownedDirectories[j]
+			if a.Files != b.Files {
+				return a.Files > b.Files
+			}
+			return a.Dir < b.Dir
+		})
+		if len(analysis.UnownedDirectories) > maxUnownedDirectories {
+			analysis.UnownedDirectories = analysis.UnownedDirectories[:maxUnownedDirectories]
+		}
+	}
+
+	return analysis
+}
+
+// ParseCodeOwners parses CODEOWNERS content. Lines GitHub would ignore,
+// such as negated patterns or malformed owners, are returned as errors and
+// left out of the rules.
+func ParseCodeOwners(content string) ([]CodeOwnersRule, []string) {
+	var rules []CodeOwnersRule
+	var errs []string
+
+	for i, line := range strings.Split(content, "\n") {
+		line = stripCodeOwnersComment(strings.TrimSpace(line))
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		pattern := strings.ReplaceAll(fields[0], `\#`, "#")
+
+		re, err := compileCodeOwnersPattern(pattern)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("line %d: %v", i+1, err))
+			continue
+		}
+
+		owners := []string{}
+		valid := true
+		for _, o := range fields[1:] {
+			if !strings.HasPrefix(o, "@") && !strings.Contains(o, "@") {
+				errs = append(errs, fmt.Sprintf("line %d: invalid owner %q", i+1, o))
+				valid = false
+				break
+			}
+			owners = append(owners, o)
+		}
+		if !valid {
+			continue
+		}
+		rules = append(rules, CodeOwnersRule{Line: i + 1, Pattern: pattern, Owners: owners, re: re})
+	}
+	return rules, errs
+}
+
+// stripCodeOwnersComment removes a trailing comment; \# is a literal #
+func stripCodeOwnersComment(line string) string {
+	for i := 0; i < len(line); i++ {
+		if line[i] == '#' && (i == 0 || line[i-1] != '\\') {
+			return strings.TrimSpace(line[:i])
+		}
+	}
+	return line
+}
+
+// compileCodeOwnersPattern converts a CODEOWNERS pattern to a regular
+// expression. The syntax follows .gitignore, except that negation and
+// character ranges are not supported and "dir/*" does not reach into
+// subdirectories.
+func compileCodeOwnersPattern(pattern string) (*regexp.Regexp, error) {
+	if strings.HasPrefix(pattern, "!") {
+		return nil, fmt.Errorf("negated pattern %q is not supported", pattern)
+	}
+	if strings.ContainsAny(pattern, "[]") {
+		return nil, fmt.Errorf("character ranges in %q are not supported", pattern)
+	}
+
+	dirOnly := strings.HasSuffix(pattern, "/")
+	trimmed := strings.TrimSuffix(pattern, "/")
+	// A slash anywhere but the end anchors the pattern to the repository root
+	anchored := strings.Contains(trimmed, "/")
+	trimmed = strings.TrimPrefix(trimmed, "/")
+
+	var b strings.Builder
+	if anchored {
+		b.WriteString("^")
+	} else {
+		b.WriteString("^(?:.*/)?")
+	}
+
+	segments := strings.Split(trimmed, "/")
+	for i, seg := range segments {
+		last := i == len(segments)-1
+		if seg == "**" {
+			if last {
+				b.WriteString(".*")
+			} else {
+				b.WriteString("(?:.*/)?")
+			}
+			continue
+		}
+		for _, r := range seg {
+			switch r {
+			case '*':
+				b.WriteString("[^/]*")
+			case '?':
+				b.WriteString("[^/]")
+			default:
+				b.WriteString(regexp.QuoteMeta(string(r)))
+			}
+		}
+		if !last {
+			b.WriteString("/")
+		}
+	}
+
+	switch {
+	case dirOnly:
+		b.WriteString("/.*$")
+	case len(segments) > 1 && segments[len(segments)-1] == "*":
+		b.WriteString("$")
+	default:
+		b.WriteString("(?:/.*)?$")
+	}
+
+	return regexp.Compile(b.String())
+}
+
+// MatchCodeOwners returns the owners of a file: the last matching rule
+// wins, as on GitHub
+func MatchCodeOwners(rules []CodeOwnersRule, file string) []string {
+	for i := len(rules) - 1; i >= 0; i-- {
+		if rules[i].re != nil && rules[i].re.MatchString(file) {
+			return rules[i].Owners
+		}
+	}
+	return nil
+}
+
+// activeCommitters collects lowercase logins and emails of commit authors.
+// known is false when there are no commits to judge activity by.
+func activeCommitters(commits []github.Commit) (active map[string]bool, known bool) {
+	active = make(map[string]bool)
+	for _, c := range commits {
+		if c.Author != nil && c.Author.Login != "" {
+			active["@"+strings.ToLower(c.Author.Login)] = true
+		}
+		if email := c.Commit.Author.Email; email != "" {
+			active[strings.ToLower(email)] = true
+		}
+	}
+	return active, len(commits) > 0
+}
+
+func ownerKey(owner string) string {
+	return strings.ToLower(owner)
+}
+
+func isTeamOwner(owner string) bool {
+	return strings.HasPrefix(owner, "@") && strings.Contains(owner, "/")
+}
+
+// AdjustBusRiskForOwnership raises the contributor bus-factor risk one level
+// when CODEOWNERS shows review ownership resting on too few people: most
+// owned files belong only to inactive owners, or a single individual owns
+// most of the owned files
+func AdjustBusRiskForOwnership(busFactor int, busRisk string, co *CodeOwnersAnalysis) (int, string) {
+	if co == nil || co.OwnedFiles == 0 || busFactor <= 1 {
+		return busFactor, busRisk
+	}
+
+	concentrated := false
+	for _, o := range co.Owners {
+		if !o.Team && float64(o.Files)/float64(co.OwnedFiles) > 0.7 {
+			concentrated = true
+			break
+		}
+	}
+	if !concentrated && float64(co.OrphanedFiles)/float64(co.OwnedFiles) <= 0.5 {
+		return busFactor, busRisk
+	}
+
+	if busFactor == 3 {
+		return 2, "Medium Risk"
+	}
+	return 1, "High Risk"
+}
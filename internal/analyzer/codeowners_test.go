@@ -0,0 +1,150 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+func TestCompileCodeOwnersPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		matches []string
+		misses  []string
+	}{
+		{"*", []string{"README.md", "a/b/c.go"}, nil},
+		{"*.js", []string{"app.js", "web/src/app.js"}, []string{"app.jsx"}},
+		{"/build/logs/", []string{"build/logs/a.log", "build/logs/x/y.log"}, []string{"src/build/logs/a.log", "build/logs"}},
+		{"docs/*", []string{"docs/getting-started.md"}, []string{"docs/build-app/troubleshooting.md"}},
+		{"apps/", []string{"apps/web/main.go", "services/apps/x.go"}, []string{"apps.go"}},
+		{"/scripts", []string{"scripts/deploy.sh"}, []string{"tools/scripts/deploy.sh"}},
+		{"**/logs", []string{"logs/a.log", "deploy/logs/b.log"}, []string{"catalogs/a"}},
+		{"docs/**/*.md", []string{"docs/a.md", "docs/x/y/z.md"}, []string{"docs/a.txt", "src/docs/a.md"}},
+		{"Makefile", []string{"Makefile", "sub/Makefile"}, []string{"Makefile.inc"}},
+	}
+	for _, tt := range tests {
+		re, err := compileCodeOwnersPattern(tt.pattern)
+		if err != nil {
+			t.Fatalf("compileCodeOwnersPattern(%q): %v", tt.pattern, err)
+		}
+		for _, p := range tt.matches {
+			if !re.MatchString(p) {
+				t.Errorf("%q should match %q", tt.pattern, p)
+			}
+		}
+		for _, p := range tt.misses {
+			if re.MatchString(p) {
+				t.Errorf("%q should not match %q", tt.pattern, p)
+			}
+		}
+	}
+
+	for _, bad := range []string{"!docs/", "src/[ab].go"} {
+		if _, err := compileCodeOwnersPattern(bad); err == nil {
+			t.Errorf("compileCodeOwnersPattern(%q) should fail", bad)
+		}
+	}
+}
+
+func TestParseCodeOwners(t *testing.T) {
+	content := strings.Join([]string{
+		"# Default owners",
+		"*       @acme/core",
+		"*.go    @gopher  # Go code",
+		"/docs/  docs@acme.io",
+		`\#notes @octocat`,
+		"!vendor/ @nobody",
+		"/web/   not-an-owner",
+		"/third_party/",
+	}, "\n")
+
+	rules, errs := ParseCodeOwners(content)
+	if len(rules) != 5 {
+		t.Fatalf("len(rules) = %d, want 5: %+v", len(rules), rules)
+	}
+	if len(errs) != 2 || !strings.HasPrefix(errs[0], "line 6:") || !strings.HasPrefix(errs[1], "line 7:") {
+		t.Errorf("errs = %v, want errors on lines 6 and 7", errs)
+	}
+	if rules[3].Pattern != "#notes" {
+		t.Errorf("escaped pattern = %q, want #notes", rules[3].Pattern)
+	}
+
+	tests := map[string]string{
+		"README.md":          "@acme/core",
+		"cmd/main.go":        "@gopher",
+		"docs/guide.go":      "docs@acme.io", // later rules win
+		"third_party/lib.go": "",             // explicitly unowned
+	}
+	for file, want := range tests {
+		if got := strings.Join(MatchCodeOwners(rules, file), ","); got != want {
+			t.Errorf("MatchCodeOwners(%q) = %q, want %q", file, got, want)
+		}
+	}
+}
+
+func TestAnalyzeCodeOwners(t *testing.T) {
+	var tree []github.TreeEntry
+	for _, p := range []string{
+		".github/CODEOWNERS",
+		"api/server.go",
+		"api/handlers/users.go",
+		"web/app.ts",
+		"web/components/button.tsx",
+		"scripts/release.sh",
+		"scripts/ci/lint.sh",
+	} {
+		tree = append(tree, github.TreeEntry{Path: p, Type: "blob"})
+	}
+	content := "/api/ @alice @bob\n/web/ @carol\n"
+	commits := []github.Commit{
+		{Author: &github.User{Login: "Alice"}},
+		{Commit: github.CommitInfo{Author: github.CommitAuthor{Email: "dave@example.com"}}},
+	}
+
+	co := analyzeCodeOwners(".github/CODEOWNERS", content, tree, commits)
+
+	if co.TotalFiles != 7 || co.OwnedFiles != 4 {
+		t.Errorf("TotalFiles, OwnedFiles = %d, %d; want 7, 4", co.TotalFiles, co.OwnedFiles)
+	}
+	if strings.Join(co.InactiveOwners, ",") != "@bob,@carol" {
+		t.Errorf("InactiveOwners = %v, want @bob and @carol", co.InactiveOwners)
+	}
+	// web/ is owned only by @carol, who has not committed; api/ still has @alice
+	if co.OrphanedFiles != 2 {
+		t.Errorf("OrphanedFiles = %d, want 2", co.OrphanedFiles)
+	}
+	if len(co.UnownedDirectories) != 2 || co.UnownedDirectories[0].Dir != "scripts" || co.UnownedDirectories[1].Dir != ".github" {
+		t.Errorf("UnownedDirectories = %+v, want scripts then .github, without scripts/ci", co.UnownedDirectories)
+	}
+
+	none := analyzeCodeOwners("", "", tree, commits)
+	if none.OwnedFiles != 0 || len(none.UnownedDirectories) != 0 {
+		t.Errorf("without CODEOWNERS: %+v", none)
+	}
+	if got := findCodeOwnersFile(tree); got != ".github/CODEOWNERS" {
+		t.Errorf("findCodeOwnersFile() = %q", got)
+	}
+}
+
+func TestAdjustBusRiskForOwnership(t *testing.T) {
+	spread := &CodeOwnersAnalysis{OwnedFiles: 10, Owners: []CodeOwnerStats{{Owner: "@a", Files: 5}, {Owner: "@b", Files: 5}}}
+	if score, risk := AdjustBusRiskForOwnership(3, "Low Risk", spread); score != 3 || risk != "Low Risk" {
+		t.Errorf("spread ownership changed risk to %d %s", score, risk)
+	}
+
+	single := &CodeOwnersAnalysis{OwnedFiles: 10, Owners: []CodeOwnerStats{{Owner: "@a", Files: 9}, {Owner: "@b", Files: 1}}}
+	if score, risk := AdjustBusRiskForOwnership(3, "Low Risk", single); score != 2 || risk != "Medium Risk" {
+		t.Errorf("single owner: got %d %s, want 2 Medium Risk", score, risk)
+	}
+
+	team := &CodeOwnersAnalysis{OwnedFiles: 10, Owners: []CodeOwnerStats{{Owner: "@acme/core", Files: 10, Team: true}}}
+	if score, _ := AdjustBusRiskForOwnership(2, "Medium Risk", team); score != 2 {
+		t.Errorf("team ownership should not raise risk, got %d", score)
+	}
+
+	orphaned := &CodeOwnersAnalysis{OwnedFiles: 10, OrphanedFiles: 8, Owners: spread.Owners}
+	if score, risk := AdjustBusRiskForOwnership(2, "Medium Risk", orphaned); score != 1 || risk != "High Risk" {
+		t.Errorf("orphaned files: got %d %s, want 1 High Risk", score, risk)
+	}
+}
@@ -0,0 +1,196 @@
+package analyzer
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// CommitTypeCount counts the Conventional Commits of one type
+type CommitTypeCount struct {
+	Type  string `json:"type"`
+	Count int    `json:"count"`
+}
+
+// CommitConventionAnalysis summarizes commit message conventions and
+// hygiene. Merge commits are counted but left out of the message ratios,
+// since their messages are generated.
+type CommitConventionAnalysis struct {
+	TotalCommits      int               `json:"total_commits"`
+	MergeCommits      int               `json:"merge_commits"`
+	MergeRatio        float64           `json:"merge_ratio"` // merges / total commits, 0-1
+	Conventional      int               `json:"conventional"`
+	ConventionalRatio float64           `json:"conventional_ratio"` // of non-merge commits, 0-1
+	Types             []CommitTypeCount `json:"types"`              // most used first
+	BreakingChanges   int               `json:"breaking_changes"`
+	IssueReferences   int               `json:"issue_references"`
+	IssueRefRatio     float64           `json:"issue_ref_ratio"`
+	ShortMessages     int               `json:"short_messages"` // subjects under minSubjectLength characters
+	WIPMessages       int               `json:"wip_messages"`   // "wip", "fixup!" and placeholder subjects
+	SignedOff         int               `json:"signed_off"`     // commits with a Signed-off-by trailer
+	SignedOffRatio    float64           `json:"signed_off_ratio"`
+	PoorMessages      []string          `json:"poor_messages"` // examples of short and WIP subjects
+}
+
+const (
+	// minSubjectLength is the shortest subject that describes a change
+	minSubjectLength = 10
+	// maxPoorMessages caps the examples of short and WIP subjects
+	maxPoorMessages = 5
+)
+
+// ConventionalCommitTypes are the types from the Conventional Commits spec
+// and the Angular convention it grew out of
+var ConventionalCommitTypes = []string{"feat", "fix", "docs", "style", "refactor", "perf", "test", "build", "ci", "chore", "revert"}
+
+var (
+	conventionalPattern = regexp.MustCompile(`^(?i)([a-z]+)(\([^)]*\))?(!)?: \S`)
+	breakingPattern     = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE: `)
+	issueRefPattern     = regexp.MustCompile(`(?:^|[\s(\[,])(#\d+|GH-\d+|([A-Z]{2,}[A-Z0-9]*)-\d+)\b|/issues/\d+`)
+	signedOffPattern    = regexp.MustCompile(`(?mi)^Signed-off-by: .+<[^>]+>`)
+	mergeSubjectPattern = regexp.MustCompile(`^Merge (pull request|branch|remote-tracking branch|tag) `)
+	wipSubjectPattern   = regexp.MustCompile(`^(?i)(wip\b|fixup!|squash!|amend!|(update|fix|fixes|changes|misc|stuff|tmp|temp|test|asdf|minor|\.+)$)`)
+)
+
+// AnalyzeCommitConventions classifies the messages of the given commits
+func AnalyzeCommitConventions(commits []github.Commit) *CommitConventionAnalysis {
+	result := &CommitConventionAnalysis{
+		Types:        []CommitTypeCount{},
+		PoorMessages: []string{},
+	}
+	types := make(map[string]int)
+
+	for _, c := range commits {
+		result.TotalCommits++
+		message := strings.TrimSpace(c.Commit.Message)
+		subject, _, _ := strings.Cut(message, "\n")
+		subject = strings.TrimSpace(subject)
+
+		if len(c.Parents) > 1 || mergeSubjectPattern.MatchString(subject) {
+			result.MergeCommits++
+			continue
+		}
+
+		if m := conventionalPattern.FindStringSubmatch(subject); m != nil && contains(ConventionalCommitTypes, strings.ToLower(m[1])) {
+			result.Conventional++
+			types[strings.ToLower(m[1])]++
+			if m[3] == "!" {
+				result.BreakingChanges++
+			}
+		}
+		if !strings.Contains(subject, "!:") && breakingPattern.MatchString(message) {
+			result.BreakingChanges++
+		}
+		if hasIssueReference(message) {
+			result.IssueReferences++
+		}
+		if signedOffPattern.MatchString(message) {
+			result.SignedOff++
+		}
+
+		poor := true
+		switch {
+		case wipSubjectPattern.MatchString(subject):
+			result.WIPMessages++
+		case utf8.RuneCountInString(subject) < minSubjectLength:
+			result.ShortMessages++
+		default:
+			poor = false
+		}
+		if poor && len(result.PoorMessages) < maxPoorMessages {
+			result.PoorMessages = append(result.PoorMessages, subject)
+		}
+	}
+
+	for t, n := range types {
+		result.Types = append(result.Types, CommitTypeCount{Type: t, Count: n})
+	}
+	sort.Slice(result.Types, func(i, j int) bool {
+		if result.Types[i].Count != result.Types[j].Count {
+			return result.Types[i].Count > result.Types[j].Count
+		}
+		return result.Types[i].Type < result.Types[j].Type
+	})
+
+	if result.TotalCommits > 0 {
+		result.MergeRatio = float64(result.MergeCommits) / float64(result.TotalCommits)
+	}
+	if authored := result.TotalCommits - result.MergeCommits; authored > 0 {
+		result.ConventionalRatio = float64(result.Conventional) / float64(authored)
+		result.IssueRefRatio = float64(result.IssueReferences) / float64(authored)
+		result.SignedOffRatio = float64(result.SignedOff) / float64(authored)
+	}
+	return result
+}
+
+// notIssueKeys are standard and algorithm names written like a JIRA key,
+// as in UTF-8 or SHA-256
+var notIssueKeys = map[string]bool{
+	"UTF": true, "UCS": true, "SHA": true, "MD": true, "AES": true, "RSA": true, "DES": true,
+	"ISO": true, "RFC": true, "CVE": true, "CWE": true, "GHSA": true, "PEP": true, "JSR": true,
+	"ECMA": true, "ES": true, "IEEE": true, "HTTP": true, "TLS": true, "SSL": true, "IPV": true,
+	"UTC": true, "GMT": true, "CP": true, "WIN": true, "ASCII": true, "ANSI": true,
+}
+
+// hasIssueReference reports whether a message refers to an issue: #12,
+// GH-12, an issue URL or a JIRA-style key such as PROJ-42
+func hasIssueReference(message string) bool {
+	for _, m := range issueRefPattern.FindAllStringSubmatch(message, -1) {
+		if m[2] == "" || !notIssueKeys[m[2]] {
+			return true
+		}
+	}
+	return false
+}
+
+// LowEffortRatio is the share of non-merge commits with short or WIP subjects
+func (r *CommitConventionAnalysis) LowEffortRatio() float64 {
+	authored := r.TotalCommits - r.MergeCommits
+	if authored == 0 {
+		return 0
+	}
+	return float64(r.ShortMessages+r.WIPMessages) / float64(authored)
+}
+
+// commitHygieneAdjustment rewards consistent, linked commit messages and
+// penalizes low-effort ones
+func commitHygieneAdjustment(r *CommitConventionAnalysis) int {
+	if r.TotalCommits-r.MergeCommits < 10 {
+		return 0
+	}
+	adjust := 0
+	if r.ConventionalRatio >= 0.8 {
+		adjust += 5
+	}
+	if r.IssueRefRatio >= 0.3 {
+		adjust += 5
+	}
+	switch low := r.LowEffortRatio(); {
+	case low > 0.2:
+		adjust -= 10
+	case low > 0.1:
+		adjust -= 5
+	}
+	return adjust
+}
+
+// commitConventionRecommendations suggests at most one fix for commit
+// messages, the most pressing first
+func commitConventionRecommendations(r *CommitConventionAnalysis) []string {
+	if r == nil || r.TotalCommits-r.MergeCommits < 10 {
+		return nil
+	}
+	switch {
+	case r.LowEffortRatio() > 0.1:
+		return []string{fmt.Sprintf("✍️ Write descriptive commit messages (%.0f%% are short or WIP)", r.LowEffortRatio()*100)}
+	case r.SignedOff > 0 && r.SignedOffRatio < 1:
+		return []string{fmt.Sprintf("🔏 Enforce DCO sign-off (%.0f%% of commits are signed off)", r.SignedOffRatio*100)}
+	case r.ConventionalRatio > 0.2 && r.ConventionalRatio < 0.8:
+		return []string{fmt.Sprintf("📐 Follow Conventional Commits consistently (%.0f%% do)", r.ConventionalRatio*100)}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+func commitWithMessage(message string, parents int) github.Commit {
+	c := github.Commit{Parents: make([]github.CommitParent, parents)}
+	c.Commit.Message = message
+	return c
+}
+
+func TestAnalyzeCommitConventions(t *testing.T) {
+	var commits []github.Commit
+	for _, msg := range []string{
+		"feat(api): add pagination (#12)",
+		"fix: handle empty responses\n\nFixes #14\n\nSigned-off-by: Alice <alice@example.com>",
+		"feat!: drop Go 1.20 support",
+		"refactor: split parser\n\nBREAKING CHANGE: Parse now returns an error",
+		"Update README with install steps for PROJ-42",
+		"wip",
+		"Fix typo",
+		"fixup! feat: add pagination",
+		"Note: this is not a type",
+	} {
+		commits = append(commits, commitWithMessage(msg, 1))
+	}
+	commits = append(commits,
+		commitWithMessage("Merge pull request #15 from alice/feature", 2),
+		commitWithMessage("Merge branch 'main' into feature", 1),
+	)
+
+	r := AnalyzeCommitConventions(commits)
+
+	if r.TotalCommits != 11 || r.MergeCommits != 2 {
+		t.Errorf("TotalCommits, MergeCommits = %d, %d; want 11, 2", r.TotalCommits, r.MergeCommits)
+	}
+	if r.Conventional != 4 || r.BreakingChanges != 2 || r.IssueReferences != 3 || r.SignedOff != 1 {
+		t.Errorf("Conventional %d, Breaking %d, IssueRefs %d, SignedOff %d; want 4, 2, 3, 1",
+			r.Conventional, r.BreakingChanges, r.IssueReferences, r.SignedOff)
+	}
+	var types []string
+	for _, tc := range r.Types {
+		types = append(types, tc.Type)
+	}
+	if got := strings.Join(types, ","); got != "feat,fix,refactor" || r.Types[0].Count != 2 {
+		t.Errorf("Types = %+v", r.Types)
+	}
+	if r.WIPMessages != 2 || r.ShortMessages != 1 || strings.Join(r.PoorMessages, "|") != "wip|Fix typo|fixup! feat: add pagination" {
+		t.Errorf("WIP, Short, Poor = %d, %d, %q", r.WIPMessages, r.ShortMessages, r.PoorMessages)
+	}
+	if r.LowEffortRatio() != 3.0/9 {
+		t.Errorf("LowEffortRatio() = %.2f, want 3/9", r.LowEffortRatio())
+	}
+}
+
+func TestHasIssueReference(t *testing.T) {
+	for message, want := range map[string]bool{
+		"Fix login (#12)":                     true,
+		"Closes GH-7":                         true,
+		"PROJ-42: add retries":                true,
+		"See https://github.com/a/b/issues/3": true,
+		"Read files as UTF-8":                 false,
+		"Switch to SHA-256 and AES-128":       false,
+		"Parse ISO-8601 dates, fixes CORE-9":  true,
+		"Bump to v2-1":                        false,
+	} {
+		if got := hasIssueReference(message); got != want {
+			t.Errorf("hasIssueReference(%q) = %v, want %v", message, got, want)
+		}
+	}
+}
+
+func TestApplyCommitConventions(t *testing.T) {
+	var tree []github.TreeEntry
+	for _, p := range []string{"README.md", "CONTRIBUTING.md", "LICENSE", "CHANGELOG.md", ".gitignore", ".editorconfig", ".github/workflows/ci.yml", "main.go", "main_test.go"} {
+		tree = append(tree, github.TreeEntry{Path: p, Type: "blob"})
+	}
+	metrics := AnalyzeCodeQuality(nil, tree, nil)
+	before := metrics.MaintenanceScore
+
+	var commits []github.Commit
+	for i := 0; i < 10; i++ {
+		commits = append(commits, commitWithMessage("wip", 1))
+	}
+	cc := AnalyzeCommitConventions(commits)
+	metrics.ApplyCommitConventions(cc)
+	metrics.ApplyCommitConventions(cc)
+
+	if metrics.MaintenanceScore != before-10 {
+		t.Errorf("MaintenanceScore = %d, want %d applied once", metrics.MaintenanceScore, before-10)
+	}
+	found := false
+	for _, rec := range metrics.Recommendations {
+		if strings.Contains(rec, "descriptive commit messages") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Recommendations = %v, want the commit message advice", metrics.Recommendations)
+	}
+}
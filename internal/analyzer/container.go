@@ -0,0 +1,517 @@
+package analyzer
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// ContainerFinding is a best-practice issue in a Dockerfile
+type ContainerFinding struct {
+	File        string `json:"file"`
+	Line        int    `json:"line,omitempty"` // 0 for issues about the whole file
+	Rule        string `json:"rule"`
+	Severity    string `json:"severity"` // HIGH, MEDIUM, LOW
+	Description string `json:"description"`
+}
+
+// Dockerfile summarizes one parsed Dockerfile
+type Dockerfile struct {
+	Path           string   `json:"path"`
+	Stages         int      `json:"stages"`
+	MultiStage     bool     `json:"multi_stage"`
+	BaseImages     []string `json:"base_images"`    // external images in FROM, in order
+	User           string   `json:"user,omitempty"` // last USER of the final stage
+	HasHealthcheck bool     `json:"has_healthcheck"`
+}
+
+// BaseImage is an external image used in FROM by one or more Dockerfiles
+type BaseImage struct {
+	Image  string   `json:"image"` // repository, e.g. "golang" or "ghcr.io/acme/base"
+	Tag    string   `json:"tag,omitempty"`
+	Digest string   `json:"digest,omitempty"`
+	Pinned bool     `json:"pinned"` // referenced by digest
+	Files  []string `json:"files"`
+}
+
+// ContainerAnalysis holds the Dockerfiles of a repository, their issues
+// and the inventory of base images they build on
+type ContainerAnalysis struct {
+	Dockerfiles []Dockerfile       `json:"dockerfiles"`
+	BaseImages  []BaseImage        `json:"base_images"`
+	Findings    []ContainerFinding `json:"findings"`
+	Errors      []string           `json:"errors,omitempty"` // files that could not be fetched
+	HighCount   int                `json:"high_count"`
+	MediumCount int                `json:"medium_count"`
+	LowCount    int                `json:"low_count"`
+	Truncated   bool               `json:"truncated"`      // more Dockerfiles exist than were fetched
+	Skipped     int                `json:"skipped"`        // Dockerfiles left out by the file cap
+	Note        string             `json:"note,omitempty"` // explains a partial analysis
+}
+
+// Dockerfile rules
+const (
+	RuleUnpinnedBaseImage  = "unpinned-base-image"
+	RuleRunsAsRoot         = "runs-as-root"
+	RuleAddFromURL         = "add-from-url"
+	RuleMissingHealthcheck = "missing-healthcheck"
+	RuleAptNoCleanup       = "apt-no-cleanup"
+	RuleSecretInEnv        = "secret-in-env"
+)
+
+// maxDockerfiles caps how many Dockerfiles are fetched
+const (
+	maxDockerfiles        = 20
+	maxDockerfilesNoToken = 5
+)
+
+var (
+	dockerVarPattern    = regexp.MustCompile(`\$\{?([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}?`)
+	dockerSecretPattern = regexp.MustCompile(`(?i)(passw(or)?d|secret|token|api[_-]?key|private[_-]?key|access[_-]?key|credential)`)
+	aptInstallPattern   = regexp.MustCompile(`\bapt(-get)?\s+(-\S+\s+)*install\b`)
+)
+
+// AnalyzeContainers fetches every Dockerfile and Containerfile in the tree
+// and checks it for container best practices
+func AnalyzeContainers(client *github.Client, owner, repo string, fileTree []github.TreeEntry) *ContainerAnalysis {
+	fetch := contentFetcher(client, owner, repo)
+	result := analyzeContainers(fileTree, fetch, fileLimit(client, maxDockerfiles, maxDockerfilesNoToken))
+	result.Note = skippedNote(client, result.Skipped, "Dockerfiles")
+	return result
+}
+
+func analyzeContainers(fileTree []github.TreeEntry, fetch func(string) (string, bool), maxFiles int) *ContainerAnalysis {
+	result := &ContainerAnalysis{
+		Dockerfiles: []Dockerfile{},
+		BaseImages:  []BaseImage{},
+		Findings:    []ContainerFinding{},
+	}
+
+	var files []string
+	for _, entry := range fileTree {
+		if entry.Type == "blob" && isDockerfile(entry.Path) && !isVendoredPath(entry.Path) {
+			files = append(files, entry.Path)
+		}
+	}
+	sort.Strings(files)
+	if len(files) > maxFiles {
+		result.Truncated = true
+		result.Skipped = len(files) - maxFiles
+		files = files[:maxFiles]
+	}
+
+	images := make(map[string]*BaseImage)
+	for _, file := range files {
+		content, ok := fetch(file)
+		if !ok {
+			result.Errors = append(result.Errors, file+": could not be fetched")
+			continue
+		}
+		df, findings := analyzeDockerfile(file, content)
+		result.Dockerfiles = append(result.Dockerfiles, df)
+		result.Findings = append(result.Findings, findings...)
+
+		for _, ref := range df.BaseImages {
+			img, ok := images[ref]
+			if !ok {
+				parsed := parseImageRef(ref)
+				img = &parsed
+				images[ref] = img
+			}
+			if !contains(img.Files, file) {
+				img.Files = append(img.Files, file)
+			}
+		}
+	}
+
+	for _, img := range images {
+		result.BaseImages = append(result.BaseImages, *img)
+	}
+	sort.Slice(result.BaseImages, func(i, j int) bool {
+		a, b := result.BaseImages[i], result.BaseImages[j]
+		if a.Image != b.Image {
+			return a.Image < b.Image
+		}
+		return a.Tag+a.Digest < b.Tag+b.Digest
+	})
+
+	sort.SliceStable(result.Findings, func(i, j int) bool {
+		a, b := result.Findings[i], result.Findings[j]
+		if workflowSeverityRank[a.Severity] != workflowSeverityRank[b.Severity] {
+			return workflowSeverityRank[a.Severity] > workflowSeverityRank[b.Severity]
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	for _, f := range result.Findings {
+		switch f.Severity {
+		case "HIGH":
+			result.HighCount++
+		case "MEDIUM":
+			result.MediumCount++
+		default:
+			result.LowCount++
+		}
+	}
+	return result
+}
+
+// dockerfileDocExts are suffixes that make a Dockerfile-named file
+// documentation or notes rather than a build file, as in Dockerfile.md
+var dockerfileDocExts = map[string]bool{
+	".md": true, ".markdown": true, ".rst": true, ".txt": true, ".adoc": true,
+	".html": true, ".pdf": true, ".orig": true, ".bak": true,
+}
+
+// isDockerfile matches Dockerfile, Containerfile and their common variants
+// such as Dockerfile.dev and api.Dockerfile
+func isDockerfile(p string) bool {
+	name := strings.ToLower(path.Base(p))
+	for _, base := range []string{"dockerfile", "containerfile"} {
+		if name == base || strings.HasSuffix(name, "."+base) {
+			return true
+		}
+		if strings.HasPrefix(name, base+".") && !dockerfileDocExts[path.Ext(name)] {
+			return true
+		}
+	}
+	return false
+}
+
+// dockerInstruction is one instruction with its continuation lines joined
+type dockerInstruction struct {
+	line int // line of the instruction keyword
+	cmd  string
+	args string
+}
+
+// parseDockerfile splits a Dockerfile into instructions, joining lines
+// continued with a backslash and dropping comments
+func parseDockerfile(content string) []dockerInstruction {
+	var instructions []dockerInstruction
+	var current strings.Builder
+	start := 0
+
+	for i, raw := range strings.Split(content, "\n") {
+		line := strings.TrimSpace(raw)
+		if strings.HasPrefix(line, "#") || (line == "" && current.Len() == 0) {
+			continue
+		}
+		if current.Len() == 0 {
+			start = i + 1
+		}
+		continued := strings.HasSuffix(line, "\\")
+		current.WriteString(strings.TrimSuffix(line, "\\"))
+		current.WriteString(" ")
+		if continued {
+			continue
+		}
+
+		text := strings.TrimSpace(current.String())
+		current.Reset()
+		if text == "" {
+			continue
+		}
+		cmd, args, _ := strings.Cut(text, " ")
+		instructions = append(instructions, dockerInstruction{line: start, cmd: strings.ToUpper(cmd), args: strings.TrimSpace(args)})
+	}
+	return instructions
+}
+
+// analyzeDockerfile checks a single Dockerfile
+func analyzeDockerfile(file, content string) (Dockerfile, []ContainerFinding) {
+	df := Dockerfile{Path: file, BaseImages: []string{}}
+	var findings []ContainerFinding
+	add := func(line int, rule, severity, description string) {
+		findings = append(findings, ContainerFinding{File: file, Line: line, Rule: rule, Severity: severity, Description: description})
+	}
+
+	args := make(map[string]string)       // ARG defaults declared before the first FROM
+	stageUsers := make(map[string]string) // USER at the end of each named stage
+	stage := ""
+	finalFrom := 0
+
+	for _, ins := range parseDockerfile(content) {
+		switch ins.cmd {
+		case "ARG":
+			if df.Stages == 0 {
+				name, value, _ := strings.Cut(ins.args, "=")
+				args[strings.TrimSpace(name)] = strings.Trim(strings.TrimSpace(value), `"'`)
+			}
+
+		case "FROM":
+			df.Stages++
+			finalFrom = ins.line
+			df.User = ""
+			stage = ""
+			fields := strings.Fields(ins.args)
+			for len(fields) > 0 && strings.HasPrefix(fields[0], "--") {
+				fields = fields[1:] // --platform=...
+			}
+			if len(fields) == 0 {
+				continue
+			}
+
+			ref := expandDockerArgs(fields[0], args)
+			parent, fromStage := stageUsers[strings.ToLower(ref)]
+			if len(fields) >= 3 && strings.EqualFold(fields[1], "as") {
+				stage = strings.ToLower(fields[2])
+				stageUsers[stage] = ""
+			}
+			if fromStage {
+				// An earlier build stage, not an external image; its USER carries over
+				df.User = parent
+				if stage != "" {
+					stageUsers[stage] = parent
+				}
+				continue
+			}
+			if strings.EqualFold(ref, "scratch") {
+				continue
+			}
+			df.BaseImages = append(df.BaseImages, ref)
+
+			img := parseImageRef(ref)
+			switch {
+			case img.Pinned:
+			case strings.Contains(ref, "$"):
+				add(ins.line, RuleUnpinnedBaseImage, "LOW", fmt.Sprintf("base image %s is set by a build argument without a default", ref))
+			case img.Tag == "" || img.Tag == "latest":
+				add(ins.line, RuleUnpinnedBaseImage, "MEDIUM", fmt.Sprintf("base image %s uses the latest tag; pin a version and digest", ref))
+			default:
+				add(ins.line, RuleUnpinnedBaseImage, "LOW", fmt.Sprintf("base image %s is not pinned by digest", ref))
+			}
+
+		case "USER":
+			if fields := strings.Fields(ins.args); len(fields) > 0 {
+				df.User = fields[0]
+				if stage != "" {
+					stageUsers[stage] = df.User
+				}
+			}
+
+		case "HEALTHCHECK":
+			df.HasHealthcheck = !strings.EqualFold(strings.TrimSpace(ins.args), "NONE")
+
+		case "ADD":
+			if strings.Contains(ins.args, "--checksum=") {
+				break
+			}
+			for _, arg := range strings.Fields(ins.args) {
+				if strings.HasPrefix(arg, "http://") || strings.HasPrefix(arg, "https://") {
+					add(ins.line, RuleAddFromURL, "MEDIUM", fmt.Sprintf("ADD downloads %s without verifying it; use curl with a checksum or ADD --checksum", arg))
+					break
+				}
+			}
+
+		case "RUN":
+			if aptInstallPattern.MatchString(ins.args) && !strings.Contains(ins.args, "/var/lib/apt/lists") {
+				add(ins.line, RuleAptNoCleanup, "LOW", "apt install without removing /var/lib/apt/lists in the same layer")
+			}
+
+		case "ENV":
+			for _, v := range envVars(ins.args) {
+				if isEnvSecret(v[0], v[1]) {
+					add(ins.line, RuleSecretInEnv, "HIGH", fmt.Sprintf("ENV %s stores a secret in the image; pass it at runtime or use a build secret", v[0]))
+				}
+			}
+		}
+	}
+
+	df.MultiStage = df.Stages > 1
+	if df.Stages == 0 {
+		return df, findings
+	}
+	if isRootUser(df.User) {
+		add(finalFrom, RuleRunsAsRoot, "MEDIUM", "the final stage runs as root; add a USER instruction")
+	}
+	if !df.HasHealthcheck {
+		add(0, RuleMissingHealthcheck, "LOW", "no HEALTHCHECK instruction")
+	}
+	return df, findings
+}
+
+// expandDockerArgs substitutes ARG defaults into a FROM reference, falling
+// back to ${NAME:-default}; unknown variables are left as they are
+func expandDockerArgs(ref string, args map[string]string) string {
+	return dockerVarPattern.ReplaceAllStringFunc(ref, func(v string) string {
+		m := dockerVarPattern.FindStringSubmatch(v)
+		if value := args[m[1]]; value != "" {
+			return value
+		}
+		if m[2] != "" {
+			return m[2] // ${NAME:-default}
+		}
+		return v
+	})
+}
+
+// parseImageRef splits an image reference into repository, tag and digest.
+// A colon is only a tag separator after the last slash, since the registry
+// part may carry a port.
+//
+//	golang:1.22-alpine         -> golang, 1.22-alpine
+//	localhost:5000/app@sha256:… -> localhost:5000/app, digest
+func parseImageRef(ref string) BaseImage {
+	img := BaseImage{Files: []string{}}
+	name := ref
+	if i := strings.Index(name, "@"); i >= 0 {
+		img.Digest = name[i+1:]
+		name = name[:i]
+		img.Pinned = strings.HasPrefix(img.Digest, "sha256:")
+	}
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		img.Tag = name[i+1:]
+		name = name[:i]
+	}
+	img.Image = name
+	return img
+}
+
+// envVars returns the name and value pairs set by an ENV instruction, in
+// either the "ENV KEY value" or the "ENV KEY=value ..." form. Quotes are
+// removed from the values.
+func envVars(args string) [][2]string {
+	args = strings.TrimSpace(args)
+	first, rest := args, ""
+	if i := strings.IndexAny(args, " \t"); i >= 0 {
+		first, rest = args[:i], args[i+1:]
+	}
+	if first == "" {
+		return nil
+	}
+	if !strings.Contains(first, "=") {
+		return [][2]string{{first, unquoteEnvValue(strings.TrimSpace(rest))}}
+	}
+
+	var vars [][2]string
+	for args != "" {
+		eq := strings.IndexAny(args, "= \t")
+		if eq < 0 || args[eq] != '=' {
+			break
+		}
+		name := args[:eq]
+		args = args[eq+1:]
+
+		// The value ends at the first space outside quotes
+		end, quote := 0, byte(0)
+		for ; end < len(args); end++ {
+			c := args[end]
+			if c == '\\' && quote != '\'' {
+				end++
+			} else if quote != 0 && c == quote {
+				quote = 0
+			} else if quote == 0 && (c == '"' || c == '\'') {
+				quote = c
+			} else if quote == 0 && (c == ' ' || c == '\t') {
+				break
+			}
+		}
+		end = min(end, len(args))
+		vars = append(vars, [2]string{name, unquoteEnvValue(args[:end])})
+		args = strings.TrimLeft(args[end:], " \t")
+	}
+	return vars
+}
+
+func unquoteEnvValue(v string) string {
+	if len(v) >= 2 && (v[0] == '"' || v[0] == '\'') && v[len(v)-1] == v[0] {
+		return v[1 : len(v)-1]
+	}
+	return v
+}
+
+// envSecretMinEntropy keeps words like "bearer" or "enabled" assigned to a
+// secret-sounding name from being reported
+const envSecretMinEntropy = 2.5
+
+// isEnvSecret reports whether an ENV variable bakes a literal secret into
+// the image. Names that point at a secret, like TOKEN_FILE or SECRET_NAME,
+// and values taken from build arguments are not secrets themselves.
+func isEnvSecret(name, value string) bool {
+	if !dockerSecretPattern.MatchString(name) {
+		return false
+	}
+	upper := strings.ToUpper(name)
+	for _, suffix := range []string{"_FILE", "_URL", "_PATH", "_NAME"} {
+		if strings.HasSuffix(upper, suffix) {
+			return false
+		}
+	}
+	if len(value) < 6 || strings.Contains(value, "$") || isPlaceholderSecret(value) {
+		return false
+	}
+	return shannonEntropy(value) >= envSecretMinEntropy
+}
+
+// PackageURL returns the docker package URL of the image, versioned by
+// digest when pinned and by tag otherwise
+//
+//	ghcr.io/acme/base:1.0 -> pkg:docker/acme/base@1.0?repository_url=ghcr.io
+func (img BaseImage) PackageURL() string {
+	name := img.Image
+	var registry string
+	if first, rest, ok := strings.Cut(name, "/"); ok && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		registry, name = first, rest
+	}
+	segments := strings.Split(name, "/")
+	for i, seg := range segments {
+		segments[i] = purlEscape(seg)
+	}
+
+	purl := "pkg:docker/" + strings.Join(segments, "/")
+	switch {
+	case img.Digest != "":
+		purl += "@" + purlEscape(img.Digest)
+	case img.Tag != "":
+		purl += "@" + purlEscape(img.Tag)
+	}
+	if registry != "" {
+		purl += "?repository_url=" + purlEscape(registry)
+	}
+	return purl
+}
+
+func isRootUser(user string) bool {
+	name, _, _ := strings.Cut(user, ":")
+	return name == "" || name == "root" || name == "0"
+}
+
+// AddBaseImageDependencies records the base images of each Dockerfile as
+// "docker" dependencies, so they appear in the dependency report and SBOM.
+// Vulnerability scanning skips them, since OSV has no container ecosystem.
+func AddBaseImageDependencies(deps *DependencyAnalysis, containers *ContainerAnalysis) {
+	if deps == nil || containers == nil {
+		return
+	}
+	for _, df := range containers.Dockerfiles {
+		var images []Dependency
+		for _, ref := range df.BaseImages {
+			img := parseImageRef(ref)
+			version := img.Tag
+			if version == "" {
+				version = "latest"
+			}
+			images = append(images, Dependency{Name: img.Image, Version: version, Type: "base-image", Resolved: img.Digest})
+		}
+		if len(images) == 0 {
+			continue
+		}
+		deps.Files = append(deps.Files, DependencyFile{
+			Filename:     df.Path,
+			FileType:     "docker",
+			Dependencies: images,
+			TotalCount:   len(images),
+		})
+		deps.TotalDeps += len(images)
+		if !contains(deps.Languages, "docker") {
+			deps.Languages = append(deps.Languages, "docker")
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+const riskyDockerfile = `# syntax=docker/dockerfile:1
+ARG GO_VERSION=1.22
+FROM golang:${GO_VERSION} AS build
+WORKDIR /src
+COPY . .
+RUN go build -o /app .
+
+FROM ubuntu
+ENV API_TOKEN=abc123 LANG=C.UTF-8
+RUN apt-get update && \
+    apt-get install -y ca-certificates
+ADD https://example.com/tool.tar.gz /opt/
+COPY --from=build /app /app
+ENTRYPOINT ["/app"]
+`
+
+const safeDockerfile = `FROM node:20-alpine@sha256:1a526b97cace6b4006256570efa1a29cd1fe4b96a5301f8d48e87c5139438a45 AS deps
+RUN npm ci
+
+FROM gcr.io/distroless/nodejs20-debian12@sha256:2b1d0b6a7a1e1f0a9d3f5c4b1e2a3d4c5b6a7f8e9d0c1b2a3f4e5d6c7b8a9f0e
+COPY --from=deps /node_modules /node_modules
+USER nonroot:nonroot
+HEALTHCHECK CMD ["/nodejs/bin/node", "healthcheck.js"]
+`
+
+func TestAnalyzeDockerfile(t *testing.T) {
+	df, findings := analyzeDockerfile("Dockerfile", riskyDockerfile)
+
+	if df.Stages != 2 || !df.MultiStage {
+		t.Errorf("Stages, MultiStage = %d, %v; want 2, true", df.Stages, df.MultiStage)
+	}
+	if strings.Join(df.BaseImages, ",") != "golang:1.22,ubuntu" {
+		t.Errorf("BaseImages = %v, want the ARG expanded and the build stage skipped", df.BaseImages)
+	}
+
+	byRule := make(map[string][]ContainerFinding)
+	for _, f := range findings {
+		byRule[f.Rule] = append(byRule[f.Rule], f)
+	}
+	unpinned := byRule[RuleUnpinnedBaseImage]
+	if len(unpinned) != 2 || unpinned[0].Severity != "LOW" || unpinned[1].Severity != "MEDIUM" || unpinned[1].Line != 8 {
+		t.Errorf("unpinned base images = %+v, want LOW for the tag and MEDIUM for ubuntu on line 8", unpinned)
+	}
+	if got := byRule[RuleSecretInEnv]; len(got) != 1 || !strings.Contains(got[0].Description, "API_TOKEN") {
+		t.Errorf("secrets in ENV = %+v, want API_TOKEN only", got)
+	}
+	if got := byRule[RuleAptNoCleanup]; len(got) != 1 || got[0].Line != 10 {
+		t.Errorf("apt without cleanup = %+v, want line 10", got)
+	}
+	for _, rule := range []string{RuleAddFromURL, RuleRunsAsRoot, RuleMissingHealthcheck} {
+		if len(byRule[rule]) != 1 {
+			t.Errorf("%s findings = %+v, want 1", rule, byRule[rule])
+		}
+	}
+
+	safe, findings := analyzeDockerfile("web/Dockerfile", safeDockerfile)
+	if len(findings) != 0 {
+		t.Errorf("safe Dockerfile findings = %+v, want none", findings)
+	}
+	if safe.User != "nonroot:nonroot" || !safe.HasHealthcheck {
+		t.Errorf("safe Dockerfile = %+v", safe)
+	}
+}
+
+func TestAnalyzeDockerfile_InheritedUser(t *testing.T) {
+	content := `FROM debian:12@sha256:0123456789abcdef AS base
+RUN useradd app
+USER app
+HEALTHCHECK CMD true
+
+FROM base AS builder
+RUN make
+
+FROM builder
+COPY . .
+HEALTHCHECK CMD true
+`
+	df, findings := analyzeDockerfile("Dockerfile", content)
+	if df.User != "app" {
+		t.Errorf("User = %q, want app inherited through base and builder", df.User)
+	}
+	for _, f := range findings {
+		if f.Rule == RuleRunsAsRoot {
+			t.Errorf("unexpected finding %+v", f)
+		}
+	}
+
+	// A stage built from an external image starts over as root
+	_, findings = analyzeDockerfile("Dockerfile", content+"\nFROM debian:12@sha256:0123456789abcdef\nHEALTHCHECK CMD true\n")
+	if len(findings) != 1 || findings[0].Rule != RuleRunsAsRoot {
+		t.Errorf("findings = %+v, want the final stage flagged as root", findings)
+	}
+}
+
+func TestParseImageRef(t *testing.T) {
+	tests := []struct {
+		ref, image, tag string
+		pinned          bool
+		purl            string
+	}{
+		{"golang:1.22-alpine", "golang", "1.22-alpine", false, "pkg:docker/golang@1.22-alpine"},
+		{"ubuntu", "ubuntu", "", false, "pkg:docker/ubuntu"},
+		{"localhost:5000/app@sha256:abc", "localhost:5000/app", "", true, "pkg:docker/app@sha256%3Aabc?repository_url=localhost%3A5000"},
+		{"ghcr.io/acme/base:1.0", "ghcr.io/acme/base", "1.0", false, "pkg:docker/acme/base@1.0?repository_url=ghcr.io"},
+	}
+	for _, tt := range tests {
+		img := parseImageRef(tt.ref)
+		if img.Image != tt.image || img.Tag != tt.tag || img.Pinned != tt.pinned {
+			t.Errorf("parseImageRef(%q) = %+v", tt.ref, img)
+		}
+		if got := img.PackageURL(); got != tt.purl {
+			t.Errorf("PackageURL(%q) = %q, want %q", tt.ref, got, tt.purl)
+		}
+	}
+}
+
+func TestEnvSecrets(t *testing.T) {
+	tests := map[string][]string{
+		`API_TOKEN=abc123 LANG=C.UTF-8`:            {"API_TOKEN"},
+		`DB_PASSWORD "s3cr3t-Pa55"`:                {"DB_PASSWORD"},
+		`GITHUB_TOKEN="ghp 1234 5678" OTHER=1`:     {"GITHUB_TOKEN"},
+		`TOKEN_URL=https://auth.example.com/token`: nil,
+		`PASSWORD_FILE=/run/secrets/db_password`:   nil,
+		`SECRET_NAME=ref`:                          nil,
+		`API_KEY=$API_KEY`:                         nil,
+		`NPM_TOKEN=${NPM_TOKEN}`:                   nil,
+		`TOKEN_TYPE=bearer`:                        nil,
+		`ADMIN_PASSWORD=changeme`:                  nil,
+		`SECRET_KEY_BASE=`:                         nil,
+	}
+	for args, want := range tests {
+		var got []string
+		for _, v := range envVars(args) {
+			if isEnvSecret(v[0], v[1]) {
+				got = append(got, v[0])
+			}
+		}
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("ENV %s: secrets = %v, want %v", args, got, want)
+		}
+	}
+}
+
+func TestAnalyzeContainers(t *testing.T) {
+	files := map[string]string{
+		"Dockerfile":                 riskyDockerfile,
+		"deploy/api.Dockerfile":      "FROM ubuntu\nUSER app\nHEALTHCHECK NONE\n",
+		"web/Containerfile":          safeDockerfile,
+		"vendor/x/Dockerfile":        riskyDockerfile,
+		"docs/dockerfile-guide.md":   "FROM ubuntu",
+		"Dockerfile.md":              "FROM ubuntu",
+		"docker/dockerfile.txt":      "FROM ubuntu",
+		"missing/Dockerfile.release": "",
+	}
+	var tree []github.TreeEntry
+	for p := range files {
+		tree = append(tree, github.TreeEntry{Path: p, Type: "blob"})
+	}
+	fetch := func(p string) (string, bool) {
+		content, ok := files[p]
+		return content, ok && content != ""
+	}
+
+	result := analyzeContainers(tree, fetch, maxDockerfiles)
+	if len(result.Dockerfiles) != 3 || len(result.Errors) != 1 {
+		t.Fatalf("Dockerfiles, Errors = %d, %v; want 3 and the missing file", len(result.Dockerfiles), result.Errors)
+	}
+	if result.HighCount != 1 || result.Findings[0].Rule != RuleSecretInEnv {
+		t.Errorf("HighCount = %d, first finding %+v; want the ENV secret first", result.HighCount, result.Findings[0])
+	}
+
+	var ubuntu *BaseImage
+	for i := range result.BaseImages {
+		if result.BaseImages[i].Image == "ubuntu" {
+			ubuntu = &result.BaseImages[i]
+		}
+	}
+	if len(result.BaseImages) != 4 || ubuntu == nil || len(ubuntu.Files) != 2 {
+		t.Errorf("BaseImages = %+v, want 4 with ubuntu used by 2 files", result.BaseImages)
+	}
+
+	deps := &DependencyAnalysis{}
+	AddBaseImageDependencies(deps, result)
+	if deps.TotalDeps != 5 || len(deps.Files) != 3 || deps.Files[0].FileType != "docker" {
+		t.Errorf("base image dependencies = %+v", deps)
+	}
+	if len(scanTargets(deps)) != 0 {
+		t.Error("base images should not be sent to the vulnerability scanner")
+	}
+
+	capped := analyzeContainers(tree, fetch, 2)
+	if !capped.Truncated || capped.Skipped != 2 || len(capped.Dockerfiles)+len(capped.Errors) != 2 {
+		t.Errorf("capped analysis = truncated %v, skipped %d; want 2 of 4 Dockerfiles read", capped.Truncated, capped.Skipped)
+	}
+}
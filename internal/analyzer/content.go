@@ -0,0 +1,44 @@
+package analyzer
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// contentFetcher returns a function that fetches and decodes a repository
+// file through the contents API, reporting false when it can't be read
+func contentFetcher(client *github.Client, owner, repo string) func(string) (string, bool) {
+	return func(p string) (string, bool) {
+		content, err := client.GetFileContent(owner, repo, p)
+		if err != nil {
+			return "", false
+		}
+		decoded, err := base64.StdEncoding.DecodeString(content)
+		if err != nil {
+			return "", false
+		}
+		return string(decoded), true
+	}
+}
+
+// fileLimit returns how many files an analyzer may fetch, using the smaller
+// cap when unauthenticated requests share the 60/hour rate limit
+func fileLimit(client *github.Client, max, maxNoToken int) int {
+	if !client.HasToken() {
+		return maxNoToken
+	}
+	return max
+}
+
+// skippedNote tells the UI that a section only covers part of the files
+func skippedNote(client *github.Client, skipped int, noun string) string {
+	if skipped == 0 {
+		return ""
+	}
+	if !client.HasToken() {
+		return fmt.Sprintf("Partial: %d %s skipped (rate limit: unauthenticated); set GITHUB_TOKEN to analyze all of them", skipped, noun)
+	}
+	return fmt.Sprintf("Partial: %d %s skipped (rate limit)", skipped, noun)
+}
@@ -0,0 +1,135 @@
+package analyzer
+
+import (
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// CalendarWeeks is how many weeks a contribution calendar covers
+const CalendarWeeks = 52
+
+// CalendarWeek is one column of a contribution calendar
+type CalendarWeek struct {
+	Start time.Time `json:"start"` // the Sunday that starts the week, UTC midnight
+	Days  [7]int    `json:"days"`  // commits per weekday, Sunday first
+	Total int       `json:"total"`
+}
+
+// ContributionCalendar counts commits per day over the last CalendarWeeks
+// weeks, like the calendar on a GitHub profile. Days are taken from each
+// commit's own date, so a late commit counts for the day its author saw.
+type ContributionCalendar struct {
+	Author string         `json:"author,omitempty"` // empty for the whole repository
+	Weeks  []CalendarWeek `json:"weeks"`            // oldest first; the last one contains end
+	Total  int            `json:"total"`
+	Max    int            `json:"max"` // busiest day
+	// Since is set when the commits were a truncated page: weeks before it
+	// are unknown rather than empty
+	Since *time.Time `json:"since,omitempty"`
+}
+
+// BuildContributionCalendar lays out commits up to end in weeks. A
+// non-empty author keeps only the commits of that login or email.
+func BuildContributionCalendar(commits []github.Commit, author string, end time.Time) *ContributionCalendar {
+	last := calendarWeekStart(end)
+	first := last.AddDate(0, 0, -7*(CalendarWeeks-1))
+
+	cal := &ContributionCalendar{Author: author, Weeks: make([]CalendarWeek, CalendarWeeks)}
+	for i := range cal.Weeks {
+		cal.Weeks[i].Start = first.AddDate(0, 0, 7*i)
+	}
+
+	for _, c := range commits {
+		if author != "" && commitAuthorKey(c) != author {
+			continue
+		}
+		day := calendarDay(c.Commit.Author.Date)
+		if day.Before(first) || day.After(calendarDay(end)) {
+			continue
+		}
+		week := int(day.Sub(first).Hours()/24) / 7
+		w := &cal.Weeks[week]
+		w.Days[day.Weekday()]++
+		w.Total++
+		cal.Total++
+		cal.Max = max(cal.Max, w.Days[day.Weekday()])
+	}
+	return cal
+}
+
+// MarkPartial records that commits are only the latest page of the
+// history, so the calendar covers the days from the oldest of them on
+func (cal *ContributionCalendar) MarkPartial(commits []github.Commit) {
+	var oldest time.Time
+	for _, c := range commits {
+		if d := c.Commit.Author.Date; oldest.IsZero() || d.Before(oldest) {
+			oldest = d
+		}
+	}
+	if !oldest.IsZero() {
+		since := calendarDay(oldest)
+		cal.Since = &since
+	}
+}
+
+// Covers reports whether the commits reach into week w
+func (cal *ContributionCalendar) Covers(w CalendarWeek) bool {
+	return cal.Since == nil || w.Start.AddDate(0, 0, 7).After(*cal.Since)
+}
+
+// CalendarFromCommitActivity lays out GitHub's weekly commit activity
+// statistics, which cover the whole default branch rather than a page of
+// commits. Those count days in UTC.
+func CalendarFromCommitActivity(activity []github.CommitActivityWeek, end time.Time) *ContributionCalendar {
+	last := calendarWeekStart(end)
+	first := last.AddDate(0, 0, -7*(CalendarWeeks-1))
+
+	cal := &ContributionCalendar{Weeks: make([]CalendarWeek, CalendarWeeks)}
+	for i := range cal.Weeks {
+		cal.Weeks[i].Start = first.AddDate(0, 0, 7*i)
+	}
+
+	for _, a := range activity {
+		start := calendarDay(time.Unix(a.Week, 0).UTC())
+		if start.Before(first) || start.After(last) {
+			continue
+		}
+		w := &cal.Weeks[int(start.Sub(first).Hours()/24)/7]
+		for day, n := range a.Days {
+			w.Days[day] += n
+			w.Total += n
+			cal.Total += n
+			cal.Max = max(cal.Max, w.Days[day])
+		}
+	}
+	return cal
+}
+
+// CommitsInWeek returns the commits of the week starting at start,
+// optionally only those of author
+func CommitsInWeek(commits []github.Commit, author string, start time.Time) []github.Commit {
+	end := start.AddDate(0, 0, 7)
+	var week []github.Commit
+	for _, c := range commits {
+		if author != "" && commitAuthorKey(c) != author {
+			continue
+		}
+		day := calendarDay(c.Commit.Author.Date)
+		if !day.Before(start) && day.Before(end) {
+			week = append(week, c)
+		}
+	}
+	return week
+}
+
+// calendarDay is the date t falls on in its own offset, as UTC midnight
+func calendarDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// calendarWeekStart is the Sunday of the week t falls in
+func calendarWeekStart(t time.Time) time.Time {
+	day := calendarDay(t)
+	return day.AddDate(0, 0, -int(day.Weekday()))
+}
@@ -0,0 +1,77 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+func TestBuildContributionCalendar(t *testing.T) {
+	end := time.Date(2026, 6, 3, 15, 0, 0, 0, time.UTC) // Wednesday
+	tokyo := time.FixedZone("", 9*3600)
+
+	commits := []github.Commit{
+		commitAt("alice", end),
+		commitAt("alice", end.Add(-time.Hour)),
+		commitAt("bob", time.Date(2026, 6, 1, 1, 0, 0, 0, tokyo)), // Monday in Tokyo, Sunday in UTC
+		commitAt("bob", end.AddDate(0, 0, -7*51)),                 // first week
+		commitAt("bob", end.AddDate(-1, 0, 0)),                    // before the calendar
+		commitAt("bob", end.AddDate(0, 0, 1)),                     // after end
+	}
+
+	cal := BuildContributionCalendar(commits, "", end)
+
+	if len(cal.Weeks) != CalendarWeeks || cal.Total != 4 || cal.Max != 2 {
+		t.Fatalf("Weeks, Total, Max = %d, %d, %d; want %d, 4, 2", len(cal.Weeks), cal.Total, cal.Max, CalendarWeeks)
+	}
+	last := cal.Weeks[CalendarWeeks-1]
+	if !last.Start.Equal(time.Date(2026, 5, 31, 0, 0, 0, 0, time.UTC)) || last.Days[time.Wednesday] != 2 || last.Days[time.Monday] != 1 {
+		t.Errorf("last week = %+v", last)
+	}
+	if cal.Weeks[0].Total != 1 {
+		t.Errorf("first week = %+v, want 1 commit", cal.Weeks[0])
+	}
+
+	bob := BuildContributionCalendar(commits, "bob", end)
+	if bob.Total != 2 || bob.Author != "bob" {
+		t.Errorf("bob's calendar Total = %d, want 2", bob.Total)
+	}
+	if got := CommitsInWeek(commits, "alice", last.Start); len(got) != 2 {
+		t.Errorf("CommitsInWeek(alice) = %d commits, want 2", len(got))
+	}
+	if !cal.Covers(cal.Weeks[0]) {
+		t.Error("a complete calendar should cover every week")
+	}
+
+	recent := commits[:3]
+	partial := BuildContributionCalendar(recent, "", end)
+	partial.MarkPartial(recent)
+	if partial.Since == nil || !partial.Since.Equal(time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Since = %v, want the oldest commit's day", partial.Since)
+	}
+	if partial.Covers(cal.Weeks[CalendarWeeks-2]) || !partial.Covers(last) {
+		t.Error("a partial calendar should only cover the weeks from its oldest commit on")
+	}
+}
+
+func TestCalendarFromCommitActivity(t *testing.T) {
+	end := time.Date(2026, 6, 3, 15, 0, 0, 0, time.UTC)
+	lastWeek := time.Date(2026, 5, 31, 0, 0, 0, 0, time.UTC)
+
+	cal := CalendarFromCommitActivity([]github.CommitActivityWeek{
+		{Week: lastWeek.Unix(), Days: [7]int{0, 4, 0, 1, 0, 0, 0}, Total: 5},
+		{Week: lastWeek.AddDate(0, 0, -7*51).Unix(), Days: [7]int{2}, Total: 2},
+		{Week: lastWeek.AddDate(0, 0, -7*52).Unix(), Days: [7]int{9}, Total: 9}, // before the calendar
+	}, end)
+
+	if cal.Total != 7 || cal.Max != 4 {
+		t.Errorf("Total, Max = %d, %d; want 7, 4", cal.Total, cal.Max)
+	}
+	if w := cal.Weeks[CalendarWeeks-1]; !w.Start.Equal(lastWeek) || w.Days[time.Monday] != 4 || w.Total != 5 {
+		t.Errorf("last week = %+v", w)
+	}
+	if cal.Weeks[0].Days[time.Sunday] != 2 {
+		t.Errorf("first week = %+v", cal.Weeks[0])
+	}
+}
@@ -0,0 +1,452 @@
+// Package analyzer provides analysis functions for GitHub repositories.
+// This file implements CVSS v3.x and v4.0 base score calculation from
+// vector strings such as "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H".
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// CVSSScore computes the score of a CVSS v3.0, v3.1 or v4.0 vector. For
+// v3.x this is the base score; v4.0 vectors may also carry threat and
+// environmental metrics, which are taken into account when present.
+func CVSSScore(vector string) (float64, error) {
+	vector = strings.TrimSpace(vector)
+	switch {
+	case strings.HasPrefix(vector, "CVSS:3.0/"), strings.HasPrefix(vector, "CVSS:3.1/"):
+		return cvss3Score(vector)
+	case strings.HasPrefix(vector, "CVSS:4.0/"):
+		return cvss4Score(vector)
+	}
+	return 0, fmt.Errorf("unsupported CVSS vector: %q", vector)
+}
+
+// CVSSSeverity returns the qualitative severity rating for a score
+func CVSSSeverity(score float64) string {
+	switch {
+	case score >= 9.0:
+		return "CRITICAL"
+	case score >= 7.0:
+		return "HIGH"
+	case score >= 4.0:
+		return "MEDIUM"
+	case score > 0:
+		return "LOW"
+	}
+	return "NONE"
+}
+
+// parseCVSSMetrics splits a vector into its metrics, checking that every
+// metric is known, appears once and has a valid value
+func parseCVSSMetrics(vector string, allowed map[string]string, required []string) (map[string]string, error) {
+	parts := strings.Split(vector, "/")
+	metrics := make(map[string]string, len(parts)-1)
+	for _, part := range parts[1:] {
+		kv := strings.SplitN(part, ":", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("malformed CVSS metric %q", part)
+		}
+		values, ok := allowed[kv[0]]
+		if !ok {
+			return nil, fmt.Errorf("unknown CVSS metric %q", kv[0])
+		}
+		if _, dup := metrics[kv[0]]; dup {
+			return nil, fmt.Errorf("duplicate CVSS metric %q", kv[0])
+		}
+		if len(kv[1]) != 1 || !strings.Contains(values, kv[1]) {
+			return nil, fmt.Errorf("invalid value %q for CVSS metric %s", kv[1], kv[0])
+		}
+		metrics[kv[0]] = kv[1]
+	}
+	for _, m := range required {
+		if _, ok := metrics[m]; !ok {
+			return nil, fmt.Errorf("CVSS vector is missing metric %s", m)
+		}
+	}
+	return metrics, nil
+}
+
+// cvss3Metrics lists the valid values of each CVSS v3.x metric, including
+// temporal and environmental metrics which are accepted but not scored
+var cvss3Metrics = map[string]string{
+	"AV": "NALP", "AC": "LH", "PR": "NLH", "UI": "NR", "S": "UC",
+	"C": "HLN", "I": "HLN", "A": "HLN",
+	"E": "XUPFH", "RL": "XOTWU", "RC": "XURC",
+	"CR": "XLMH", "IR": "XLMH", "AR": "XLMH",
+	"MAV": "XNALP", "MAC": "XLH", "MPR": "XNLH", "MUI": "XNR", "MS": "XUC",
+	"MC": "XHLN", "MI": "XHLN", "MA": "XHLN",
+}
+
+// cvss3Score implements the CVSS v3.1 base score equations
+func cvss3Score(vector string) (float64, error) {
+	m, err := parseCVSSMetrics(vector, cvss3Metrics, []string{"AV", "AC", "PR", "UI", "S", "C", "I", "A"})
+	if err != nil {
+		return 0, err
+	}
+
+	changed := m["S"] == "C"
+	av := map[string]float64{"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2}[m["AV"]]
+	ac := map[string]float64{"L": 0.77, "H": 0.44}[m["AC"]]
+	ui := map[string]float64{"N": 0.85, "R": 0.62}[m["UI"]]
+	pr := map[string]float64{"N": 0.85, "L": 0.62, "H": 0.27}[m["PR"]]
+	if changed {
+		pr = map[string]float64{"N": 0.85, "L": 0.68, "H": 0.5}[m["PR"]]
+	}
+	cia := map[string]float64{"H": 0.56, "L": 0.22, "N": 0}
+
+	iss := 1 - (1-cia[m["C"]])*(1-cia[m["I"]])*(1-cia[m["A"]])
+	impact := 6.42 * iss
+	if changed {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	}
+	if impact <= 0 {
+		return 0, nil
+	}
+
+	exploitability := 8.22 * av * ac * pr * ui
+	if changed {
+		return cvssRoundUp(math.Min(1.08*(impact+exploitability), 10)), nil
+	}
+	return cvssRoundUp(math.Min(impact+exploitability, 10)), nil
+}
+
+// cvssRoundUp is the CVSS v3.1 Roundup function: the smallest number with
+// one decimal place that is equal to or higher than its input, computed
+// on integers to avoid floating point artifacts
+func cvssRoundUp(x float64) float64 {
+	n := int64(math.Round(x * 100000))
+	if n%10000 == 0 {
+		return float64(n) / 100000
+	}
+	return float64(n/10000+1) / 10
+}
+
+// cvss4Metrics lists the valid values of each scored CVSS v4.0 metric;
+// supplemental metrics are accepted and ignored
+var cvss4Metrics = map[string]string{
+	"AV": "NALP", "AC": "LH", "AT": "NP", "PR": "NLH", "UI": "NPA",
+	"VC": "HLN", "VI": "HLN", "VA": "HLN", "SC": "HLN", "SI": "HLN", "SA": "HLN",
+	"E":  "XAPU",
+	"CR": "XHML", "IR": "XHML", "AR": "XHML",
+	"MAV": "XNALP", "MAC": "XLH", "MAT": "XNP", "MPR": "XNLH", "MUI": "XNPA",
+	"MVC": "XHLN", "MVI": "XHLN", "MVA": "XHLN", "MSC": "XHLN", "MSI": "XSHLN", "MSA": "XSHLN",
+	"S": "XNP", "AU": "XNY", "R": "XAUI", "V": "XDC", "RE": "XLMH", "U": "X",
+}
+
+// cvss4Vector holds the effective value of each metric, with modified
+// environmental metrics applied and unset metrics at their defaults
+type cvss4Vector map[string]string
+
+func (v cvss4Vector) get(metric string) string {
+	if mod, ok := v["M"+metric]; ok && mod != "X" {
+		return mod
+	}
+	switch val := v[metric]; {
+	case metric == "E" && (val == "" || val == "X"):
+		return "A" // Unreported exploit maturity is scored as Attacked
+	case (metric == "CR" || metric == "IR" || metric == "AR") && (val == "" || val == "X"):
+		return "H"
+	default:
+		return val
+	}
+}
+
+// cvss4Score implements the CVSS v4.0 scoring algorithm: the vector is
+// mapped to a macrovector of six equivalence classes whose score comes
+// from the specification's lookup table, then interpolated by the
+// vector's distance from the most severe vector in its class.
+func cvss4Score(vector string) (float64, error) {
+	m, err := parseCVSSMetrics(vector, cvss4Metrics, []string{"AV", "AC", "AT", "PR", "UI", "VC", "VI", "VA", "SC", "SI", "SA"})
+	if err != nil {
+		return 0, err
+	}
+	v := cvss4Vector(m)
+
+	// No impact on any system means a score of zero
+	noImpact := true
+	for _, metric := range []string{"VC", "VI", "VA", "SC", "SI", "SA"} {
+		if v.get(metric) != "N" {
+			noImpact = false
+		}
+	}
+	if noImpact {
+		return 0, nil
+	}
+
+	eq := cvss4MacroVector(v)
+	value, ok := cvss4Lookup(eq)
+	if !ok {
+		return 0, fmt.Errorf("no CVSS v4.0 score for macrovector %v", eq)
+	}
+
+	lower := func(class, delta int) (float64, bool) {
+		next := eq
+		next[class] += delta
+		return cvss4Lookup(next)
+	}
+	nextEQ1, hasEQ1 := lower(0, 1)
+	nextEQ2, hasEQ2 := lower(1, 1)
+	nextEQ4, hasEQ4 := lower(3, 1)
+	nextEQ5, hasEQ5 := lower(4, 1)
+
+	// EQ3 and EQ6 are scored jointly; when both can step down the more
+	// severe of the two neighbours is used
+	var nextEQ3EQ6 float64
+	var hasEQ3EQ6 bool
+	switch {
+	case eq[2] <= 1 && eq[5] == 0:
+		left, okLeft := lower(5, 1)
+		next := eq
+		next[2]++
+		right, okRight := cvss4Lookup(next)
+		switch {
+		case okLeft && okRight:
+			nextEQ3EQ6, hasEQ3EQ6 = math.Max(left, right), true
+		case okLeft:
+			nextEQ3EQ6, hasEQ3EQ6 = left, true
+		case okRight:
+			nextEQ3EQ6, hasEQ3EQ6 = right, true
+		}
+	default:
+		nextEQ3EQ6, hasEQ3EQ6 = lower(2, 1)
+	}
+
+	// Find the most severe vector of the macrovector that this vector
+	// does not exceed in any metric
+	var distances map[string]float64
+	for _, max := range cvss4MaxVectors(eq) {
+		d := make(map[string]float64, len(cvss4Levels))
+		valid := true
+		for metric, levels := range cvss4Levels {
+			d[metric] = levels[v.get(metric)] - levels[max[metric]]
+			if d[metric] < 0 {
+				valid = false
+				break
+			}
+		}
+		if valid {
+			distances = d
+			break
+		}
+	}
+	if distances == nil {
+		return 0, fmt.Errorf("no CVSS v4.0 maximal vector for macrovector %v", eq)
+	}
+
+	const step = 0.1
+	type class struct {
+		next, distance, maxSeverity float64
+		exists                      bool
+	}
+	classes := []class{
+		{nextEQ1, distances["AV"] + distances["PR"] + distances["UI"], cvss4MaxSeverityEQ1[eq[0]] * step, hasEQ1},
+		{nextEQ2, distances["AC"] + distances["AT"], cvss4MaxSeverityEQ2[eq[1]] * step, hasEQ2},
+		{nextEQ3EQ6, distances["VC"] + distances["VI"] + distances["VA"] + distances["CR"] + distances["IR"] + distances["AR"],
+			cvss4MaxSeverityEQ3EQ6[[2]int{eq[2], eq[5]}] * step, hasEQ3EQ6},
+		{nextEQ4, distances["SC"] + distances["SI"] + distances["SA"], cvss4MaxSeverityEQ4[eq[3]] * step, hasEQ4},
+		{nextEQ5, 0, step, hasEQ5}, // EQ5 has a single vector per level
+	}
+
+	var total float64
+	var existing int
+	for _, c := range classes {
+		if !c.exists {
+			continue
+		}
+		existing++
+		total += (value - c.next) * (c.distance / c.maxSeverity)
+	}
+	if existing > 0 {
+		value -= total / float64(existing)
+	}
+
+	value = math.Max(0, math.Min(10, value))
+	return math.Round(value*10) / 10, nil
+}
+
+// cvss4MacroVector computes the six equivalence classes EQ1..EQ6
+func cvss4MacroVector(v cvss4Vector) [6]int {
+	var eq [6]int
+
+	av, pr, ui := v.get("AV"), v.get("PR"), v.get("UI")
+	switch {
+	case av == "N" && pr == "N" && ui == "N":
+		eq[0] = 0
+	case (av == "N" || pr == "N" || ui == "N") && av != "P":
+		eq[0] = 1
+	default:
+		eq[0] = 2
+	}
+
+	if v.get("AC") != "L" || v.get("AT") != "N" {
+		eq[1] = 1
+	}
+
+	vc, vi, va := v.get("VC"), v.get("VI"), v.get("VA")
+	switch {
+	case vc == "H" && vi == "H":
+		eq[2] = 0
+	case vc == "H" || vi == "H" || va == "H":
+		eq[2] = 1
+	default:
+		eq[2] = 2
+	}
+
+	sc, si, sa := v.get("SC"), v.get("SI"), v.get("SA")
+	switch {
+	case si == "S" || sa == "S":
+		eq[3] = 0
+	case sc == "H" || si == "H" || sa == "H":
+		eq[3] = 1
+	default:
+		eq[3] = 2
+	}
+
+	switch v.get("E") {
+	case "P":
+		eq[4] = 1
+	case "U":
+		eq[4] = 2
+	}
+
+	if !((v.get("CR") == "H" && vc == "H") || (v.get("IR") == "H" && vi == "H") || (v.get("AR") == "H" && va == "H")) {
+		eq[5] = 1
+	}
+	return eq
+}
+
+// cvss4Lookup returns the score of a macrovector
+func cvss4Lookup(eq [6]int) (float64, bool) {
+	key := fmt.Sprintf("%d%d%d%d%d%d", eq[0], eq[1], eq[2], eq[3], eq[4], eq[5])
+	score, ok := cvss4MacroScores[key]
+	return score, ok
+}
+
+// cvss4MaxVectors returns the most severe vectors of a macrovector, as
+// metric maps, in the specification's order
+func cvss4MaxVectors(eq [6]int) []map[string]string {
+	var result []map[string]string
+	for _, e1 := range cvss4MaxEQ1[eq[0]] {
+		for _, e2 := range cvss4MaxEQ2[eq[1]] {
+			for _, e36 := range cvss4MaxEQ3EQ6[[2]int{eq[2], eq[5]}] {
+				for _, e4 := range cvss4MaxEQ4[eq[3]] {
+					max := make(map[string]string)
+					for _, part := range strings.Split(e1+"/"+e2+"/"+e36+"/"+e4, "/") {
+						kv := strings.SplitN(part, ":", 2)
+						max[kv[0]] = kv[1]
+					}
+					result = append(result, max)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// cvss4Levels gives each metric value's severity distance from the most
+// severe value, in steps of 0.1
+var cvss4Levels = map[string]map[string]float64{
+	"AV": {"N": 0.0, "A": 0.1, "L": 0.2, "P": 0.3},
+	"PR": {"N": 0.0, "L": 0.1, "H": 0.2},
+	"UI": {"N": 0.0, "P": 0.1, "A": 0.2},
+	"AC": {"L": 0.0, "H": 0.1},
+	"AT": {"N": 0.0, "P": 0.1},
+	"VC": {"H": 0.0, "L": 0.1, "N": 0.2},
+	"VI": {"H": 0.0, "L": 0.1, "N": 0.2},
+	"VA": {"H": 0.0, "L": 0.1, "N": 0.2},
+	"SC": {"H": 0.1, "L": 0.2, "N": 0.3},
+	"SI": {"S": 0.0, "H": 0.1, "L": 0.2, "N": 0.3},
+	"SA": {"S": 0.0, "H": 0.1, "L": 0.2, "N": 0.3},
+	"CR": {"H": 0.0, "M": 0.1, "L": 0.2},
+	"IR": {"H": 0.0, "M": 0.1, "L": 0.2},
+	"AR": {"H": 0.0, "M": 0.1, "L": 0.2},
+}
+
+// Most severe vectors of each equivalence class level
+var (
+	cvss4MaxEQ1 = map[int][]string{
+		0: {"AV:N/PR:N/UI:N"},
+		1: {"AV:A/PR:N/UI:N", "AV:N/PR:L/UI:N", "AV:N/PR:N/UI:P"},
+		2: {"AV:P/PR:N/UI:N", "AV:A/PR:L/UI:P"},
+	}
+	cvss4MaxEQ2 = map[int][]string{
+		0: {"AC:L/AT:N"},
+		1: {"AC:H/AT:N", "AC:L/AT:P"},
+	}
+	cvss4MaxEQ3EQ6 = map[[2]int][]string{
+		{0, 0}: {"VC:H/VI:H/VA:H/CR:H/IR:H/AR:H"},
+		{0, 1}: {"VC:H/VI:H/VA:L/CR:M/IR:M/AR:H", "VC:H/VI:H/VA:H/CR:M/IR:M/AR:M"},
+		{1, 0}: {"VC:L/VI:H/VA:H/CR:H/IR:H/AR:H", "VC:H/VI:L/VA:H/CR:H/IR:H/AR:H"},
+		{1, 1}: {"VC:L/VI:H/VA:L/CR:H/IR:M/AR:H", "VC:L/VI:H/VA:H/CR:H/IR:M/AR:M", "VC:H/VI:L/VA:H/CR:M/IR:H/AR:M",
+			"VC:H/VI:L/VA:L/CR:M/IR:H/AR:H", "VC:L/VI:L/VA:H/CR:H/IR:H/AR:M"},
+		{2, 1}: {"VC:L/VI:L/VA:L/CR:H/IR:H/AR:H"},
+	}
+	cvss4MaxEQ4 = map[int][]string{
+		0: {"SC:H/SI:S/SA:S"},
+		1: {"SC:H/SI:H/SA:H"},
+		2: {"SC:L/SI:L/SA:L"},
+	}
+)
+
+// Maximal severity distances within each equivalence class level
+var (
+	cvss4MaxSeverityEQ1    = map[int]float64{0: 1, 1: 4, 2: 5}
+	cvss4MaxSeverityEQ2    = map[int]float64{0: 1, 1: 2}
+	cvss4MaxSeverityEQ3EQ6 = map[[2]int]float64{{0, 0}: 7, {0, 1}: 6, {1, 0}: 8, {1, 1}: 8, {2, 1}: 10}
+	cvss4MaxSeverityEQ4    = map[int]float64{0: 6, 1: 5, 2: 4}
+)
+
+// cvss4MacroScores is the CVSS v4.0 macrovector lookup table, keyed by the
+// EQ1..EQ6 levels
+var cvss4MacroScores = map[string]float64{
+	"000000": 10, "000001": 9.9, "000010": 9.8, "000011": 9.5, "000020": 9.5, "000021": 9.2,
+	"000100": 10, "000101": 9.6, "000110": 9.3, "000111": 8.7, "000120": 9.1, "000121": 8.1,
+	"000200": 9.3, "000201": 9, "000210": 8.9, "000211": 8, "000220": 8.1, "000221": 6.8,
+	"001000": 9.8, "001001": 9.5, "001010": 9.5, "001011": 9.2, "001020": 9, "001021": 8.4,
+	"001100": 9.3, "001101": 9.2, "001110": 8.9, "001111": 8.1, "001120": 8.1, "001121": 6.5,
+	"001200": 8.8, "001201": 8, "001210": 7.8, "001211": 7, "001220": 6.9, "001221": 4.8,
+	"002001": 9.2, "002011": 8.2, "002021": 7.2, "002101": 7.9, "002111": 6.9, "002121": 5,
+	"002201": 6.9, "002211": 5.5, "002221": 2.7,
+	"010000": 9.9, "010001": 9.7, "010010": 9.5, "010011": 9.2, "010020": 9.2, "010021": 8.5,
+	"010100": 9.5, "010101": 9.1, "010110": 9, "010111": 8.3, "010120": 8.4, "010121": 7.1,
+	"010200": 9.2, "010201": 8.1, "010210": 8.2, "010211": 7.1, "010220": 7.2, "010221": 5.3,
+	"011000": 9.5, "011001": 9.3, "011010": 9.2, "011011": 8.5, "011020": 8.5, "011021": 7.3,
+	"011100": 9.2, "011101": 8.2, "011110": 8, "011111": 7.2, "011120": 7, "011121": 5.9,
+	"011200": 8.4, "011201": 7, "011210": 7.1, "011211": 5.2, "011220": 5, "011221": 3,
+	"012001": 8.6, "012011": 7.5, "012021": 5.2, "012101": 7.1, "012111": 5.2, "012121": 2.9,
+	"012201": 6.3, "012211": 2.9, "012221": 1.7,
+	"100000": 9.8, "100001": 9.5, "100010": 9.4, "100011": 8.7, "100020": 9.1, "100021": 8.1,
+	"100100": 9.4, "100101": 8.9, "100110": 8.6, "100111": 7.4, "100120": 7.7, "100121": 6.4,
+	"100200": 8.7, "100201": 7.5, "100210": 7.4, "100211": 6.3, "100220": 6.3, "100221": 4.9,
+	"101000": 9.4, "101001": 8.9, "101010": 8.8, "101011": 7.7, "101020": 7.6, "101021": 6.7,
+	"101100": 8.6, "101101": 7.6, "101110": 7.4, "101111": 5.8, "101120": 5.9, "101121": 5,
+	"101200": 7.2, "101201": 5.7, "101210": 5.7, "101211": 5.2, "101220": 5.2, "101221": 2.5,
+	"102001": 8.3, "102011": 7, "102021": 5.4, "102101": 6.5, "102111": 5.8, "102121": 2.6,
+	"102201": 5.3, "102211": 2.1, "102221": 1.3,
+	"110000": 9.5, "110001": 9, "110010": 8.8, "110011": 7.6, "110020": 7.6, "110021": 7,
+	"110100": 9, "110101": 7.7, "110110": 7.5, "110111": 6.2, "110120": 6.1, "110121": 5.3,
+	"110200": 7.7, "110201": 6.6, "110210": 6.8, "110211": 5.9, "110220": 5.2, "110221": 3,
+	"111000": 8.9, "111001": 7.8, "111010": 7.6, "111011": 6.7, "111020": 6.2, "111021": 5.8,
+	"111100": 7.4, "111101": 5.9, "111110": 5.7, "111111": 5.7, "111120": 4.7, "111121": 2.3,
+	"111200": 6.1, "111201": 5.2, "111210": 5.7, "111211": 2.9, "111220": 2.4, "111221": 1.6,
+	"112001": 7.1, "112011": 5.9, "112021": 3, "112101": 5.8, "112111": 2.6, "112121": 1.5,
+	"112201": 2.3, "112211": 1.3, "112221": 0.6,
+	"200000": 9.3, "200001": 8.7, "200010": 8.6, "200011": 7.2, "200020": 7.5, "200021": 5.8,
+	"200100": 8.6, "200101": 7.4, "200110": 7.4, "200111": 6.1, "200120": 5.6, "200121": 3.4,
+	"200200": 7, "200201": 5.4, "200210": 5.2, "200211": 4, "200220": 4, "200221": 2.2,
+	"201000": 8.5, "201001": 7.5, "201010": 7.4, "201011": 5.5, "201020": 6.2, "201021": 5.1,
+	"201100": 7.2, "201101": 5.7, "201110": 5.5, "201111": 4.1, "201120": 4.6, "201121": 1.9,
+	"201200": 5.3, "201201": 3.6, "201210": 3.4, "201211": 1.9, "201220": 1.9, "201221": 0.8,
+	"202001": 6.4, "202011": 5.1, "202021": 2, "202101": 4.7, "202111": 2.1, "202121": 1.1,
+	"202201": 2.4, "202211": 0.9, "202221": 0.4,
+	"210000": 8.8, "210001": 7.5, "210010": 7.3, "210011": 5.3, "210020": 6, "210021": 5,
+	"210100": 7.3, "210101": 5.5, "210110": 5.9, "210111": 4, "210120": 4.1, "210121": 2,
+	"210200": 5.4, "210201": 4.3, "210210": 4.5, "210211": 2.2, "210220": 2, "210221": 1.1,
+	"211000": 7.5, "211001": 5.5, "211010": 5.8, "211011": 4.5, "211020": 4, "211021": 2.1,
+	"211100": 6.1, "211101": 5.1, "211110": 4.8, "211111": 1.8, "211120": 2, "211121": 0.9,
+	"211200": 4.6, "211201": 1.8, "211210": 1.7, "211211": 0.7, "211220": 0.8, "211221": 0.2,
+	"212001": 5.3, "212011": 2.4, "212021": 1.4, "212101": 2.4, "212111": 1.2, "212121": 0.5,
+	"212201": 1, "212211": 0.3, "212221": 0.1,
+}
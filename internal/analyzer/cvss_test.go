@@ -0,0 +1,76 @@
+package analyzer
+
+import "testing"
+
+func TestCVSSScore(t *testing.T) {
+	tests := []struct {
+		vector string
+		want   float64
+	}{
+		// CVSS v3.x
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", 9.8},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H", 10.0},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:R/S:C/C:L/I:L/A:N", 6.1},
+		{"CVSS:3.1/AV:L/AC:L/PR:L/UI:N/S:U/C:H/I:H/A:H", 7.8},
+		{"CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:U/C:H/I:N/A:N", 5.9},
+		{"CVSS:3.0/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:H", 7.5},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:N", 0},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/E:P/RL:O", 9.8}, // Temporal metrics are not scored
+
+		// CVSS v4.0
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N", 9.3},
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:H/SI:H/SA:H", 10.0},
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:L/VI:N/VA:N/SC:N/SI:N/SA:N", 6.9},
+		{"CVSS:4.0/AV:L/AC:L/AT:N/PR:L/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N", 8.5},
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:N/VI:N/VA:N/SC:N/SI:N/SA:N", 0},
+	}
+
+	for _, tt := range tests {
+		got, err := CVSSScore(tt.vector)
+		if err != nil {
+			t.Errorf("CVSSScore(%s) error = %v", tt.vector, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("CVSSScore(%s) = %.1f, want %.1f", tt.vector, got, tt.want)
+		}
+	}
+}
+
+func TestCVSSScore_Invalid(t *testing.T) {
+	vectors := []string{
+		"9.8",
+		"CVSS:2.0/AV:N/AC:L/Au:N/C:P/I:P/A:P",
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H",     // Missing A
+		"CVSS:3.1/AV:X/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", // Invalid value
+		"CVSS:3.1/AV:N/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+		"CVSS:4.0/AV:N/AC:L/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N", // Missing AT
+	}
+	for _, v := range vectors {
+		if _, err := CVSSScore(v); err == nil {
+			t.Errorf("CVSSScore(%s) expected an error", v)
+		}
+	}
+}
+
+func TestAssessSeverity(t *testing.T) {
+	v := osvRecord("GHSA-1", "npm", "lodash", osvEvent{Introduced: "0"})
+	v.Severity = []osvSeverity{
+		{Type: "CVSS_V3", Score: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"},
+		{Type: "CVSS_V4", Score: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:L/VI:N/VA:N/SC:N/SI:N/SA:N"},
+	}
+	sev, score, vector := assessSeverity(v, "npm", "lodash")
+	if sev != "MEDIUM" || score != 6.9 || vector != v.Severity[1].Score {
+		t.Errorf("assessSeverity() = %s, %.1f, %s; want the v4.0 vector rated MEDIUM 6.9", sev, score, vector)
+	}
+
+	fallback := osvRecord("GHSA-2", "npm", "lodash", osvEvent{Introduced: "0"})
+	fallback.DatabaseSpecific.Severity = "HIGH"
+	if sev, score, _ := assessSeverity(fallback, "npm", "lodash"); sev != "HIGH" || score != 0 {
+		t.Errorf("assessSeverity() without vectors = %s, %.1f; want HIGH from database_specific", sev, score)
+	}
+
+	if sev, _, _ := assessSeverity(osvVuln{ID: "GHSA-3"}, "npm", "lodash"); sev != "MEDIUM" {
+		t.Errorf("assessSeverity() without any rating = %s, want MEDIUM", sev)
+	}
+}
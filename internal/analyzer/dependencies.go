@@ -6,6 +6,7 @@ package analyzer
 import (
 	"encoding/base64"
 	"encoding/json"
+	"path"
 	"regexp"
 	"sort"
 	"strings"
@@ -17,9 +18,10 @@ import (
 // It captures the essential information about a package dependency
 // regardless of the package manager used.
 type Dependency struct {
-	Name    string `json:"name"`    // Package name (e.g., "react", "github.com/gin-gonic/gin")
-	Version string `json:"version"` // Version constraint (e.g., "^1.0.0", "v1.9.1")
-	Type    string `json:"type"`    // Dependency type: "production", "dev", "peer", "indirect"
+	Name     string `json:"name"`               // Package name (e.g., "react", "github.com/gin-gonic/gin")
+	Version  string `json:"version"`            // Version constraint (e.g., "^1.0.0", "v1.9.1")
+	Type     string `json:"type"`               // Dependency type: "production", "dev", "peer", "optional", "indirect"
+	Resolved string `json:"resolved,omitempty"` // Exact version pinned by a lock file (e.g., "1.2.3")
 }
 
 // DependencyFile represents all dependencies extracted from a single file.
@@ -27,7 +29,7 @@ type Dependency struct {
 // multiple package.json files).
 type DependencyFile struct {
 	Filename     string       `json:"filename"`  // Full path to the file (e.g., "packages/web/package.json")
-	FileType     string       `json:"file_type"` // Package manager type: "npm", "go", "python", "rust", "ruby"
+	FileType     string       `json:"file_type"` // Package manager type: "npm", "go", "python", "rust", "ruby", "maven", ...
 	Dependencies []Dependency `json:"dependencies"`
 	TotalCount   int          `json:"total_count"` // Total number of dependencies in this file
 }
@@ -35,12 +37,25 @@ type DependencyFile struct {
 // DependencyAnalysis holds the complete dependency analysis for a repository.
 // It aggregates information from all dependency files found in the repo.
 type DependencyAnalysis struct {
-	Files       []DependencyFile `json:"files"`         // All parsed dependency files
-	TotalDeps   int              `json:"total_deps"`    // Total dependencies across all files
-	Languages   []string         `json:"languages"`     // Detected package managers/languages
-	HasLockFile bool             `json:"has_lock_file"` // Whether a lock file exists
+	Files          []DependencyFile `json:"files"`                // All parsed dependency files
+	TotalDeps      int              `json:"total_deps"`           // Total dependencies across all files
+	Languages      []string         `json:"languages"`            // Detected package managers/languages
+	HasLockFile    bool             `json:"has_lock_file"`        // Whether a lock file exists
+	LockFiles      []LockFile       `json:"lock_files,omitempty"` // Parsed lock files with the full resolved dependency set
+	ResolvedDeps   int              `json:"resolved_deps"`        // Manifest dependencies with a lock file version
+	TransitiveDeps int              `json:"transitive_deps"`      // Locked packages not declared in any manifest
+	Truncated      bool             `json:"truncated"`            // More lock files exist than were fetched
+	Skipped        int              `json:"skipped"`              // Lock files left out by the file cap
+	Note           string           `json:"note,omitempty"`       // Explains a partial lock file analysis
 }
 
+// maxLockFiles caps how many lock files are fetched; they are often the
+// largest files in a repository
+const (
+	maxLockFiles        = 20
+	maxLockFilesNoToken = 5
+)
+
 // AnalyzeDependencies fetches and parses dependency files from a repository.
 // It supports multiple package managers and handles monorepos with multiple
 // dependency files.
@@ -51,6 +66,16 @@ type DependencyAnalysis struct {
 //   - Python (requirements.txt, Pipfile, pyproject.toml)
 //   - Rust (Cargo.toml)
 //   - Ruby (Gemfile)
+//   - Maven (pom.xml) and Gradle (build.gradle, build.gradle.kts)
+//   - .NET (*.csproj, packages.config)
+//   - PHP (composer.json)
+//   - Dart (pubspec.yaml)
+//   - Swift (Package.swift, Podfile)
+//   - Elixir (mix.exs)
+//
+// Lock files (package-lock.json, yarn.lock, pnpm-lock.yaml, go.sum,
+// Cargo.lock, poetry.lock, Pipfile.lock, Gemfile.lock) are parsed as well
+// to record exact resolved versions and the transitive dependency set.
 //
 // Parameters:
 //   - client: GitHub API client for fetching file contents
@@ -95,10 +120,30 @@ func AnalyzeDependencies(client *github.Client, owner, repo, branch string, file
 			deps, fileType = parseGoMod(decoded)
 		case "python":
 			deps, fileType = parseRequirementsTxt(decoded)
+		case "pyproject":
+			deps, fileType = parsePyprojectToml(decoded)
+		case "pipfile":
+			deps, fileType = parsePipfile(decoded)
 		case "rust":
 			deps, fileType = parseCargoToml(decoded)
 		case "ruby":
 			deps, fileType = parseGemfile(decoded)
+		case "maven":
+			deps, fileType = parsePomXML(decoded)
+		case "gradle":
+			deps, fileType = parseGradle(decoded)
+		case "nuget":
+			deps, fileType = parseNuGet(decoded)
+		case "php":
+			deps, fileType = parseComposerJSON(decoded)
+		case "dart":
+			deps, fileType = parsePubspec(decoded)
+		case "swift":
+			deps, fileType = parsePackageSwift(decoded)
+		case "cocoapods":
+			deps, fileType = parsePodfile(decoded)
+		case "elixir":
+			deps, fileType = parseMixExs(decoded)
 		}
 
 		if len(deps) > 0 {
@@ -120,6 +165,34 @@ func AnalyzeDependencies(client *github.Client, owner, repo, branch string, file
 	// Check for lock files (indicates reproducible builds)
 	analysis.HasLockFile = hasLockFile(fileTree)
 
+	lockFiles := findLockFiles(fileTree)
+	if limit := fileLimit(client, maxLockFiles, maxLockFilesNoToken); len(lockFiles) > limit {
+		// Keep the lock files closest to the root, which pin the main project
+		sort.SliceStable(lockFiles, func(i, j int) bool {
+			return strings.Count(lockFiles[i].Filename, "/") < strings.Count(lockFiles[j].Filename, "/")
+		})
+		analysis.Truncated = true
+		analysis.Skipped = len(lockFiles) - limit
+		analysis.Note = skippedNote(client, analysis.Skipped, "lock files")
+		lockFiles = lockFiles[:limit]
+	}
+	for _, lf := range lockFiles {
+		content, err := client.GetFileContent(owner, repo, lf.Filename)
+		if err != nil {
+			continue // Large lock files may exceed the contents API size limit
+		}
+		decoded, err := base64.StdEncoding.DecodeString(content)
+		if err != nil {
+			continue
+		}
+
+		lf.Packages = parseLockFile(lf.Format, decoded)
+		if len(lf.Packages) > 0 {
+			analysis.LockFiles = append(analysis.LockFiles, lf)
+		}
+	}
+	linkLockFiles(analysis)
+
 	return analysis, nil
 }
 
@@ -139,10 +212,26 @@ func findDependencyFiles(tree []github.TreeEntry) []depFileInfo {
 		"package.json":     "npm",
 		"go.mod":           "go",
 		"requirements.txt": "python",
-		"Pipfile":          "python",
-		"pyproject.toml":   "python",
+		"Pipfile":          "pipfile",
+		"pyproject.toml":   "pyproject",
 		"Cargo.toml":       "rust",
 		"Gemfile":          "ruby",
+		"pom.xml":          "maven",
+		"build.gradle":     "gradle",
+		"build.gradle.kts": "gradle",
+		"packages.config":  "nuget",
+		"composer.json":    "php",
+		"pubspec.yaml":     "dart",
+		"Package.swift":    "swift",
+		"Podfile":          "cocoapods",
+		"mix.exs":          "elixir",
+	}
+
+	// Project files matched by extension rather than exact name
+	depFileExtensions := map[string]string{
+		".csproj": "nuget",
+		".fsproj": "nuget",
+		".vbproj": "nuget",
 	}
 
 	for _, entry := range tree {
@@ -160,24 +249,57 @@ func findDependencyFiles(tree []github.TreeEntry) []depFileInfo {
 				path:     entry.Path,
 				fileType: fileType,
 			})
+			continue
+		}
+
+		if fileType, ok := depFileExtensions[path.Ext(filename)]; ok {
+			files = append(files, depFileInfo{
+				path:     entry.Path,
+				fileType: fileType,
+			})
 		}
 	}
 
 	return files
 }
 
+// findLockFiles scans the file tree for lock files that can be parsed.
+func findLockFiles(tree []github.TreeEntry) []LockFile {
+	var files []LockFile
+	for _, entry := range tree {
+		if entry.Type != "blob" {
+			continue
+		}
+		if f, ok := lockFileFormats[path.Base(entry.Path)]; ok {
+			files = append(files, LockFile{
+				Filename: entry.Path,
+				FileType: f.fileType,
+				Format:   f.format,
+			})
+		}
+	}
+	return files
+}
+
 // hasLockFile checks if the repository contains any lock files.
 // Lock files indicate that the project uses reproducible dependency resolution.
 func hasLockFile(tree []github.TreeEntry) bool {
 	lockFiles := []string{
-		"package-lock.json", // npm
-		"yarn.lock",         // Yarn
-		"pnpm-lock.yaml",    // pnpm
-		"go.sum",            // Go modules
-		"Pipfile.lock",      // Pipenv
-		"poetry.lock",       // Poetry
-		"Cargo.lock",        // Cargo (Rust)
-		"Gemfile.lock",      // Bundler (Ruby)
+		"package-lock.json",  // npm
+		"yarn.lock",          // Yarn
+		"pnpm-lock.yaml",     // pnpm
+		"go.sum",             // Go modules
+		"Pipfile.lock",       // Pipenv
+		"poetry.lock",        // Poetry
+		"Cargo.lock",         // Cargo (Rust)
+		"Gemfile.lock",       // Bundler (Ruby)
+		"gradle.lockfile",    // Gradle
+		"packages.lock.json", // NuGet
+		"composer.lock",      // Composer (PHP)
+		"pubspec.lock",       // Pub (Dart)
+		"Package.resolved",   // Swift Package Manager
+		"Podfile.lock",       // CocoaPods
+		"mix.lock",           // Mix (Elixir)
 	}
 
 	for _, entry := range tree {
@@ -0,0 +1,339 @@
+// Package analyzer provides analysis functions for GitHub repositories.
+// This file builds a dependency graph from parsed lock files and answers
+// questions such as "why is this package here?".
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GraphNode is a single package at a specific version in the dependency graph.
+type GraphNode struct {
+	ID           string   `json:"id"` // "fileType:name@version", see graphNodeID
+	Name         string   `json:"name"`
+	Version      string   `json:"version"`
+	FileType     string   `json:"file_type"` // Package manager type: "npm", "go", "python", ...
+	Direct       bool     `json:"direct"`
+	Dev          bool     `json:"dev,omitempty"`
+	Depth        int      `json:"depth"`                  // Shortest distance from the project (1 = direct, 0 = unknown)
+	Dependencies []string `json:"dependencies,omitempty"` // IDs of the packages this one depends on
+	Dependents   []string `json:"dependents,omitempty"`   // IDs of the packages depending on this one
+}
+
+// DuplicatePackage is a package resolved at more than one version.
+type DuplicatePackage struct {
+	Name     string   `json:"name"`
+	Versions []string `json:"versions"`
+}
+
+// DependencyGraph is the resolved package graph of a repository.
+// Nodes are sorted by ID; edges are stored on the nodes themselves.
+type DependencyGraph struct {
+	Nodes []*GraphNode `json:"nodes"`
+
+	index map[string]*GraphNode
+}
+
+// BuildDependencyGraph builds the dependency graph from the lock files of a
+// dependency analysis. Lock files without edge information (go.sum,
+// Pipfile.lock) still contribute nodes, but their transitive packages have
+// an unknown depth.
+func BuildDependencyGraph(deps *DependencyAnalysis) *DependencyGraph {
+	g := &DependencyGraph{index: make(map[string]*GraphNode)}
+	if deps == nil {
+		return g
+	}
+
+	for _, lf := range deps.LockFiles {
+		for _, p := range lf.Packages {
+			id := graphNodeID(lf.FileType, p.Name, p.Version)
+			node, ok := g.index[id]
+			if !ok {
+				node = &GraphNode{ID: id, Name: p.Name, Version: p.Version, FileType: lf.FileType, Dev: p.Dev}
+				g.index[id] = node
+				g.Nodes = append(g.Nodes, node)
+			}
+			node.Direct = node.Direct || p.Direct
+			node.Dev = node.Dev && p.Dev
+		}
+	}
+
+	for _, lf := range deps.LockFiles {
+		for _, p := range lf.Packages {
+			from := g.index[graphNodeID(lf.FileType, p.Name, p.Version)]
+			for _, ref := range p.Dependencies {
+				to, ok := g.index[graphNodeID(lf.FileType, ref.Name, ref.Version)]
+				if !ok || to == from || contains(from.Dependencies, to.ID) {
+					continue
+				}
+				from.Dependencies = append(from.Dependencies, to.ID)
+				to.Dependents = append(to.Dependents, from.ID)
+			}
+		}
+	}
+
+	sort.Slice(g.Nodes, func(i, j int) bool { return g.Nodes[i].ID < g.Nodes[j].ID })
+	for _, n := range g.Nodes {
+		sort.Strings(n.Dependencies)
+		sort.Strings(n.Dependents)
+	}
+	g.computeDepths()
+	return g
+}
+
+// graphNodeID identifies a package version within one package manager, so
+// that same-named packages of different ecosystems stay separate nodes
+func graphNodeID(fileType, name, version string) string {
+	return fileType + ":" + name + "@" + version
+}
+
+// Label returns the node's "name@version" for display
+func (n *GraphNode) Label() string {
+	return n.Name + "@" + n.Version
+}
+
+// Ecosystem returns the OSV ecosystem of the node's package manager
+func (n *GraphNode) Ecosystem() string {
+	return mapEcosystem(n.FileType)
+}
+
+// computeDepths assigns each node its shortest distance from a direct dependency
+func (g *DependencyGraph) computeDepths() {
+	var queue []*GraphNode
+	for _, n := range g.Nodes {
+		n.Depth = 0
+		if n.Direct {
+			n.Depth = 1
+			queue = append(queue, n)
+		}
+	}
+
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		for _, id := range n.Dependencies {
+			child := g.Node(id)
+			if child != nil && child.Depth == 0 {
+				child.Depth = n.Depth + 1
+				queue = append(queue, child)
+			}
+		}
+	}
+}
+
+// Node returns the node with the given "fileType:name@version" ID, or nil.
+func (g *DependencyGraph) Node(id string) *GraphNode {
+	if g == nil {
+		return nil
+	}
+	// The index is not serialized; rebuild it after loading from cache
+	if g.index == nil {
+		g.index = make(map[string]*GraphNode, len(g.Nodes))
+		for _, n := range g.Nodes {
+			g.index[n.ID] = n
+		}
+	}
+	return g.index[id]
+}
+
+// Roots returns the direct dependencies, sorted by ID.
+func (g *DependencyGraph) Roots() []*GraphNode {
+	var roots []*GraphNode
+	if g == nil {
+		return roots
+	}
+	for _, n := range g.Nodes {
+		if n.Direct {
+			roots = append(roots, n)
+		}
+	}
+	return roots
+}
+
+// Depth returns the shortest dependency depth of a package (1 = direct),
+// or 0 when the package is unknown or not reachable from a direct dependency.
+func (g *DependencyGraph) Depth(id string) int {
+	if n := g.Node(id); n != nil {
+		return n.Depth
+	}
+	return 0
+}
+
+// MaxDepth returns the length of the longest shortest-path in the graph.
+func (g *DependencyGraph) MaxDepth() int {
+	max := 0
+	if g == nil {
+		return max
+	}
+	for _, n := range g.Nodes {
+		if n.Depth > max {
+			max = n.Depth
+		}
+	}
+	return max
+}
+
+// TransitiveCount returns the number of packages that are not direct dependencies.
+func (g *DependencyGraph) TransitiveCount() int {
+	count := 0
+	if g == nil {
+		return count
+	}
+	for _, n := range g.Nodes {
+		if !n.Direct {
+			count++
+		}
+	}
+	return count
+}
+
+// Duplicates returns packages resolved at more than one version, sorted by name.
+func (g *DependencyGraph) Duplicates() []DuplicatePackage {
+	var dups []DuplicatePackage
+	if g == nil {
+		return dups
+	}
+
+	versions := make(map[string][]string)
+	var names []string
+	for _, n := range g.Nodes {
+		key := n.FileType + "|" + n.Name
+		if _, ok := versions[key]; !ok {
+			names = append(names, key)
+		}
+		versions[key] = append(versions[key], n.Version)
+	}
+	sort.Strings(names)
+
+	for _, key := range names {
+		if v := versions[key]; len(v) > 1 {
+			sort.Slice(v, func(i, j int) bool { return compareLooseVersions(v[i], v[j]) < 0 })
+			dups = append(dups, DuplicatePackage{Name: key[strings.Index(key, "|")+1:], Versions: v})
+		}
+	}
+	return dups
+}
+
+// PathsTo returns dependency paths from a direct dependency to the given
+// package, shortest first. Each path is a list of node IDs starting with the
+// direct dependency and ending with the target. An empty version matches any
+// version of the package. At most max paths are returned.
+//
+// Example: PathsTo("minimist", "", 3) might return
+//
+//	[["npm:mkdirp@0.5.1", "npm:minimist@0.0.8"]]
+func (g *DependencyGraph) PathsTo(name, version string, max int) [][]string {
+	var paths [][]string
+	if g == nil {
+		return paths
+	}
+
+	for _, target := range g.Nodes {
+		if target.Name != name || (version != "" && target.Version != version) {
+			continue
+		}
+		// Breadth-first search over reverse edges so shorter paths come first
+		queue := [][]string{{target.ID}}
+		for len(queue) > 0 && len(paths) < max {
+			path := queue[0]
+			queue = queue[1:]
+			head := g.Node(path[0])
+			if head.Direct {
+				paths = append(paths, path)
+				continue
+			}
+			for _, parentID := range head.Dependents {
+				if contains(path, parentID) {
+					continue // Cycle
+				}
+				queue = append(queue, append([]string{parentID}, path...))
+			}
+			// Bound the search on dense graphs
+			if len(queue) > 10000 {
+				break
+			}
+		}
+	}
+
+	sort.SliceStable(paths, func(i, j int) bool { return len(paths[i]) < len(paths[j]) })
+	if len(paths) > max {
+		paths = paths[:max]
+	}
+	return paths
+}
+
+// graphEdge is a single dependency edge in the JSON export
+type graphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// JSON returns the graph as indented JSON with nodes, edges and summary
+// statistics.
+func (g *DependencyGraph) JSON() ([]byte, error) {
+	out := struct {
+		Nodes      []*GraphNode       `json:"nodes"`
+		Edges      []graphEdge        `json:"edges"`
+		Total      int                `json:"total_packages"`
+		Direct     int                `json:"direct"`
+		Transitive int                `json:"transitive"`
+		MaxDepth   int                `json:"max_depth"`
+		Duplicates []DuplicatePackage `json:"duplicates"`
+	}{
+		Nodes:      g.Nodes,
+		Edges:      []graphEdge{},
+		Total:      len(g.Nodes),
+		Transitive: g.TransitiveCount(),
+		MaxDepth:   g.MaxDepth(),
+		Duplicates: g.Duplicates(),
+	}
+	out.Direct = out.Total - out.Transitive
+	if out.Nodes == nil {
+		out.Nodes = []*GraphNode{}
+	}
+	for _, n := range g.Nodes {
+		for _, id := range n.Dependencies {
+			out.Edges = append(out.Edges, graphEdge{From: n.ID, To: id})
+		}
+	}
+	return json.MarshalIndent(out, "", "  ")
+}
+
+// DOT returns the graph in Graphviz DOT format. Direct dependencies are
+// drawn as boxes connected to a root node named after the project.
+func (g *DependencyGraph) DOT(project string) string {
+	var sb strings.Builder
+	sb.WriteString("digraph dependencies {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=ellipse, fontsize=10];\n")
+	fmt.Fprintf(&sb, "  %q [shape=doubleoctagon];\n", project)
+
+	for _, n := range g.Nodes {
+		attrs := ""
+		if n.Direct {
+			attrs = " [shape=box]"
+		}
+		if n.Dev {
+			attrs = strings.TrimSuffix(attrs, "]")
+			if attrs == "" {
+				attrs = " [style=dashed]"
+			} else {
+				attrs += ", style=dashed]"
+			}
+		}
+		fmt.Fprintf(&sb, "  %q%s;\n", n.ID, attrs)
+	}
+	for _, n := range g.Roots() {
+		fmt.Fprintf(&sb, "  %q -> %q;\n", project, n.ID)
+	}
+	for _, n := range g.Nodes {
+		for _, id := range n.Dependencies {
+			fmt.Fprintf(&sb, "  %q -> %q;\n", n.ID, id)
+		}
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
@@ -0,0 +1,153 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// sampleGraphAnalysis models app -> express -> debug@2.6.9 -> ms@2.0.0 and
+// app -> mocha -> debug@4.3.4 -> ms@2.1.2
+func sampleGraphAnalysis() *DependencyAnalysis {
+	return &DependencyAnalysis{
+		LockFiles: []LockFile{{
+			Filename: "package-lock.json",
+			FileType: "npm",
+			Packages: []LockedPackage{
+				{Name: "express", Version: "4.18.2", Direct: true, Dependencies: []PackageRef{{"debug", "2.6.9"}}},
+				{Name: "mocha", Version: "10.2.0", Direct: true, Dev: true, Dependencies: []PackageRef{{"debug", "4.3.4"}}},
+				{Name: "debug", Version: "2.6.9", Dependencies: []PackageRef{{"ms", "2.0.0"}}},
+				{Name: "debug", Version: "4.3.4", Dev: true, Dependencies: []PackageRef{{"ms", "2.1.2"}}},
+				{Name: "ms", Version: "2.0.0"},
+				{Name: "ms", Version: "2.1.2", Dev: true},
+			},
+		}},
+	}
+}
+
+func TestBuildDependencyGraph(t *testing.T) {
+	g := BuildDependencyGraph(sampleGraphAnalysis())
+
+	if len(g.Nodes) != 6 {
+		t.Fatalf("len(Nodes) = %d, want 6", len(g.Nodes))
+	}
+	if got := len(g.Roots()); got != 2 {
+		t.Errorf("len(Roots) = %d, want 2", got)
+	}
+	if got := g.TransitiveCount(); got != 4 {
+		t.Errorf("TransitiveCount = %d, want 4", got)
+	}
+	if got := g.Depth("npm:ms@2.0.0"); got != 3 {
+		t.Errorf("Depth(ms@2.0.0) = %d, want 3", got)
+	}
+	if got := g.MaxDepth(); got != 3 {
+		t.Errorf("MaxDepth = %d, want 3", got)
+	}
+	if deps := g.Node("npm:debug@2.6.9").Dependents; len(deps) != 1 || deps[0] != "npm:express@4.18.2" {
+		t.Errorf("debug@2.6.9 dependents = %v, want [npm:express@4.18.2]", deps)
+	}
+
+	dups := g.Duplicates()
+	if len(dups) != 2 || dups[0].Name != "debug" || strings.Join(dups[0].Versions, ",") != "2.6.9,4.3.4" {
+		t.Errorf("Duplicates = %+v, want debug and ms with two versions each", dups)
+	}
+}
+
+func TestDependencyGraph_PathsTo(t *testing.T) {
+	g := BuildDependencyGraph(sampleGraphAnalysis())
+
+	paths := g.PathsTo("ms", "", 5)
+	if len(paths) != 2 {
+		t.Fatalf("len(PathsTo(ms)) = %d, want 2: %v", len(paths), paths)
+	}
+	want := "npm:express@4.18.2 > npm:debug@2.6.9 > npm:ms@2.0.0"
+	if got := strings.Join(paths[0], " > "); got != want && strings.Join(paths[1], " > ") != want {
+		t.Errorf("paths = %v, want one of them to be %s", paths, want)
+	}
+
+	if paths := g.PathsTo("ms", "2.1.2", 5); len(paths) != 1 || paths[0][0] != "npm:mocha@10.2.0" {
+		t.Errorf("PathsTo(ms@2.1.2) = %v, want a single path from mocha", paths)
+	}
+	if paths := g.PathsTo("missing", "", 5); len(paths) != 0 {
+		t.Errorf("PathsTo(missing) = %v, want none", paths)
+	}
+}
+
+func TestDependencyGraph_Cycle(t *testing.T) {
+	g := BuildDependencyGraph(&DependencyAnalysis{LockFiles: []LockFile{{
+		FileType: "npm",
+		Packages: []LockedPackage{
+			{Name: "a", Version: "1.0.0", Direct: true, Dependencies: []PackageRef{{"b", "1.0.0"}}},
+			{Name: "b", Version: "1.0.0", Dependencies: []PackageRef{{"c", "1.0.0"}}},
+			{Name: "c", Version: "1.0.0", Dependencies: []PackageRef{{"b", "1.0.0"}}},
+		},
+	}}})
+
+	if paths := g.PathsTo("c", "", 5); len(paths) != 1 || len(paths[0]) != 3 {
+		t.Errorf("PathsTo(c) = %v, want [[a b c]]", paths)
+	}
+}
+
+func TestDependencyGraph_Exports(t *testing.T) {
+	g := BuildDependencyGraph(sampleGraphAnalysis())
+
+	dot := g.DOT("owner/app")
+	for _, want := range []string{
+		"digraph dependencies {",
+		`"owner/app" -> "npm:express@4.18.2";`,
+		`"npm:debug@2.6.9" -> "npm:ms@2.0.0";`,
+		`"npm:mocha@10.2.0" [shape=box, style=dashed];`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("DOT output missing %q:\n%s", want, dot)
+		}
+	}
+
+	data, err := g.JSON()
+	if err != nil {
+		t.Fatalf("JSON() error = %v", err)
+	}
+	var out struct {
+		Edges      []graphEdge `json:"edges"`
+		Transitive int         `json:"transitive"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(out.Edges) != 4 || out.Transitive != 4 {
+		t.Errorf("edges = %d, transitive = %d, want 4 and 4", len(out.Edges), out.Transitive)
+	}
+}
+
+func TestDependencyGraph_SeparatesEcosystems(t *testing.T) {
+	g := BuildDependencyGraph(&DependencyAnalysis{LockFiles: []LockFile{
+		{FileType: "npm", Packages: []LockedPackage{
+			{Name: "six", Version: "1.0.0", Direct: true, Dependencies: []PackageRef{{"left", "1.0.0"}}},
+			{Name: "left", Version: "1.0.0"},
+		}},
+		{FileType: "python", Packages: []LockedPackage{
+			{Name: "six", Version: "1.0.0", Direct: true},
+		}},
+	}})
+
+	if len(g.Nodes) != 3 {
+		t.Fatalf("len(Nodes) = %d, want npm and PyPI six kept apart", len(g.Nodes))
+	}
+	if py := g.Node("python:six@1.0.0"); py == nil || len(py.Dependencies) != 0 || py.Label() != "six@1.0.0" {
+		t.Errorf("python six = %+v, want no dependencies", py)
+	}
+	if npm := g.Node("npm:six@1.0.0"); npm == nil || len(npm.Dependencies) != 1 {
+		t.Errorf("npm six = %+v, want one dependency", npm)
+	}
+}
+
+func TestDependencyGraph_IndexRebuiltAfterUnmarshal(t *testing.T) {
+	data, _ := json.Marshal(BuildDependencyGraph(sampleGraphAnalysis()))
+	var g DependencyGraph
+	if err := json.Unmarshal(data, &g); err != nil {
+		t.Fatal(err)
+	}
+	if g.Node("npm:express@4.18.2") == nil {
+		t.Error("Node lookup should work on a graph loaded from JSON")
+	}
+}
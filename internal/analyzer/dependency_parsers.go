@@ -0,0 +1,538 @@
+// Package analyzer provides analysis functions for GitHub repositories.
+// This file contains dependency parsers for the JVM, .NET, PHP, Dart, Swift
+// and Elixir ecosystems.
+package analyzer
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// parsePomXML parses a Maven pom.xml file.
+// Dependency names use the "groupId:artifactId" form expected by OSV, and
+// ${property} placeholders are resolved from the <properties> section.
+//
+// Example pom.xml:
+//
+//	<dependency>
+//	  <groupId>org.springframework</groupId>
+//	  <artifactId>spring-core</artifactId>
+//	  <version>${spring.version}</version>
+//	  <scope>test</scope>
+//	</dependency>
+func parsePomXML(content []byte) ([]Dependency, string) {
+	type pomDependency struct {
+		GroupID    string `xml:"groupId"`
+		ArtifactID string `xml:"artifactId"`
+		Version    string `xml:"version"`
+		Scope      string `xml:"scope"`
+	}
+	var pom struct {
+		Version string `xml:"version"`
+		Parent  struct {
+			Version string `xml:"version"`
+		} `xml:"parent"`
+		Properties struct {
+			Entries []struct {
+				XMLName xml.Name
+				Value   string `xml:",chardata"`
+			} `xml:",any"`
+		} `xml:"properties"`
+		Dependencies []pomDependency `xml:"dependencies>dependency"`
+		Managed      []pomDependency `xml:"dependencyManagement>dependencies>dependency"`
+	}
+
+	if err := xml.Unmarshal(content, &pom); err != nil {
+		return nil, "maven"
+	}
+
+	props := map[string]string{
+		"project.version": pom.Version,
+		"version":         pom.Version,
+	}
+	if pom.Version == "" {
+		props["project.version"] = pom.Parent.Version
+		props["version"] = pom.Parent.Version
+	}
+	for _, p := range pom.Properties.Entries {
+		props[p.XMLName.Local] = strings.TrimSpace(p.Value)
+	}
+
+	// Versions declared in dependencyManagement apply to bare dependencies
+	managed := make(map[string]string)
+	for _, d := range pom.Managed {
+		managed[d.GroupID+":"+d.ArtifactID] = resolveMavenProperty(d.Version, props)
+	}
+
+	var deps []Dependency
+	for _, d := range pom.Dependencies {
+		if d.GroupID == "" || d.ArtifactID == "" {
+			continue
+		}
+		name := resolveMavenProperty(d.GroupID, props) + ":" + resolveMavenProperty(d.ArtifactID, props)
+
+		version := resolveMavenProperty(d.Version, props)
+		if version == "" {
+			version = managed[name]
+		}
+		if version == "" {
+			version = "*"
+		}
+
+		depType := "production"
+		if d.Scope == "test" {
+			depType = "dev"
+		}
+
+		deps = append(deps, Dependency{
+			Name:    name,
+			Version: version,
+			Type:    depType,
+		})
+	}
+
+	return deps, "maven"
+}
+
+// mavenPropertyPattern matches ${property} placeholders
+var mavenPropertyPattern = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// resolveMavenProperty substitutes ${property} placeholders in a pom value
+func resolveMavenProperty(value string, props map[string]string) string {
+	value = strings.TrimSpace(value)
+	return mavenPropertyPattern.ReplaceAllStringFunc(value, func(m string) string {
+		key := m[2 : len(m)-1]
+		if v, ok := props[key]; ok && v != "" {
+			return v
+		}
+		return m
+	})
+}
+
+// gradleStringPattern matches `configuration "group:name:version"` in Groovy
+// and `configuration("group:name:version")` in the Kotlin DSL.
+var gradleStringPattern = regexp.MustCompile(
+	`^\s*([A-Za-z]+)\s*\(?\s*(?:platform\s*\(\s*)?['"]([^'":\s]+):([^'":\s]+)(?::([^'"@\s]+))?(?:@[^'"]*)?['"]`)
+
+// gradleMapPattern matches `configuration group: 'g', name: 'n', version: 'v'`
+var gradleMapPattern = regexp.MustCompile(
+	`^\s*([A-Za-z]+)\s*\(?\s*group\s*[:=]\s*['"]([^'"]+)['"]\s*,\s*name\s*[:=]\s*['"]([^'"]+)['"](?:\s*,\s*version\s*[:=]\s*['"]([^'"]+)['"])?`)
+
+// gradleConfigurations lists dependency configurations that declare packages
+var gradleConfigurations = map[string]string{
+	"implementation":            "production",
+	"api":                       "production",
+	"compile":                   "production",
+	"runtimeOnly":               "production",
+	"runtime":                   "production",
+	"compileOnly":               "peer",
+	"annotationProcessor":       "dev",
+	"kapt":                      "dev",
+	"ksp":                       "dev",
+	"testImplementation":        "dev",
+	"testCompile":               "dev",
+	"testRuntimeOnly":           "dev",
+	"testCompileOnly":           "dev",
+	"androidTestImplementation": "dev",
+	"debugImplementation":       "dev",
+	"classpath":                 "dev",
+}
+
+// parseGradle parses a Gradle build.gradle or build.gradle.kts file.
+// It recognises string and map notation for common configurations.
+//
+// Example build.gradle:
+//
+//	dependencies {
+//	    implementation 'com.google.guava:guava:32.1.2-jre'
+//	    testImplementation("junit:junit:4.13.2")
+//	}
+func parseGradle(content []byte) ([]Dependency, string) {
+	var deps []Dependency
+
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "//") {
+			continue
+		}
+
+		var config, group, name, version string
+		if m := gradleStringPattern.FindStringSubmatch(line); m != nil {
+			config, group, name, version = m[1], m[2], m[3], m[4]
+		} else if m := gradleMapPattern.FindStringSubmatch(line); m != nil {
+			config, group, name, version = m[1], m[2], m[3], m[4]
+		} else {
+			continue
+		}
+
+		depType, ok := gradleConfigurations[config]
+		if !ok {
+			continue
+		}
+		// $var and ${var} versions come from properties or version catalogs
+		// this parser cannot see, so leave them unresolved
+		if version == "" || strings.Contains(version, "$") {
+			version = "*"
+		}
+
+		deps = append(deps, Dependency{
+			Name:    group + ":" + name,
+			Version: version,
+			Type:    depType,
+		})
+	}
+
+	return deps, "gradle"
+}
+
+// parseNuGet parses .NET project files (*.csproj and friends) using
+// <PackageReference> as well as legacy packages.config files.
+//
+// Example .csproj:
+//
+//	<ItemGroup>
+//	  <PackageReference Include="Newtonsoft.Json" Version="13.0.3" />
+//	</ItemGroup>
+//
+// Example packages.config:
+//
+//	<packages>
+//	  <package id="NUnit" version="3.13.3" developmentDependency="true" />
+//	</packages>
+func parseNuGet(content []byte) ([]Dependency, string) {
+	var doc struct {
+		XMLName    xml.Name
+		ItemGroups []struct {
+			References []struct {
+				Include       string `xml:"Include,attr"`
+				Update        string `xml:"Update,attr"`
+				Version       string `xml:"Version,attr"`
+				VersionElem   string `xml:"Version"`
+				PrivateAssets string `xml:"PrivateAssets,attr"`
+			} `xml:"PackageReference"`
+		} `xml:"ItemGroup"`
+		Packages []struct {
+			ID          string `xml:"id,attr"`
+			Version     string `xml:"version,attr"`
+			Development string `xml:"developmentDependency,attr"`
+		} `xml:"package"`
+	}
+
+	if err := xml.Unmarshal(content, &doc); err != nil {
+		return nil, "nuget"
+	}
+
+	var deps []Dependency
+
+	// SDK-style project files
+	for _, group := range doc.ItemGroups {
+		for _, ref := range group.References {
+			name := ref.Include
+			if name == "" {
+				name = ref.Update
+			}
+			if name == "" {
+				continue
+			}
+			version := ref.Version
+			if version == "" {
+				version = strings.TrimSpace(ref.VersionElem)
+			}
+			if version == "" {
+				version = "*"
+			}
+			depType := "production"
+			if strings.EqualFold(ref.PrivateAssets, "all") {
+				depType = "dev"
+			}
+			deps = append(deps, Dependency{Name: name, Version: version, Type: depType})
+		}
+	}
+
+	// Legacy packages.config
+	for _, pkg := range doc.Packages {
+		if pkg.ID == "" {
+			continue
+		}
+		version := pkg.Version
+		if version == "" {
+			version = "*"
+		}
+		depType := "production"
+		if pkg.Development == "true" {
+			depType = "dev"
+		}
+		deps = append(deps, Dependency{Name: pkg.ID, Version: version, Type: depType})
+	}
+
+	return deps, "nuget"
+}
+
+// parseComposerJSON parses a PHP composer.json file.
+// Platform requirements such as "php" and "ext-*" are skipped.
+//
+// Example composer.json:
+//
+//	{
+//	  "require": { "laravel/framework": "^10.0" },
+//	  "require-dev": { "phpunit/phpunit": "^10.1" }
+//	}
+func parseComposerJSON(content []byte) ([]Dependency, string) {
+	var composer struct {
+		Require    map[string]string `json:"require"`
+		RequireDev map[string]string `json:"require-dev"`
+	}
+
+	if err := json.Unmarshal(content, &composer); err != nil {
+		return nil, "php"
+	}
+
+	var deps []Dependency
+	add := func(reqs map[string]string, depType string) {
+		for name, version := range reqs {
+			if isComposerPlatformPackage(name) {
+				continue
+			}
+			deps = append(deps, Dependency{
+				Name:    name,
+				Version: cleanVersion(version),
+				Type:    depType,
+			})
+		}
+	}
+	add(composer.Require, "production")
+	add(composer.RequireDev, "dev")
+
+	sort.Slice(deps, func(i, j int) bool {
+		return deps[i].Name < deps[j].Name
+	})
+
+	return deps, "php"
+}
+
+// isComposerPlatformPackage reports whether a requirement targets the PHP
+// runtime or an extension rather than a Packagist package.
+func isComposerPlatformPackage(name string) bool {
+	return name == "php" || name == "hhvm" || name == "composer-plugin-api" ||
+		strings.HasPrefix(name, "ext-") || strings.HasPrefix(name, "lib-") ||
+		!strings.Contains(name, "/")
+}
+
+// parsePubspec parses a Dart/Flutter pubspec.yaml file.
+// SDK dependencies (e.g. flutter) are skipped; git and path dependencies
+// are reported with version "*".
+//
+// Example pubspec.yaml:
+//
+//	dependencies:
+//	  http: ^1.1.0
+//	  flutter:
+//	    sdk: flutter
+//	dev_dependencies:
+//	  test: ^1.24.0
+func parsePubspec(content []byte) ([]Dependency, string) {
+	var deps []Dependency
+
+	doc, err := parseYAML(content)
+	if err != nil {
+		return deps, "dart"
+	}
+	root := yamlMap(doc)
+
+	for _, section := range []struct{ key, depType string }{
+		{"dependencies", "production"},
+		{"dev_dependencies", "dev"},
+	} {
+		packages := yamlMap(root, section.key)
+		names := make([]string, 0, len(packages))
+		for name := range packages {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			version := "*"
+			switch value := packages[name].(type) {
+			case string:
+				if value != "" {
+					version = value
+				}
+			case map[string]interface{}:
+				if _, ok := value["sdk"]; ok {
+					continue
+				}
+				if v := yamlString(value, "version"); v != "" {
+					version = v
+				}
+			}
+			deps = append(deps, Dependency{Name: name, Version: version, Type: section.depType})
+		}
+	}
+
+	return deps, "dart"
+}
+
+// swiftPackagePattern matches .package(url: "...", <requirement>) declarations
+var swiftPackagePattern = regexp.MustCompile(`\.package\s*\(\s*(?:name:\s*"[^"]*"\s*,\s*)?url:\s*"([^"]+)"\s*,?\s*([^)]*\)?)`)
+
+// swiftVersionPattern extracts the first quoted version in a requirement
+var swiftVersionPattern = regexp.MustCompile(`"([0-9][^"]*)"`)
+
+// parsePackageSwift parses a Swift Package Manager Package.swift manifest.
+// Package names are normalized to the host/owner/repo form used by OSV's
+// SwiftURL ecosystem.
+//
+// Example Package.swift:
+//
+//	dependencies: [
+//	    .package(url: "https://github.com/apple/swift-nio.git", from: "2.58.0"),
+//	]
+func parsePackageSwift(content []byte) ([]Dependency, string) {
+	var deps []Dependency
+
+	for _, m := range swiftPackagePattern.FindAllStringSubmatch(string(content), -1) {
+		name := normalizeSwiftURL(m[1])
+		version := "*"
+		if v := swiftVersionPattern.FindStringSubmatch(m[2]); v != nil {
+			version = v[1]
+			if strings.Contains(m[2], "from:") || strings.Contains(m[2], "upToNextMajor") {
+				version = "^" + version
+			} else if strings.Contains(m[2], "upToNextMinor") {
+				version = "~" + version
+			}
+		}
+		deps = append(deps, Dependency{Name: name, Version: version, Type: "production"})
+	}
+
+	return deps, "swift"
+}
+
+// normalizeSwiftURL strips the scheme and .git suffix from a package URL
+func normalizeSwiftURL(url string) string {
+	url = strings.TrimPrefix(url, "https://")
+	url = strings.TrimPrefix(url, "http://")
+	url = strings.TrimPrefix(url, "git@")
+	url = strings.Replace(url, ":", "/", 1)
+	return strings.TrimSuffix(url, ".git")
+}
+
+// podPattern matches: pod 'Name' or pod 'Name', '~> 1.0'
+var podPattern = regexp.MustCompile(`^pod\s+['"]([^'"]+)['"](?:\s*,\s*['"]([^'"]+)['"])?`)
+
+// podBlockPattern matches Ruby lines that open a block closed by `end`,
+// such as `def shared_pods`, `post_install do |installer|` or `if cond`
+var podBlockPattern = regexp.MustCompile(`^(?:def|if|unless|case|begin|while|until)\b|\bdo\s*(?:\|[^|]*\|)?$`)
+
+// parsePodfile parses a CocoaPods Podfile.
+// Pods declared inside a test target are reported as dev dependencies.
+// Other Ruby blocks (def, do, if) are tracked so that their `end` does not
+// close the enclosing target.
+//
+// Example Podfile:
+//
+//	target 'App' do
+//	  pod 'Alamofire', '~> 5.8'
+//	  target 'AppTests' do
+//	    pod 'Quick'
+//	  end
+//	end
+func parsePodfile(content []byte) ([]Dependency, string) {
+	var deps []Dependency
+	var targets []bool // stack of "is test target" flags, one per open block
+
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "target ") || strings.HasPrefix(line, "abstract_target ") {
+			inTest := strings.Contains(strings.ToLower(line), "test")
+			if len(targets) > 0 && targets[len(targets)-1] {
+				inTest = true
+			}
+			targets = append(targets, inTest)
+			continue
+		}
+		if podBlockPattern.MatchString(line) {
+			// Non-target blocks inherit the enclosing target's flag
+			inTest := len(targets) > 0 && targets[len(targets)-1]
+			targets = append(targets, inTest)
+			continue
+		}
+		if line == "end" {
+			if len(targets) > 0 {
+				targets = targets[:len(targets)-1]
+			}
+			continue
+		}
+
+		if m := podPattern.FindStringSubmatch(line); m != nil {
+			version := "*"
+			if m[2] != "" {
+				version = m[2]
+			}
+			depType := "production"
+			if len(targets) > 0 && targets[len(targets)-1] {
+				depType = "dev"
+			}
+			deps = append(deps, Dependency{Name: m[1], Version: version, Type: depType})
+		}
+	}
+
+	return deps, "cocoapods"
+}
+
+// mixDepPattern matches {:name, "~> 1.0", opts...} tuples in mix.exs
+var mixDepPattern = regexp.MustCompile(`\{\s*:([a-z0-9_]+)\s*,\s*(?:"([^"]+)"\s*)?([^{}]*)\}`)
+
+// mixOnlyPattern extracts the environments listed in an `only:` option
+var mixOnlyPattern = regexp.MustCompile(`only:\s*(\[[^\]]*\]|:[a-z_]+)`)
+
+// parseMixExs parses an Elixir mix.exs file.
+// Dependencies restricted to non-production environments with `only:` are
+// reported as dev dependencies.
+//
+// Example mix.exs:
+//
+//	defp deps do
+//	  [
+//	    {:phoenix, "~> 1.7.0"},
+//	    {:credo, "~> 1.7", only: [:dev, :test], runtime: false}
+//	  ]
+//	end
+func parseMixExs(content []byte) ([]Dependency, string) {
+	text := string(content)
+
+	// Restrict to the deps function body when present
+	if idx := strings.Index(text, "defp deps"); idx >= 0 {
+		text = text[idx:]
+	}
+
+	var deps []Dependency
+	for _, m := range mixDepPattern.FindAllStringSubmatch(text, -1) {
+		opts := m[3]
+		// Skip keyword tuples that are not dependencies (e.g. {:ok, ...})
+		if m[2] == "" && !strings.Contains(opts, "git:") && !strings.Contains(opts, "path:") &&
+			!strings.Contains(opts, "github:") && !strings.Contains(opts, "only:") {
+			continue
+		}
+
+		version := m[2]
+		if version == "" {
+			version = "*"
+		}
+
+		depType := "production"
+		if only := mixOnlyPattern.FindStringSubmatch(opts); only != nil && !strings.Contains(only[1], ":prod") {
+			depType = "dev"
+		}
+
+		deps = append(deps, Dependency{Name: m[1], Version: version, Type: depType})
+	}
+
+	return deps, "elixir"
+}
@@ -0,0 +1,382 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// depsByName indexes parsed dependencies for easier assertions
+func depsByName(deps []Dependency) map[string]Dependency {
+	m := make(map[string]Dependency, len(deps))
+	for _, d := range deps {
+		m[d.Name] = d
+	}
+	return m
+}
+
+func TestParsePomXML(t *testing.T) {
+	pom := []byte(`<project>
+  <version>1.0.0</version>
+  <properties>
+    <spring.version>6.0.11</spring.version>
+  </properties>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>com.fasterxml.jackson.core</groupId>
+        <artifactId>jackson-databind</artifactId>
+        <version>2.15.2</version>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+  <dependencies>
+    <dependency>
+      <groupId>org.springframework</groupId>
+      <artifactId>spring-core</artifactId>
+      <version>${spring.version}</version>
+    </dependency>
+    <dependency>
+      <groupId>com.fasterxml.jackson.core</groupId>
+      <artifactId>jackson-databind</artifactId>
+    </dependency>
+    <dependency>
+      <groupId>junit</groupId>
+      <artifactId>junit</artifactId>
+      <version>4.13.2</version>
+      <scope>test</scope>
+    </dependency>
+  </dependencies>
+</project>`)
+
+	deps, fileType := parsePomXML(pom)
+	if fileType != "maven" {
+		t.Errorf("fileType = %s, want maven", fileType)
+	}
+	if len(deps) != 3 {
+		t.Fatalf("len(deps) = %d, want 3", len(deps))
+	}
+
+	m := depsByName(deps)
+	if got := m["org.springframework:spring-core"].Version; got != "6.0.11" {
+		t.Errorf("spring-core version = %s, want 6.0.11", got)
+	}
+	if got := m["com.fasterxml.jackson.core:jackson-databind"].Version; got != "2.15.2" {
+		t.Errorf("jackson-databind version = %s, want managed 2.15.2", got)
+	}
+	if got := m["junit:junit"].Type; got != "dev" {
+		t.Errorf("junit type = %s, want dev", got)
+	}
+}
+
+func TestParseGradle(t *testing.T) {
+	gradle := []byte(`
+dependencies {
+    implementation 'com.google.guava:guava:32.1.2-jre'
+    api("org.slf4j:slf4j-api:2.0.7")
+    testImplementation group: 'junit', name: 'junit', version: '4.13.2'
+    implementation project(':core')
+    implementation "io.ktor:ktor-server-core:$ktor_version"
+    // implementation 'commented:out:1.0'
+}`)
+
+	deps, _ := parseGradle(gradle)
+	if len(deps) != 4 {
+		t.Fatalf("len(deps) = %d, want 4: %+v", len(deps), deps)
+	}
+
+	m := depsByName(deps)
+	if got := m["com.google.guava:guava"].Version; got != "32.1.2-jre" {
+		t.Errorf("guava version = %s, want 32.1.2-jre", got)
+	}
+	if got := m["org.slf4j:slf4j-api"].Version; got != "2.0.7" {
+		t.Errorf("slf4j version = %s, want 2.0.7", got)
+	}
+	if got := m["junit:junit"].Type; got != "dev" {
+		t.Errorf("junit type = %s, want dev", got)
+	}
+	if got := m["io.ktor:ktor-server-core"].Version; got != "*" {
+		t.Errorf("ktor version = %s, want unresolved *", got)
+	}
+}
+
+func TestParseNuGet(t *testing.T) {
+	csproj := []byte(`<Project Sdk="Microsoft.NET.Sdk">
+  <ItemGroup>
+    <PackageReference Include="Newtonsoft.Json" Version="13.0.3" />
+    <PackageReference Include="Serilog">
+      <Version>3.0.1</Version>
+    </PackageReference>
+    <PackageReference Include="StyleCop.Analyzers" Version="1.1.118" PrivateAssets="all" />
+  </ItemGroup>
+</Project>`)
+
+	deps, fileType := parseNuGet(csproj)
+	if fileType != "nuget" {
+		t.Errorf("fileType = %s, want nuget", fileType)
+	}
+	m := depsByName(deps)
+	if got := m["Newtonsoft.Json"].Version; got != "13.0.3" {
+		t.Errorf("Newtonsoft.Json version = %s, want 13.0.3", got)
+	}
+	if got := m["Serilog"].Version; got != "3.0.1" {
+		t.Errorf("Serilog version = %s, want 3.0.1", got)
+	}
+	if got := m["StyleCop.Analyzers"].Type; got != "dev" {
+		t.Errorf("StyleCop type = %s, want dev", got)
+	}
+
+	config := []byte(`<?xml version="1.0" encoding="utf-8"?>
+<packages>
+  <package id="EntityFramework" version="6.4.4" targetFramework="net48" />
+  <package id="NUnit" version="3.13.3" developmentDependency="true" />
+</packages>`)
+
+	deps, _ = parseNuGet(config)
+	m = depsByName(deps)
+	if len(deps) != 2 {
+		t.Fatalf("len(deps) = %d, want 2", len(deps))
+	}
+	if got := m["NUnit"].Type; got != "dev" {
+		t.Errorf("NUnit type = %s, want dev", got)
+	}
+}
+
+func TestParseComposerJSON(t *testing.T) {
+	composer := []byte(`{
+  "require": {"php": "^8.1", "ext-json": "*", "laravel/framework": "^10.0"},
+  "require-dev": {"phpunit/phpunit": "^10.1"}
+}`)
+
+	deps, _ := parseComposerJSON(composer)
+	if len(deps) != 2 {
+		t.Fatalf("len(deps) = %d, want 2: %+v", len(deps), deps)
+	}
+	m := depsByName(deps)
+	if got := m["phpunit/phpunit"].Type; got != "dev" {
+		t.Errorf("phpunit type = %s, want dev", got)
+	}
+}
+
+func TestParsePubspec(t *testing.T) {
+	pubspec := []byte(`name: app
+dependencies:
+  flutter:
+    sdk: flutter
+  http: ^1.1.0
+  provider: "6.0.5"
+  local_pkg:
+    path: ../local_pkg
+  hosted_pkg:
+    hosted: https://pub.example.com
+    version: ^2.0.0
+dev_dependencies:
+  test: ^1.24.0 # latest
+flutter:
+  uses-material-design: true
+`)
+
+	deps, _ := parsePubspec(pubspec)
+	m := depsByName(deps)
+	if len(deps) != 5 {
+		t.Fatalf("len(deps) = %d, want 5: %+v", len(deps), deps)
+	}
+	if _, ok := m["flutter"]; ok {
+		t.Error("SDK dependency flutter should be skipped")
+	}
+	if got := m["provider"].Version; got != "6.0.5" {
+		t.Errorf("provider version = %s, want 6.0.5", got)
+	}
+	if got := m["local_pkg"].Version; got != "*" {
+		t.Errorf("local_pkg version = %s, want *", got)
+	}
+	if got := m["hosted_pkg"].Version; got != "^2.0.0" {
+		t.Errorf("hosted_pkg version = %s, want ^2.0.0", got)
+	}
+	if got := m["test"]; got.Type != "dev" || got.Version != "^1.24.0" {
+		t.Errorf("test = %+v, want dev ^1.24.0", got)
+	}
+}
+
+func TestParsePubspecFourSpaceIndent(t *testing.T) {
+	pubspec := []byte(`name: app
+dependencies:
+    flutter:
+        sdk: flutter
+    http: ^1.1.0
+    hosted_pkg:
+        version: ^2.0.0
+dev_dependencies:
+    test:
+`)
+
+	deps, _ := parsePubspec(pubspec)
+	m := depsByName(deps)
+	if len(deps) != 3 {
+		t.Fatalf("len(deps) = %d, want 3: %+v", len(deps), deps)
+	}
+	if got := m["hosted_pkg"].Version; got != "^2.0.0" {
+		t.Errorf("hosted_pkg version = %s, want ^2.0.0", got)
+	}
+	if got := m["test"]; got.Type != "dev" || got.Version != "*" {
+		t.Errorf("test = %+v, want dev *", got)
+	}
+}
+
+func TestParsePackageSwift(t *testing.T) {
+	manifest := []byte(`let package = Package(
+    dependencies: [
+        .package(url: "https://github.com/apple/swift-nio.git", from: "2.58.0"),
+        .package(url: "https://github.com/vapor/vapor", exact: "4.77.1"),
+        .package(url: "https://github.com/pointfreeco/swift-snapshot-testing", branch: "main"),
+    ]
+)`)
+
+	deps, _ := parsePackageSwift(manifest)
+	m := depsByName(deps)
+	if len(deps) != 3 {
+		t.Fatalf("len(deps) = %d, want 3", len(deps))
+	}
+	if got := m["github.com/apple/swift-nio"].Version; got != "^2.58.0" {
+		t.Errorf("swift-nio version = %s, want ^2.58.0", got)
+	}
+	if got := m["github.com/vapor/vapor"].Version; got != "4.77.1" {
+		t.Errorf("vapor version = %s, want 4.77.1", got)
+	}
+	if got := m["github.com/pointfreeco/swift-snapshot-testing"].Version; got != "*" {
+		t.Errorf("snapshot-testing version = %s, want *", got)
+	}
+}
+
+func TestParsePodfile(t *testing.T) {
+	podfile := []byte(`platform :ios, '15.0'
+target 'App' do
+  pod 'Alamofire', '~> 5.8'
+  target 'AppTests' do
+    inherit! :search_paths
+    pod 'Quick'
+  end
+end`)
+
+	deps, _ := parsePodfile(podfile)
+	m := depsByName(deps)
+	if got := m["Alamofire"]; got.Version != "~> 5.8" || got.Type != "production" {
+		t.Errorf("Alamofire = %+v, want production ~> 5.8", got)
+	}
+	if got := m["Quick"].Type; got != "dev" {
+		t.Errorf("Quick type = %s, want dev", got)
+	}
+}
+
+func TestParsePodfileNestedBlocks(t *testing.T) {
+	podfile := []byte(`def shared_pods
+  pod 'SwiftLint'
+end
+
+target 'App' do
+  shared_pods
+  target 'AppTests' do
+    pod 'Quick'
+    [1].each do |i|
+      pod 'Nimble'
+    end
+    pod 'OHHTTPStubs'
+  end
+  post_install do |installer|
+    installer.pods_project.targets.each do |t|
+      puts t.name
+    end
+  end
+  pod 'Alamofire'
+end`)
+
+	deps, _ := parsePodfile(podfile)
+	m := depsByName(deps)
+	for name, want := range map[string]string{
+		"SwiftLint":   "production",
+		"Quick":       "dev",
+		"Nimble":      "dev",
+		"OHHTTPStubs": "dev",
+		"Alamofire":   "production",
+	} {
+		if got := m[name].Type; got != want {
+			t.Errorf("%s type = %s, want %s", name, got, want)
+		}
+	}
+}
+
+func TestParseMixExs(t *testing.T) {
+	mix := []byte(`defmodule App.MixProject do
+  def project do
+    [app: :app, version: "0.1.0"]
+  end
+
+  defp deps do
+    [
+      {:phoenix, "~> 1.7.0"},
+      {:credo, "~> 1.7", only: [:dev, :test], runtime: false},
+      {:plug_cowboy, github: "elixir-plug/plug_cowboy"}
+    ]
+  end
+end`)
+
+	deps, _ := parseMixExs(mix)
+	m := depsByName(deps)
+	if len(deps) != 3 {
+		t.Fatalf("len(deps) = %d, want 3: %+v", len(deps), deps)
+	}
+	if got := m["credo"].Type; got != "dev" {
+		t.Errorf("credo type = %s, want dev", got)
+	}
+	if got := m["plug_cowboy"].Version; got != "*" {
+		t.Errorf("plug_cowboy version = %s, want *", got)
+	}
+}
+
+func TestFindDependencyFiles_NewEcosystems(t *testing.T) {
+	tree := []github.TreeEntry{
+		{Path: "service/pom.xml", Type: "blob"},
+		{Path: "app/build.gradle.kts", Type: "blob"},
+		{Path: "src/Api/Api.csproj", Type: "blob"},
+		{Path: "composer.json", Type: "blob"},
+		{Path: "mobile/pubspec.yaml", Type: "blob"},
+		{Path: "Package.swift", Type: "blob"},
+		{Path: "ios/Podfile", Type: "blob"},
+		{Path: "mix.exs", Type: "blob"},
+		{Path: "README.md", Type: "blob"},
+	}
+
+	files := findDependencyFiles(tree)
+	if len(files) != 8 {
+		t.Fatalf("len(files) = %d, want 8", len(files))
+	}
+	want := map[string]string{
+		"service/pom.xml":      "maven",
+		"app/build.gradle.kts": "gradle",
+		"src/Api/Api.csproj":   "nuget",
+		"Package.swift":        "swift",
+	}
+	for _, f := range files {
+		if w, ok := want[f.path]; ok && f.fileType != w {
+			t.Errorf("%s fileType = %s, want %s", f.path, f.fileType, w)
+		}
+	}
+}
+
+func TestMapEcosystem_NewEcosystems(t *testing.T) {
+	tests := map[string]string{
+		"maven":     "Maven",
+		"gradle":    "Maven",
+		"nuget":     "NuGet",
+		"php":       "Packagist",
+		"dart":      "Pub",
+		"swift":     "SwiftURL",
+		"elixir":    "Hex",
+		"cocoapods": "",
+	}
+	for fileType, want := range tests {
+		if got := mapEcosystem(fileType); got != want {
+			t.Errorf("mapEcosystem(%s) = %q, want %q", fileType, got, want)
+		}
+	}
+}
@@ -0,0 +1,481 @@
+package analyzer
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// DocLink is a link or image in a Markdown file
+type DocLink struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Target string `json:"target"`
+}
+
+// DocumentationAnalysis describes the README and Markdown docs: which
+// sections they cover, and the links and images that need fixing
+type DocumentationAnalysis struct {
+	Readme           string     `json:"readme"` // empty when the repository has no README
+	Files            []string   `json:"files"`  // Markdown files analyzed, README first
+	Headings         []string   `json:"headings"`
+	HasInstall       bool       `json:"has_install"`
+	HasUsage         bool       `json:"has_usage"`
+	HasExamples      bool       `json:"has_examples"`
+	HasContributing  bool       `json:"has_contributing"` // a README section or a CONTRIBUTING file
+	HasLicense       bool       `json:"has_license"`      // a README section or a LICENSE file
+	CodeBlocks       int        `json:"code_blocks"`
+	Badges           int        `json:"badges"` // README badges
+	BrokenLinks      []DocLink  `json:"broken_links"`
+	ImagesWithoutAlt []DocLink  `json:"images_without_alt"`
+	ReadmeUpdated    *time.Time `json:"readme_updated,omitempty"`
+	LastCommit       *time.Time `json:"last_commit,omitempty"`
+	StaleDays        int        `json:"stale_days"` // days the README lags the latest commit, -1 when unknown
+	Recommendations  []string   `json:"recommendations"`
+	Errors           []string   `json:"errors,omitempty"`
+	Truncated        bool       `json:"truncated"`      // more Markdown files exist than were fetched
+	Skipped          int        `json:"skipped"`        // Markdown files left out by the file cap
+	Note             string     `json:"note,omitempty"` // explains a partial analysis
+}
+
+const (
+	// maxDocFiles caps how many Markdown files besides the README are fetched
+	maxDocFiles        = 10
+	maxDocFilesNoToken = 3
+	// docsStalenessWindow is how far back README commits are looked up
+	docsStalenessWindow = 365
+	// staleDocDays is when a README lagging the code is worth updating
+	staleDocDays = 180
+)
+
+// readmeLocations are the directories GitHub looks in for a README, in order
+var readmeLocations = []string{"", ".github", "docs"}
+
+// docSections maps README sections to the heading words that introduce them
+var docSections = []struct {
+	name     string
+	keywords []string
+}{
+	{"install", []string{"install", "getting started", "setup", "set up", "quick start", "quickstart", "requirements"}},
+	{"usage", []string{"usage", "how to use", "quick start", "quickstart", "running", "commands", "configuration"}},
+	{"examples", []string{"example", "demo", "tutorial", "walkthrough"}},
+	{"contributing", []string{"contribut", "development", "developing"}},
+	{"license", []string{"license", "licence", "licensing"}},
+}
+
+var (
+	mdATXHeadingPattern = regexp.MustCompile(`^ {0,3}(#{1,6})\s+(.*?)\s*#*\s*$`)
+	mdSetextPattern     = regexp.MustCompile(`^ {0,3}(=+|-+)\s*$`)
+	mdFencePattern      = regexp.MustCompile("^ {0,3}(```+|~~~+)")
+	mdCodeSpanPattern   = regexp.MustCompile("`[^`]*`")
+	mdImagePattern      = regexp.MustCompile(`!\[([^\]]*)\]\(\s*<?([^)\s>]*)>?(?:\s+["'][^)]*["'])?\s*\)`)
+	mdLinkPattern       = regexp.MustCompile(`\[([^\]]*)\]\(\s*<?([^)\s>]*)>?(?:\s+["'][^)]*["'])?\s*\)`)
+	mdRefDefPattern     = regexp.MustCompile(`^ {0,3}\[[^\]]+\]:\s*<?([^\s>]+)>?`)
+	htmlImgPattern      = regexp.MustCompile(`(?i)<img\b[^>]*>`)
+	htmlSrcPattern      = regexp.MustCompile(`(?i)\bsrc\s*=\s*["']([^"']*)["']`)
+	htmlAltPattern      = regexp.MustCompile(`(?i)\balt\s*=\s*["']([^"']*)["']`)
+)
+
+// mdImagePlaceholder replaces images inside a line so that the link around
+// a badge still matches mdLinkPattern
+const mdImagePlaceholder = "\x00img\x00"
+
+// markdownDoc is what parseMarkdown extracts from one file
+type markdownDoc struct {
+	headings   []string
+	codeBlocks int
+	links      []DocLink // relative and absolute link targets
+	images     []markdownImage
+}
+
+type markdownImage struct {
+	DocLink
+	alt    string
+	linked bool // wrapped in a link, as badges usually are
+}
+
+// AnalyzeDocumentation fetches the README and Markdown docs, and compares
+// the README's last commit with the repository's latest commit
+func AnalyzeDocumentation(client *github.Client, owner, repo string, fileTree []github.TreeEntry, commits []github.Commit) *DocumentationAnalysis {
+	fetch := contentFetcher(client, owner, repo)
+	doc := analyzeDocumentation(fileTree, fetch, fileLimit(client, maxDocFiles, maxDocFilesNoToken))
+	doc.Note = skippedNote(client, doc.Skipped, "Markdown files")
+
+	if doc.Readme != "" && len(commits) > 0 {
+		readmeCommits, err := client.GetCommitsForPath(owner, repo, doc.Readme, docsStalenessWindow)
+		if err == nil {
+			since := time.Now().AddDate(0, 0, -docsStalenessWindow)
+			setDocumentationStaleness(doc, readmeCommits, commits, since)
+		}
+	}
+	return doc
+}
+
+func analyzeDocumentation(fileTree []github.TreeEntry, fetch func(string) (string, bool), maxFiles int) *DocumentationAnalysis {
+	doc := &DocumentationAnalysis{
+		Files:            []string{},
+		Headings:         []string{},
+		BrokenLinks:      []DocLink{},
+		ImagesWithoutAlt: []DocLink{},
+		StaleDays:        -1,
+	}
+
+	present := make(map[string]bool)
+	var docFiles []string
+	for _, entry := range fileTree {
+		present[entry.Path] = true
+		for dir := path.Dir(entry.Path); dir != "."; dir = path.Dir(dir) {
+			present[dir] = true
+		}
+		if entry.Type != "blob" || isVendoredPath(entry.Path) {
+			continue
+		}
+		base := strings.ToLower(path.Base(entry.Path))
+		if strings.HasPrefix(base, "contributing") {
+			doc.HasContributing = true
+		}
+		if strings.HasPrefix(base, "license") || strings.HasPrefix(base, "licence") || base == "copying" {
+			doc.HasLicense = true
+		}
+		topLevel := !strings.Contains(entry.Path, "/")
+		inDocsDir := strings.HasPrefix(entry.Path, "docs/") || strings.HasPrefix(entry.Path, "doc/")
+		if isMarkdownFile(entry.Path) && (inDocsDir || topLevel && strings.HasPrefix(base, "contributing")) {
+			docFiles = append(docFiles, entry.Path)
+		}
+	}
+
+	doc.Readme = findReadme(fileTree)
+	files := docFiles
+	if doc.Readme != "" {
+		files = nil
+		for _, f := range docFiles {
+			if f != doc.Readme {
+				files = append(files, f)
+			}
+		}
+	}
+	sort.Strings(files)
+	if len(files) > maxFiles {
+		doc.Truncated = true
+		doc.Skipped = len(files) - maxFiles
+		files = files[:maxFiles]
+	}
+	if doc.Readme != "" {
+		files = append([]string{doc.Readme}, files...)
+	}
+
+	for _, file := range files {
+		content, ok := fetch(file)
+		if !ok {
+			doc.Errors = append(doc.Errors, file+": could not be fetched")
+			continue
+		}
+		doc.Files = append(doc.Files, file)
+		md := parseMarkdown(file, content)
+		readme := file == doc.Readme
+
+		if readme && md.headings != nil {
+			doc.Headings = md.headings
+		}
+		// Docs pages count for the sections their headings or names cover
+		sectionText := md.headings
+		if !readme {
+			sectionText = append(sectionText, strings.TrimSuffix(path.Base(file), path.Ext(file)))
+		}
+		for _, text := range sectionText {
+			markDocSection(doc, text)
+		}
+		doc.CodeBlocks += md.codeBlocks
+
+		for _, link := range md.links {
+			if target, ok := resolveDocLink(file, link.Target); ok && !present[target] {
+				doc.BrokenLinks = append(doc.BrokenLinks, link)
+			}
+		}
+		for _, img := range md.images {
+			if strings.TrimSpace(img.alt) == "" {
+				doc.ImagesWithoutAlt = append(doc.ImagesWithoutAlt, img.DocLink)
+			}
+			if target, ok := resolveDocLink(file, img.Target); ok && !present[target] {
+				doc.BrokenLinks = append(doc.BrokenLinks, img.DocLink)
+			}
+			if readme && isBadge(img) {
+				doc.Badges++
+			}
+		}
+	}
+
+	doc.Recommendations = documentationRecommendations(doc)
+	return doc
+}
+
+// findReadme returns the README GitHub would display, preferring Markdown
+func findReadme(fileTree []github.TreeEntry) string {
+	best, bestRank := "", len(readmeLocations)*2
+	for _, entry := range fileTree {
+		if entry.Type != "blob" || !strings.HasPrefix(strings.ToLower(path.Base(entry.Path)), "readme") {
+			continue
+		}
+		dir := path.Dir(entry.Path)
+		if dir == "." {
+			dir = ""
+		}
+		for i, loc := range readmeLocations {
+			if dir != loc {
+				continue
+			}
+			rank := i * 2
+			if !isMarkdownFile(entry.Path) {
+				rank++
+			}
+			if rank < bestRank || rank == bestRank && entry.Path < best {
+				best, bestRank = entry.Path, rank
+			}
+		}
+	}
+	return best
+}
+
+func isMarkdownFile(p string) bool {
+	switch strings.ToLower(path.Ext(p)) {
+	case ".md", ".markdown", ".mdx":
+		return true
+	}
+	return false
+}
+
+// markDocSection sets the section flags a heading or file name covers
+func markDocSection(doc *DocumentationAnalysis, text string) {
+	lower := strings.ToLower(text)
+	for _, section := range docSections {
+		for _, kw := range section.keywords {
+			if !strings.Contains(lower, kw) {
+				continue
+			}
+			switch section.name {
+			case "install":
+				doc.HasInstall = true
+			case "usage":
+				doc.HasUsage = true
+			case "examples":
+				doc.HasExamples = true
+			case "contributing":
+				doc.HasContributing = true
+			case "license":
+				doc.HasLicense = true
+			}
+			break
+		}
+	}
+}
+
+// parseMarkdown extracts headings, fenced code blocks, links and images,
+// ignoring anything inside code
+func parseMarkdown(file, content string) markdownDoc {
+	var md markdownDoc
+	fence := ""
+	prev := ""
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, "\r")
+		lineNo := i + 1
+
+		if m := mdFencePattern.FindStringSubmatch(line); m != nil {
+			marker := m[1]
+			switch {
+			case fence == "":
+				fence = marker
+				md.codeBlocks++
+			case marker[0] == fence[0] && len(marker) >= len(fence) && strings.TrimSpace(line) == marker:
+				fence = ""
+			}
+			prev = ""
+			continue
+		}
+		if fence != "" {
+			continue
+		}
+
+		if m := mdATXHeadingPattern.FindStringSubmatch(line); m != nil {
+			md.headings = append(md.headings, m[2])
+			prev = ""
+			continue
+		}
+		if mdSetextPattern.MatchString(line) && strings.TrimSpace(prev) != "" && !strings.HasPrefix(strings.TrimSpace(prev), "-") {
+			md.headings = append(md.headings, strings.TrimSpace(prev))
+			prev = ""
+			continue
+		}
+		prev = line
+
+		text := mdCodeSpanPattern.ReplaceAllString(line, "")
+		if m := mdRefDefPattern.FindStringSubmatch(text); m != nil {
+			md.links = append(md.links, DocLink{File: file, Line: lineNo, Target: m[1]})
+			continue
+		}
+
+		var images []markdownImage
+		text = mdImagePattern.ReplaceAllStringFunc(text, func(s string) string {
+			m := mdImagePattern.FindStringSubmatch(s)
+			images = append(images, markdownImage{DocLink: DocLink{File: file, Line: lineNo, Target: m[2]}, alt: m[1]})
+			return mdImagePlaceholder
+		})
+		for _, m := range mdLinkPattern.FindAllStringSubmatch(text, -1) {
+			if strings.Contains(m[1], mdImagePlaceholder) {
+				for i := range images {
+					images[i].linked = true
+				}
+			}
+			md.links = append(md.links, DocLink{File: file, Line: lineNo, Target: m[2]})
+		}
+		for _, tag := range htmlImgPattern.FindAllString(text, -1) {
+			img := markdownImage{DocLink: DocLink{File: file, Line: lineNo}}
+			if m := htmlSrcPattern.FindStringSubmatch(tag); m != nil {
+				img.Target = m[1]
+			}
+			if m := htmlAltPattern.FindStringSubmatch(tag); m != nil {
+				img.alt = m[1]
+			}
+			img.linked = strings.Contains(strings.ToLower(text), "<a ")
+			images = append(images, img)
+		}
+		md.images = append(md.images, images...)
+	}
+	return md
+}
+
+// isBadge reports whether an image looks like a status badge
+func isBadge(img markdownImage) bool {
+	lower := strings.ToLower(img.Target)
+	return strings.Contains(lower, "shields.io") || strings.Contains(lower, "badge") ||
+		img.linked && strings.HasSuffix(lower, ".svg")
+}
+
+// resolveDocLink returns the repository path a relative link points at.
+// Absolute URLs, anchors and links leaving the repository are not checked.
+func resolveDocLink(file, target string) (string, bool) {
+	if target == "" || strings.HasPrefix(target, "#") || strings.HasPrefix(target, "//") || strings.Contains(target, ":") {
+		return "", false
+	}
+	if i := strings.IndexAny(target, "#?"); i >= 0 {
+		target = target[:i]
+	}
+	if unescaped, err := url.PathUnescape(target); err == nil {
+		target = unescaped
+	}
+	var resolved string
+	if strings.HasPrefix(target, "/") {
+		resolved = path.Clean(strings.TrimPrefix(target, "/"))
+	} else {
+		resolved = path.Join(path.Dir(file), target)
+	}
+	if resolved == "." || resolved == "" || strings.HasPrefix(resolved, "..") {
+		return "", false
+	}
+	return resolved, true
+}
+
+// setDocumentationStaleness compares the README's latest commit with the
+// repository's. When the README has no commit since since, the lag is
+// counted from since, so it is a lower bound. The recommendations are
+// regenerated to include a stale README.
+func setDocumentationStaleness(doc *DocumentationAnalysis, readmeCommits, commits []github.Commit, since time.Time) {
+	doc.LastCommit = latestCommitDate(commits)
+	doc.ReadmeUpdated = latestCommitDate(readmeCommits)
+	if doc.LastCommit == nil {
+		doc.StaleDays = -1
+		return
+	}
+	updated := since
+	if doc.ReadmeUpdated != nil {
+		updated = *doc.ReadmeUpdated
+	}
+	doc.StaleDays = max(0, int(doc.LastCommit.Sub(updated).Hours()/24))
+	doc.Recommendations = documentationRecommendations(doc)
+}
+
+func latestCommitDate(commits []github.Commit) *time.Time {
+	var latest *time.Time
+	for _, c := range commits {
+		date := c.Commit.Author.Date
+		if latest == nil || date.After(*latest) {
+			latest = &date
+		}
+	}
+	return latest
+}
+
+// documentationRecommendations lists the documentation fixes with the most
+// impact first
+func documentationRecommendations(doc *DocumentationAnalysis) []string {
+	recs := []string{}
+	if doc.Readme == "" {
+		return recs
+	}
+	if !doc.HasInstall {
+		recs = append(recs, "📦 Add an Installation section to the README")
+	}
+	if !doc.HasUsage {
+		recs = append(recs, "🚀 Add a Usage section to the README")
+	}
+	if doc.CodeBlocks == 0 {
+		recs = append(recs, "💻 Add code blocks with commands and examples to the README")
+	} else if !doc.HasExamples {
+		recs = append(recs, "📖 Add an Examples section to the README")
+	}
+	if n := len(doc.BrokenLinks); n > 0 {
+		link := doc.BrokenLinks[0]
+		recs = append(recs, fmt.Sprintf("🔗 Fix %d broken relative link(s), e.g. %s in %s", n, link.Target, link.File))
+	}
+	if n := len(doc.ImagesWithoutAlt); n > 0 {
+		recs = append(recs, fmt.Sprintf("🖼️ Add alt text to %d image(s), starting with %s:%d", n, doc.ImagesWithoutAlt[0].File, doc.ImagesWithoutAlt[0].Line))
+	}
+	if doc.StaleDays >= staleDocDays {
+		recs = append(recs, fmt.Sprintf("⏳ Review the README, last changed %d days before the latest commit", doc.StaleDays))
+	}
+	if doc.Badges == 0 {
+		recs = append(recs, "🏷️ Add status badges (CI, version, license) to the README")
+	}
+	return recs
+}
+
+// documentationScore scores the documentation from 0 to 100 based on the
+// README's content, penalizing broken links, missing alt text and a stale
+// README
+func documentationScore(metrics *CodeQualityMetrics, doc *DocumentationAnalysis) int {
+	score := 25
+	for _, s := range []struct {
+		ok     bool
+		points int
+	}{
+		{doc.HasInstall, 12},
+		{doc.HasUsage, 12},
+		{doc.HasExamples, 8},
+		{doc.HasContributing, 8},
+		{doc.HasLicense, 5},
+		{doc.CodeBlocks > 0, 10},
+		{doc.Badges > 0, 5},
+		{len(doc.Files) > 1, 5},
+		{metrics.HasChangelog, 5},
+		{metrics.HasCodeOfConduct, 5},
+	} {
+		if s.ok {
+			score += s.points
+		}
+	}
+
+	score -= min(3*len(doc.BrokenLinks), 15)
+	score -= min(2*len(doc.ImagesWithoutAlt), 10)
+	switch {
+	case doc.StaleDays >= staleDocDays:
+		score -= 10
+	case doc.StaleDays >= staleDocDays/2:
+		score -= 5
+	}
+	return max(0, min(score, 100))
+}
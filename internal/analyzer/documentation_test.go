@@ -0,0 +1,169 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+const sampleReadme = "# Widget\n" +
+	"[![CI](https://github.com/acme/widget/actions/workflows/ci.yml/badge.svg)](https://github.com/acme/widget/actions)\n" +
+	"![](docs/screenshot.png)\n" +
+	"\n" +
+	"Getting Started\n" +
+	"---------------\n" +
+	"```sh\n" +
+	"# not a heading\n" +
+	"go install ./cmd/widget\n" +
+	"```\n" +
+	"## Usage\n" +
+	"See the [guide](docs/guide.md#flags), the [API](/docs/api.md) and `[not](a-link.md)`.\n" +
+	"<img src=\"docs/logo.svg\" alt=\"\">\n" +
+	"[changelog]: ./CHANGELOG.md\n" +
+	"Report bugs on [GitHub](https://github.com/acme/widget/issues) or in [issues](../../issues).\n"
+
+func TestParseMarkdown(t *testing.T) {
+	md := parseMarkdown("README.md", sampleReadme)
+
+	if got := strings.Join(md.headings, "|"); got != "Widget|Getting Started|Usage" {
+		t.Errorf("headings = %q", got)
+	}
+	if md.codeBlocks != 1 {
+		t.Errorf("codeBlocks = %d, want 1", md.codeBlocks)
+	}
+	var targets []string
+	for _, l := range md.links {
+		targets = append(targets, l.Target)
+	}
+	want := "https://github.com/acme/widget/actions,docs/guide.md#flags,/docs/api.md,./CHANGELOG.md,https://github.com/acme/widget/issues,../../issues"
+	if got := strings.Join(targets, ","); got != want {
+		t.Errorf("links = %s\nwant    %s", got, want)
+	}
+	if len(md.images) != 3 || !md.images[0].linked || md.images[1].linked || md.images[2].Line != 13 {
+		t.Errorf("images = %+v", md.images)
+	}
+}
+
+func TestResolveDocLink(t *testing.T) {
+	tests := []struct {
+		file, target, want string
+		ok                 bool
+	}{
+		{"README.md", "docs/guide.md#flags", "docs/guide.md", true},
+		{"docs/guide.md", "../README.md", "README.md", true},
+		{"docs/guide.md", "/src/My%20File.go", "src/My File.go", true},
+		{"README.md", "../../issues", "", false},
+		{"README.md", "https://example.com/x.md", "", false},
+		{"README.md", "#usage", "", false},
+		{"README.md", "mailto:dev@example.com", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := resolveDocLink(tt.file, tt.target)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("resolveDocLink(%q, %q) = %q, %v; want %q, %v", tt.file, tt.target, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestAnalyzeDocumentation(t *testing.T) {
+	files := map[string]string{
+		"README.md":           sampleReadme,
+		"docs/guide.md":       "# Guide\nBack to the [README](../README.md) or the [missing page](missing.md).\n",
+		"docs/examples.md":    "Plain page\n",
+		"docs/screenshot.png": "",
+		"docs/logo.svg":       "",
+		"LICENSE":             "",
+		"vendor/x/README.md":  "# vendored",
+	}
+	var tree []github.TreeEntry
+	for p := range files {
+		tree = append(tree, github.TreeEntry{Path: p, Type: "blob"})
+	}
+	fetch := func(p string) (string, bool) {
+		content, ok := files[p]
+		return content, ok
+	}
+
+	doc := analyzeDocumentation(tree, fetch, maxDocFiles)
+
+	if doc.Readme != "README.md" || strings.Join(doc.Files, ",") != "README.md,docs/examples.md,docs/guide.md" {
+		t.Fatalf("Readme, Files = %q, %v", doc.Readme, doc.Files)
+	}
+	if doc.Truncated {
+		t.Error("Truncated = true, want false below the cap")
+	}
+	if capped := analyzeDocumentation(tree, fetch, 1); !capped.Truncated || capped.Skipped != 1 || len(capped.Files) != 2 {
+		t.Errorf("capped Files = %v, skipped %d; want the README and one doc", capped.Files, capped.Skipped)
+	}
+	if !doc.HasInstall || !doc.HasUsage || !doc.HasExamples || !doc.HasLicense || doc.HasContributing {
+		t.Errorf("sections = %+v, want all but contributing", doc)
+	}
+	if doc.Badges != 1 || doc.CodeBlocks != 1 {
+		t.Errorf("Badges, CodeBlocks = %d, %d; want 1, 1", doc.Badges, doc.CodeBlocks)
+	}
+	var broken []string
+	for _, l := range doc.BrokenLinks {
+		broken = append(broken, l.File+":"+l.Target)
+	}
+	if got := strings.Join(broken, ","); got != "README.md:/docs/api.md,README.md:./CHANGELOG.md,docs/guide.md:missing.md" {
+		t.Errorf("BrokenLinks = %s", got)
+	}
+	if len(doc.ImagesWithoutAlt) != 2 {
+		t.Errorf("ImagesWithoutAlt = %+v, want the screenshot and the logo", doc.ImagesWithoutAlt)
+	}
+
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	latest, readme := github.Commit{}, github.Commit{}
+	latest.Commit.Author.Date = now
+	readme.Commit.Author.Date = now.AddDate(0, 0, -200)
+	setDocumentationStaleness(doc, []github.Commit{readme}, []github.Commit{readme, latest}, now.AddDate(-1, 0, 0))
+	if doc.StaleDays != 200 {
+		t.Errorf("StaleDays = %d, want 200", doc.StaleDays)
+	}
+	setDocumentationStaleness(doc, nil, []github.Commit{latest}, now.AddDate(-1, 0, 0))
+	if doc.StaleDays != 365 || doc.ReadmeUpdated != nil {
+		t.Errorf("StaleDays = %d, want the whole window when the README has no commits", doc.StaleDays)
+	}
+	if !strings.HasPrefix(doc.Recommendations[0], "🔗 Fix 3 broken") {
+		t.Errorf("Recommendations = %v, want broken links first", doc.Recommendations)
+	}
+}
+
+func TestApplyDocumentation(t *testing.T) {
+	tree := []github.TreeEntry{
+		{Path: "README.md", Type: "blob"},
+		{Path: "main.go", Type: "blob"},
+		{Path: "main_test.go", Type: "blob"},
+	}
+	metrics := AnalyzeCodeQuality(nil, tree, nil)
+	before := metrics.DocumentationScore
+
+	doc := &DocumentationAnalysis{
+		Readme:           "README.md",
+		Files:            []string{"README.md"},
+		HasInstall:       true,
+		HasUsage:         true,
+		CodeBlocks:       2,
+		BrokenLinks:      []DocLink{{File: "README.md", Line: 3, Target: "docs/x.md"}},
+		ImagesWithoutAlt: []DocLink{},
+		StaleDays:        -1,
+	}
+	doc.Recommendations = documentationRecommendations(doc)
+	metrics.ApplyDocumentation(doc)
+
+	// 25 README + 12 install + 12 usage + 10 code blocks - 3 broken link
+	if metrics.DocumentationScore != 56 || before == 56 {
+		t.Errorf("DocumentationScore = %d (was %d), want 56", metrics.DocumentationScore, before)
+	}
+	found := false
+	for _, rec := range metrics.Recommendations {
+		if strings.Contains(rec, "Examples section") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Recommendations = %v, want the README fixes included", metrics.Recommendations)
+	}
+}
@@ -88,14 +88,8 @@ func makeCommits(count int) []github.Commit {
 	for i := 0; i < count; i++ {
 		commits[i] = github.Commit{
 			SHA: "abc123",
-			Commit: struct {
-				Author struct {
-					Date time.Time `json:"date"`
-				} `json:"author"`
-			}{
-				Author: struct {
-					Date time.Time `json:"date"`
-				}{
+			Commit: github.CommitInfo{
+				Author: github.CommitAuthor{
 					Date: time.Now().Add(-time.Duration(i) * 24 * time.Hour),
 				},
 			},
@@ -3,7 +3,9 @@
 package analyzer
 
 import (
-	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/agnivo988/Repo-lyzer/internal/github"
@@ -19,20 +21,43 @@ type LicenseInfo struct {
 	Distribute bool   `json:"distribute"`  // Allows distribution
 	Patent     bool   `json:"patent"`      // Includes patent grant
 	SourceFile string `json:"source_file"` // File where license was found
+	Confidence int    `json:"confidence"`  // 0-100 match confidence
+}
+
+// DeclaredLicense is a license field from a package manifest
+type DeclaredLicense struct {
+	Source     string `json:"source"`     // Manifest path
+	Expression string `json:"expression"` // SPDX expression, canonicalized when valid
+	Valid      bool   `json:"valid"`      // Parses as an SPDX expression
+}
+
+// FileLicense is an SPDX-License-Identifier header in a source file
+type FileLicense struct {
+	Path       string `json:"path"`
+	Expression string `json:"expression"`
 }
 
 // LicenseAnalysis holds complete license analysis results
 type LicenseAnalysis struct {
-	MainLicense    *LicenseInfo   `json:"main_license"`    // Primary project license
-	OtherLicenses  []LicenseInfo  `json:"other_licenses"`  // Other licenses found
-	Compatibility  string         `json:"compatibility"`   // "compatible", "warning", "conflict"
-	Warnings       []string       `json:"warnings"`        // Potential issues
-	LicenseScore   int            `json:"license_score"`   // 0-100 score
+	MainLicense      *LicenseInfo      `json:"main_license"`      // Primary project license
+	OtherLicenses    []LicenseInfo     `json:"other_licenses"`    // Other licenses found
+	DeclaredLicenses []DeclaredLicense `json:"declared_licenses"` // Licenses declared in package manifests
+	FileLicenses     []FileLicense     `json:"file_licenses"`     // SPDX headers in sampled source files
+	Compatibility    string            `json:"compatibility"`     // "compatible", "warning", "conflict"
+	Warnings         []string          `json:"warnings"`          // Potential issues
+	LicenseScore     int               `json:"license_score"`     // 0-100 score
 }
 
-// Known license patterns for detection
-var licensePatterns = map[string]LicenseInfo{
-	"mit": {
+// maxSPDXHeaderFiles caps how many source files are fetched to look for
+// SPDX-License-Identifier headers. Unauthenticated clients skip the headers
+// to stay within the API rate limit.
+const maxSPDXHeaderFiles = 10
+
+// knownLicenses describes the licenses we can identify, keyed by SPDX
+// identifier. GNU licenses are keyed by version family; lookupLicense adds
+// the -only or -or-later suffix.
+var knownLicenses = map[string]LicenseInfo{
+	"MIT": {
 		Name:       "MIT License",
 		SPDX:       "MIT",
 		Category:   "permissive",
@@ -41,7 +66,16 @@ var licensePatterns = map[string]LicenseInfo{
 		Distribute: true,
 		Patent:     false,
 	},
-	"apache": {
+	"MIT-0": {
+		Name:       "MIT No Attribution",
+		SPDX:       "MIT-0",
+		Category:   "permissive",
+		Commercial: true,
+		Modify:     true,
+		Distribute: true,
+		Patent:     false,
+	},
+	"Apache-2.0": {
 		Name:       "Apache License 2.0",
 		SPDX:       "Apache-2.0",
 		Category:   "permissive",
@@ -50,7 +84,7 @@ var licensePatterns = map[string]LicenseInfo{
 		Distribute: true,
 		Patent:     true,
 	},
-	"gpl-3": {
+	"GPL-3.0": {
 		Name:       "GNU GPL v3",
 		SPDX:       "GPL-3.0",
 		Category:   "copyleft",
@@ -59,7 +93,7 @@ var licensePatterns = map[string]LicenseInfo{
 		Distribute: true,
 		Patent:     true,
 	},
-	"gpl-2": {
+	"GPL-2.0": {
 		Name:       "GNU GPL v2",
 		SPDX:       "GPL-2.0",
 		Category:   "copyleft",
@@ -68,16 +102,34 @@ var licensePatterns = map[string]LicenseInfo{
 		Distribute: true,
 		Patent:     false,
 	},
-	"lgpl": {
-		Name:       "GNU LGPL",
+	"LGPL-3.0": {
+		Name:       "GNU LGPL v3",
 		SPDX:       "LGPL-3.0",
 		Category:   "copyleft",
 		Commercial: true,
 		Modify:     true,
 		Distribute: true,
+		Patent:     true,
+	},
+	"LGPL-2.1": {
+		Name:       "GNU LGPL v2.1",
+		SPDX:       "LGPL-2.1",
+		Category:   "copyleft",
+		Commercial: true,
+		Modify:     true,
+		Distribute: true,
 		Patent:     false,
 	},
-	"bsd-3": {
+	"AGPL-3.0": {
+		Name:       "GNU AGPL v3",
+		SPDX:       "AGPL-3.0",
+		Category:   "copyleft",
+		Commercial: true,
+		Modify:     true,
+		Distribute: true,
+		Patent:     true,
+	},
+	"BSD-3-Clause": {
 		Name:       "BSD 3-Clause",
 		SPDX:       "BSD-3-Clause",
 		Category:   "permissive",
@@ -86,7 +138,7 @@ var licensePatterns = map[string]LicenseInfo{
 		Distribute: true,
 		Patent:     false,
 	},
-	"bsd-2": {
+	"BSD-2-Clause": {
 		Name:       "BSD 2-Clause",
 		SPDX:       "BSD-2-Clause",
 		Category:   "permissive",
@@ -95,7 +147,16 @@ var licensePatterns = map[string]LicenseInfo{
 		Distribute: true,
 		Patent:     false,
 	},
-	"isc": {
+	"0BSD": {
+		Name:       "BSD Zero Clause",
+		SPDX:       "0BSD",
+		Category:   "permissive",
+		Commercial: true,
+		Modify:     true,
+		Distribute: true,
+		Patent:     false,
+	},
+	"ISC": {
 		Name:       "ISC License",
 		SPDX:       "ISC",
 		Category:   "permissive",
@@ -104,7 +165,7 @@ var licensePatterns = map[string]LicenseInfo{
 		Distribute: true,
 		Patent:     false,
 	},
-	"mpl": {
+	"MPL-2.0": {
 		Name:       "Mozilla Public License 2.0",
 		SPDX:       "MPL-2.0",
 		Category:   "copyleft",
@@ -113,7 +174,7 @@ var licensePatterns = map[string]LicenseInfo{
 		Distribute: true,
 		Patent:     true,
 	},
-	"unlicense": {
+	"Unlicense": {
 		Name:       "The Unlicense",
 		SPDX:       "Unlicense",
 		Category:   "permissive",
@@ -122,7 +183,7 @@ var licensePatterns = map[string]LicenseInfo{
 		Distribute: true,
 		Patent:     false,
 	},
-	"cc0": {
+	"CC0-1.0": {
 		Name:       "CC0 1.0 Universal",
 		SPDX:       "CC0-1.0",
 		Category:   "permissive",
@@ -131,24 +192,219 @@ var licensePatterns = map[string]LicenseInfo{
 		Distribute: true,
 		Patent:     false,
 	},
-	"agpl": {
-		Name:       "GNU AGPL v3",
-		SPDX:       "AGPL-3.0",
+	"Zlib": {
+		Name:       "zlib License",
+		SPDX:       "Zlib",
+		Category:   "permissive",
+		Commercial: true,
+		Modify:     true,
+		Distribute: true,
+		Patent:     false,
+	},
+	"BSL-1.0": {
+		Name:       "Boost Software License 1.0",
+		SPDX:       "BSL-1.0",
+		Category:   "permissive",
+		Commercial: true,
+		Modify:     true,
+		Distribute: true,
+		Patent:     false,
+	},
+	"WTFPL": {
+		Name:       "WTFPL",
+		SPDX:       "WTFPL",
+		Category:   "permissive",
+		Commercial: true,
+		Modify:     true,
+		Distribute: true,
+		Patent:     false,
+	},
+	"GPL-1.0": {
+		Name:       "GNU GPL v1",
+		SPDX:       "GPL-1.0",
+		Category:   "copyleft",
+		Commercial: true,
+		Modify:     true,
+		Distribute: true,
+		Patent:     false,
+	},
+	"LGPL-2.0": {
+		Name:       "GNU LGPL v2",
+		SPDX:       "LGPL-2.0",
+		Category:   "copyleft",
+		Commercial: true,
+		Modify:     true,
+		Distribute: true,
+		Patent:     false,
+	},
+	"MPL-1.1": {
+		Name:       "Mozilla Public License 1.1",
+		SPDX:       "MPL-1.1",
+		Category:   "copyleft",
+		Commercial: true,
+		Modify:     true,
+		Distribute: true,
+		Patent:     true,
+	},
+	"EPL-1.0": {
+		Name:       "Eclipse Public License 1.0",
+		SPDX:       "EPL-1.0",
+		Category:   "copyleft",
+		Commercial: true,
+		Modify:     true,
+		Distribute: true,
+		Patent:     true,
+	},
+	"EPL-2.0": {
+		Name:       "Eclipse Public License 2.0",
+		SPDX:       "EPL-2.0",
+		Category:   "copyleft",
+		Commercial: true,
+		Modify:     true,
+		Distribute: true,
+		Patent:     true,
+	},
+	"CDDL-1.0": {
+		Name:       "Common Development and Distribution License 1.0",
+		SPDX:       "CDDL-1.0",
 		Category:   "copyleft",
 		Commercial: true,
 		Modify:     true,
 		Distribute: true,
 		Patent:     true,
 	},
+	"MS-PL": {
+		Name:       "Microsoft Public License",
+		SPDX:       "MS-PL",
+		Category:   "permissive",
+		Commercial: true,
+		Modify:     true,
+		Distribute: true,
+		Patent:     true,
+	},
+	"MS-RL": {
+		Name:       "Microsoft Reciprocal License",
+		SPDX:       "MS-RL",
+		Category:   "copyleft",
+		Commercial: true,
+		Modify:     true,
+		Distribute: true,
+		Patent:     true,
+	},
+	"Artistic-2.0": {
+		Name:       "Artistic License 2.0",
+		SPDX:       "Artistic-2.0",
+		Category:   "permissive",
+		Commercial: true,
+		Modify:     true,
+		Distribute: true,
+		Patent:     true,
+	},
+	"OFL-1.1": {
+		Name:       "SIL Open Font License 1.1",
+		SPDX:       "OFL-1.1",
+		Category:   "copyleft",
+		Commercial: true,
+		Modify:     true,
+		Distribute: true,
+		Patent:     false,
+	},
+	"UPL-1.0": {
+		Name:       "Universal Permissive License 1.0",
+		SPDX:       "UPL-1.0",
+		Category:   "permissive",
+		Commercial: true,
+		Modify:     true,
+		Distribute: true,
+		Patent:     true,
+	},
+	"BlueOak-1.0.0": {
+		Name:       "Blue Oak Model License 1.0.0",
+		SPDX:       "BlueOak-1.0.0",
+		Category:   "permissive",
+		Commercial: true,
+		Modify:     true,
+		Distribute: true,
+		Patent:     true,
+	},
+	"NCSA": {
+		Name:       "University of Illinois/NCSA Open Source License",
+		SPDX:       "NCSA",
+		Category:   "permissive",
+		Commercial: true,
+		Modify:     true,
+		Distribute: true,
+		Patent:     false,
+	},
+	"PostgreSQL": {
+		Name:       "PostgreSQL License",
+		SPDX:       "PostgreSQL",
+		Category:   "permissive",
+		Commercial: true,
+		Modify:     true,
+		Distribute: true,
+		Patent:     false,
+	},
+	"PSF-2.0": {
+		Name:       "Python Software Foundation License 2.0",
+		SPDX:       "PSF-2.0",
+		Category:   "permissive",
+		Commercial: true,
+		Modify:     true,
+		Distribute: true,
+		Patent:     false,
+	},
+	"Unicode-3.0": {
+		Name:       "Unicode License v3",
+		SPDX:       "Unicode-3.0",
+		Category:   "permissive",
+		Commercial: true,
+		Modify:     true,
+		Distribute: true,
+		Patent:     false,
+	},
 }
 
 // AnalyzeLicense detects and analyzes licenses in a repository
 func AnalyzeLicense(client *github.Client, owner, repo string, fileTree []github.TreeEntry) (*LicenseAnalysis, error) {
 	analysis := &LicenseAnalysis{
-		OtherLicenses: []LicenseInfo{},
-		Warnings:      []string{},
-		Compatibility: "compatible",
-		LicenseScore:  100,
+		OtherLicenses:    []LicenseInfo{},
+		DeclaredLicenses: []DeclaredLicense{},
+		FileLicenses:     []FileLicense{},
+		Warnings:         []string{},
+		Compatibility:    "compatible",
+		LicenseScore:     100,
+	}
+
+	fetch := contentFetcher(client, owner, repo)
+
+	// License fields in package manifests
+	for _, path := range findLicenseManifests(fileTree) {
+		content, ok := fetch(path)
+		if !ok {
+			continue
+		}
+		if declared, ok := parseManifestLicense(path, []byte(content)); ok {
+			analysis.DeclaredLicenses = append(analysis.DeclaredLicenses, declared)
+		}
+	}
+
+	// SPDX headers in a sample of source files
+	headerFiles := maxSPDXHeaderFiles
+	if !client.HasToken() {
+		headerFiles = 0
+	}
+	for _, path := range sampleSourceFiles(fileTree, headerFiles) {
+		content, ok := fetch(path)
+		if !ok {
+			continue
+		}
+		if expr := extractSPDXHeader(content); expr != "" {
+			if parsed, err := ParseSPDXExpression(expr); err == nil {
+				expr = parsed.String()
+			}
+			analysis.FileLicenses = append(analysis.FileLicenses, FileLicense{Path: path, Expression: expr})
+		}
 	}
 
 	// Find license files
@@ -157,22 +413,18 @@ func AnalyzeLicense(client *github.Client, owner, repo string, fileTree []github
 	if len(licenseFiles) == 0 {
 		analysis.Warnings = append(analysis.Warnings, "No LICENSE file found")
 		analysis.LicenseScore = 50
+		analysis.MainLicense = declaredMainLicense(analysis.DeclaredLicenses)
 		return analysis, nil
 	}
 
 	// Analyze each license file
 	for i, path := range licenseFiles {
-		content, err := client.GetFileContent(owner, repo, path)
-		if err != nil {
+		content, ok := fetch(path)
+		if !ok {
 			continue
 		}
 
-		decoded, err := base64.StdEncoding.DecodeString(content)
-		if err != nil {
-			continue
-		}
-
-		license := detectLicense(string(decoded), path)
+		license := detectLicense(content, path)
 		if license != nil {
 			if i == 0 {
 				analysis.MainLicense = license
@@ -184,6 +436,8 @@ func AnalyzeLicense(client *github.Client, owner, repo string, fileTree []github
 
 	// Check for compatibility issues
 	if analysis.MainLicense != nil {
+		resolveLicenseVariants(analysis)
+		checkDeclaredLicenses(analysis)
 		checkCompatibility(analysis)
 	} else {
 		analysis.Warnings = append(analysis.Warnings, "Could not detect license type")
@@ -228,95 +482,290 @@ func findLicenseFiles(tree []github.TreeEntry) []string {
 	return files
 }
 
-// detectLicense detects license type from content
+// detectLicense identifies the license in a license file, preferring a full
+// text match and falling back to keywords with low confidence
 func detectLicense(content, sourcePath string) *LicenseInfo {
-	contentLower := strings.ToLower(content)
+	id, similarity := identifyLicenseText(content)
+	confidence := int(similarity*100 + 0.5)
+	if id == "" {
+		id = guessLicenseFromKeywords(content)
+		confidence = keywordMatchConfidence
+	}
+	if id == "" {
+		return nil
+	}
+
+	if isGNULicenseFamily(id) {
+		id += gnuLicenseSuffix(content)
+	}
+
+	license, ok := lookupLicense(id)
+	if !ok {
+		return nil
+	}
+	license.SourceFile = sourcePath
+	license.Confidence = confidence
+	return &license
+}
+
+// lookupLicense returns the description of an SPDX identifier, including
+// the -only and -or-later variants of GNU licenses
+func lookupLicense(id string) (LicenseInfo, bool) {
+	family, suffix := id, ""
+	for _, s := range []string{"-only", "-or-later"} {
+		if strings.HasSuffix(id, s) {
+			family, suffix = strings.TrimSuffix(id, s), s
+		}
+	}
+
+	license, ok := knownLicenses[family]
+	if !ok {
+		return LicenseInfo{}, false
+	}
+	license.SPDX = id
+	switch suffix {
+	case "-only":
+		license.Name += " only"
+	case "-or-later":
+		license.Name += " or later"
+	}
+	return license, true
+}
+
+// isGNULicenseFamily reports whether id is a GNU license without the
+// -only or -or-later suffix
+func isGNULicenseFamily(id string) bool {
+	return spdxDeprecatedGNUs[strings.ToLower(id)] != ""
+}
+
+// resolveLicenseVariants settles whether GNU licenses are "-only" or
+// "-or-later" when the license file doesn't say, using SPDX headers and
+// manifest declarations. Without either it assumes "-only".
+func resolveLicenseVariants(analysis *LicenseAnalysis) {
+	var hints []string
+	for _, fl := range analysis.FileLicenses {
+		hints = append(hints, spdxLicenseIDsOf(fl.Expression)...)
+	}
+	for _, dl := range analysis.DeclaredLicenses {
+		hints = append(hints, spdxLicenseIDsOf(dl.Expression)...)
+	}
 
-	// Check for specific license patterns
-	if strings.Contains(contentLower, "mit license") ||
-		strings.Contains(contentLower, "permission is hereby granted, free of charge") {
-		license := licensePatterns["mit"]
-		license.SourceFile = sourcePath
-		return &license
+	resolve := func(license *LicenseInfo) {
+		if !isGNULicenseFamily(license.SPDX) {
+			return
+		}
+		family, id := license.SPDX, license.SPDX+"-only"
+		resolved := false
+		for _, hint := range hints {
+			if hint == family+"-only" || hint == family+"-or-later" {
+				id, resolved = hint, true
+				break
+			}
+		}
+		if !resolved {
+			analysis.Warnings = append(analysis.Warnings, fmt.Sprintf(
+				"%s does not state whether later versions apply; assuming %s. Add SPDX-License-Identifier headers to make it explicit",
+				license.SourceFile, id))
+		}
+		if info, ok := lookupLicense(id); ok {
+			info.SourceFile, info.Confidence = license.SourceFile, license.Confidence
+			*license = info
+		}
 	}
 
-	if strings.Contains(contentLower, "apache license") &&
-		strings.Contains(contentLower, "version 2.0") {
-		license := licensePatterns["apache"]
-		license.SourceFile = sourcePath
-		return &license
+	resolve(analysis.MainLicense)
+	for i := range analysis.OtherLicenses {
+		resolve(&analysis.OtherLicenses[i])
 	}
+}
 
-	if strings.Contains(contentLower, "gnu general public license") {
-		if strings.Contains(contentLower, "version 3") {
-			license := licensePatterns["gpl-3"]
-			license.SourceFile = sourcePath
-			return &license
+// spdxLicenseIDsOf returns the license identifiers in an SPDX expression,
+// or nothing when it does not parse
+func spdxLicenseIDsOf(expr string) []string {
+	parsed, err := ParseSPDXExpression(expr)
+	if err != nil {
+		return nil
+	}
+	return parsed.Licenses()
+}
+
+// checkDeclaredLicenses warns when manifests or SPDX headers name a license
+// the license file does not grant
+func checkDeclaredLicenses(analysis *LicenseAnalysis) {
+	main := analysis.MainLicense
+
+	for _, declared := range analysis.DeclaredLicenses {
+		if !declared.Valid {
+			analysis.Warnings = append(analysis.Warnings, fmt.Sprintf(
+				"%s license %q is not a valid SPDX expression", declared.Source, declared.Expression))
+			continue
 		}
-		if strings.Contains(contentLower, "version 2") {
-			license := licensePatterns["gpl-2"]
-			license.SourceFile = sourcePath
-			return &license
+		parsed, _ := ParseSPDXExpression(declared.Expression)
+		if !parsed.Permits(main.SPDX) {
+			analysis.Compatibility = "warning"
+			analysis.Warnings = append(analysis.Warnings, fmt.Sprintf(
+				"%s declares %s but %s contains %s", declared.Source, declared.Expression, main.SourceFile, main.SPDX))
+			analysis.LicenseScore -= 10
 		}
 	}
 
-	if strings.Contains(contentLower, "gnu lesser general public license") ||
-		strings.Contains(contentLower, "gnu library general public license") {
-		license := licensePatterns["lgpl"]
-		license.SourceFile = sourcePath
-		return &license
+	var mismatched []FileLicense
+	for _, fl := range analysis.FileLicenses {
+		parsed, err := ParseSPDXExpression(fl.Expression)
+		if err != nil || !parsed.Permits(main.SPDX) {
+			mismatched = append(mismatched, fl)
+		}
+	}
+	if len(mismatched) > 0 {
+		analysis.Warnings = append(analysis.Warnings, fmt.Sprintf(
+			"%d of %d sampled source files have SPDX headers other than %s (e.g. %s: %s)",
+			len(mismatched), len(analysis.FileLicenses), main.SPDX, mismatched[0].Path, mismatched[0].Expression))
 	}
+}
 
-	if strings.Contains(contentLower, "gnu affero general public license") {
-		license := licensePatterns["agpl"]
-		license.SourceFile = sourcePath
-		return &license
+// declaredMainLicense describes the first known license declared in a
+// manifest, for repositories without a license file
+func declaredMainLicense(declared []DeclaredLicense) *LicenseInfo {
+	for _, dl := range declared {
+		for _, id := range spdxLicenseIDsOf(dl.Expression) {
+			if license, ok := lookupLicense(id); ok {
+				license.SourceFile = dl.Source
+				license.Confidence = 100
+				return &license
+			}
+		}
 	}
+	return nil
+}
 
-	if strings.Contains(contentLower, "bsd 3-clause") ||
-		(strings.Contains(contentLower, "redistribution") &&
-			strings.Contains(contentLower, "neither the name")) {
-		license := licensePatterns["bsd-3"]
-		license.SourceFile = sourcePath
-		return &license
+// findLicenseManifests returns the root package manifests that can declare a
+// license
+func findLicenseManifests(tree []github.TreeEntry) []string {
+	var files []string
+	for _, entry := range tree {
+		if entry.Type != "blob" {
+			continue
+		}
+		switch entry.Path {
+		case "package.json", "composer.json", "Cargo.toml", "pyproject.toml":
+			files = append(files, entry.Path)
+		}
 	}
+	return files
+}
 
-	if strings.Contains(contentLower, "bsd 2-clause") ||
-		(strings.Contains(contentLower, "redistribution") &&
-			!strings.Contains(contentLower, "neither the name") &&
-			strings.Contains(contentLower, "binary form")) {
-		license := licensePatterns["bsd-2"]
-		license.SourceFile = sourcePath
-		return &license
+// parseManifestLicense reads the license field of a package manifest.
+// Lists of licenses are alternatives and are joined with OR.
+func parseManifestLicense(path string, content []byte) (DeclaredLicense, bool) {
+	var raw string
+
+	switch path {
+	case "package.json", "composer.json":
+		var manifest struct {
+			License  json.RawMessage `json:"license"`
+			Licenses []struct {
+				Type string `json:"type"`
+			} `json:"licenses"`
+		}
+		if err := json.Unmarshal(content, &manifest); err != nil {
+			return DeclaredLicense{}, false
+		}
+		raw = jsonLicenseField(manifest.License)
+		if raw == "" {
+			var types []string
+			for _, l := range manifest.Licenses {
+				types = append(types, l.Type)
+			}
+			raw = strings.Join(types, " OR ")
+		}
+	case "Cargo.toml":
+		doc, err := parseTOML(content)
+		if err != nil {
+			return DeclaredLicense{}, false
+		}
+		// Older crates separate alternatives with a slash
+		raw = strings.ReplaceAll(tomlString(tomlTable(doc, "package"), "license"), "/", " OR ")
+	case "pyproject.toml":
+		doc, err := parseTOML(content)
+		if err != nil {
+			return DeclaredLicense{}, false
+		}
+		if project := tomlTable(doc, "project"); project != nil {
+			raw = tomlString(project, "license")
+			if raw == "" {
+				// PEP 621 table form: license = { text = "MIT" }
+				raw = tomlString(tomlTable(project, "license"), "text")
+			}
+		}
+		if raw == "" {
+			raw = tomlString(tomlTable(doc, "tool", "poetry"), "license")
+		}
 	}
 
-	if strings.Contains(contentLower, "isc license") {
-		license := licensePatterns["isc"]
-		license.SourceFile = sourcePath
-		return &license
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return DeclaredLicense{}, false
 	}
 
-	if strings.Contains(contentLower, "mozilla public license") {
-		license := licensePatterns["mpl"]
-		license.SourceFile = sourcePath
-		return &license
+	declared := DeclaredLicense{Source: path, Expression: raw}
+	if parsed, err := ParseSPDXExpression(raw); err == nil {
+		declared.Expression = parsed.String()
+		declared.Valid = true
 	}
+	return declared, true
+}
 
-	if strings.Contains(contentLower, "unlicense") ||
-		strings.Contains(contentLower, "this is free and unencumbered software") {
-		license := licensePatterns["unlicense"]
-		license.SourceFile = sourcePath
-		return &license
+// jsonLicenseField reads a license given as a string, an array of strings,
+// or a legacy {"type": "MIT"} object
+func jsonLicenseField(field json.RawMessage) string {
+	if len(field) == 0 {
+		return ""
 	}
 
-	if strings.Contains(contentLower, "cc0") ||
-		strings.Contains(contentLower, "creative commons zero") {
-		license := licensePatterns["cc0"]
-		license.SourceFile = sourcePath
-		return &license
+	var s string
+	if json.Unmarshal(field, &s) == nil {
+		return s
+	}
+	var list []string
+	if json.Unmarshal(field, &list) == nil {
+		return strings.Join(list, " OR ")
+	}
+	var obj struct {
+		Type string `json:"type"`
+	}
+	if json.Unmarshal(field, &obj) == nil {
+		return obj.Type
 	}
+	return ""
+}
 
-	return nil
+// sampleSourceFiles picks up to limit source files, shallowest first, skipping
+// vendored code whose headers describe third-party licenses
+func sampleSourceFiles(tree []github.TreeEntry, limit int) []string {
+	var files []string
+	for _, entry := range tree {
+		if entry.Type != "blob" || !isSourceFile(entry.Path) || isVendoredPath(entry.Path) {
+			continue
+		}
+		files = append(files, entry.Path)
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		return strings.Count(files[i], "/") < strings.Count(files[j], "/")
+	})
+	if len(files) > limit {
+		files = files[:limit]
+	}
+	return files
+}
+
+func isVendoredPath(path string) bool {
+	for _, dir := range []string{"vendor/", "node_modules/", "third_party/", "third-party/", "external/"} {
+		if strings.HasPrefix(path, dir) || strings.Contains(path, "/"+dir) {
+			return true
+		}
+	}
+	return false
 }
 
 // checkCompatibility checks for license compatibility issues
@@ -334,7 +783,7 @@ func checkCompatibility(analysis *LicenseAnalysis) {
 	}
 
 	// Check for AGPL
-	if analysis.MainLicense.SPDX == "AGPL-3.0" {
+	if strings.HasPrefix(analysis.MainLicense.SPDX, "AGPL-") {
 		analysis.Warnings = append(analysis.Warnings,
 			"AGPL requires source disclosure for network use")
 	}
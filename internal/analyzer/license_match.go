@@ -0,0 +1,224 @@
+// Package analyzer provides license detection and compliance analysis.
+// This file identifies license texts by similarity to the SPDX license list.
+package analyzer
+
+import (
+	"embed"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// licenseTexts holds reference texts from the SPDX license list. Files are
+// named after their SPDX identifier; GNU licenses are named after the version
+// family (e.g. GPL-3.0) since the -only and -or-later variants share one text.
+//
+// Coverage is the OSI-approved licenses in common use: the GNU family (GPL
+// 1.0-3.0, LGPL 2.0-3.0, AGPL-3.0), Apache-2.0, the BSD and MIT variants,
+// ISC, MPL 1.1 and 2.0, EPL 1.0 and 2.0, CDDL-1.0, MS-PL, MS-RL,
+// Artistic-2.0, UPL-1.0, BlueOak-1.0.0, NCSA, PostgreSQL, PSF-2.0, Zlib,
+// BSL-1.0, Unicode-3.0 and OFL-1.1, plus the public-domain dedications CC0-1.0,
+// Unlicense and WTFPL. Other license texts are left to keyword matching.
+//
+//go:embed licenses/*.txt
+var licenseTexts embed.FS
+
+// licenseMatchThreshold is the minimum similarity for a text match.
+// Reformatted or lightly edited copies of a license stay well above it,
+// while texts of different licenses score far below.
+const licenseMatchThreshold = 0.85
+
+// keywordMatchConfidence is reported when a license is only recognized by
+// keywords because its text does not match any reference text
+const keywordMatchConfidence = 40
+
+type licenseTemplate struct {
+	id     string
+	tokens map[string]struct{}
+}
+
+var (
+	licenseTemplatesOnce sync.Once
+	licenseTemplates     []licenseTemplate
+)
+
+var (
+	// Copyright lines vary per project and are not part of the license terms
+	copyrightLinePattern = regexp.MustCompile(`(?mi)^[\s#*/;-]*(copyright\s*(\(c\)|©|<|\d)|\(c\)|©).*$`)
+	urlPattern           = regexp.MustCompile(`(?i)(https?://|www\.)\S+`)
+	placeholderPattern   = regexp.MustCompile(`<[^>\n]*>|\[[^\]\n]*\]`)
+
+	// Matches the "Version 3, 29 June 2007" line heading every GNU license
+	gnuVersionLinePattern = regexp.MustCompile(`(?i)version \d(\.\d)?, (\d{1,2} )?[a-z]+ \d{4}`)
+)
+
+// loadLicenseTemplates tokenizes the embedded license texts once
+func loadLicenseTemplates() []licenseTemplate {
+	licenseTemplatesOnce.Do(func() {
+		entries, err := licenseTexts.ReadDir("licenses")
+		if err != nil {
+			return
+		}
+		for _, entry := range entries {
+			data, err := licenseTexts.ReadFile("licenses/" + entry.Name())
+			if err != nil {
+				continue
+			}
+			licenseTemplates = append(licenseTemplates, licenseTemplate{
+				id:     strings.TrimSuffix(entry.Name(), ".txt"),
+				tokens: licenseShingles(normalizeLicenseText(string(data))),
+			})
+		}
+	})
+	return licenseTemplates
+}
+
+// identifyLicenseText compares content with the reference license texts and
+// returns the closest SPDX identifier with its similarity (0-1). The
+// identifier is empty when no text reaches licenseMatchThreshold.
+func identifyLicenseText(content string) (string, float64) {
+	tokens := licenseShingles(normalizeLicenseText(content))
+	if len(tokens) == 0 {
+		return "", 0
+	}
+
+	bestID, best := "", 0.0
+	for _, tmpl := range loadLicenseTemplates() {
+		if score := diceCoefficient(tokens, tmpl.tokens); score > best {
+			bestID, best = tmpl.id, score
+		}
+	}
+	if best < licenseMatchThreshold {
+		return "", best
+	}
+	return bestID, best
+}
+
+// normalizeLicenseText reduces a license text to its lowercase words,
+// ignoring copyright lines, placeholders, URLs, punctuation, list markers
+// and the "How to Apply" appendix of GNU licenses
+func normalizeLicenseText(content string) []string {
+	text := strings.ToLower(content)
+	if idx := strings.Index(text, "end of terms and conditions"); idx >= 0 {
+		text = text[:idx]
+	}
+	text = copyrightLinePattern.ReplaceAllString(text, "")
+	text = urlPattern.ReplaceAllString(text, "")
+	text = placeholderPattern.ReplaceAllString(text, "")
+	text = strings.ReplaceAll(text, "licence", "license")
+
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	// Drop list markers such as "1.", "(a)" and "iv."
+	filtered := words[:0]
+	for _, w := range words {
+		if len(w) == 1 || isRomanNumeral(w) || strings.Trim(w, "0123456789") == "" {
+			continue
+		}
+		filtered = append(filtered, w)
+	}
+	return filtered
+}
+
+func isRomanNumeral(w string) bool {
+	switch w {
+	case "ii", "iii", "iv", "vi", "vii", "viii", "ix", "xi", "xii":
+		return true
+	}
+	return false
+}
+
+// licenseShingles returns the set of adjacent word pairs, which tells apart
+// licenses that share a vocabulary but differ in wording
+func licenseShingles(words []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(words))
+	for i := 0; i+1 < len(words); i++ {
+		set[words[i]+" "+words[i+1]] = struct{}{}
+	}
+	return set
+}
+
+// diceCoefficient returns the Sørensen–Dice similarity of two sets
+func diceCoefficient(a, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	shared := 0
+	for token := range a {
+		if _, ok := b[token]; ok {
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(a)+len(b))
+}
+
+// gnuLicenseSuffix reads the notice ahead of a GNU license text for the
+// version the project chose, returning "-or-later", "-only", or "" when the
+// notice doesn't say. Short files without the license text are read whole.
+func gnuLicenseSuffix(content string) string {
+	notice := content
+	if loc := gnuVersionLinePattern.FindStringIndex(content); loc != nil {
+		notice = content[:loc[0]]
+	} else if len(content) > 2000 {
+		// The license terms themselves mention "any later version"
+		return ""
+	}
+	notice = strings.ToLower(notice)
+
+	switch {
+	case strings.Contains(notice, "or later") || strings.Contains(notice, "any later version") ||
+		strings.Contains(notice, "or-later"):
+		return "-or-later"
+	case strings.Contains(notice, "only"):
+		return "-only"
+	}
+	return ""
+}
+
+// guessLicenseFromKeywords recognizes a license by characteristic phrases.
+// It is a fallback for texts too heavily edited to match a reference text.
+func guessLicenseFromKeywords(content string) string {
+	contentLower := strings.ToLower(content)
+
+	switch {
+	case strings.Contains(contentLower, "gnu affero general public license"):
+		return "AGPL-3.0"
+	case strings.Contains(contentLower, "gnu lesser general public license") ||
+		strings.Contains(contentLower, "gnu library general public license"):
+		if strings.Contains(contentLower, "version 2.1") {
+			return "LGPL-2.1"
+		}
+		return "LGPL-3.0"
+	case strings.Contains(contentLower, "gnu general public license"):
+		if strings.Contains(contentLower, "version 3") {
+			return "GPL-3.0"
+		}
+		if strings.Contains(contentLower, "version 2") {
+			return "GPL-2.0"
+		}
+	case strings.Contains(contentLower, "apache license") && strings.Contains(contentLower, "version 2.0"):
+		return "Apache-2.0"
+	case strings.Contains(contentLower, "mozilla public license"):
+		return "MPL-2.0"
+	case strings.Contains(contentLower, "mit license") ||
+		strings.Contains(contentLower, "permission is hereby granted, free of charge"):
+		return "MIT"
+	case strings.Contains(contentLower, "redistribution") && strings.Contains(contentLower, "neither the name"):
+		return "BSD-3-Clause"
+	case strings.Contains(contentLower, "redistribution") && strings.Contains(contentLower, "binary form"):
+		return "BSD-2-Clause"
+	case strings.Contains(contentLower, "isc license"):
+		return "ISC"
+	case strings.Contains(contentLower, "this is free and unencumbered software"):
+		return "Unlicense"
+	case strings.Contains(contentLower, "creative commons zero") || strings.Contains(contentLower, "cc0 1.0"):
+		return "CC0-1.0"
+	}
+	return ""
+}
@@ -0,0 +1,433 @@
+// Package analyzer provides license detection and compliance analysis.
+// This file checks dependency licenses against an allow/flag/deny policy.
+//
+// Example policy (~/.repo-lyzer/license-policy.yml):
+//
+//	allow: [MIT, Apache-2.0, BSD-*, ISC]
+//	flag:                    # Allowed after legal review
+//	  - LGPL-2.1             # Matches -only and -or-later
+//	  - MPL-2.0
+//	deny: [GPL-*, AGPL-*]
+//	unknown: flag            # Unresolved licenses: allow (default), flag or deny
+//	unlisted: flag           # Licenses on no list: allow, flag or deny
+//	packages:                # Per-package decisions, e.g. after a review
+//	  some-gpl-tool: allow
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Compliance statuses of a dependency license
+const (
+	LicenseAllowed = "allowed"
+	LicenseFlagged = "flagged"
+	LicenseDenied  = "denied"
+)
+
+// LicensePolicy lists SPDX identifiers by how dependencies using them are
+// treated. Entries match case-insensitively; a trailing "*" matches any
+// suffix, and a GNU identifier without -only/-or-later matches both.
+type LicensePolicy struct {
+	Allow    []string          `json:"allow"`
+	Flag     []string          `json:"flag"`
+	Deny     []string          `json:"deny"`
+	Unknown  string            `json:"unknown"`  // Status for unresolved or invalid licenses, allowed by default
+	Unlisted string            `json:"unlisted"` // Status for licenses on no list
+	Packages map[string]string `json:"packages"` // Package name -> status override
+	Source   string            `json:"source"`   // Policy file, or "default"
+}
+
+// DependencyLicense is the compliance result for one dependency
+type DependencyLicense struct {
+	Package   string `json:"package"`
+	Version   string `json:"version,omitempty"`
+	Ecosystem string `json:"ecosystem"`
+	Direct    bool   `json:"direct"`
+	License   string `json:"license"`          // SPDX expression, empty when unresolved
+	Source    string `json:"source,omitempty"` // Where the license was found
+	Status    string `json:"status"`           // "allowed", "flagged", "denied"
+	Reason    string `json:"reason,omitempty"`
+}
+
+// LicenseComplianceReport holds the policy evaluation of all dependencies
+type LicenseComplianceReport struct {
+	Policy       string              `json:"policy"` // Policy file, or "default"
+	Dependencies []DependencyLicense `json:"dependencies"`
+	Allowed      int                 `json:"allowed"`
+	Flagged      int                 `json:"flagged"`
+	Denied       int                 `json:"denied"`
+	Unresolved   int                 `json:"unresolved"` // Dependencies whose license could not be determined
+	Compliant    bool                `json:"compliant"`  // No dependency is denied
+}
+
+// DefaultLicensePolicy allows permissive licenses and flags copyleft ones
+// for review. Licenses of most registries can't be resolved offline, so
+// unresolved dependencies are counted but not flagged.
+func DefaultLicensePolicy() *LicensePolicy {
+	return &LicensePolicy{
+		Allow: []string{
+			"MIT", "MIT-0", "Apache-2.0", "BSD-*", "0BSD", "ISC", "Unlicense", "CC0-1.0",
+			"Zlib", "BSL-1.0", "WTFPL", "Python-2.0", "PSF-2.0", "BlueOak-1.0.0", "UPL-1.0",
+		},
+		Flag:     []string{"LGPL-*", "MPL-*", "EPL-*", "CDDL-*", "GPL-*", "AGPL-*"},
+		Unknown:  LicenseAllowed,
+		Unlisted: LicenseFlagged,
+		Source:   "default",
+	}
+}
+
+// DefaultLicensePolicyPath returns ~/.repo-lyzer/license-policy.yml
+func DefaultLicensePolicyPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".repo-lyzer", "license-policy.yml"), nil
+}
+
+// LoadLicensePolicy reads a YAML policy file. With an empty path it reads
+// the default location and falls back to DefaultLicensePolicy when no file
+// exists there.
+func LoadLicensePolicy(path string) (*LicensePolicy, error) {
+	explicit := path != ""
+	if !explicit {
+		var err error
+		if path, err = DefaultLicensePolicyPath(); err != nil {
+			return DefaultLicensePolicy(), nil
+		}
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && os.IsNotExist(err) {
+			return DefaultLicensePolicy(), nil
+		}
+		return nil, err
+	}
+
+	policy, err := parseLicensePolicy(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid license policy %s: %w", path, err)
+	}
+	policy.Source = path
+	return policy, nil
+}
+
+// parseLicensePolicy decodes a YAML license policy
+func parseLicensePolicy(content []byte) (*LicensePolicy, error) {
+	doc, err := parseYAML(content)
+	if err != nil {
+		return nil, err
+	}
+	root := yamlMap(doc)
+	if root == nil {
+		return nil, fmt.Errorf("expected a mapping with allow, flag and deny lists")
+	}
+
+	policy := &LicensePolicy{
+		Allow:    yamlStrings(root, "allow"),
+		Flag:     yamlStrings(root, "flag"),
+		Deny:     yamlStrings(root, "deny"),
+		Unknown:  LicenseAllowed,
+		Unlisted: LicenseFlagged,
+		Packages: make(map[string]string),
+	}
+
+	for key, target := range map[string]*string{"unknown": &policy.Unknown, "unlisted": &policy.Unlisted} {
+		if value := yamlString(root, key); value != "" {
+			status, ok := policyStatus(value)
+			if !ok {
+				return nil, fmt.Errorf("%s: unknown action %q (want allow, flag or deny)", key, value)
+			}
+			*target = status
+		}
+	}
+
+	for name := range yamlMap(root, "packages") {
+		status, ok := policyStatus(yamlString(yamlMap(root, "packages"), name))
+		if !ok {
+			return nil, fmt.Errorf("packages.%s: want allow, flag or deny", name)
+		}
+		policy.Packages[name] = status
+	}
+	return policy, nil
+}
+
+// policyStatus maps a policy action to a compliance status
+func policyStatus(action string) (string, bool) {
+	switch strings.ToLower(strings.TrimSpace(action)) {
+	case "allow", "allowed":
+		return LicenseAllowed, true
+	case "flag", "flagged", "review":
+		return LicenseFlagged, true
+	case "deny", "denied", "forbid", "forbidden":
+		return LicenseDenied, true
+	}
+	return "", false
+}
+
+// statusRank orders statuses from best to worst
+func statusRank(status string) int {
+	switch status {
+	case LicenseAllowed:
+		return 0
+	case LicenseFlagged:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Evaluate returns the status of a dependency with the given license
+// expression, and why. An OR expression takes its best alternative and an
+// AND expression its worst part.
+func (p *LicensePolicy) Evaluate(pkg, expression string) (string, string) {
+	if status, ok := p.Packages[pkg]; ok {
+		return status, "package exception in policy"
+	}
+	if expression == "" {
+		return p.Unknown, "license could not be determined"
+	}
+
+	parsed, err := ParseSPDXExpression(expression)
+	if err != nil {
+		return p.Unknown, "license is not a valid SPDX expression"
+	}
+
+	status, license := p.evaluate(parsed)
+	switch {
+	case status == LicenseAllowed:
+		return status, ""
+	case !p.listed(license):
+		return status, license + " is not listed in the policy"
+	case status == LicenseFlagged:
+		return status, license + " requires review"
+	default:
+		return status, license + " is not allowed"
+	}
+}
+
+// evaluate returns the status of an expression and the license deciding it
+func (p *LicensePolicy) evaluate(e *SPDXExpression) (string, string) {
+	if e.Op == "" {
+		return p.classify(e.License), e.License
+	}
+
+	best := e.Op == "OR"
+	status, license := "", ""
+	for _, operand := range e.Operands {
+		s, l := p.evaluate(operand)
+		if status == "" || (best && statusRank(s) < statusRank(status)) || (!best && statusRank(s) > statusRank(status)) {
+			status, license = s, l
+		}
+	}
+	return status, license
+}
+
+// classify returns the status of a single license. Deny entries win over
+// flag entries, which win over allow entries.
+func (p *LicensePolicy) classify(license string) string {
+	switch {
+	case matchesLicensePattern(p.Deny, license):
+		return LicenseDenied
+	case matchesLicensePattern(p.Flag, license):
+		return LicenseFlagged
+	case matchesLicensePattern(p.Allow, license):
+		return LicenseAllowed
+	}
+	return p.Unlisted
+}
+
+func (p *LicensePolicy) listed(license string) bool {
+	return matchesLicensePattern(p.Deny, license) || matchesLicensePattern(p.Flag, license) ||
+		matchesLicensePattern(p.Allow, license)
+}
+
+func matchesLicensePattern(patterns []string, license string) bool {
+	id := strings.ToLower(license)
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		switch {
+		case strings.HasSuffix(pattern, "*"):
+			if strings.HasPrefix(id, strings.TrimSuffix(pattern, "*")) {
+				return true
+			}
+		case isGNULicenseFamily(pattern):
+			if id == pattern+"-only" || id == pattern+"-or-later" {
+				return true
+			}
+		case id == pattern:
+			return true
+		}
+	}
+	return false
+}
+
+// licenseCaches locates package metadata left on disk by package managers
+type licenseCaches struct {
+	goModCache string // GOMODCACHE, e.g. ~/go/pkg/mod
+	cargoHome  string // CARGO_HOME, e.g. ~/.cargo
+}
+
+func defaultLicenseCaches() licenseCaches {
+	home, _ := os.UserHomeDir()
+
+	caches := licenseCaches{
+		goModCache: os.Getenv("GOMODCACHE"),
+		cargoHome:  os.Getenv("CARGO_HOME"),
+	}
+	if caches.goModCache == "" {
+		gopath := os.Getenv("GOPATH")
+		if gopath == "" && home != "" {
+			gopath = filepath.Join(home, "go")
+		}
+		if gopath != "" {
+			caches.goModCache = filepath.Join(filepath.SplitList(gopath)[0], "pkg", "mod")
+		}
+	}
+	if caches.cargoHome == "" && home != "" {
+		caches.cargoHome = filepath.Join(home, ".cargo")
+	}
+	return caches
+}
+
+// CheckLicenseCompliance resolves the license of every dependency and
+// evaluates it against the policy. Licenses are read from lock file
+// metadata (package-lock.json, composer.lock) and from the local Go module
+// and Cargo registry caches; anything else is reported as unresolved.
+func CheckLicenseCompliance(deps *DependencyAnalysis, policy *LicensePolicy) *LicenseComplianceReport {
+	return checkLicenseCompliance(deps, policy, defaultLicenseCaches())
+}
+
+func checkLicenseCompliance(deps *DependencyAnalysis, policy *LicensePolicy, caches licenseCaches) *LicenseComplianceReport {
+	if policy == nil {
+		policy = DefaultLicensePolicy()
+	}
+	report := &LicenseComplianceReport{Policy: policy.Source, Dependencies: []DependencyLicense{}}
+	if deps == nil {
+		report.Compliant = true
+		return report
+	}
+
+	// Licenses recorded in lock files, keyed by ecosystem and package
+	locked := make(map[string]string)
+	for _, lf := range deps.LockFiles {
+		for _, pkg := range lf.Packages {
+			if pkg.License != "" {
+				locked[mapEcosystem(lf.FileType)+"|"+strings.ToLower(pkg.Name)] = pkg.License
+			}
+		}
+	}
+
+	seen := make(map[string]int)
+	for _, target := range scanTargets(deps) {
+		key := target.ecosystem + "|" + strings.ToLower(target.name)
+		if i, ok := seen[key]; ok {
+			report.Dependencies[i].Direct = report.Dependencies[i].Direct || target.direct
+			continue
+		}
+		seen[key] = len(report.Dependencies)
+
+		dep := DependencyLicense{
+			Package:   target.name,
+			Version:   target.version,
+			Ecosystem: target.ecosystem,
+			Direct:    target.direct,
+		}
+		if license, ok := locked[key]; ok {
+			dep.License, dep.Source = license, "lock file"
+		} else {
+			dep.License, dep.Source = caches.lookup(target.ecosystem, target.name, target.version)
+		}
+		if parsed, err := ParseSPDXExpression(dep.License); err == nil {
+			dep.License = parsed.String()
+		}
+
+		dep.Status, dep.Reason = policy.Evaluate(dep.Package, dep.License)
+		report.Dependencies = append(report.Dependencies, dep)
+	}
+
+	for _, dep := range report.Dependencies {
+		switch dep.Status {
+		case LicenseAllowed:
+			report.Allowed++
+		case LicenseFlagged:
+			report.Flagged++
+		default:
+			report.Denied++
+		}
+		if dep.License == "" {
+			report.Unresolved++
+		}
+	}
+	report.Compliant = report.Denied == 0
+
+	// Worst first, then by name
+	sort.SliceStable(report.Dependencies, func(i, j int) bool {
+		a, b := report.Dependencies[i], report.Dependencies[j]
+		if statusRank(a.Status) != statusRank(b.Status) {
+			return statusRank(a.Status) > statusRank(b.Status)
+		}
+		return strings.ToLower(a.Package) < strings.ToLower(b.Package)
+	})
+	return report
+}
+
+// lookup reads a package's license from the local package manager caches.
+// Only exact versions can be found there.
+func (c licenseCaches) lookup(ecosystem, name, version string) (string, string) {
+	if !isExactVersion(version) {
+		return "", ""
+	}
+
+	switch ecosystem {
+	case "Go":
+		if c.goModCache == "" {
+			return "", ""
+		}
+		dir := filepath.Join(c.goModCache, filepath.FromSlash(escapeModulePath(name))+"@"+version)
+		for _, file := range []string{"LICENSE", "LICENSE.md", "LICENSE.txt", "LICENCE", "COPYING"} {
+			content, err := os.ReadFile(filepath.Join(dir, file))
+			if err != nil {
+				continue
+			}
+			if license := detectLicense(string(content), file); license != nil {
+				return license.SPDX, "Go module cache"
+			}
+		}
+	case "crates.io":
+		if c.cargoHome == "" {
+			return "", ""
+		}
+		manifests, _ := filepath.Glob(filepath.Join(c.cargoHome, "registry", "src", "*", name+"-"+version, "Cargo.toml"))
+		for _, path := range manifests {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				continue
+			}
+			if declared, ok := parseManifestLicense("Cargo.toml", content); ok {
+				return declared.Expression, "Cargo registry cache"
+			}
+		}
+	}
+	return "", ""
+}
+
+// escapeModulePath applies the Go module cache's case encoding, which
+// replaces each upper-case letter with "!" and its lower-case form
+func escapeModulePath(path string) string {
+	var b strings.Builder
+	for _, r := range path {
+		if r >= 'A' && r <= 'Z' {
+			b.WriteByte('!')
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,139 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testLicensePolicy = `
+allow: [MIT, Apache-2.0, BSD-*, ISC]
+flag:
+  - LGPL-2.1   # Matches -only and -or-later
+  - MPL-2.0
+deny:
+  - GPL-*
+  - AGPL-*
+unknown: deny
+unlisted: flag
+packages:
+  internal-tool: allow
+`
+
+func TestParseLicensePolicy(t *testing.T) {
+	policy, err := parseLicensePolicy([]byte(testLicensePolicy))
+	if err != nil {
+		t.Fatalf("parseLicensePolicy() error = %v", err)
+	}
+	if len(policy.Allow) != 4 || len(policy.Flag) != 2 || len(policy.Deny) != 2 {
+		t.Errorf("policy lists = %v / %v / %v", policy.Allow, policy.Flag, policy.Deny)
+	}
+	if policy.Unknown != LicenseDenied || policy.Unlisted != LicenseFlagged {
+		t.Errorf("Unknown = %s, Unlisted = %s; want denied, flagged", policy.Unknown, policy.Unlisted)
+	}
+	if policy.Packages["internal-tool"] != LicenseAllowed {
+		t.Errorf("Packages = %v, want internal-tool allowed", policy.Packages)
+	}
+
+	if _, err := parseLicensePolicy([]byte("unknown: maybe\n")); err == nil {
+		t.Error("parseLicensePolicy() should reject unknown actions")
+	}
+}
+
+func TestLicensePolicyEvaluate(t *testing.T) {
+	policy, err := parseLicensePolicy([]byte(testLicensePolicy))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		pkg        string
+		expression string
+		want       string
+	}{
+		{"a", "MIT", LicenseAllowed},
+		{"b", "BSD-3-Clause", LicenseAllowed},
+		{"c", "LGPL-2.1-or-later", LicenseFlagged},
+		{"d", "GPL-3.0-only", LicenseDenied},
+		{"e", "MIT OR GPL-3.0-only", LicenseAllowed},
+		{"f", "MIT AND GPL-2.0-or-later", LicenseDenied},
+		{"g", "(MIT OR GPL-2.0-only) AND MPL-2.0", LicenseFlagged},
+		{"h", "EPL-2.0", LicenseFlagged},
+		{"i", "", LicenseDenied},
+		{"j", "Some Custom License", LicenseDenied},
+		{"internal-tool", "GPL-3.0-only", LicenseAllowed},
+	}
+	for _, tt := range tests {
+		if got, reason := policy.Evaluate(tt.pkg, tt.expression); got != tt.want {
+			t.Errorf("Evaluate(%s, %q) = %s (%s), want %s", tt.pkg, tt.expression, got, reason, tt.want)
+		}
+	}
+
+	if _, reason := policy.Evaluate("h", "EPL-2.0"); reason != "EPL-2.0 is not listed in the policy" {
+		t.Errorf("reason = %q", reason)
+	}
+}
+
+func TestCheckLicenseCompliance(t *testing.T) {
+	caches := licenseCaches{goModCache: t.TempDir(), cargoHome: t.TempDir()}
+
+	moduleDir := filepath.Join(caches.goModCache, "github.com", "!burnt!sushi", "toml@v1.3.2")
+	if err := os.MkdirAll(moduleDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(moduleDir, "LICENSE"), []byte(mitText), 0644)
+
+	crateDir := filepath.Join(caches.cargoHome, "registry", "src", "index.crates.io-6f17d22bba15001f", "serde-1.0.190")
+	if err := os.MkdirAll(crateDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(crateDir, "Cargo.toml"), []byte("[package]\nname = \"serde\"\nlicense = \"MIT OR Apache-2.0\"\n"), 0644)
+
+	deps := &DependencyAnalysis{
+		Files: []DependencyFile{
+			{Filename: "go.mod", FileType: "go", Dependencies: []Dependency{
+				{Name: "github.com/BurntSushi/toml", Version: "v1.3.2", Type: "production"},
+			}},
+			{Filename: "Cargo.toml", FileType: "rust", Dependencies: []Dependency{
+				{Name: "serde", Version: "1.0", Resolved: "1.0.190", Type: "production"},
+			}},
+			{Filename: "requirements.txt", FileType: "python", Dependencies: []Dependency{
+				{Name: "requests", Version: "2.31.0", Type: "production"},
+			}},
+		},
+		LockFiles: []LockFile{{Filename: "package-lock.json", FileType: "npm", Packages: []LockedPackage{
+			{Name: "readline-sync", Version: "1.4.10", Direct: true, License: "GPL-3.0"},
+			{Name: "lodash", Version: "4.17.21", License: "MIT"},
+		}}},
+	}
+
+	report := checkLicenseCompliance(deps, DefaultLicensePolicy(), caches)
+	if len(report.Dependencies) != 5 {
+		t.Fatalf("Dependencies = %+v, want 5 entries", report.Dependencies)
+	}
+
+	byName := make(map[string]DependencyLicense)
+	for _, d := range report.Dependencies {
+		byName[d.Package] = d
+	}
+	if d := byName["github.com/BurntSushi/toml"]; d.License != "MIT" || d.Source != "Go module cache" || d.Status != LicenseAllowed {
+		t.Errorf("toml = %+v, want MIT from the Go module cache", d)
+	}
+	if d := byName["serde"]; d.License != "MIT OR Apache-2.0" || d.Status != LicenseAllowed {
+		t.Errorf("serde = %+v, want MIT OR Apache-2.0 from the Cargo registry", d)
+	}
+	if d := byName["readline-sync"]; d.License != "GPL-3.0-only" || d.Status != LicenseFlagged {
+		t.Errorf("readline-sync = %+v, want GPL-3.0-only flagged", d)
+	}
+	if d := byName["requests"]; d.License != "" || d.Status != LicenseAllowed {
+		t.Errorf("requests = %+v, want an unresolved license counted but not flagged", d)
+	}
+
+	if report.Allowed != 4 || report.Flagged != 1 || report.Unresolved != 1 || !report.Compliant {
+		t.Errorf("report counts = %d allowed, %d flagged, %d unresolved, compliant %v",
+			report.Allowed, report.Flagged, report.Unresolved, report.Compliant)
+	}
+	if report.Dependencies[0].Status != LicenseFlagged {
+		t.Errorf("Dependencies should list the worst status first, got %s", report.Dependencies[0].Status)
+	}
+}
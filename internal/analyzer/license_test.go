@@ -0,0 +1,217 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+)
+
+const mitText = `MIT License
+
+Copyright (c) 2024 Jane Doe
+Copyright (c) 2023 Example Corp.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+`
+
+const bsd2Text = `Copyright (c) 2015, The Example Authors
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+* Redistributions of source code must retain the above copyright notice, this
+  list of conditions and the following disclaimer.
+
+* Redistributions in binary form must reproduce the above copyright notice,
+  this list of conditions and the following disclaimer in the documentation
+  and/or other materials provided with the distribution.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+`
+
+// bsd3Text adds the non-endorsement clause to bsd2Text
+var bsd3Text = strings.Replace(bsd2Text, "THIS SOFTWARE IS PROVIDED", `* Neither the name of Example Corp. nor the names of its
+  contributors may be used to endorse or promote products derived from
+  this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED`, 1)
+
+func referenceLicenseText(t *testing.T, name string) string {
+	t.Helper()
+	data, err := licenseTexts.ReadFile("licenses/" + name + ".txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestDetectLicense(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"MIT with copyright lines", mitText, "MIT"},
+		{"BSD 2-clause", bsd2Text, "BSD-2-Clause"},
+		{"BSD 3-clause", bsd3Text, "BSD-3-Clause"},
+		{"Apache", referenceLicenseText(t, "Apache-2.0"), "Apache-2.0"},
+		{"GPL-3.0 without notice", referenceLicenseText(t, "GPL-3.0"), "GPL-3.0"},
+		{"AGPL-3.0 without notice", referenceLicenseText(t, "AGPL-3.0"), "AGPL-3.0"},
+		{"LGPL-2.1 without notice", referenceLicenseText(t, "LGPL-2.1"), "LGPL-2.1"},
+		{"GPL-2.0 or later", "This program is licensed under the GPL, version 2 or (at your option) any later version.\n\n" +
+			referenceLicenseText(t, "GPL-2.0"), "GPL-2.0-or-later"},
+		{"GPL-3.0 only", "Licensed under GPL-3.0-only.\n\n" + referenceLicenseText(t, "GPL-3.0"), "GPL-3.0-only"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := detectLicense(tt.content, "LICENSE")
+			if got == nil {
+				t.Fatalf("detectLicense() = nil, want %s", tt.want)
+			}
+			if got.SPDX != tt.want {
+				t.Errorf("detectLicense() = %s, want %s", got.SPDX, tt.want)
+			}
+			if got.Confidence < 90 {
+				t.Errorf("Confidence = %d, want a full text match", got.Confidence)
+			}
+		})
+	}
+}
+
+func TestIdentifyLicenseText_ReferenceTexts(t *testing.T) {
+	entries, err := licenseTexts.ReadDir("licenses")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		id := strings.TrimSuffix(entry.Name(), ".txt")
+		t.Run(id, func(t *testing.T) {
+			if got, score := identifyLicenseText(referenceLicenseText(t, id)); got != id {
+				t.Errorf("identifyLicenseText() = %s (%.2f), want %s", got, score, id)
+			}
+			if _, ok := lookupLicense(id); !ok {
+				t.Errorf("lookupLicense(%s) found no description", id)
+			}
+		})
+	}
+}
+
+func TestDetectLicense_KeywordFallback(t *testing.T) {
+	got := detectLicense("This project is released under the MIT License.", "LICENSE")
+	if got == nil || got.SPDX != "MIT" {
+		t.Fatalf("detectLicense() = %+v, want MIT", got)
+	}
+	if got.Confidence != keywordMatchConfidence {
+		t.Errorf("Confidence = %d, want %d for a keyword match", got.Confidence, keywordMatchConfidence)
+	}
+}
+
+func TestIdentifyLicenseText_NoMatch(t *testing.T) {
+	readme := "# Project\n\nLike many projects, we considered the MIT license and the Apache License, Version 2.0, " +
+		"but chose to permit redistribution in binary form only.\n"
+	if id, score := identifyLicenseText(readme); id != "" {
+		t.Errorf("identifyLicenseText(readme) = %s (%.2f), want no match", id, score)
+	}
+}
+
+func TestResolveLicenseVariants(t *testing.T) {
+	analysis := &LicenseAnalysis{
+		MainLicense:  detectLicense(referenceLicenseText(t, "GPL-3.0"), "COPYING"),
+		FileLicenses: []FileLicense{{Path: "main.c", Expression: "GPL-3.0-or-later"}},
+	}
+	resolveLicenseVariants(analysis)
+	if analysis.MainLicense.SPDX != "GPL-3.0-or-later" || len(analysis.Warnings) != 0 {
+		t.Errorf("MainLicense = %s, warnings %v; want GPL-3.0-or-later from the SPDX header", analysis.MainLicense.SPDX, analysis.Warnings)
+	}
+
+	analysis = &LicenseAnalysis{MainLicense: detectLicense(referenceLicenseText(t, "GPL-2.0"), "COPYING")}
+	resolveLicenseVariants(analysis)
+	if analysis.MainLicense.SPDX != "GPL-2.0-only" || len(analysis.Warnings) != 1 {
+		t.Errorf("MainLicense = %s, warnings %v; want GPL-2.0-only with a warning", analysis.MainLicense.SPDX, analysis.Warnings)
+	}
+}
+
+func TestParseManifestLicense(t *testing.T) {
+	tests := []struct {
+		path    string
+		content string
+		want    string
+		valid   bool
+	}{
+		{"package.json", `{"license": "(MIT OR Apache-2.0)"}`, "MIT OR Apache-2.0", true},
+		{"package.json", `{"license": {"type": "ISC"}}`, "ISC", true},
+		{"package.json", `{"licenses": [{"type": "MIT"}, {"type": "GPL-2.0"}]}`, "MIT OR GPL-2.0-only", true},
+		{"composer.json", `{"license": ["LGPL-2.1-only", "GPL-3.0-or-later"]}`, "LGPL-2.1-only OR GPL-3.0-or-later", true},
+		{"Cargo.toml", "[package]\nname = \"demo\"\nlicense = \"MIT/Apache-2.0\"\n", "MIT OR Apache-2.0", true},
+		{"pyproject.toml", "[project]\nname = \"demo\"\nlicense = { text = \"BSD-3-Clause\" }\n", "BSD-3-Clause", true},
+		{"pyproject.toml", "[tool.poetry]\nlicense = \"Apache Software License\"\n", "Apache Software License", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseManifestLicense(tt.path, []byte(tt.content))
+		if !ok {
+			t.Errorf("parseManifestLicense(%s, %s) found no license", tt.path, tt.content)
+			continue
+		}
+		if got.Expression != tt.want || got.Valid != tt.valid {
+			t.Errorf("parseManifestLicense(%s, %s) = %q (valid %v), want %q (valid %v)",
+				tt.path, tt.content, got.Expression, got.Valid, tt.want, tt.valid)
+		}
+	}
+
+	if _, ok := parseManifestLicense("package.json", []byte(`{"name": "demo"}`)); ok {
+		t.Error("parseManifestLicense() should report no license when the field is missing")
+	}
+}
+
+func TestCheckDeclaredLicenses(t *testing.T) {
+	analysis := &LicenseAnalysis{
+		MainLicense: detectLicense(mitText, "LICENSE"),
+		DeclaredLicenses: []DeclaredLicense{
+			{Source: "package.json", Expression: "MIT OR Apache-2.0", Valid: true},
+			{Source: "Cargo.toml", Expression: "Apache-2.0", Valid: true},
+		},
+		FileLicenses: []FileLicense{
+			{Path: "src/a.rs", Expression: "MIT"},
+			{Path: "src/b.rs", Expression: "GPL-2.0-only"},
+		},
+		Compatibility: "compatible",
+		LicenseScore:  100,
+	}
+	checkDeclaredLicenses(analysis)
+
+	if analysis.Compatibility != "warning" || analysis.LicenseScore != 90 {
+		t.Errorf("Compatibility = %s, score %d; want warning, 90", analysis.Compatibility, analysis.LicenseScore)
+	}
+	if len(analysis.Warnings) != 2 ||
+		!strings.Contains(analysis.Warnings[0], "Cargo.toml declares Apache-2.0") ||
+		!strings.Contains(analysis.Warnings[1], "src/b.rs: GPL-2.0-only") {
+		t.Errorf("Warnings = %v", analysis.Warnings)
+	}
+}
@@ -0,0 +1,993 @@
+// Package analyzer provides analysis functions for GitHub repositories.
+// This file contains lock file parsers that recover the exact resolved
+// versions and the full transitive dependency set of a project.
+package analyzer
+
+import (
+	"bufio"
+	"encoding/json"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PackageRef identifies a package at a specific resolved version.
+type PackageRef struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// LockedPackage is a package pinned to an exact version by a lock file.
+type LockedPackage struct {
+	Name         string       `json:"name"`
+	Version      string       `json:"version"`                // Exact resolved version (e.g., "4.17.21")
+	Direct       bool         `json:"direct"`                 // Declared in the project manifest rather than pulled in transitively
+	Dev          bool         `json:"dev,omitempty"`          // Only needed for development
+	License      string       `json:"license,omitempty"`      // Declared license, when the lock file records it
+	Dependencies []PackageRef `json:"dependencies,omitempty"` // Packages this package depends on
+}
+
+// LockFile holds the packages resolved by a single lock file.
+type LockFile struct {
+	Filename        string          `json:"filename"`  // Full path to the file (e.g., "web/yarn.lock")
+	FileType        string          `json:"file_type"` // Package manager type of the matching manifest: "npm", "go", "python", "rust", "ruby"
+	Format          string          `json:"format"`    // Lock file format: "package-lock", "yarn", "pnpm", "go.sum", "cargo", "poetry", "pipenv", "bundler"
+	Packages        []LockedPackage `json:"packages"`
+	DirectCount     int             `json:"direct_count"`
+	TransitiveCount int             `json:"transitive_count"`
+}
+
+// lockFileFormats maps lock file names to their format and manifest file type
+var lockFileFormats = map[string]struct{ format, fileType string }{
+	"package-lock.json": {"package-lock", "npm"},
+	"yarn.lock":         {"yarn", "npm"},
+	"pnpm-lock.yaml":    {"pnpm", "npm"},
+	"go.sum":            {"go.sum", "go"},
+	"Cargo.lock":        {"cargo", "rust"},
+	"poetry.lock":       {"poetry", "python"},
+	"Pipfile.lock":      {"pipenv", "python"},
+	"Gemfile.lock":      {"bundler", "ruby"},
+	"composer.lock":     {"composer", "php"},
+}
+
+// parseLockFile dispatches to the parser for the given lock file format
+func parseLockFile(format string, content []byte) []LockedPackage {
+	var pkgs []LockedPackage
+	switch format {
+	case "package-lock":
+		pkgs = parsePackageLock(content)
+	case "yarn":
+		pkgs = parseYarnLock(content)
+	case "pnpm":
+		pkgs = parsePnpmLock(content)
+	case "go.sum":
+		pkgs = parseGoSum(content)
+	case "cargo":
+		pkgs = parseCargoLock(content)
+	case "poetry":
+		pkgs = parsePoetryLock(content)
+	case "pipenv":
+		pkgs = parsePipfileLock(content)
+	case "bundler":
+		pkgs = parseGemfileLock(content)
+	case "composer":
+		pkgs = parseComposerLock(content)
+	}
+
+	sort.Slice(pkgs, func(i, j int) bool {
+		if pkgs[i].Name != pkgs[j].Name {
+			return pkgs[i].Name < pkgs[j].Name
+		}
+		return pkgs[i].Version < pkgs[j].Version
+	})
+	return pkgs
+}
+
+// lockedPackageSet collects packages keyed by name@version, merging
+// duplicates that appear at several places in the install tree.
+type lockedPackageSet struct {
+	order []string
+	pkgs  map[string]*LockedPackage
+}
+
+func newLockedPackageSet() *lockedPackageSet {
+	return &lockedPackageSet{pkgs: make(map[string]*LockedPackage)}
+}
+
+func (s *lockedPackageSet) add(pkg LockedPackage) {
+	key := pkg.Name + "@" + pkg.Version
+	existing, ok := s.pkgs[key]
+	if !ok {
+		s.order = append(s.order, key)
+		s.pkgs[key] = &pkg
+		return
+	}
+	existing.Direct = existing.Direct || pkg.Direct
+	existing.Dev = existing.Dev && pkg.Dev
+	if len(existing.Dependencies) == 0 {
+		existing.Dependencies = pkg.Dependencies
+	}
+}
+
+func (s *lockedPackageSet) list() []LockedPackage {
+	result := make([]LockedPackage, 0, len(s.order))
+	for _, key := range s.order {
+		result = append(result, *s.pkgs[key])
+	}
+	return result
+}
+
+// resolveByName fills in dependency versions for lock files that only
+// record dependency names. Names with several locked versions resolve to
+// the highest one.
+func resolveByName(pkgs []LockedPackage, normalize func(string) string) {
+	versions := make(map[string]string)
+	for _, p := range pkgs {
+		key := normalize(p.Name)
+		if v, ok := versions[key]; !ok || compareLooseVersions(p.Version, v) > 0 {
+			versions[key] = p.Version
+		}
+	}
+	names := make(map[string]string)
+	for _, p := range pkgs {
+		names[normalize(p.Name)] = p.Name
+	}
+
+	for i := range pkgs {
+		for j, dep := range pkgs[i].Dependencies {
+			key := normalize(dep.Name)
+			if dep.Version == "" {
+				pkgs[i].Dependencies[j].Version = versions[key]
+			}
+			if name, ok := names[key]; ok {
+				pkgs[i].Dependencies[j].Name = name
+			}
+		}
+	}
+}
+
+func identity(s string) string { return s }
+
+// parsePackageLock parses an npm package-lock.json (lockfileVersion 1, 2 or 3).
+// Version 2+ files list every installed path under "packages"; the root
+// entry ("") declares the direct dependencies. Version 1 files nest
+// packages under "dependencies" instead.
+func parsePackageLock(content []byte) []LockedPackage {
+	var lock struct {
+		Packages     map[string]packageLockEntry `json:"packages"`
+		Dependencies map[string]packageLockV1Dep `json:"dependencies"`
+	}
+	if err := json.Unmarshal(content, &lock); err != nil {
+		return nil
+	}
+
+	set := newLockedPackageSet()
+	if len(lock.Packages) > 0 {
+		root := lock.Packages[""]
+		direct := make(map[string]bool)
+		for _, deps := range []map[string]string{root.Dependencies, root.DevDependencies, root.OptionalDependencies, root.PeerDependencies} {
+			for name := range deps {
+				direct[name] = true
+			}
+		}
+
+		for p, entry := range lock.Packages {
+			idx := strings.LastIndex(p, "node_modules/")
+			if idx < 0 || entry.Link || entry.Version == "" {
+				continue // Root project, workspace members and symlinks
+			}
+			name := entry.Name
+			if name == "" {
+				name = p[idx+len("node_modules/"):]
+			}
+
+			pkg := LockedPackage{
+				Name:    name,
+				Version: entry.Version,
+				Direct:  p == "node_modules/"+name && direct[name],
+				Dev:     entry.Dev || entry.DevOptional,
+				License: jsonLicenseField(entry.License),
+			}
+			for _, deps := range []map[string]string{entry.Dependencies, entry.OptionalDependencies, entry.PeerDependencies} {
+				for _, depName := range sortedStringKeys(deps) {
+					if resolved := resolvePackageLockPath(lock.Packages, p, depName); resolved != "" {
+						pkg.Dependencies = append(pkg.Dependencies, PackageRef{Name: depName, Version: lock.Packages[resolved].Version})
+					}
+				}
+			}
+			set.add(pkg)
+		}
+		return set.list()
+	}
+
+	walkPackageLockV1(lock.Dependencies, []map[string]packageLockV1Dep{lock.Dependencies}, set)
+	return set.list()
+}
+
+type packageLockEntry struct {
+	Name                 string            `json:"name"`
+	Version              string            `json:"version"`
+	Dev                  bool              `json:"dev"`
+	DevOptional          bool              `json:"devOptional"`
+	Link                 bool              `json:"link"`
+	License              json.RawMessage   `json:"license"`
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+}
+
+type packageLockV1Dep struct {
+	Version      string                      `json:"version"`
+	Dev          bool                        `json:"dev"`
+	Requires     map[string]string           `json:"requires"`
+	Dependencies map[string]packageLockV1Dep `json:"dependencies"`
+}
+
+// resolvePackageLockPath finds the install path a dependency resolves to
+// from a given package path, following Node's module resolution: the
+// nearest enclosing node_modules directory wins.
+func resolvePackageLockPath(packages map[string]packageLockEntry, from, name string) string {
+	for {
+		candidate := "node_modules/" + name
+		if from != "" {
+			candidate = from + "/node_modules/" + name
+		}
+		if _, ok := packages[candidate]; ok {
+			return candidate
+		}
+		if from == "" {
+			return ""
+		}
+		if idx := strings.LastIndex(from, "/node_modules/"); idx >= 0 {
+			from = from[:idx]
+		} else {
+			from = ""
+		}
+	}
+}
+
+// walkPackageLockV1 visits a v1 dependency tree. scopes holds the
+// enclosing dependency maps, innermost last, for resolving "requires".
+func walkPackageLockV1(deps map[string]packageLockV1Dep, scopes []map[string]packageLockV1Dep, set *lockedPackageSet) {
+	for _, name := range sortedPackageLockV1Keys(deps) {
+		dep := deps[name]
+		if dep.Version == "" || strings.HasPrefix(dep.Version, "file:") {
+			continue
+		}
+
+		inner := scopes
+		if len(dep.Dependencies) > 0 {
+			inner = append(append([]map[string]packageLockV1Dep{}, scopes...), dep.Dependencies)
+		}
+
+		pkg := LockedPackage{Name: name, Version: dep.Version, Dev: dep.Dev}
+		for _, req := range sortedStringKeys(dep.Requires) {
+			for i := len(inner) - 1; i >= 0; i-- {
+				if resolved, ok := inner[i][req]; ok {
+					pkg.Dependencies = append(pkg.Dependencies, PackageRef{Name: req, Version: resolved.Version})
+					break
+				}
+			}
+		}
+		set.add(pkg)
+
+		if len(dep.Dependencies) > 0 {
+			walkPackageLockV1(dep.Dependencies, inner, set)
+		}
+	}
+}
+
+func sortedPackageLockV1Keys(m map[string]packageLockV1Dep) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedStringKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// splitPackageSpec splits "name@range" where scoped names start with "@"
+func splitPackageSpec(spec string) (string, string) {
+	idx := strings.LastIndex(spec, "@")
+	if idx <= 0 {
+		return spec, ""
+	}
+	return spec[:idx], spec[idx+1:]
+}
+
+// parseYarnLock parses a Yarn lock file, both the classic v1 format and the
+// YAML-based Berry (v2+) format. Each entry lists the ranges it satisfies,
+// which are used to resolve dependency ranges to exact versions.
+//
+// Example (classic):
+//
+//	"@babel/code-frame@^7.0.0", "@babel/code-frame@^7.10.4":
+//	  version "7.12.13"
+//	  dependencies:
+//	    "@babel/highlight" "^7.12.13"
+func parseYarnLock(content []byte) []LockedPackage {
+	type yarnEntry struct {
+		name      string
+		version   string
+		workspace bool
+		deps      [][2]string // name, range
+	}
+
+	var entries []*yarnEntry
+	ranges := make(map[string]*yarnEntry) // name@range -> entry
+	var current *yarnEntry
+	inDeps := false
+
+	scanner := bufio.NewScanner(strings.NewReader(string(content)))
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		switch {
+		case indent == 0:
+			inDeps = false
+			current = nil
+			if !strings.HasSuffix(trimmed, ":") || strings.HasPrefix(trimmed, "__metadata") {
+				continue
+			}
+			current = &yarnEntry{}
+			for _, spec := range strings.Split(strings.TrimSuffix(trimmed, ":"), ",") {
+				spec = strings.Trim(strings.TrimSpace(spec), `"`)
+				name, rng := splitPackageSpec(spec)
+				current.name = name
+				if strings.HasPrefix(rng, "workspace:") || strings.HasPrefix(rng, "link:") || strings.HasPrefix(rng, "portal:") {
+					current.workspace = true
+				}
+				ranges[name+"@"+rng] = current
+			}
+			entries = append(entries, current)
+
+		case current == nil:
+			continue
+
+		case indent == 2:
+			key, value := splitYarnField(trimmed)
+			inDeps = (key == "dependencies" || key == "optionalDependencies" || key == "peerDependencies") && value == ""
+			if key == "version" {
+				current.version = value
+			}
+
+		case inDeps && indent >= 4:
+			name, rng := splitYarnField(trimmed)
+			current.deps = append(current.deps, [2]string{name, rng})
+		}
+	}
+
+	lookup := func(name, rng string) *yarnEntry {
+		if e, ok := ranges[name+"@"+rng]; ok {
+			return e
+		}
+		if e, ok := ranges[name+"@npm:"+rng]; ok {
+			return e
+		}
+		return nil
+	}
+
+	// Workspace entries (Berry) declare the project's direct dependencies
+	direct := make(map[*yarnEntry]bool)
+	for _, e := range entries {
+		if !e.workspace {
+			continue
+		}
+		for _, d := range e.deps {
+			if target := lookup(d[0], d[1]); target != nil {
+				direct[target] = true
+			}
+		}
+	}
+
+	set := newLockedPackageSet()
+	for _, e := range entries {
+		if e.workspace || e.version == "" {
+			continue
+		}
+		pkg := LockedPackage{Name: e.name, Version: e.version, Direct: direct[e]}
+		for _, d := range e.deps {
+			if target := lookup(d[0], d[1]); target != nil && !target.workspace {
+				pkg.Dependencies = append(pkg.Dependencies, PackageRef{Name: target.name, Version: target.version})
+			}
+		}
+		set.add(pkg)
+	}
+	return set.list()
+}
+
+// splitYarnField splits a `key "value"` (classic) or `key: value` (Berry) line
+func splitYarnField(line string) (string, string) {
+	var key, value string
+	if strings.HasPrefix(line, `"`) {
+		end := strings.Index(line[1:], `"`)
+		if end < 0 {
+			return strings.Trim(line, `":`), ""
+		}
+		key = line[1 : end+1]
+		value = line[end+2:]
+	} else if idx := strings.IndexAny(line, ": "); idx >= 0 {
+		key = line[:idx]
+		value = line[idx:]
+	} else {
+		return line, ""
+	}
+	value = strings.TrimSpace(strings.TrimPrefix(value, ":"))
+	return key, strings.Trim(value, `"`)
+}
+
+// parsePnpmLock parses a pnpm-lock.yaml file (lockfile versions 5 through 9).
+// Direct dependencies come from the "importers" section, or from the
+// top-level dependency maps in single-project lock files.
+func parsePnpmLock(content []byte) []LockedPackage {
+	doc, err := parseYAML(content)
+	if err != nil {
+		return nil
+	}
+	root := yamlMap(doc)
+	if root == nil {
+		return nil
+	}
+
+	major := 0
+	if v := yamlString(root, "lockfileVersion"); v != "" {
+		major, _ = strconv.Atoi(strings.SplitN(v, ".", 2)[0])
+	}
+	legacy := major > 0 && major < 6
+
+	// Collect direct dependency name@version pairs
+	direct := make(map[string]bool)
+	collectDirect := func(project map[string]interface{}) {
+		for _, section := range []string{"dependencies", "devDependencies", "optionalDependencies"} {
+			for name, value := range yamlMap(project, section) {
+				version := pnpmVersionValue(value, legacy)
+				if version != "" {
+					direct[name+"@"+version] = true
+				}
+			}
+		}
+	}
+	if importers := yamlMap(root, "importers"); importers != nil {
+		for _, project := range importers {
+			if m, ok := project.(map[string]interface{}); ok {
+				collectDirect(m)
+			}
+		}
+	} else {
+		collectDirect(root)
+	}
+
+	// Version 9 moved the dependency graph into "snapshots"
+	entries := yamlMap(root, "snapshots")
+	if entries == nil {
+		entries = yamlMap(root, "packages")
+	}
+	packages := yamlMap(root, "packages")
+
+	set := newLockedPackageSet()
+	for key, value := range entries {
+		name, version := parsePnpmPackageKey(key, legacy)
+		if name == "" || version == "" {
+			continue
+		}
+		entry, _ := value.(map[string]interface{})
+		meta := entry
+		if pkgMeta, ok := packages[key].(map[string]interface{}); ok {
+			meta = pkgMeta
+		}
+
+		pkg := LockedPackage{
+			Name:    name,
+			Version: version,
+			Direct:  direct[name+"@"+version],
+			Dev:     yamlString(meta, "dev") == "true",
+		}
+		for _, section := range []string{"dependencies", "optionalDependencies"} {
+			deps := yamlMap(entry, section)
+			names := make([]string, 0, len(deps))
+			for depName := range deps {
+				names = append(names, depName)
+			}
+			sort.Strings(names)
+			for _, depName := range names {
+				depVersion, _ := deps[depName].(string)
+				if trimmed := trimPnpmPeerSuffix(depVersion, legacy); strings.HasPrefix(trimmed, "/") || strings.Contains(trimmed, "@") {
+					// Aliased dependency pointing at another package key
+					if n, v := parsePnpmPackageKey(trimmed, legacy); n != "" && v != "" {
+						pkg.Dependencies = append(pkg.Dependencies, PackageRef{Name: n, Version: v})
+						continue
+					}
+				}
+				if v := pnpmVersionValue(depVersion, legacy); v != "" {
+					pkg.Dependencies = append(pkg.Dependencies, PackageRef{Name: depName, Version: v})
+				}
+			}
+		}
+		set.add(pkg)
+	}
+	return set.list()
+}
+
+// pnpmVersionValue extracts the resolved version from an importer or
+// dependency value, which is either a version string or, since lockfile
+// version 6, a mapping with "specifier" and "version" keys.
+func pnpmVersionValue(value interface{}, legacy bool) string {
+	var version string
+	switch v := value.(type) {
+	case string:
+		version = v
+	case map[string]interface{}:
+		version = yamlString(v, "version")
+	}
+	if version == "" || strings.HasPrefix(version, "link:") || strings.HasPrefix(version, "file:") {
+		return ""
+	}
+	return trimPnpmPeerSuffix(version, legacy)
+}
+
+// parsePnpmPackageKey splits a pnpm package key into name and version.
+//
+// Examples:
+//
+//	"/@babel/core/7.22.5_supports-color@8.1.1" (v5) -> ("@babel/core", "7.22.5")
+//	"/@babel/core@7.22.5(supports-color@8.1.1)" (v6) -> ("@babel/core", "7.22.5")
+//	"@babel/core@7.22.5" (v9)                         -> ("@babel/core", "7.22.5")
+func parsePnpmPackageKey(key string, legacy bool) (string, string) {
+	key = strings.TrimPrefix(key, "/")
+	if legacy {
+		idx := strings.LastIndex(trimPnpmPeerSuffix(key, true), "/")
+		if idx <= 0 {
+			return "", ""
+		}
+		return key[:idx], trimPnpmPeerSuffix(key[idx+1:], true)
+	}
+	key = trimPnpmPeerSuffix(key, false)
+	return splitPackageSpec(key)
+}
+
+// trimPnpmPeerSuffix drops peer dependency suffixes: "(peer@1.0.0)" in
+// lockfile v6+ and "_peer@1.0.0" in v5
+func trimPnpmPeerSuffix(s string, legacy bool) string {
+	if idx := strings.Index(s, "("); idx >= 0 {
+		s = s[:idx]
+	}
+	if legacy {
+		if idx := strings.Index(s, "_"); idx >= 0 {
+			s = s[:idx]
+		}
+	}
+	return s
+}
+
+// parseGoSum parses a go.sum file. Each module appears with the hash of
+// its contents and of its go.mod; only modules whose contents are recorded
+// are part of the build. When several versions are listed, the highest one
+// is the version selected by minimal version selection.
+//
+// Example go.sum:
+//
+//	github.com/gin-gonic/gin v1.9.1 h1:4idEAncQnU5cB7BeOkPtxjfCSye0AAm1R0RVIqJ+Jmg=
+//	github.com/gin-gonic/gin v1.9.1/go.mod h1:hPrL7YrpYKXt5YId3A/Tnip5kqbEAP+KLuI3SUcPTeU=
+func parseGoSum(content []byte) []LockedPackage {
+	versions := make(map[string]string)
+	var order []string
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || strings.HasSuffix(fields[1], "/go.mod") {
+			continue
+		}
+		module, version := fields[0], fields[1]
+		existing, ok := versions[module]
+		if !ok {
+			order = append(order, module)
+		}
+		if !ok || compareLooseVersions(version, existing) > 0 {
+			versions[module] = version
+		}
+	}
+
+	pkgs := make([]LockedPackage, 0, len(order))
+	for _, module := range order {
+		pkgs = append(pkgs, LockedPackage{Name: module, Version: versions[module]})
+	}
+	return pkgs
+}
+
+// parseCargoLock parses a Cargo.lock file. Packages without a "source"
+// are the workspace's own crates; their dependencies are the direct ones.
+//
+// Example Cargo.lock:
+//
+//	[[package]]
+//	name = "serde_json"
+//	version = "1.0.108"
+//	source = "registry+https://github.com/rust-lang/crates.io-index"
+//	dependencies = ["itoa", "ryu", "serde"]
+func parseCargoLock(content []byte) []LockedPackage {
+	doc, err := parseTOML(content)
+	if err != nil {
+		return nil
+	}
+	entries, _ := doc["package"].([]interface{})
+
+	parseRef := func(s string) PackageRef {
+		fields := strings.Fields(s)
+		ref := PackageRef{Name: fields[0]}
+		if len(fields) > 1 {
+			ref.Version = fields[1]
+		}
+		return ref
+	}
+
+	var pkgs []LockedPackage
+	direct := make(map[string]bool)
+	for _, e := range entries {
+		table, ok := e.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var deps []PackageRef
+		for _, d := range tomlStrings(table, "dependencies") {
+			if strings.TrimSpace(d) != "" {
+				deps = append(deps, parseRef(d))
+			}
+		}
+
+		if tomlString(table, "source") == "" {
+			for _, d := range deps {
+				direct[d.Name] = true
+			}
+			continue
+		}
+		pkgs = append(pkgs, LockedPackage{
+			Name:         tomlString(table, "name"),
+			Version:      tomlString(table, "version"),
+			Dependencies: deps,
+		})
+	}
+
+	resolveByName(pkgs, identity)
+	for i := range pkgs {
+		pkgs[i].Direct = direct[pkgs[i].Name]
+	}
+	return pkgs
+}
+
+// parsePoetryLock parses a poetry.lock file. Direct dependencies are not
+// recorded in the lock file and are taken from pyproject.toml instead.
+//
+// Example poetry.lock:
+//
+//	[[package]]
+//	name = "requests"
+//	version = "2.31.0"
+//
+//	[package.dependencies]
+//	urllib3 = ">=1.21.1,<3"
+func parsePoetryLock(content []byte) []LockedPackage {
+	doc, err := parseTOML(content)
+	if err != nil {
+		return nil
+	}
+	entries, _ := doc["package"].([]interface{})
+
+	var pkgs []LockedPackage
+	for _, e := range entries {
+		table, ok := e.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		pkg := LockedPackage{
+			Name:    tomlString(table, "name"),
+			Version: tomlString(table, "version"),
+			Dev:     tomlString(table, "category") == "dev",
+		}
+		for _, name := range sortedKeys(tomlTable(table, "dependencies")) {
+			pkg.Dependencies = append(pkg.Dependencies, PackageRef{Name: name})
+		}
+		pkgs = append(pkgs, pkg)
+	}
+
+	resolveByName(pkgs, normalizePythonName)
+	// Drop references to packages that were not locked (platform markers)
+	for i := range pkgs {
+		deps := pkgs[i].Dependencies[:0]
+		for _, d := range pkgs[i].Dependencies {
+			if d.Version != "" {
+				deps = append(deps, d)
+			}
+		}
+		pkgs[i].Dependencies = deps
+	}
+	return pkgs
+}
+
+// parsePipfileLock parses a Pipenv Pipfile.lock. The lock file is flat:
+// "default" holds production packages and "develop" dev packages.
+func parsePipfileLock(content []byte) []LockedPackage {
+	var lock map[string]map[string]struct {
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(content, &lock); err != nil {
+		return nil
+	}
+
+	set := newLockedPackageSet()
+	for _, section := range []string{"default", "develop"} {
+		for name, entry := range lock[section] {
+			version := strings.TrimPrefix(entry.Version, "==")
+			if version == "" {
+				continue // VCS and path dependencies
+			}
+			set.add(LockedPackage{Name: name, Version: version, Dev: section == "develop"})
+		}
+	}
+	return set.list()
+}
+
+// parseComposerLock parses a Composer composer.lock. "packages" holds
+// production packages and "packages-dev" dev packages; each records its
+// declared licenses and the packages it requires.
+//
+// Example composer.lock:
+//
+//	{
+//	  "packages": [
+//	    {"name": "monolog/monolog", "version": "3.5.0", "license": ["MIT"],
+//	     "require": {"php": ">=8.1", "psr/log": "^2.0 || ^3.0"}}
+//	  ],
+//	  "packages-dev": []
+//	}
+func parseComposerLock(content []byte) []LockedPackage {
+	type composerPackage struct {
+		Name    string            `json:"name"`
+		Version string            `json:"version"`
+		License json.RawMessage   `json:"license"`
+		Require map[string]string `json:"require"`
+	}
+	var lock struct {
+		Packages    []composerPackage `json:"packages"`
+		PackagesDev []composerPackage `json:"packages-dev"`
+	}
+	if err := json.Unmarshal(content, &lock); err != nil {
+		return nil
+	}
+
+	var pkgs []LockedPackage
+	for _, section := range []struct {
+		entries []composerPackage
+		dev     bool
+	}{{lock.Packages, false}, {lock.PackagesDev, true}} {
+		for _, entry := range section.entries {
+			pkg := LockedPackage{
+				Name:    entry.Name,
+				Version: strings.TrimPrefix(entry.Version, "v"),
+				Dev:     section.dev,
+				License: jsonLicenseField(entry.License),
+			}
+			for _, name := range sortedStringKeys(entry.Require) {
+				if !isComposerPlatformPackage(name) {
+					pkg.Dependencies = append(pkg.Dependencies, PackageRef{Name: name})
+				}
+			}
+			pkgs = append(pkgs, pkg)
+		}
+	}
+	resolveByName(pkgs, strings.ToLower)
+	return pkgs
+}
+
+// gemSpecPattern matches "name (version)" entries in Gemfile.lock
+var gemSpecPattern = regexp.MustCompile(`^([A-Za-z0-9_.\-]+)(?: \(([^)]*)\))?!?$`)
+
+// parseGemfileLock parses a Bundler Gemfile.lock. Specs are listed at four
+// spaces of indentation with their dependencies at six; the DEPENDENCIES
+// section lists the gems declared in the Gemfile.
+//
+// Example Gemfile.lock:
+//
+//	GEM
+//	  remote: https://rubygems.org/
+//	  specs:
+//	    actionpack (7.0.4)
+//	      rack (~> 2.0)
+//
+//	DEPENDENCIES
+//	  rails (~> 7.0)
+func parseGemfileLock(content []byte) []LockedPackage {
+	var pkgs []LockedPackage
+	direct := make(map[string]bool)
+	section := ""
+
+	for _, line := range strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if indent == 0 {
+			section = strings.TrimSpace(line)
+			continue
+		}
+
+		m := gemSpecPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+
+		switch {
+		case section == "DEPENDENCIES" && indent == 2:
+			direct[m[1]] = true
+		case (section == "GEM" || section == "GIT" || section == "PATH") && indent == 4:
+			// Platform-specific gems carry a suffix like "1.15.4-x86_64-linux"
+			version := m[2]
+			if idx := strings.Index(version, "-"); idx > 0 {
+				version = version[:idx]
+			}
+			pkgs = append(pkgs, LockedPackage{Name: m[1], Version: version})
+		case (section == "GEM" || section == "GIT" || section == "PATH") && indent == 6 && len(pkgs) > 0:
+			last := &pkgs[len(pkgs)-1]
+			last.Dependencies = append(last.Dependencies, PackageRef{Name: m[1]})
+		}
+	}
+
+	// Platform variants of the same gem collapse into one package
+	set := newLockedPackageSet()
+	for _, p := range pkgs {
+		set.add(p)
+	}
+	pkgs = set.list()
+
+	resolveByName(pkgs, identity)
+	for i := range pkgs {
+		pkgs[i].Direct = direct[pkgs[i].Name]
+	}
+	return pkgs
+}
+
+// normalizePythonName applies PEP 503 name normalization
+func normalizePythonName(name string) string {
+	name = strings.ToLower(name)
+	return strings.NewReplacer("_", "-", ".", "-").Replace(name)
+}
+
+// compareLooseVersions compares two version strings numerically by their
+// dot-separated components, ignoring a leading "v". Non-numeric suffixes
+// (pre-releases) sort before the plain release.
+func compareLooseVersions(a, b string) int {
+	split := func(v string) ([]string, string) {
+		v = strings.TrimPrefix(v, "v")
+		pre := ""
+		if idx := strings.IndexAny(v, "-+"); idx >= 0 {
+			v, pre = v[:idx], v[idx:]
+		}
+		return strings.Split(v, "."), pre
+	}
+	pa, preA := split(a)
+	pb, preB := split(b)
+
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var na, nb int
+		if i < len(pa) {
+			na, _ = strconv.Atoi(pa[i])
+		}
+		if i < len(pb) {
+			nb, _ = strconv.Atoi(pb[i])
+		}
+		if na != nb {
+			if na < nb {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	switch {
+	case preA == preB:
+		return 0
+	case preA == "":
+		return 1
+	case preB == "":
+		return -1
+	case preA < preB:
+		return -1
+	}
+	return 1
+}
+
+// linkLockFiles cross-references lock files with the manifests they lock.
+// Each manifest is locked by the nearest lock file of the same package
+// manager in its directory or an ancestor directory (workspaces share a
+// root lock file). Matching manifest dependencies get their resolved
+// version and mark the locked package as direct.
+func linkLockFiles(analysis *DependencyAnalysis) {
+	for f := range analysis.Files {
+		file := &analysis.Files[f]
+		lf := nearestLockFile(analysis.LockFiles, file)
+		if lf == nil {
+			continue
+		}
+		normalize := identity
+		if lf.FileType == "python" {
+			normalize = normalizePythonName
+		}
+
+		for d := range file.Dependencies {
+			dep := &file.Dependencies[d]
+			chosen := -1
+			for j, p := range lf.Packages {
+				if normalize(p.Name) != normalize(dep.Name) {
+					continue
+				}
+				// Prefer the copy the lock file already marks as direct
+				if chosen < 0 || (p.Direct && !lf.Packages[chosen].Direct) {
+					chosen = j
+				}
+			}
+			if chosen < 0 {
+				continue
+			}
+			dep.Resolved = lf.Packages[chosen].Version
+			if dep.Type != "indirect" {
+				lf.Packages[chosen].Direct = true
+			}
+		}
+	}
+
+	for i := range analysis.LockFiles {
+		lf := &analysis.LockFiles[i]
+		lf.DirectCount, lf.TransitiveCount = 0, 0
+		for _, p := range lf.Packages {
+			if p.Direct {
+				lf.DirectCount++
+			} else {
+				lf.TransitiveCount++
+			}
+		}
+		analysis.TransitiveDeps += lf.TransitiveCount
+	}
+
+	for _, file := range analysis.Files {
+		for _, dep := range file.Dependencies {
+			if dep.Resolved != "" {
+				analysis.ResolvedDeps++
+			}
+		}
+	}
+}
+
+// nearestLockFile returns the lock file closest to a manifest, or nil
+func nearestLockFile(lockFiles []LockFile, file *DependencyFile) *LockFile {
+	manifestDir := path.Dir(file.Filename)
+	var best *LockFile
+	bestLen := -1
+	for i := range lockFiles {
+		lf := &lockFiles[i]
+		if lf.FileType != file.FileType {
+			continue
+		}
+		lockDir := path.Dir(lf.Filename)
+		covers := lockDir == manifestDir || lockDir == "." || strings.HasPrefix(manifestDir, lockDir+"/")
+		if covers && len(lockDir) > bestLen {
+			best, bestLen = lf, len(lockDir)
+		}
+	}
+	return best
+}
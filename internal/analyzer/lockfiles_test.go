@@ -0,0 +1,428 @@
+package analyzer
+
+import (
+	"testing"
+)
+
+// lockedByName indexes locked packages by name@version for assertions
+func lockedByName(pkgs []LockedPackage) map[string]LockedPackage {
+	m := make(map[string]LockedPackage)
+	for _, p := range pkgs {
+		m[p.Name+"@"+p.Version] = p
+	}
+	return m
+}
+
+func hasRef(refs []PackageRef, name, version string) bool {
+	for _, r := range refs {
+		if r.Name == name && r.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+func TestParsePackageLock_V3(t *testing.T) {
+	content := []byte(`{
+  "lockfileVersion": 3,
+  "packages": {
+    "": { "name": "app", "dependencies": { "express": "^4.18.0" }, "devDependencies": { "jest": "^29.0.0" } },
+    "node_modules/express": { "version": "4.18.2", "dependencies": { "debug": "2.6.9" } },
+    "node_modules/debug": { "version": "4.3.4" },
+    "node_modules/express/node_modules/debug": { "version": "2.6.9" },
+    "node_modules/jest": { "version": "29.7.0", "dev": true },
+    "packages/lib": { "version": "1.0.0" },
+    "node_modules/lib": { "link": true, "resolved": "packages/lib" }
+  }
+}`)
+
+	pkgs := parseLockFile("package-lock", content)
+	m := lockedByName(pkgs)
+	if len(pkgs) != 4 {
+		t.Fatalf("len(pkgs) = %d, want 4: %+v", len(pkgs), pkgs)
+	}
+	if !m["express@4.18.2"].Direct || m["debug@4.3.4"].Direct {
+		t.Error("express should be direct and debug transitive")
+	}
+	if !hasRef(m["express@4.18.2"].Dependencies, "debug", "2.6.9") {
+		t.Errorf("express should resolve the nested debug@2.6.9: %+v", m["express@4.18.2"].Dependencies)
+	}
+	if !m["jest@29.7.0"].Dev {
+		t.Error("jest should be a dev package")
+	}
+}
+
+func TestParsePackageLock_V1(t *testing.T) {
+	content := []byte(`{
+  "lockfileVersion": 1,
+  "dependencies": {
+    "express": { "version": "4.18.2", "requires": { "debug": "2.6.9" },
+      "dependencies": { "debug": { "version": "2.6.9" } } },
+    "debug": { "version": "4.3.4" }
+  }
+}`)
+
+	m := lockedByName(parsePackageLock(content))
+	if len(m) != 3 {
+		t.Fatalf("len(pkgs) = %d, want 3: %+v", len(m), m)
+	}
+	if !hasRef(m["express@4.18.2"].Dependencies, "debug", "2.6.9") {
+		t.Errorf("express should require debug@2.6.9: %+v", m["express@4.18.2"].Dependencies)
+	}
+}
+
+func TestParseYarnLock_Classic(t *testing.T) {
+	content := []byte(`# THIS IS AN AUTOGENERATED FILE.
+# yarn lockfile v1
+
+
+"@babel/code-frame@^7.0.0", "@babel/code-frame@^7.10.4":
+  version "7.12.13"
+  resolved "https://registry.yarnpkg.com/@babel/code-frame/-/code-frame-7.12.13.tgz"
+  dependencies:
+    "@babel/highlight" "^7.12.13"
+
+"@babel/highlight@^7.12.13":
+  version "7.13.10"
+`)
+
+	m := lockedByName(parseYarnLock(content))
+	if len(m) != 2 {
+		t.Fatalf("len(pkgs) = %d, want 2: %+v", len(m), m)
+	}
+	if !hasRef(m["@babel/code-frame@7.12.13"].Dependencies, "@babel/highlight", "7.13.10") {
+		t.Errorf("code-frame deps = %+v", m["@babel/code-frame@7.12.13"].Dependencies)
+	}
+}
+
+func TestParseYarnLock_Berry(t *testing.T) {
+	content := []byte(`__metadata:
+  version: 6
+
+"app@workspace:.":
+  version: 0.0.0-use.local
+  dependencies:
+    lodash: ^4.17.0
+
+"lodash@npm:^4.17.0":
+  version: 4.17.21
+  resolution: "lodash@npm:4.17.21"
+`)
+
+	pkgs := parseYarnLock(content)
+	if len(pkgs) != 1 || pkgs[0].Name != "lodash" || pkgs[0].Version != "4.17.21" {
+		t.Fatalf("pkgs = %+v, want lodash@4.17.21", pkgs)
+	}
+	if !pkgs[0].Direct {
+		t.Error("lodash should be direct via the workspace entry")
+	}
+}
+
+func TestParsePnpmLock(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"v6", `lockfileVersion: '6.0'
+
+importers:
+  .:
+    dependencies:
+      react:
+        specifier: ^18.2.0
+        version: 18.2.0
+
+packages:
+  /loose-envify@1.4.0:
+    resolution: {integrity: sha512-abc}
+    dev: false
+
+  /react@18.2.0:
+    resolution: {integrity: sha512-def}
+    dependencies:
+      loose-envify: 1.4.0
+    dev: false
+`},
+		{"v5", `lockfileVersion: 5.4
+
+specifiers:
+  react: ^18.2.0
+
+dependencies:
+  react: 18.2.0
+
+packages:
+  /loose-envify/1.4.0:
+    resolution: {integrity: sha512-abc}
+    dev: false
+
+  /react/18.2.0:
+    resolution: {integrity: sha512-def}
+    dependencies:
+      loose-envify: 1.4.0
+    dev: false
+`},
+		{"v9", `lockfileVersion: '9.0'
+
+importers:
+  .:
+    dependencies:
+      react:
+        specifier: ^18.2.0
+        version: 18.2.0
+
+packages:
+  loose-envify@1.4.0:
+    resolution: {integrity: sha512-abc}
+  react@18.2.0:
+    resolution: {integrity: sha512-def}
+
+snapshots:
+  loose-envify@1.4.0: {}
+  react@18.2.0:
+    dependencies:
+      loose-envify: 1.4.0
+`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := lockedByName(parsePnpmLock([]byte(tt.content)))
+			if len(m) != 2 {
+				t.Fatalf("len(pkgs) = %d, want 2: %+v", len(m), m)
+			}
+			react := m["react@18.2.0"]
+			if !react.Direct || m["loose-envify@1.4.0"].Direct {
+				t.Error("react should be direct and loose-envify transitive")
+			}
+			if !hasRef(react.Dependencies, "loose-envify", "1.4.0") {
+				t.Errorf("react deps = %+v", react.Dependencies)
+			}
+		})
+	}
+}
+
+func TestParseGoSum(t *testing.T) {
+	content := []byte(`github.com/pkg/errors v0.8.1/go.mod h1:abc=
+github.com/pkg/errors v0.9.1 h1:def=
+github.com/pkg/errors v0.9.1/go.mod h1:ghi=
+golang.org/x/text v0.3.0/go.mod h1:jkl=
+golang.org/x/sys v0.9.0 h1:mno=
+golang.org/x/sys v0.10.0 h1:pqr=
+`)
+
+	m := lockedByName(parseGoSum(content))
+	if len(m) != 2 {
+		t.Fatalf("len(pkgs) = %d, want 2: %+v", len(m), m)
+	}
+	if _, ok := m["golang.org/x/sys@v0.10.0"]; !ok {
+		t.Errorf("expected highest golang.org/x/sys version, got %+v", m)
+	}
+}
+
+func TestParseCargoLock(t *testing.T) {
+	content := []byte(`version = 3
+
+[[package]]
+name = "app"
+version = "0.1.0"
+dependencies = ["serde"]
+
+[[package]]
+name = "serde"
+version = "1.0.190"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+dependencies = ["serde_derive 1.0.190"]
+
+[[package]]
+name = "serde_derive"
+version = "1.0.190"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+`)
+
+	m := lockedByName(parseCargoLock(content))
+	if len(m) != 2 {
+		t.Fatalf("len(pkgs) = %d, want 2: %+v", len(m), m)
+	}
+	if !m["serde@1.0.190"].Direct || m["serde_derive@1.0.190"].Direct {
+		t.Error("serde should be direct and serde_derive transitive")
+	}
+	if !hasRef(m["serde@1.0.190"].Dependencies, "serde_derive", "1.0.190") {
+		t.Errorf("serde deps = %+v", m["serde@1.0.190"].Dependencies)
+	}
+}
+
+func TestParsePoetryLock(t *testing.T) {
+	content := []byte(`[[package]]
+name = "requests"
+version = "2.31.0"
+category = "main"
+
+[package.dependencies]
+urllib3 = ">=1.21.1,<3"
+win32-setctime = {version = ">=1.0.0", markers = "sys_platform == \"win32\""}
+
+[[package]]
+name = "urllib3"
+version = "2.0.7"
+
+[[package]]
+name = "pytest"
+version = "7.4.3"
+category = "dev"
+`)
+
+	m := lockedByName(parsePoetryLock(content))
+	if len(m) != 3 {
+		t.Fatalf("len(pkgs) = %d, want 3: %+v", len(m), m)
+	}
+	deps := m["requests@2.31.0"].Dependencies
+	if len(deps) != 1 || !hasRef(deps, "urllib3", "2.0.7") {
+		t.Errorf("requests deps = %+v, want only urllib3@2.0.7", deps)
+	}
+	if !m["pytest@7.4.3"].Dev {
+		t.Error("pytest should be a dev package")
+	}
+}
+
+func TestParsePipfileLock(t *testing.T) {
+	content := []byte(`{
+  "_meta": {"hash": {"sha256": "abc"}},
+  "default": {"requests": {"version": "==2.31.0"}, "mylib": {"git": "https://example.com/mylib.git"}},
+  "develop": {"pytest": {"version": "==7.4.3"}}
+}`)
+
+	m := lockedByName(parsePipfileLock(content))
+	if len(m) != 2 {
+		t.Fatalf("len(pkgs) = %d, want 2: %+v", len(m), m)
+	}
+	if !m["pytest@7.4.3"].Dev {
+		t.Error("pytest should be a dev package")
+	}
+}
+
+func TestParseComposerLock(t *testing.T) {
+	content := []byte(`{
+  "packages": [
+    {"name": "monolog/monolog", "version": "3.5.0", "license": ["MIT"], "require": {"php": ">=8.1", "psr/log": "^2.0 || ^3.0"}},
+    {"name": "psr/log", "version": "v3.0.0", "license": ["MIT"]},
+    {"name": "symfony/polyfill-ctype", "version": "v1.28.0", "license": ["MIT", "Apache-2.0"]}
+  ],
+  "packages-dev": [
+    {"name": "phpunit/phpunit", "version": "10.5.0", "license": ["BSD-3-Clause"]}
+  ]
+}`)
+
+	m := lockedByName(parseComposerLock(content))
+	if len(m) != 4 {
+		t.Fatalf("len(pkgs) = %d, want 4: %+v", len(m), m)
+	}
+	monolog := m["monolog/monolog@3.5.0"]
+	if monolog.License != "MIT" || len(monolog.Dependencies) != 1 || !hasRef(monolog.Dependencies, "psr/log", "3.0.0") {
+		t.Errorf("monolog = %+v, want MIT depending on psr/log@3.0.0", monolog)
+	}
+	if got := m["symfony/polyfill-ctype@1.28.0"].License; got != "MIT OR Apache-2.0" {
+		t.Errorf("polyfill license = %q, want MIT OR Apache-2.0", got)
+	}
+	if !m["phpunit/phpunit@10.5.0"].Dev {
+		t.Error("phpunit should be a dev package")
+	}
+}
+
+func TestParseGemfileLock(t *testing.T) {
+	content := []byte(`GEM
+  remote: https://rubygems.org/
+  specs:
+    nokogiri (1.15.4-x86_64-linux)
+      racc (~> 1.4)
+    nokogiri (1.15.4-arm64-darwin)
+      racc (~> 1.4)
+    racc (1.7.1)
+    rails (7.0.8)
+      nokogiri (>= 1.6)
+
+PLATFORMS
+  x86_64-linux
+
+DEPENDENCIES
+  rails (~> 7.0)
+
+BUNDLED WITH
+   2.4.10
+`)
+
+	m := lockedByName(parseGemfileLock(content))
+	if len(m) != 3 {
+		t.Fatalf("len(pkgs) = %d, want 3: %+v", len(m), m)
+	}
+	if !m["rails@7.0.8"].Direct || m["racc@1.7.1"].Direct {
+		t.Error("rails should be direct and racc transitive")
+	}
+	if !hasRef(m["nokogiri@1.15.4"].Dependencies, "racc", "1.7.1") {
+		t.Errorf("nokogiri deps = %+v", m["nokogiri@1.15.4"].Dependencies)
+	}
+}
+
+func TestLinkLockFiles(t *testing.T) {
+	analysis := &DependencyAnalysis{
+		Files: []DependencyFile{
+			{Filename: "go.mod", FileType: "go", Dependencies: []Dependency{
+				{Name: "github.com/pkg/errors", Version: "v0.9.1", Type: "production"},
+				{Name: "golang.org/x/sys", Version: "v0.10.0", Type: "indirect"},
+			}},
+			{Filename: "web/package.json", FileType: "npm", Dependencies: []Dependency{
+				{Name: "react", Version: "^18.0.0", Type: "production"},
+			}},
+		},
+		LockFiles: []LockFile{
+			{Filename: "go.sum", FileType: "go", Packages: []LockedPackage{
+				{Name: "github.com/pkg/errors", Version: "v0.9.1"},
+				{Name: "golang.org/x/sys", Version: "v0.10.0"},
+			}},
+			{Filename: "web/package-lock.json", FileType: "npm", Packages: []LockedPackage{
+				{Name: "loose-envify", Version: "1.4.0"},
+				{Name: "react", Version: "18.2.0"},
+			}},
+		},
+	}
+	analysis.TotalDeps = 3
+
+	linkLockFiles(analysis)
+
+	if got := analysis.Files[1].Dependencies[0].Resolved; got != "18.2.0" {
+		t.Errorf("react resolved = %q, want 18.2.0", got)
+	}
+	if got := analysis.LockFiles[0].DirectCount; got != 1 {
+		t.Errorf("go.sum direct count = %d, want 1 (indirect requirement stays transitive)", got)
+	}
+	if analysis.ResolvedDeps != 3 || analysis.TransitiveDeps != 2 {
+		t.Errorf("ResolvedDeps = %d, TransitiveDeps = %d, want 3 and 2", analysis.ResolvedDeps, analysis.TransitiveDeps)
+	}
+
+	targets := scanTargets(analysis)
+	if len(targets) != 4 {
+		t.Fatalf("len(scanTargets) = %d, want 4 after deduplication: %+v", len(targets), targets)
+	}
+	for _, target := range targets {
+		if target.name == "react" && (target.version != "18.2.0" || !target.direct) {
+			t.Errorf("react target = %+v, want direct at resolved version", target)
+		}
+	}
+}
+
+func TestCompareLooseVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"v0.10.0", "v0.9.0", 1},
+		{"1.2.3", "1.2.3", 0},
+		{"1.2.3-rc.1", "1.2.3", -1},
+		{"2.0", "2.0.1", -1},
+	}
+	for _, tt := range tests {
+		if got := compareLooseVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareLooseVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
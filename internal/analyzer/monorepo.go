@@ -0,0 +1,501 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// WorkspacePackage is one package of a monorepo with the metrics of the
+// files under its directory. Files belong to the innermost package that
+// contains them.
+type WorkspacePackage struct {
+	Name            string         `json:"name"`
+	Path            string         `json:"path"`
+	Tool            string         `json:"tool"` // workspace tool that declares the package
+	Files           int            `json:"files"`
+	Languages       map[string]int `json:"languages"` // bytes of source per language
+	DependencyFiles []string       `json:"dependency_files"`
+	Dependencies    int            `json:"dependencies"`
+	Vulnerabilities int            `json:"vulnerabilities"` // in the package's declared dependencies
+	CriticalVulns   int            `json:"critical_vulnerabilities"`
+	SourceFiles     int            `json:"source_files"`
+	TestFiles       int            `json:"test_files"`
+	TestedSources   int            `json:"tested_sources"`
+	TestCoverage    float64        `json:"test_coverage"` // source files with a matching test, 0-1
+	Owners          []string       `json:"owners"`        // CODEOWNERS owners, most files first
+	OwnedFiles      int            `json:"owned_files"`
+	RecentCommits   int            `json:"recent_commits"` // -1 when activity was not fetched
+	LastCommit      *time.Time     `json:"last_commit,omitempty"`
+}
+
+// MonorepoAnalysis describes the workspace layout of a repository
+type MonorepoAnalysis struct {
+	Tools    []string           `json:"tools"`   // npm, yarn, pnpm, lerna, nx, go, cargo, bazel
+	Configs  []string           `json:"configs"` // files the workspaces were read from
+	Packages []WorkspacePackage `json:"packages"`
+}
+
+// IsMonorepo reports whether the repository declares more than one package
+func (m *MonorepoAnalysis) IsMonorepo() bool {
+	return m != nil && len(m.Packages) > 1
+}
+
+const (
+	// maxWorkspacePackages caps how many packages are analyzed
+	maxWorkspacePackages = 50
+	// maxOwnersPerPackage caps the owners listed for each package
+	maxOwnersPerPackage = 3
+)
+
+var (
+	goWorkUsePattern = regexp.MustCompile(`(?m)^\s*use\s+(\S+)\s*$`)
+	goWorkUseBlock   = regexp.MustCompile(`(?s)\buse\s*\((.*?)\)`)
+	goModulePattern  = regexp.MustCompile(`(?m)^\s*module\s+(\S+)`)
+)
+
+// DetectWorkspaces reads the workspace configuration of npm, yarn, pnpm,
+// Lerna, Nx, Go, Cargo and Bazel and lists the packages it declares. Package
+// names are read from the manifests of at most maxNamed packages; the others
+// keep their directory name.
+func DetectWorkspaces(client *github.Client, owner, repo string, fileTree []github.TreeEntry, maxNamed int) *MonorepoAnalysis {
+	fetch := contentFetcher(client, owner, repo)
+	return detectWorkspaces(fileTree, fetch, maxNamed)
+}
+
+func detectWorkspaces(fileTree []github.TreeEntry, fetch func(string) (string, bool), maxNamed int) *MonorepoAnalysis {
+	analysis := &MonorepoAnalysis{Tools: []string{}, Configs: []string{}, Packages: []WorkspacePackage{}}
+
+	present := make(map[string]bool)
+	manifestDirs := make(map[string][]string) // manifest name -> directories containing it
+	for _, entry := range fileTree {
+		if entry.Type != "blob" || isVendoredPath(entry.Path) {
+			continue
+		}
+		present[entry.Path] = true
+		dir, name := path.Split(entry.Path)
+		switch name {
+		case "package.json", "Cargo.toml", "go.mod", "project.json", "BUILD", "BUILD.bazel":
+			manifestDirs[name] = append(manifestDirs[name], strings.TrimSuffix(dir, "/"))
+		}
+	}
+
+	seen := make(map[string]bool)
+	addTool := func(tool, config string) {
+		if !contains(analysis.Tools, tool) {
+			analysis.Tools = append(analysis.Tools, tool)
+		}
+		if !contains(analysis.Configs, config) {
+			analysis.Configs = append(analysis.Configs, config)
+		}
+	}
+	addPackages := func(tool string, dirs []string) {
+		for _, dir := range dirs {
+			if dir == "" || seen[dir] {
+				continue
+			}
+			seen[dir] = true
+			analysis.Packages = append(analysis.Packages, WorkspacePackage{Name: path.Base(dir), Path: dir, Tool: tool})
+		}
+	}
+
+	// npm, yarn and pnpm workspaces, with Lerna and Nx on top
+	if content, ok := fetchPresent(present, fetch, "package.json"); ok {
+		var pkg struct {
+			Workspaces json.RawMessage `json:"workspaces"`
+		}
+		if json.Unmarshal([]byte(content), &pkg) == nil {
+			if patterns := npmWorkspacePatterns(pkg.Workspaces); len(patterns) > 0 {
+				tool := "npm"
+				if present["yarn.lock"] {
+					tool = "yarn"
+				}
+				addTool(tool, "package.json")
+				addPackages(tool, matchWorkspaceDirs(patterns, manifestDirs["package.json"]))
+			}
+		}
+	}
+	if content, ok := fetchPresent(present, fetch, "pnpm-workspace.yaml"); ok {
+		if doc, err := parseYAML([]byte(content)); err == nil {
+			addTool("pnpm", "pnpm-workspace.yaml")
+			addPackages("pnpm", matchWorkspaceDirs(yamlStrings(yamlMap(doc), "packages"), manifestDirs["package.json"]))
+		}
+	}
+	if content, ok := fetchPresent(present, fetch, "lerna.json"); ok {
+		var lerna struct {
+			Packages []string `json:"packages"`
+		}
+		if json.Unmarshal([]byte(content), &lerna) == nil {
+			if len(lerna.Packages) == 0 {
+				lerna.Packages = []string{"packages/*"}
+			}
+			addTool("lerna", "lerna.json")
+			addPackages("lerna", matchWorkspaceDirs(lerna.Packages, manifestDirs["package.json"]))
+		}
+	}
+	if present["nx.json"] {
+		addTool("nx", "nx.json")
+		addPackages("nx", manifestDirs["project.json"])
+	}
+
+	// Go workspaces
+	if content, ok := fetchPresent(present, fetch, "go.work"); ok {
+		addTool("go", "go.work")
+		addPackages("go", goWorkDirs(content))
+	}
+
+	// Cargo workspaces
+	if content, ok := fetchPresent(present, fetch, "Cargo.toml"); ok {
+		if doc, err := parseTOML([]byte(content)); err == nil {
+			if ws := tomlTable(doc, "workspace"); ws != nil {
+				addTool("cargo", "Cargo.toml")
+				members := matchWorkspaceDirs(tomlStrings(ws, "members"), manifestDirs["Cargo.toml"])
+				excluded := matchWorkspaceDirs(tomlStrings(ws, "exclude"), members)
+				var dirs []string
+				for _, m := range members {
+					if !contains(excluded, m) {
+						dirs = append(dirs, m)
+					}
+				}
+				addPackages("cargo", dirs)
+			}
+		}
+	}
+
+	// Bazel: every top-most directory with a BUILD file is a package
+	for _, config := range []string{"MODULE.bazel", "WORKSPACE", "WORKSPACE.bazel"} {
+		if present[config] {
+			addTool("bazel", config)
+			addPackages("bazel", topMostDirs(append(manifestDirs["BUILD"], manifestDirs["BUILD.bazel"]...)))
+			break
+		}
+	}
+
+	sort.Slice(analysis.Packages, func(i, j int) bool { return analysis.Packages[i].Path < analysis.Packages[j].Path })
+	if len(analysis.Packages) > maxWorkspacePackages {
+		analysis.Packages = analysis.Packages[:maxWorkspacePackages]
+	}
+	for i := range analysis.Packages {
+		if i >= maxNamed {
+			break
+		}
+		analysis.Packages[i].Name = workspacePackageName(analysis.Packages[i], present, fetch)
+	}
+	return analysis
+}
+
+// fetchPresent fetches a file only when it is in the tree
+func fetchPresent(present map[string]bool, fetch func(string) (string, bool), p string) (string, bool) {
+	if !present[p] {
+		return "", false
+	}
+	return fetch(p)
+}
+
+// npmWorkspacePatterns reads package.json "workspaces", which is either an
+// array of globs or, for yarn, an object with a "packages" array
+func npmWorkspacePatterns(raw json.RawMessage) []string {
+	var patterns []string
+	if json.Unmarshal(raw, &patterns) == nil {
+		return patterns
+	}
+	var obj struct {
+		Packages []string `json:"packages"`
+	}
+	if json.Unmarshal(raw, &obj) == nil {
+		return obj.Packages
+	}
+	return nil
+}
+
+// goWorkDirs returns the directories of the use directives in go.work
+func goWorkDirs(content string) []string {
+	var dirs []string
+	add := func(d string) {
+		d = path.Clean(strings.Trim(d, `"`))
+		if d != "." && !strings.HasPrefix(d, "..") {
+			dirs = append(dirs, d)
+		}
+	}
+	for _, block := range goWorkUseBlock.FindAllStringSubmatch(content, -1) {
+		for _, line := range strings.Split(block[1], "\n") {
+			if fields := strings.Fields(stripGoComment(line)); len(fields) > 0 {
+				add(fields[0])
+			}
+		}
+	}
+	for _, m := range goWorkUsePattern.FindAllStringSubmatch(content, -1) {
+		if m[1] != "(" {
+			add(m[1])
+		}
+	}
+	return dirs
+}
+
+func stripGoComment(line string) string {
+	if i := strings.Index(line, "//"); i >= 0 {
+		return line[:i]
+	}
+	return line
+}
+
+// matchWorkspaceDirs returns the candidate directories matched by the
+// workspace globs; patterns starting with ! exclude directories again
+func matchWorkspaceDirs(patterns, candidates []string) []string {
+	var matched []string
+	for _, dir := range candidates {
+		include := false
+		for _, p := range patterns {
+			negate := strings.HasPrefix(p, "!")
+			if matchWorkspaceGlob(strings.TrimPrefix(p, "!"), dir) {
+				include = !negate
+			}
+		}
+		if include {
+			matched = append(matched, dir)
+		}
+	}
+	sort.Strings(matched)
+	return matched
+}
+
+// matchWorkspaceGlob matches a directory against a workspace glob such as
+// "packages/*" or "apps/**"; ** matches any number of directories
+func matchWorkspaceGlob(pattern, dir string) bool {
+	pattern = strings.TrimSuffix(strings.TrimPrefix(path.Clean(pattern), "./"), "/")
+	return matchGlobSegments(strings.Split(pattern, "/"), strings.Split(dir, "/"))
+}
+
+func matchGlobSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchGlobSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+		return false
+	}
+	return matchGlobSegments(pattern[1:], segments[1:])
+}
+
+// topMostDirs drops directories nested in another listed directory
+func topMostDirs(dirs []string) []string {
+	set := make(map[string]bool)
+	for _, d := range dirs {
+		set[d] = true
+	}
+	var top []string
+	for _, d := range dirs {
+		nested := false
+		for parent := path.Dir(d); parent != "." && parent != "/"; parent = path.Dir(parent) {
+			if set[parent] {
+				nested = true
+				break
+			}
+		}
+		if !nested && d != "" {
+			top = append(top, d)
+		}
+	}
+	return top
+}
+
+// workspacePackageName reads the package name from its manifest, falling
+// back to the directory name
+func workspacePackageName(pkg WorkspacePackage, present map[string]bool, fetch func(string) (string, bool)) string {
+	switch pkg.Tool {
+	case "npm", "yarn", "pnpm", "lerna", "nx":
+		for _, manifest := range []string{"package.json", "project.json"} {
+			if content, ok := fetchPresent(present, fetch, path.Join(pkg.Path, manifest)); ok {
+				var m struct {
+					Name string `json:"name"`
+				}
+				if json.Unmarshal([]byte(content), &m) == nil && m.Name != "" {
+					return m.Name
+				}
+			}
+		}
+	case "go":
+		if content, ok := fetchPresent(present, fetch, path.Join(pkg.Path, "go.mod")); ok {
+			if m := goModulePattern.FindStringSubmatch(content); m != nil {
+				return m[1]
+			}
+		}
+	case "cargo":
+		if content, ok := fetchPresent(present, fetch, path.Join(pkg.Path, "Cargo.toml")); ok {
+			if doc, err := parseTOML([]byte(content)); err == nil {
+				if name := tomlString(tomlTable(doc, "package"), "name"); name != "" {
+					return name
+				}
+			}
+		}
+	case "bazel":
+		return "//" + pkg.Path
+	}
+	return pkg.Name
+}
+
+// AnalyzeWorkspacePackages fills in the per-package metrics from the
+// repository-wide analyses. deps, security and codeOwners may be nil.
+func AnalyzeWorkspacePackages(mono *MonorepoAnalysis, fileTree []github.TreeEntry, deps *DependencyAnalysis, security *SecurityScanResult, codeOwners *CodeOwnersAnalysis) {
+	if mono == nil || len(mono.Packages) == 0 {
+		return
+	}
+
+	trees := make([][]github.TreeEntry, len(mono.Packages))
+	ownerFiles := make([]map[string]int, len(mono.Packages))
+	for i := range mono.Packages {
+		mono.Packages[i].Languages = make(map[string]int)
+		mono.Packages[i].DependencyFiles = []string{}
+		mono.Packages[i].Owners = []string{}
+		mono.Packages[i].RecentCommits = -1
+		ownerFiles[i] = make(map[string]int)
+	}
+
+	for _, entry := range fileTree {
+		if entry.Type != "blob" {
+			continue
+		}
+		i := mono.packageIndex(entry.Path)
+		if i < 0 {
+			continue
+		}
+		pkg := &mono.Packages[i]
+		pkg.Files++
+		trees[i] = append(trees[i], entry)
+		if lang, ok := sourceLanguages[path.Ext(entry.Path)]; ok {
+			pkg.Languages[lang] += entry.Size
+		}
+		if codeOwners != nil {
+			if owners := MatchCodeOwners(codeOwners.Rules, entry.Path); len(owners) > 0 {
+				pkg.OwnedFiles++
+				for _, o := range owners {
+					ownerFiles[i][o]++
+				}
+			}
+		}
+	}
+
+	for i := range mono.Packages {
+		pkg := &mono.Packages[i]
+		tests := MapTestsToSources(trees[i])
+		pkg.SourceFiles, pkg.TestFiles, pkg.TestedSources = tests.SourceFiles, tests.TestFiles, tests.TestedSources
+		pkg.TestCoverage = tests.Coverage
+
+		for o := range ownerFiles[i] {
+			pkg.Owners = append(pkg.Owners, o)
+		}
+		sort.Slice(pkg.Owners, func(a, b int) bool {
+			fa, fb := ownerFiles[i][pkg.Owners[a]], ownerFiles[i][pkg.Owners[b]]
+			if fa != fb {
+				return fa > fb
+			}
+			return pkg.Owners[a] < pkg.Owners[b]
+		})
+		if len(pkg.Owners) > maxOwnersPerPackage {
+			pkg.Owners = pkg.Owners[:maxOwnersPerPackage]
+		}
+	}
+
+	if deps == nil {
+		return
+	}
+	// Packages are keyed the way scanTargets keys them, so a vulnerability
+	// only counts for the packages that resolve the affected version
+	declared := make([]map[string]bool, len(mono.Packages))
+	declare := func(i int, fileType, name, version string) {
+		if declared[i] == nil {
+			declared[i] = make(map[string]bool)
+		}
+		declared[i][mapEcosystem(fileType)+"|"+strings.ToLower(name)+"|"+version] = true
+	}
+	for _, file := range deps.Files {
+		i := mono.packageIndex(file.Filename)
+		if i < 0 {
+			continue
+		}
+		pkg := &mono.Packages[i]
+		pkg.DependencyFiles = append(pkg.DependencyFiles, file.Filename)
+		pkg.Dependencies += len(file.Dependencies)
+		for _, dep := range file.Dependencies {
+			version := dep.Resolved
+			if version == "" {
+				version = dep.Version
+			}
+			declare(i, file.FileType, dep.Name, version)
+		}
+	}
+	for _, lf := range deps.LockFiles {
+		if i := mono.packageIndex(lf.Filename); i >= 0 {
+			for _, p := range lf.Packages {
+				declare(i, lf.FileType, p.Name, p.Version)
+			}
+		}
+	}
+	if security == nil {
+		return
+	}
+	for i := range mono.Packages {
+		for _, v := range security.Vulnerabilities {
+			if declared[i][v.Ecosystem+"|"+strings.ToLower(v.Package)+"|"+v.Version] {
+				mono.Packages[i].Vulnerabilities++
+				if v.Severity == "CRITICAL" {
+					mono.Packages[i].CriticalVulns++
+				}
+			}
+		}
+	}
+}
+
+// packageIndex returns the innermost package containing a file, or -1
+func (m *MonorepoAnalysis) packageIndex(file string) int {
+	best := -1
+	for i, pkg := range m.Packages {
+		if strings.HasPrefix(file, pkg.Path+"/") && (best < 0 || len(pkg.Path) > len(m.Packages[best].Path)) {
+			best = i
+		}
+	}
+	return best
+}
+
+// FetchWorkspaceActivity counts the commits of the last days in each
+// package directory, for at most max packages
+func FetchWorkspaceActivity(client *github.Client, owner, repo string, mono *MonorepoAnalysis, days, max int) {
+	if mono == nil {
+		return
+	}
+	for i := range mono.Packages {
+		if i >= max {
+			break
+		}
+		commits, err := client.GetCommitsForPath(owner, repo, mono.Packages[i].Path, days)
+		if err != nil {
+			continue
+		}
+		setWorkspaceActivity(&mono.Packages[i], commits)
+	}
+}
+
+func setWorkspaceActivity(pkg *WorkspacePackage, commits []github.Commit) {
+	pkg.RecentCommits = len(commits)
+	for _, c := range commits {
+		date := c.Commit.Author.Date
+		if pkg.LastCommit == nil || date.After(*pkg.LastCommit) {
+			pkg.LastCommit = &date
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+func TestMatchWorkspaceGlob(t *testing.T) {
+	tests := []struct {
+		pattern, dir string
+		want         bool
+	}{
+		{"packages/*", "packages/web", true},
+		{"packages/*", "packages/web/src", false},
+		{"./apps/*/", "apps/api", true},
+		{"apps/**", "apps/api/v2", true},
+		{"libs/**/core", "libs/core", true},
+		{"libs/**/core", "libs/a/b/core", true},
+		{"tools", "tools", true},
+		{"tools", "toolsx", false},
+	}
+	for _, tt := range tests {
+		if got := matchWorkspaceGlob(tt.pattern, tt.dir); got != tt.want {
+			t.Errorf("matchWorkspaceGlob(%q, %q) = %v, want %v", tt.pattern, tt.dir, got, tt.want)
+		}
+	}
+}
+
+func TestGoWorkDirs(t *testing.T) {
+	content := "go 1.22\n\nuse (\n\t./api // service\n\t./lib\n\t.\n)\n\nuse ./tools\n"
+	if got := strings.Join(goWorkDirs(content), ","); got != "api,lib,tools" {
+		t.Errorf("goWorkDirs() = %q, want api,lib,tools", got)
+	}
+}
+
+func TestDetectWorkspaces(t *testing.T) {
+	files := map[string]string{
+		"package.json":                 `{"name": "root", "workspaces": {"packages": ["packages/*", "!packages/legacy"]}}`,
+		"yarn.lock":                    "",
+		"packages/web/package.json":    `{"name": "@acme/web"}`,
+		"packages/ui/package.json":     `{"name": "@acme/ui"}`,
+		"packages/legacy/package.json": `{"name": "legacy"}`,
+		"docs/package.json":            `{"name": "docs"}`,
+		"go.work":                      "go 1.22\nuse ./services/api\n",
+		"services/api/go.mod":          "module github.com/acme/api\n",
+		"Cargo.toml":                   "[workspace]\nmembers = [\"crates/*\"]\nexclude = [\"crates/scratch\"]\n",
+		"crates/parser/Cargo.toml":     "[package]\nname = \"acme-parser\"\n",
+		"crates/scratch/Cargo.toml":    "[package]\nname = \"scratch\"\n",
+		"node_modules/x/package.json":  `{"name": "x"}`,
+	}
+	var tree []github.TreeEntry
+	for p := range files {
+		tree = append(tree, github.TreeEntry{Path: p, Type: "blob"})
+	}
+	fetch := func(p string) (string, bool) {
+		content, ok := files[p]
+		return content, ok
+	}
+
+	mono := detectWorkspaces(tree, fetch, maxWorkspacePackages)
+
+	if got := strings.Join(mono.Tools, ","); got != "yarn,go,cargo" {
+		t.Errorf("Tools = %q, want yarn,go,cargo", got)
+	}
+	var names []string
+	for _, p := range mono.Packages {
+		names = append(names, p.Path+"="+p.Name)
+	}
+	want := "crates/parser=acme-parser,packages/ui=@acme/ui,packages/web=@acme/web,services/api=github.com/acme/api"
+	if got := strings.Join(names, ","); got != want {
+		t.Errorf("Packages = %s\nwant       %s", got, want)
+	}
+	if !mono.IsMonorepo() {
+		t.Error("IsMonorepo() = false")
+	}
+	if few := detectWorkspaces(tree, fetch, 1); few.Packages[0].Name != "acme-parser" || few.Packages[1].Name != "ui" {
+		t.Errorf("with maxNamed 1, names = %s, %s; want acme-parser, ui", few.Packages[0].Name, few.Packages[1].Name)
+	}
+
+	bazel := detectWorkspaces([]github.TreeEntry{
+		{Path: "MODULE.bazel", Type: "blob"},
+		{Path: "BUILD", Type: "blob"},
+		{Path: "server/BUILD.bazel", Type: "blob"},
+		{Path: "server/handlers/BUILD.bazel", Type: "blob"},
+		{Path: "client/BUILD", Type: "blob"},
+	}, fetch, maxWorkspacePackages)
+	if len(bazel.Packages) != 2 || bazel.Packages[0].Name != "//client" || bazel.Packages[1].Path != "server" {
+		t.Errorf("bazel packages = %+v, want //client and //server", bazel.Packages)
+	}
+}
+
+func TestAnalyzeWorkspacePackages(t *testing.T) {
+	mono := &MonorepoAnalysis{Packages: []WorkspacePackage{
+		{Name: "api", Path: "services/api", Tool: "go"},
+		{Name: "web", Path: "web", Tool: "npm"},
+		{Name: "widgets", Path: "web/widgets", Tool: "npm"},
+	}}
+	var tree []github.TreeEntry
+	for _, p := range []string{
+		"services/api/go.mod",
+		"services/api/server.go",
+		"services/api/server_test.go",
+		"services/api/db.go",
+		"web/package.json",
+		"web/src/app.ts",
+		"web/widgets/package.json",
+		"web/widgets/button.tsx",
+		"README.md",
+	} {
+		tree = append(tree, github.TreeEntry{Path: p, Type: "blob", Size: 100})
+	}
+	deps := &DependencyAnalysis{Files: []DependencyFile{
+		{Filename: "services/api/go.mod", FileType: "go", Dependencies: []Dependency{{Name: "github.com/gin-gonic/gin", Version: "v1.9.0"}}},
+		{Filename: "web/package.json", FileType: "npm", Dependencies: []Dependency{{Name: "lodash", Version: "4.17.20"}, {Name: "react", Version: "^18.0.0", Resolved: "18.2.0"}}},
+		{Filename: "web/widgets/package.json", FileType: "npm", Dependencies: []Dependency{{Name: "react", Version: "18.3.1"}}},
+	}, LockFiles: []LockFile{
+		{Filename: "web/widgets/package-lock.json", FileType: "npm", Packages: []LockedPackage{{Name: "ms", Version: "2.0.0"}}},
+	}}
+	security := &SecurityScanResult{Vulnerabilities: []Vulnerability{
+		{Package: "lodash", Ecosystem: "npm", Version: "4.17.20", Severity: "CRITICAL"},
+		{Package: "react", Ecosystem: "npm", Version: "18.2.0", Severity: "LOW"},
+		{Package: "ms", Ecosystem: "npm", Version: "2.0.0", Severity: "MEDIUM"},
+		{Package: "lodash", Ecosystem: "PyPI", Version: "4.17.20", Severity: "HIGH"},
+	}}
+	rules, _ := ParseCodeOwners("/services/ @backend\n/web/ @frontend\n")
+
+	AnalyzeWorkspacePackages(mono, tree, deps, security, &CodeOwnersAnalysis{Rules: rules})
+
+	api, web, widgets := mono.Packages[0], mono.Packages[1], mono.Packages[2]
+	if api.Files != 4 || api.SourceFiles != 2 || api.TestCoverage != 0.5 || api.Languages["Go"] != 300 {
+		t.Errorf("api = %+v", api)
+	}
+	if web.Files != 2 || web.Dependencies != 2 || web.Vulnerabilities != 2 || web.CriticalVulns != 1 {
+		t.Errorf("web = %+v, want the widgets files left out", web)
+	}
+	// widgets pins a safe react, but its own lock file has a vulnerable ms
+	if widgets.Vulnerabilities != 1 || strings.Join(widgets.Owners, ",") != "@frontend" || widgets.RecentCommits != -1 {
+		t.Errorf("widgets = %+v", widgets)
+	}
+
+	now := time.Now()
+	c := github.Commit{}
+	c.Commit.Author.Date = now
+	setWorkspaceActivity(&mono.Packages[0], []github.Commit{{}, c})
+	if mono.Packages[0].RecentCommits != 2 || !mono.Packages[0].LastCommit.Equal(now) {
+		t.Errorf("activity = %d, %v", mono.Packages[0].RecentCommits, mono.Packages[0].LastCommit)
+	}
+}
@@ -0,0 +1,196 @@
+package analyzer
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// PunchCard counts commits by weekday and hour in each commit's own UTC
+// offset, and infers where contributors work from those offsets. The REST
+// API reports every date in UTC, so callers restore the offsets with
+// ApplyCommitDates first; when none were restored, KnownOffsets is false and
+// the hours are UTC.
+type PunchCard struct {
+	Matrix            [7][24]int      `json:"matrix"` // [weekday][hour], Sunday first
+	TotalCommits      int             `json:"total_commits"`
+	PeakDay           string          `json:"peak_day"`
+	PeakHour          int             `json:"peak_hour"`
+	WeekendCommits    int             `json:"weekend_commits"`
+	WeekendRatio      float64         `json:"weekend_ratio"`
+	AfterHoursCommits int             `json:"after_hours_commits"` // weekday commits outside working hours
+	AfterHoursRatio   float64         `json:"after_hours_ratio"`
+	Timezones         []TimezoneCount `json:"timezones"`       // most contributors first
+	TimezoneSpread    int             `json:"timezone_spread"` // hours between the westernmost and easternmost contributor
+	KnownOffsets      bool            `json:"known_offsets"`
+}
+
+// TimezoneCount is the number of contributors whose commits mostly carry
+// one UTC offset
+type TimezoneCount struct {
+	Offset       string `json:"offset"` // e.g. UTC+05:30
+	Contributors int    `json:"contributors"`
+	Commits      int    `json:"commits"`
+}
+
+// Working hours for the after-hours ratio, in the commit's local time
+const (
+	workdayStartHour = 9
+	workdayEndHour   = 18
+)
+
+// AnalyzePunchCard builds the weekday × hour matrix and timezone spread.
+// knownOffsets reports whether ApplyCommitDates restored the commits' UTC
+// offsets; a team working in UTC+0 is indistinguishable from UTC dates
+// otherwise.
+func AnalyzePunchCard(commits []github.Commit, knownOffsets bool) *PunchCard {
+	pc := &PunchCard{Timezones: []TimezoneCount{}, KnownOffsets: knownOffsets}
+	authorOffsets := make(map[string]map[int]int)
+
+	for _, c := range commits {
+		date := c.Commit.Author.Date
+		if date.IsZero() {
+			continue
+		}
+		pc.TotalCommits++
+		pc.Matrix[date.Weekday()][date.Hour()]++
+
+		switch date.Weekday() {
+		case time.Saturday, time.Sunday:
+			pc.WeekendCommits++
+		default:
+			if date.Hour() < workdayStartHour || date.Hour() >= workdayEndHour {
+				pc.AfterHoursCommits++
+			}
+		}
+
+		_, offset := date.Zone()
+		author := commitAuthorKey(c)
+		if authorOffsets[author] == nil {
+			authorOffsets[author] = make(map[int]int)
+		}
+		authorOffsets[author][offset]++
+	}
+	if pc.TotalCommits == 0 {
+		return pc
+	}
+
+	pc.WeekendRatio = float64(pc.WeekendCommits) / float64(pc.TotalCommits)
+	pc.AfterHoursRatio = float64(pc.AfterHoursCommits) / float64(pc.TotalCommits)
+
+	peak := -1
+	for day := range pc.Matrix {
+		for hour, n := range pc.Matrix[day] {
+			if n > peak {
+				peak = n
+				pc.PeakDay = time.Weekday(day).String()
+				pc.PeakHour = hour
+			}
+		}
+	}
+
+	// Each contributor is placed at the offset most of their commits carry
+	type zone struct{ contributors, commits int }
+	zones := make(map[int]*zone)
+	for _, offsets := range authorOffsets {
+		best, bestCount, commits := 0, -1, 0
+		for offset, n := range offsets {
+			commits += n
+			if n > bestCount || n == bestCount && offset < best {
+				best, bestCount = offset, n
+			}
+		}
+		if zones[best] == nil {
+			zones[best] = &zone{}
+		}
+		zones[best].contributors++
+		zones[best].commits += commits
+	}
+
+	var offsets []int
+	for offset := range zones {
+		offsets = append(offsets, offset)
+	}
+	sort.Ints(offsets)
+	pc.TimezoneSpread = (offsets[len(offsets)-1] - offsets[0]) / 3600
+	for _, offset := range offsets {
+		pc.Timezones = append(pc.Timezones, TimezoneCount{
+			Offset:       FormatUTCOffset(offset),
+			Contributors: zones[offset].contributors,
+			Commits:      zones[offset].commits,
+		})
+	}
+	sort.SliceStable(pc.Timezones, func(i, j int) bool {
+		return pc.Timezones[i].Contributors > pc.Timezones[j].Contributors
+	})
+	return pc
+}
+
+// ReadLocalCommitDates reads the author dates of a local clone's commits
+// since the given time, keyed by SHA, with the author's original UTC offset
+func ReadLocalCommitDates(repoPath string, since time.Time) (map[string]time.Time, error) {
+	cmd := exec.Command("git", "-C", repoPath, "log", "--format=%H %aI", "--since="+since.Format(time.RFC3339))
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log failed: %w", err)
+	}
+	return parseGitLogDates(string(out)), nil
+}
+
+// parseGitLogDates parses `git log --format="%H %aI"` output
+func parseGitLogDates(out string) map[string]time.Time {
+	dates := make(map[string]time.Time)
+	for _, line := range strings.Split(out, "\n") {
+		sha, date, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339, date); err == nil {
+			dates[sha] = t
+		}
+	}
+	return dates
+}
+
+// ApplyCommitDates replaces the UTC author dates the REST API returns with
+// the same instants in their original offsets, and returns how many commits
+// were found in dates
+func ApplyCommitDates(commits []github.Commit, dates map[string]time.Time) int {
+	applied := 0
+	for i := range commits {
+		if date, ok := dates[commits[i].SHA]; ok {
+			commits[i].Commit.Author.Date = date
+			applied++
+		}
+	}
+	return applied
+}
+
+// commitAuthorKey identifies a commit's author by login, falling back to
+// the git email for authors without a GitHub account
+func commitAuthorKey(c github.Commit) string {
+	if c.Author != nil && c.Author.Login != "" {
+		return c.Author.Login
+	}
+	if c.Commit.Author.Email != "" {
+		return strings.ToLower(c.Commit.Author.Email)
+	}
+	return c.Commit.Author.Name
+}
+
+// FormatUTCOffset renders an offset in seconds as UTC, UTC+05:30 or UTC-08:00
+func FormatUTCOffset(seconds int) string {
+	if seconds == 0 {
+		return "UTC"
+	}
+	sign := "+"
+	if seconds < 0 {
+		sign = "-"
+		seconds = -seconds
+	}
+	return fmt.Sprintf("UTC%s%02d:%02d", sign, seconds/3600, seconds%3600/60)
+}
@@ -0,0 +1,94 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+func commitAt(login string, date time.Time) github.Commit {
+	c := github.Commit{Author: &github.User{Login: login}}
+	c.Commit.Author.Date = date
+	return c
+}
+
+func TestAnalyzePunchCard(t *testing.T) {
+	berlin := time.FixedZone("", 2*3600)
+	india := time.FixedZone("", 5*3600+1800)
+	pacific := time.FixedZone("", -7*3600)
+
+	commits := []github.Commit{
+		commitAt("alice", time.Date(2026, 6, 1, 10, 0, 0, 0, berlin)),  // Monday
+		commitAt("alice", time.Date(2026, 6, 1, 10, 30, 0, 0, berlin)), // Monday
+		commitAt("alice", time.Date(2026, 6, 2, 22, 0, 0, 0, berlin)),  // Tuesday, after hours
+		commitAt("bob", time.Date(2026, 6, 6, 11, 0, 0, 0, india)),     // Saturday
+		commitAt("carol", time.Date(2026, 6, 3, 7, 0, 0, 0, pacific)),  // Wednesday, after hours
+		commitAt("carol", time.Date(2026, 6, 4, 14, 0, 0, 0, berlin)),  // travelling
+		commitAt("carol", time.Date(2026, 6, 5, 14, 0, 0, 0, pacific)),
+		{},
+	}
+
+	pc := AnalyzePunchCard(commits, true)
+
+	if pc.TotalCommits != 7 || pc.Matrix[time.Monday][10] != 2 || pc.Matrix[time.Saturday][11] != 1 {
+		t.Errorf("TotalCommits = %d, Matrix = %v", pc.TotalCommits, pc.Matrix)
+	}
+	if pc.PeakDay != "Monday" || pc.PeakHour != 10 {
+		t.Errorf("peak = %s %d:00, want Monday 10:00", pc.PeakDay, pc.PeakHour)
+	}
+	if pc.WeekendCommits != 1 || pc.AfterHoursCommits != 2 {
+		t.Errorf("WeekendCommits, AfterHoursCommits = %d, %d; want 1, 2", pc.WeekendCommits, pc.AfterHoursCommits)
+	}
+	if !pc.KnownOffsets || pc.TimezoneSpread != 12 || len(pc.Timezones) != 3 {
+		t.Errorf("KnownOffsets, TimezoneSpread, Timezones = %v, %d, %+v", pc.KnownOffsets, pc.TimezoneSpread, pc.Timezones)
+	}
+	for _, tz := range pc.Timezones {
+		if tz.Offset == "UTC-07:00" && (tz.Contributors != 1 || tz.Commits != 3) {
+			t.Errorf("UTC-07:00 = %+v, want carol's 3 commits", tz)
+		}
+	}
+
+	utcCommits := []github.Commit{commitAt("dave", time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC))}
+	if utc := AnalyzePunchCard(utcCommits, false); utc.KnownOffsets || utc.Timezones[0].Offset != "UTC" {
+		t.Errorf("punch card without restored offsets = %+v", utc)
+	}
+
+	// Restored offsets that are all UTC+0 are still known
+	if utc := AnalyzePunchCard(utcCommits, true); !utc.KnownOffsets || utc.Timezones[0].Offset != "UTC" {
+		t.Errorf("punch card with restored UTC offsets = %+v", utc)
+	}
+}
+
+func TestFormatUTCOffset(t *testing.T) {
+	for seconds, want := range map[int]string{0: "UTC", 19800: "UTC+05:30", -28800: "UTC-08:00", -9000: "UTC-02:30"} {
+		if got := FormatUTCOffset(seconds); got != want {
+			t.Errorf("FormatUTCOffset(%d) = %q, want %q", seconds, got, want)
+		}
+	}
+}
+
+func TestApplyCommitDates(t *testing.T) {
+	dates := parseGitLogDates("a1b2 2026-06-01T10:00:00+02:00\nc3d4 2026-06-02T22:30:00-07:00\nnot a date line\n")
+	if len(dates) != 2 {
+		t.Fatalf("parseGitLogDates() = %v, want 2 dates", dates)
+	}
+
+	utc := commitAt("alice", time.Date(2026, 6, 1, 8, 0, 0, 0, time.UTC))
+	utc.SHA = "a1b2"
+	other := commitAt("bob", time.Date(2026, 6, 3, 5, 30, 0, 0, time.UTC))
+	other.SHA = "ffff"
+	commits := []github.Commit{utc, other}
+
+	n := ApplyCommitDates(commits, dates)
+	if n != 1 {
+		t.Errorf("ApplyCommitDates() = %d, want 1", n)
+	}
+	date := commits[0].Commit.Author.Date
+	if _, offset := date.Zone(); offset != 2*3600 || date.Hour() != 10 || !date.Equal(utc.Commit.Author.Date) {
+		t.Errorf("restored date = %v, want 10:00 UTC+02:00", date)
+	}
+	if pc := AnalyzePunchCard(commits, n > 0); !pc.KnownOffsets || pc.Matrix[time.Monday][10] != 1 {
+		t.Errorf("KnownOffsets, Matrix[Monday][10] = %v, %d", pc.KnownOffsets, pc.Matrix[time.Monday][10])
+	}
+}
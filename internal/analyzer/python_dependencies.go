@@ -0,0 +1,224 @@
+// Package analyzer provides analysis functions for GitHub repositories.
+// This file contains parsers for Python project manifests written in TOML:
+// pyproject.toml (PEP 621, PEP 735, Poetry, PDM, uv) and Pipenv's Pipfile.
+package analyzer
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// pep508NamePattern matches the distribution name at the start of a PEP 508 requirement
+var pep508NamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*`)
+
+// parsePEP508 splits a PEP 508 requirement string into name and version specifier.
+// Extras, environment markers and direct URL references are dropped.
+//
+// Examples:
+//
+//	"requests[socks]>=2.28,<3 ; python_version >= '3.8'" -> ("requests", ">=2.28,<3")
+//	"pkg @ https://example.com/pkg.whl"                    -> ("pkg", "*")
+func parsePEP508(req string) (string, string) {
+	// Drop environment markers
+	if idx := strings.Index(req, ";"); idx >= 0 {
+		req = req[:idx]
+	}
+	req = strings.TrimSpace(req)
+
+	name := pep508NamePattern.FindString(req)
+	if name == "" {
+		return "", ""
+	}
+	rest := strings.TrimSpace(req[len(name):])
+
+	// Drop extras
+	if strings.HasPrefix(rest, "[") {
+		if end := strings.Index(rest, "]"); end >= 0 {
+			rest = strings.TrimSpace(rest[end+1:])
+		}
+	}
+
+	// Direct references carry no version
+	if strings.HasPrefix(rest, "@") {
+		return name, "*"
+	}
+
+	rest = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(rest, "("), ")"))
+	rest = strings.ReplaceAll(rest, " ", "")
+	if rest == "" {
+		rest = "*"
+	}
+	return name, rest
+}
+
+// parsePyprojectToml parses a pyproject.toml file.
+// It understands PEP 621 [project] dependencies and optional-dependencies,
+// PEP 735 [dependency-groups], Poetry's [tool.poetry.*dependencies] tables and
+// groups, and the dev dependency tables of PDM and uv.
+//
+// Example pyproject.toml:
+//
+//	[project]
+//	dependencies = ["httpx>=0.24"]
+//
+//	[project.optional-dependencies]
+//	cli = ["rich>=13"]
+//
+//	[tool.poetry.group.dev.dependencies]
+//	pytest = "^7.4"
+func parsePyprojectToml(content []byte) ([]Dependency, string) {
+	doc, err := parseTOML(content)
+	if err != nil {
+		return nil, "python"
+	}
+
+	var deps []Dependency
+	addRequirements := func(reqs []string, depType string) {
+		for _, req := range reqs {
+			if name, version := parsePEP508(req); name != "" {
+				deps = append(deps, Dependency{Name: name, Version: version, Type: depType})
+			}
+		}
+	}
+
+	// PEP 621
+	if project := tomlTable(doc, "project"); project != nil {
+		addRequirements(tomlStrings(project, "dependencies"), "production")
+		if optional := tomlTable(project, "optional-dependencies"); optional != nil {
+			for _, extra := range sortedKeys(optional) {
+				addRequirements(tomlStrings(optional, extra), "optional")
+			}
+		}
+	}
+
+	// PEP 735 dependency groups
+	if groups := tomlTable(doc, "dependency-groups"); groups != nil {
+		for _, group := range sortedKeys(groups) {
+			addRequirements(tomlStrings(groups, group), "dev")
+		}
+	}
+
+	// Poetry
+	if poetry := tomlTable(doc, "tool", "poetry"); poetry != nil {
+		deps = append(deps, poetryDependencies(tomlTable(poetry, "dependencies"), "production")...)
+		deps = append(deps, poetryDependencies(tomlTable(poetry, "dev-dependencies"), "dev")...)
+
+		if groups := tomlTable(poetry, "group"); groups != nil {
+			for _, name := range sortedKeys(groups) {
+				group, _ := groups[name].(map[string]interface{})
+				depType := "dev"
+				if name == "main" {
+					depType = "production"
+				}
+				deps = append(deps, poetryDependencies(tomlTable(group, "dependencies"), depType)...)
+			}
+		}
+	}
+
+	// PDM
+	if pdmDev := tomlTable(doc, "tool", "pdm", "dev-dependencies"); pdmDev != nil {
+		for _, group := range sortedKeys(pdmDev) {
+			addRequirements(tomlStrings(pdmDev, group), "dev")
+		}
+	}
+
+	// uv
+	if uv := tomlTable(doc, "tool", "uv"); uv != nil {
+		addRequirements(tomlStrings(uv, "dev-dependencies"), "dev")
+	}
+
+	return deps, "python"
+}
+
+// poetryDependencies converts a Poetry dependency table into dependencies.
+// Values may be a version string, an inline table with a "version" key, or
+// an array of such tables for multiple-constraint dependencies.
+func poetryDependencies(table map[string]interface{}, depType string) []Dependency {
+	var deps []Dependency
+	for _, name := range sortedKeys(table) {
+		if strings.EqualFold(name, "python") {
+			continue
+		}
+
+		version, optional := tomlDependencySpec(table[name])
+		t := depType
+		if optional && depType == "production" {
+			t = "optional"
+		}
+		deps = append(deps, Dependency{Name: name, Version: version, Type: t})
+	}
+	return deps
+}
+
+// tomlDependencySpec extracts the version and optional flag from a TOML
+// dependency value as used by Poetry and Pipenv.
+func tomlDependencySpec(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		if v == "" {
+			return "*", false
+		}
+		return v, false
+	case map[string]interface{}:
+		version := tomlString(v, "version")
+		if version == "" {
+			version = "*"
+		}
+		optional, _ := v["optional"].(bool)
+		return version, optional
+	case []interface{}:
+		if len(v) > 0 {
+			return tomlDependencySpec(v[0])
+		}
+	}
+	return "*", false
+}
+
+// parsePipfile parses a Pipenv Pipfile.
+// [packages] are production dependencies and [dev-packages] are dev
+// dependencies; git, path and editable entries are reported with version "*".
+//
+// Example Pipfile:
+//
+//	[packages]
+//	requests = "*"
+//	django = {version = ">=4.2", extras = ["argon2"]}
+//
+//	[dev-packages]
+//	pytest = "==7.4.0"
+func parsePipfile(content []byte) ([]Dependency, string) {
+	doc, err := parseTOML(content)
+	if err != nil {
+		return nil, "python"
+	}
+
+	var deps []Dependency
+	sections := []struct {
+		table   string
+		depType string
+	}{
+		{"packages", "production"},
+		{"dev-packages", "dev"},
+	}
+
+	for _, section := range sections {
+		table := tomlTable(doc, section.table)
+		for _, name := range sortedKeys(table) {
+			version, _ := tomlDependencySpec(table[name])
+			deps = append(deps, Dependency{Name: name, Version: version, Type: section.depType})
+		}
+	}
+
+	return deps, "python"
+}
+
+// sortedKeys returns the keys of a table in sorted order for stable output
+func sortedKeys(table map[string]interface{}) []string {
+	keys := make([]string, 0, len(table))
+	for k := range table {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,152 @@
+package analyzer
+
+import (
+	"testing"
+)
+
+func TestParsePEP508(t *testing.T) {
+	tests := []struct {
+		req         string
+		wantName    string
+		wantVersion string
+	}{
+		{"requests", "requests", "*"},
+		{"requests>=2.28", "requests", ">=2.28"},
+		{"requests[socks] >= 2.28, < 3 ; python_version >= '3.8'", "requests", ">=2.28,<3"},
+		{"Django (>=4.2)", "Django", ">=4.2"},
+		{"pkg @ https://example.com/pkg.whl", "pkg", "*"},
+		{"zope.interface~=6.0", "zope.interface", "~=6.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.req, func(t *testing.T) {
+			name, version := parsePEP508(tt.req)
+			if name != tt.wantName || version != tt.wantVersion {
+				t.Errorf("parsePEP508(%q) = (%q, %q), want (%q, %q)", tt.req, name, version, tt.wantName, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestParsePyprojectToml_PEP621(t *testing.T) {
+	content := []byte(`
+[build-system]
+requires = ["hatchling"]
+
+[project]
+name = "demo"
+dependencies = [
+    "httpx>=0.24",
+    "pydantic[email]>=2,<3",
+]
+
+[project.optional-dependencies]
+cli = ["rich>=13"]
+
+[dependency-groups]
+test = ["pytest>=7"]
+`)
+
+	deps, fileType := parsePyprojectToml(content)
+	if fileType != "python" {
+		t.Errorf("fileType = %s, want python", fileType)
+	}
+	m := depsByName(deps)
+	if len(deps) != 4 {
+		t.Fatalf("len(deps) = %d, want 4: %+v", len(deps), deps)
+	}
+	if got := m["pydantic"]; got.Version != ">=2,<3" || got.Type != "production" {
+		t.Errorf("pydantic = %+v, want production >=2,<3", got)
+	}
+	if got := m["rich"].Type; got != "optional" {
+		t.Errorf("rich type = %s, want optional", got)
+	}
+	if got := m["pytest"].Type; got != "dev" {
+		t.Errorf("pytest type = %s, want dev", got)
+	}
+	if _, ok := m["hatchling"]; ok {
+		t.Error("build-system requirements should not be reported")
+	}
+}
+
+func TestParsePyprojectToml_Poetry(t *testing.T) {
+	content := []byte(`
+[tool.poetry]
+name = "demo"
+
+[tool.poetry.dependencies]
+python = "^3.10"
+fastapi = "^0.100.0"
+uvicorn = { version = "^0.23", extras = ["standard"] }
+psycopg = { version = "^3.1", optional = true }
+numpy = [
+    { version = "<1.25", python = "<3.9" },
+    { version = "^1.25", python = ">=3.9" },
+]
+
+[tool.poetry.dev-dependencies]
+black = "^23.7"
+
+[tool.poetry.group.test.dependencies]
+pytest = "^7.4"
+`)
+
+	deps, _ := parsePyprojectToml(content)
+	m := depsByName(deps)
+	if _, ok := m["python"]; ok {
+		t.Error("python interpreter constraint should be skipped")
+	}
+	if len(deps) != 6 {
+		t.Fatalf("len(deps) = %d, want 6: %+v", len(deps), deps)
+	}
+	if got := m["uvicorn"].Version; got != "^0.23" {
+		t.Errorf("uvicorn version = %s, want ^0.23", got)
+	}
+	if got := m["psycopg"].Type; got != "optional" {
+		t.Errorf("psycopg type = %s, want optional", got)
+	}
+	if got := m["numpy"].Version; got != "<1.25" {
+		t.Errorf("numpy version = %s, want <1.25", got)
+	}
+	if got := m["black"].Type; got != "dev" {
+		t.Errorf("black type = %s, want dev", got)
+	}
+	if got := m["pytest"].Type; got != "dev" {
+		t.Errorf("pytest type = %s, want dev", got)
+	}
+}
+
+func TestParsePipfile(t *testing.T) {
+	content := []byte(`
+[[source]]
+url = "https://pypi.org/simple"
+verify_ssl = true
+name = "pypi"
+
+[packages]
+requests = "*"
+django = {version = ">=4.2", extras = ["argon2"]}
+mylib = {git = "https://github.com/me/mylib.git", editable = true}
+
+[dev-packages]
+pytest = "==7.4.0"
+
+[requires]
+python_version = "3.11"
+`)
+
+	deps, _ := parsePipfile(content)
+	m := depsByName(deps)
+	if len(deps) != 4 {
+		t.Fatalf("len(deps) = %d, want 4: %+v", len(deps), deps)
+	}
+	if got := m["django"].Version; got != ">=4.2" {
+		t.Errorf("django version = %s, want >=4.2", got)
+	}
+	if got := m["mylib"].Version; got != "*" {
+		t.Errorf("mylib version = %s, want *", got)
+	}
+	if got := m["pytest"]; got.Type != "dev" || got.Version != "==7.4.0" {
+		t.Errorf("pytest = %+v, want dev ==7.4.0", got)
+	}
+}
@@ -1,5 +1,7 @@
 package analyzer
 
+import "fmt"
+
 type RiskAlertsResult struct {
 	Alerts []string
 }
@@ -9,6 +11,7 @@ func AnalyzeRiskAlerts(
 	healthScore int,
 	commitsLast90Days int,
 	hasCriticalVulns bool,
+	leakedSecrets int,
 ) *RiskAlertsResult {
 
 	var alerts []string
@@ -29,6 +32,10 @@ func AnalyzeRiskAlerts(
 		alerts = append(alerts, "Critical dependency vulnerabilities detected")
 	}
 
+	if leakedSecrets > 0 {
+		alerts = append(alerts, fmt.Sprintf("Leaked credentials found in repository files (%d)", leakedSecrets))
+	}
+
 	if len(alerts) == 0 {
 		return nil
 	}
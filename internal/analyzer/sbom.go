@@ -0,0 +1,720 @@
+// Package analyzer provides analysis functions for GitHub repositories.
+// This file builds software bills of materials (SBOMs) from the dependency
+// analysis, in CycloneDX 1.5 JSON and SPDX 2.3 JSON and tag-value formats.
+// Vulnerabilities found by the security scan are attached to CycloneDX
+// components as VEX entries and to SPDX packages as advisory references.
+package analyzer
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SBOMSubject describes the repository an SBOM is generated for
+type SBOMSubject struct {
+	Name    string // Repository full name (e.g., "octocat/Hello-World")
+	Version string // Branch or commit the dependencies were read from
+	URL     string // Repository URL
+}
+
+// SBOM is the dependency inventory of a repository, ready to be rendered
+// in one of the supported SBOM formats.
+type SBOM struct {
+	Subject    SBOMSubject
+	Components []SBOMComponent
+	Vulns      []Vulnerability
+	Timestamp  time.Time
+	SerialUUID string // Unique document ID shared by all renderings
+}
+
+// SBOMComponent is a single package in the SBOM
+type SBOMComponent struct {
+	Ref       string   // Unique reference within the document
+	Name      string   // Package name as used by its ecosystem
+	Version   string   // Exact version, or the declared constraint when unresolved
+	Ecosystem string   // OSV ecosystem (e.g., "npm", "PyPI")
+	PURL      string   // Package URL
+	License   string   // SPDX expression or free-text license, empty when unknown
+	Direct    bool     // Declared in a manifest
+	Dev       bool     // Only needed for development
+	DependsOn []string // Refs of the components this one depends on
+}
+
+// NewSBOM collects the packages of a dependency analysis into an SBOM.
+// Licenses come from the compliance report when given, and from lock file
+// metadata otherwise; vulnerabilities come from the security scan. Both
+// may be nil.
+func NewSBOM(subject SBOMSubject, deps *DependencyAnalysis, security *SecurityScanResult, licenses *LicenseComplianceReport) *SBOM {
+	sbom := &SBOM{
+		Subject:    subject,
+		Timestamp:  time.Now().UTC(),
+		SerialUUID: newUUID(),
+	}
+	if security != nil {
+		sbom.Vulns = security.Vulnerabilities
+	}
+	if deps == nil {
+		return sbom
+	}
+
+	licenseOf := make(map[string]string)
+	dev := make(map[string]bool)
+	for _, lf := range deps.LockFiles {
+		ecosystem := mapEcosystem(lf.FileType)
+		for _, pkg := range lf.Packages {
+			key := ecosystem + "|" + pkg.Name + "@" + pkg.Version
+			dev[key] = pkg.Dev
+			if pkg.License != "" {
+				licenseOf[ecosystem+"|"+strings.ToLower(pkg.Name)] = pkg.License
+			}
+		}
+	}
+	for _, file := range deps.Files {
+		ecosystem := mapEcosystem(file.FileType)
+		for _, dep := range file.Dependencies {
+			version := dep.Resolved
+			if version == "" {
+				version = dep.Version
+			}
+			if dep.Type == "dev" {
+				dev[ecosystem+"|"+dep.Name+"@"+version] = true
+			}
+		}
+	}
+	if licenses != nil {
+		for _, dep := range licenses.Dependencies {
+			if dep.License != "" {
+				licenseOf[dep.Ecosystem+"|"+strings.ToLower(dep.Package)] = dep.License
+			}
+		}
+	}
+
+	refs := make(map[string]string) // ecosystem|name@version -> ref
+	for _, t := range scanTargets(deps) {
+		key := t.ecosystem + "|" + t.name + "@" + t.version
+		ref := t.ecosystem + "/" + t.name + "@" + t.version
+		refs[key] = ref
+		sbom.Components = append(sbom.Components, SBOMComponent{
+			Ref:       ref,
+			Name:      t.name,
+			Version:   t.version,
+			Ecosystem: t.ecosystem,
+			PURL:      PackageURL(t.ecosystem, t.name, t.version),
+			License:   licenseOf[t.ecosystem+"|"+strings.ToLower(t.name)],
+			Direct:    t.direct,
+			Dev:       dev[key],
+		})
+	}
+
+	// Container base images, which no vulnerability database covers
+	for _, file := range deps.Files {
+		if file.FileType != "docker" {
+			continue
+		}
+		for _, dep := range file.Dependencies {
+			key := "Docker|" + dep.Name + "@" + dep.Version
+			if _, ok := refs[key]; ok {
+				continue
+			}
+			ref := "Docker/" + dep.Name + "@" + dep.Version
+			refs[key] = ref
+			img := BaseImage{Image: dep.Name, Tag: dep.Version, Digest: dep.Resolved}
+			sbom.Components = append(sbom.Components, SBOMComponent{
+				Ref:       ref,
+				Name:      dep.Name,
+				Version:   dep.Version,
+				Ecosystem: "Docker",
+				PURL:      img.PackageURL(),
+				Direct:    true,
+			})
+		}
+	}
+
+	// Edges between locked packages
+	graph := BuildDependencyGraph(deps)
+	byRef := make(map[string]*SBOMComponent, len(sbom.Components))
+	for i := range sbom.Components {
+		byRef[sbom.Components[i].Ref] = &sbom.Components[i]
+	}
+	for _, node := range graph.Nodes {
+		ecosystem := node.Ecosystem()
+		c := byRef[refs[ecosystem+"|"+node.Label()]]
+		if c == nil {
+			continue
+		}
+		for _, id := range node.Dependencies {
+			child := graph.Node(id)
+			if ref, ok := refs[ecosystem+"|"+child.Label()]; ok && !contains(c.DependsOn, ref) {
+				c.DependsOn = append(c.DependsOn, ref)
+			}
+		}
+	}
+	return sbom
+}
+
+// component returns the component a vulnerability was reported for
+func (s *SBOM) component(v Vulnerability) *SBOMComponent {
+	for i := range s.Components {
+		c := &s.Components[i]
+		if c.Name == v.Package && c.Version == v.Version && (v.Ecosystem == "" || v.Ecosystem == c.Ecosystem) {
+			return c
+		}
+	}
+	return nil
+}
+
+// purlTypes maps OSV ecosystems to package URL types
+var purlTypes = map[string]string{
+	"npm":       "npm",
+	"Go":        "golang",
+	"PyPI":      "pypi",
+	"crates.io": "cargo",
+	"RubyGems":  "gem",
+	"Maven":     "maven",
+	"NuGet":     "nuget",
+	"Packagist": "composer",
+	"Pub":       "pub",
+	"Hex":       "hex",
+	"SwiftURL":  "swift",
+}
+
+// PackageURL returns the package URL (purl) of a package. The version is
+// only included when it is exact, since purls cannot express ranges.
+//
+// Examples:
+//
+//	npm, "@babel/core", "7.23.0"    -> pkg:npm/%40babel/core@7.23.0
+//	Maven, "org.slf4j:slf4j-api", "" -> pkg:maven/org.slf4j/slf4j-api
+//	PyPI, "Django_Rest", "3.14.0"   -> pkg:pypi/django-rest@3.14.0
+func PackageURL(ecosystem, name, version string) string {
+	typ, ok := purlTypes[ecosystem]
+	if !ok {
+		typ = "generic"
+	}
+
+	var namespace string
+	switch typ {
+	case "pypi":
+		name = normalizePythonName(name)
+	case "maven":
+		if i := strings.Index(name, ":"); i >= 0 {
+			namespace, name = name[:i], name[i+1:]
+		}
+	case "composer":
+		name = strings.ToLower(name)
+	case "swift":
+		name = strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(name, "https://"), "http://"), ".git")
+		if !strings.Contains(name, "/") {
+			typ = "cocoapods" // Podfile pods are plain names
+		}
+	}
+	if typ == "npm" || typ == "golang" || typ == "composer" || typ == "swift" {
+		if i := strings.LastIndex(name, "/"); i >= 0 {
+			namespace, name = name[:i], name[i+1:]
+		}
+	}
+
+	purl := "pkg:" + typ + "/"
+	if namespace != "" {
+		segments := strings.Split(namespace, "/")
+		for i, seg := range segments {
+			segments[i] = purlEscape(seg)
+		}
+		purl += strings.Join(segments, "/") + "/"
+	}
+	purl += purlEscape(name)
+
+	if isExactVersion(version) {
+		purl += "@" + purlEscape(strings.TrimPrefix(strings.TrimSpace(version), "=="))
+	}
+	return purl
+}
+
+// purlEscape percent-encodes everything but unreserved characters
+func purlEscape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+			c == '-' || c == '.' || c == '_' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// newUUID returns a random version 4 UUID
+func newUUID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// osvAdvisoryURL links to a vulnerability on osv.dev
+func osvAdvisoryURL(id string) string {
+	return "https://osv.dev/vulnerability/" + id
+}
+
+// CycloneDX 1.5 JSON document
+// https://cyclonedx.org/docs/1.5/json/
+type cdxBOM struct {
+	BOMFormat       string             `json:"bomFormat"`
+	SpecVersion     string             `json:"specVersion"`
+	SerialNumber    string             `json:"serialNumber"`
+	Version         int                `json:"version"`
+	Metadata        cdxMetadata        `json:"metadata"`
+	Components      []cdxComponent     `json:"components"`
+	Dependencies    []cdxDependency    `json:"dependencies"`
+	Vulnerabilities []cdxVulnerability `json:"vulnerabilities,omitempty"`
+}
+
+type cdxMetadata struct {
+	Timestamp string `json:"timestamp"`
+	Tools     struct {
+		Components []cdxComponent `json:"components"`
+	} `json:"tools"`
+	Component cdxComponent `json:"component"`
+}
+
+type cdxComponent struct {
+	Type               string           `json:"type"`
+	BOMRef             string           `json:"bom-ref,omitempty"`
+	Name               string           `json:"name"`
+	Version            string           `json:"version,omitempty"`
+	Scope              string           `json:"scope,omitempty"`
+	PURL               string           `json:"purl,omitempty"`
+	Licenses           []cdxLicense     `json:"licenses,omitempty"`
+	ExternalReferences []cdxExternalRef `json:"externalReferences,omitempty"`
+}
+
+type cdxLicense struct {
+	License *struct {
+		ID   string `json:"id,omitempty"`
+		Name string `json:"name,omitempty"`
+	} `json:"license,omitempty"`
+	Expression string `json:"expression,omitempty"`
+}
+
+type cdxExternalRef struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+type cdxDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+type cdxVulnerability struct {
+	BOMRef         string      `json:"bom-ref"`
+	ID             string      `json:"id"`
+	Source         cdxSource   `json:"source"`
+	Ratings        []cdxRating `json:"ratings,omitempty"`
+	Description    string      `json:"description,omitempty"`
+	Recommendation string      `json:"recommendation,omitempty"`
+	Advisories     []struct {
+		URL string `json:"url"`
+	} `json:"advisories,omitempty"`
+	Published string `json:"published,omitempty"`
+	Analysis  struct {
+		State  string `json:"state"`
+		Detail string `json:"detail,omitempty"`
+	} `json:"analysis"`
+	Affects []cdxAffect `json:"affects"`
+}
+
+type cdxSource struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+type cdxRating struct {
+	Source   *cdxSource `json:"source,omitempty"`
+	Score    float64    `json:"score,omitempty"`
+	Severity string     `json:"severity"`
+	Method   string     `json:"method,omitempty"`
+	Vector   string     `json:"vector,omitempty"`
+}
+
+type cdxAffect struct {
+	Ref      string `json:"ref"`
+	Versions []struct {
+		Version string `json:"version"`
+		Status  string `json:"status"`
+	} `json:"versions,omitempty"`
+}
+
+// CycloneDX renders the SBOM as a CycloneDX 1.5 JSON document with the
+// scan results as VEX. Affected components are reported "in_triage": the
+// version is in a vulnerable range, but exploitability was not assessed.
+func (s *SBOM) CycloneDX() ([]byte, error) {
+	bom := cdxBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + s.SerialUUID,
+		Version:      1,
+		Components:   []cdxComponent{},
+		Dependencies: []cdxDependency{},
+	}
+	bom.Metadata.Timestamp = s.Timestamp.Format(time.RFC3339)
+	bom.Metadata.Tools.Components = []cdxComponent{{Type: "application", Name: "repo-lyzer"}}
+	bom.Metadata.Component = cdxComponent{
+		Type:    "application",
+		BOMRef:  s.Subject.Name,
+		Name:    s.Subject.Name,
+		Version: s.Subject.Version,
+	}
+	if s.Subject.URL != "" {
+		bom.Metadata.Component.ExternalReferences = []cdxExternalRef{{Type: "vcs", URL: s.Subject.URL}}
+	}
+
+	root := cdxDependency{Ref: s.Subject.Name, DependsOn: []string{}}
+	for _, c := range s.Components {
+		comp := cdxComponent{
+			Type:     "library",
+			BOMRef:   c.Ref,
+			Name:     c.Name,
+			Version:  c.Version,
+			PURL:     c.PURL,
+			Licenses: cdxLicenses(c.License),
+		}
+		if c.Dev {
+			comp.Scope = "optional"
+		}
+		if c.Ecosystem == "Docker" {
+			comp.Type = "container"
+		}
+		bom.Components = append(bom.Components, comp)
+
+		dependsOn := c.DependsOn
+		if dependsOn == nil {
+			dependsOn = []string{}
+		}
+		bom.Dependencies = append(bom.Dependencies, cdxDependency{Ref: c.Ref, DependsOn: dependsOn})
+		if c.Direct {
+			root.DependsOn = append(root.DependsOn, c.Ref)
+		}
+	}
+	bom.Dependencies = append([]cdxDependency{root}, bom.Dependencies...)
+
+	// One entry per advisory, affecting every component it was found in
+	index := make(map[string]int)
+	for _, v := range s.Vulns {
+		c := s.component(v)
+		if c == nil {
+			continue
+		}
+		i, ok := index[v.ID]
+		if !ok {
+			i = len(bom.Vulnerabilities)
+			index[v.ID] = i
+			bom.Vulnerabilities = append(bom.Vulnerabilities, newCDXVulnerability(v))
+		}
+		affect := cdxAffect{Ref: c.Ref}
+		affect.Versions = append(affect.Versions, struct {
+			Version string `json:"version"`
+			Status  string `json:"status"`
+		}{c.Version, "affected"})
+		bom.Vulnerabilities[i].Affects = append(bom.Vulnerabilities[i].Affects, affect)
+	}
+
+	return json.MarshalIndent(bom, "", "  ")
+}
+
+func newCDXVulnerability(v Vulnerability) cdxVulnerability {
+	cv := cdxVulnerability{
+		BOMRef:      "vuln/" + v.ID,
+		ID:          v.ID,
+		Source:      cdxSource{Name: "OSV", URL: osvAdvisoryURL(v.ID)},
+		Description: v.Summary,
+		Published:   v.PublishedAt,
+	}
+
+	rating := cdxRating{Source: &cdxSource{Name: "OSV"}, Severity: strings.ToLower(v.Severity), Score: v.Score}
+	switch {
+	case strings.HasPrefix(v.Vector, "CVSS:4.0/"):
+		rating.Method, rating.Vector = "CVSSv4", v.Vector
+	case strings.HasPrefix(v.Vector, "CVSS:3.1/"):
+		rating.Method, rating.Vector = "CVSSv31", v.Vector
+	case strings.HasPrefix(v.Vector, "CVSS:3.0/"):
+		rating.Method, rating.Vector = "CVSSv3", v.Vector
+	}
+	if rating.Severity == "" {
+		rating.Severity = "unknown"
+	}
+	cv.Ratings = []cdxRating{rating}
+
+	for _, ref := range v.References {
+		cv.Advisories = append(cv.Advisories, struct {
+			URL string `json:"url"`
+		}{ref})
+	}
+
+	cv.Analysis.State = "in_triage"
+	if v.FixedIn != "" {
+		cv.Recommendation = "Upgrade to " + v.FixedIn + " or later"
+	} else {
+		cv.Analysis.Detail = "No fixed release is available"
+	}
+	return cv
+}
+
+// cdxLicenses describes a license as a single SPDX ID, an SPDX expression,
+// or a free-text name
+func cdxLicenses(license string) []cdxLicense {
+	if license == "" {
+		return nil
+	}
+	parsed, err := ParseSPDXExpression(license)
+	if err != nil {
+		l := cdxLicense{License: &struct {
+			ID   string `json:"id,omitempty"`
+			Name string `json:"name,omitempty"`
+		}{Name: license}}
+		return []cdxLicense{l}
+	}
+	if parsed.Op == "" && !parsed.OrLater && parsed.Exception == "" && spdxLicenseLookup[strings.ToLower(parsed.License)] != "" {
+		l := cdxLicense{License: &struct {
+			ID   string `json:"id,omitempty"`
+			Name string `json:"name,omitempty"`
+		}{ID: parsed.License}}
+		return []cdxLicense{l}
+	}
+	return []cdxLicense{{Expression: parsed.String()}}
+}
+
+// SPDX 2.3 document
+// https://spdx.github.io/spdx-spec/v2.3/
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	DocumentDescribes []string           `json:"documentDescribes"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	SPDXID                string            `json:"SPDXID"`
+	Name                  string            `json:"name"`
+	VersionInfo           string            `json:"versionInfo,omitempty"`
+	DownloadLocation      string            `json:"downloadLocation"`
+	FilesAnalyzed         bool              `json:"filesAnalyzed"`
+	LicenseConcluded      string            `json:"licenseConcluded"`
+	LicenseDeclared       string            `json:"licenseDeclared"`
+	CopyrightText         string            `json:"copyrightText"`
+	PrimaryPackagePurpose string            `json:"primaryPackagePurpose,omitempty"`
+	ExternalRefs          []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+const spdxNoAssertion = "NOASSERTION"
+
+// spdx builds the SPDX document shared by the JSON and tag-value renderings
+func (s *SBOM) spdx() spdxDocument {
+	doc := spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              s.Subject.Name,
+		DocumentNamespace: "https://spdx.org/spdxdocs/repo-lyzer/" + strings.ReplaceAll(s.Subject.Name, "/", "-") + "-" + s.SerialUUID,
+		CreationInfo: spdxCreationInfo{
+			Created:  s.Timestamp.Format("2006-01-02T15:04:05Z"),
+			Creators: []string{"Tool: repo-lyzer"},
+		},
+		DocumentDescribes: []string{"SPDXRef-Repository"},
+	}
+
+	root := spdxPackage{
+		SPDXID:                "SPDXRef-Repository",
+		Name:                  s.Subject.Name,
+		VersionInfo:           s.Subject.Version,
+		DownloadLocation:      spdxNoAssertion,
+		LicenseConcluded:      spdxNoAssertion,
+		LicenseDeclared:       spdxNoAssertion,
+		CopyrightText:         spdxNoAssertion,
+		PrimaryPackagePurpose: "SOURCE",
+	}
+	if s.Subject.URL != "" {
+		root.DownloadLocation = "git+" + s.Subject.URL + ".git"
+	}
+	doc.Packages = append(doc.Packages, root)
+	doc.Relationships = append(doc.Relationships, spdxRelationship{"SPDXRef-DOCUMENT", "DESCRIBES", "SPDXRef-Repository"})
+
+	advisories := make(map[string][]string) // component ref -> advisory URLs
+	for _, v := range s.Vulns {
+		if c := s.component(v); c != nil && !contains(advisories[c.Ref], osvAdvisoryURL(v.ID)) {
+			advisories[c.Ref] = append(advisories[c.Ref], osvAdvisoryURL(v.ID))
+		}
+	}
+
+	ids := make(map[string]string, len(s.Components))
+	for i, c := range s.Components {
+		ids[c.Ref] = fmt.Sprintf("SPDXRef-Package-%d-%s", i+1, spdxIDString(c.Name))
+	}
+
+	for _, c := range s.Components {
+		pkg := spdxPackage{
+			SPDXID:                ids[c.Ref],
+			Name:                  c.Name,
+			VersionInfo:           c.Version,
+			DownloadLocation:      spdxNoAssertion,
+			LicenseConcluded:      spdxNoAssertion,
+			LicenseDeclared:       spdxLicenseField(c.License),
+			CopyrightText:         spdxNoAssertion,
+			PrimaryPackagePurpose: "LIBRARY",
+			ExternalRefs: []spdxExternalRef{
+				{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: c.PURL},
+			},
+		}
+		for _, url := range advisories[c.Ref] {
+			pkg.ExternalRefs = append(pkg.ExternalRefs, spdxExternalRef{
+				ReferenceCategory: "SECURITY", ReferenceType: "advisory", ReferenceLocator: url,
+			})
+		}
+		doc.Packages = append(doc.Packages, pkg)
+
+		if c.Direct {
+			if c.Dev {
+				doc.Relationships = append(doc.Relationships, spdxRelationship{ids[c.Ref], "DEV_DEPENDENCY_OF", "SPDXRef-Repository"})
+			} else {
+				doc.Relationships = append(doc.Relationships, spdxRelationship{"SPDXRef-Repository", "DEPENDS_ON", ids[c.Ref]})
+			}
+		}
+		for _, dep := range c.DependsOn {
+			doc.Relationships = append(doc.Relationships, spdxRelationship{ids[c.Ref], "DEPENDS_ON", ids[dep]})
+		}
+	}
+	return doc
+}
+
+// SPDXJSON renders the SBOM as an SPDX 2.3 JSON document
+func (s *SBOM) SPDXJSON() ([]byte, error) {
+	return json.MarshalIndent(s.spdx(), "", "  ")
+}
+
+// SPDXTagValue renders the SBOM as an SPDX 2.3 tag-value document
+func (s *SBOM) SPDXTagValue() string {
+	doc := s.spdx()
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "SPDXVersion: %s\n", doc.SPDXVersion)
+	fmt.Fprintf(&b, "DataLicense: %s\n", doc.DataLicense)
+	fmt.Fprintf(&b, "SPDXID: %s\n", doc.SPDXID)
+	fmt.Fprintf(&b, "DocumentName: %s\n", doc.Name)
+	fmt.Fprintf(&b, "DocumentNamespace: %s\n", doc.DocumentNamespace)
+	for _, creator := range doc.CreationInfo.Creators {
+		fmt.Fprintf(&b, "Creator: %s\n", creator)
+	}
+	fmt.Fprintf(&b, "Created: %s\n", doc.CreationInfo.Created)
+
+	for _, pkg := range doc.Packages {
+		fmt.Fprintf(&b, "\n##### Package: %s\n\n", pkg.Name)
+		fmt.Fprintf(&b, "PackageName: %s\n", pkg.Name)
+		fmt.Fprintf(&b, "SPDXID: %s\n", pkg.SPDXID)
+		if pkg.VersionInfo != "" {
+			fmt.Fprintf(&b, "PackageVersion: %s\n", pkg.VersionInfo)
+		}
+		fmt.Fprintf(&b, "PackageDownloadLocation: %s\n", pkg.DownloadLocation)
+		fmt.Fprintf(&b, "FilesAnalyzed: %t\n", pkg.FilesAnalyzed)
+		fmt.Fprintf(&b, "PackageLicenseConcluded: %s\n", pkg.LicenseConcluded)
+		fmt.Fprintf(&b, "PackageLicenseDeclared: %s\n", pkg.LicenseDeclared)
+		fmt.Fprintf(&b, "PackageCopyrightText: %s\n", pkg.CopyrightText)
+		if pkg.PrimaryPackagePurpose != "" {
+			fmt.Fprintf(&b, "PrimaryPackagePurpose: %s\n", pkg.PrimaryPackagePurpose)
+		}
+		for _, ref := range pkg.ExternalRefs {
+			fmt.Fprintf(&b, "ExternalRef: %s %s %s\n", ref.ReferenceCategory, ref.ReferenceType, ref.ReferenceLocator)
+		}
+	}
+
+	b.WriteString("\n##### Relationships\n\n")
+	for _, rel := range doc.Relationships {
+		fmt.Fprintf(&b, "Relationship: %s %s %s\n", rel.SPDXElementID, rel.RelationshipType, rel.RelatedSPDXElement)
+	}
+	return b.String()
+}
+
+// spdxLicenseField returns a license expression usable in an SPDX document.
+// Free-text licenses and LicenseRefs without extracted texts are replaced
+// with NOASSERTION.
+func spdxLicenseField(license string) string {
+	parsed, err := ParseSPDXExpression(license)
+	if err != nil {
+		return spdxNoAssertion
+	}
+	for _, id := range parsed.Licenses() {
+		lower := strings.ToLower(id)
+		if strings.HasPrefix(lower, "licenseref-") || strings.HasPrefix(lower, "documentref-") {
+			return spdxNoAssertion
+		}
+	}
+	return parsed.String()
+}
+
+// spdxIDString replaces characters not allowed in SPDX element IDs
+func spdxIDString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('-')
+		}
+	}
+	return b.String()
+}
+
+// SBOMFormats lists the supported SBOM formats with their file extensions
+var SBOMFormats = map[string]string{
+	"cyclonedx": "cdx.json",
+	"spdx":      "spdx.json",
+	"spdx-tv":   "spdx",
+}
+
+// Render renders the SBOM in the given format: "cyclonedx", "spdx" (JSON)
+// or "spdx-tv" (tag-value)
+func (s *SBOM) Render(format string) ([]byte, error) {
+	switch format {
+	case "cyclonedx":
+		return s.CycloneDX()
+	case "spdx":
+		return s.SPDXJSON()
+	case "spdx-tv":
+		return []byte(s.SPDXTagValue()), nil
+	}
+
+	formats := make([]string, 0, len(SBOMFormats))
+	for f := range SBOMFormats {
+		formats = append(formats, f)
+	}
+	sort.Strings(formats)
+	return nil, fmt.Errorf("unsupported SBOM format '%s'. Supported formats are: %s", format, strings.Join(formats, ", "))
+}
@@ -0,0 +1,228 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sampleSBOM() *SBOM {
+	deps := sampleGraphAnalysis()
+	deps.LockFiles[0].Packages[0].License = "MIT"
+	deps.Files = []DependencyFile{{
+		Filename: "requirements.txt",
+		FileType: "python",
+		Dependencies: []Dependency{
+			{Name: "Django_Rest", Version: "==3.14.0", Type: "production"},
+		},
+	}}
+	security := &SecurityScanResult{Vulnerabilities: []Vulnerability{{
+		ID:       "GHSA-gxpj-cx7g-858c",
+		Summary:  "Regular Expression Denial of Service in debug",
+		Severity: "MEDIUM",
+		Score:    5.3,
+		Vector:   "CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:U/C:N/I:N/A:H",
+		Package:  "debug",
+		Version:  "2.6.9",
+		FixedIn:  "3.1.0",
+	}}}
+	licenses := &LicenseComplianceReport{Dependencies: []DependencyLicense{
+		{Package: "mocha", Ecosystem: "npm", License: "MIT OR Apache-2.0"},
+	}}
+
+	sbom := NewSBOM(SBOMSubject{Name: "octocat/app", Version: "main", URL: "https://github.com/octocat/app"}, deps, security, licenses)
+	sbom.Timestamp = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	sbom.SerialUUID = "3e671687-395b-41f5-a30f-a58921a69b79"
+	return sbom
+}
+
+func TestPackageURL(t *testing.T) {
+	tests := []struct {
+		ecosystem, name, version, want string
+	}{
+		{"npm", "@babel/core", "7.23.0", "pkg:npm/%40babel/core@7.23.0"},
+		{"npm", "lodash", "^4.17.0", "pkg:npm/lodash"},
+		{"Go", "github.com/gin-gonic/gin", "v1.9.1", "pkg:golang/github.com/gin-gonic/gin@v1.9.1"},
+		{"PyPI", "Django_Rest", "==3.14.0", "pkg:pypi/django-rest@3.14.0"},
+		{"crates.io", "serde", "1.0.193", "pkg:cargo/serde@1.0.193"},
+		{"Maven", "org.slf4j:slf4j-api", "2.0.9", "pkg:maven/org.slf4j/slf4j-api@2.0.9"},
+		{"Packagist", "Laravel/Framework", "10.0.0", "pkg:composer/laravel/framework@10.0.0"},
+		{"SwiftURL", "https://github.com/apple/swift-nio.git", "2.0.0", "pkg:swift/github.com/apple/swift-nio@2.0.0"},
+		{"SwiftURL", "Alamofire", "5.8.0", "pkg:cocoapods/Alamofire@5.8.0"},
+	}
+	for _, tt := range tests {
+		if got := PackageURL(tt.ecosystem, tt.name, tt.version); got != tt.want {
+			t.Errorf("PackageURL(%q, %q, %q) = %q, want %q", tt.ecosystem, tt.name, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestNewSBOM(t *testing.T) {
+	sbom := sampleSBOM()
+
+	if len(sbom.Components) != 7 {
+		t.Fatalf("len(Components) = %d, want 7", len(sbom.Components))
+	}
+	express := sbom.Components[0]
+	if express.Ref != "npm/express@4.18.2" || express.License != "MIT" || !express.Direct {
+		t.Errorf("express = %+v", express)
+	}
+	if len(express.DependsOn) != 1 || express.DependsOn[0] != "npm/debug@2.6.9" {
+		t.Errorf("express.DependsOn = %v, want [npm/debug@2.6.9]", express.DependsOn)
+	}
+	if mocha := sbom.Components[1]; mocha.License != "MIT OR Apache-2.0" || !mocha.Dev {
+		t.Errorf("mocha = %+v, want dev component licensed MIT OR Apache-2.0", mocha)
+	}
+
+	// Base images are listed as containers after the packages
+	deps := &DependencyAnalysis{Files: []DependencyFile{{
+		Filename:     "Dockerfile",
+		FileType:     "docker",
+		Dependencies: []Dependency{{Name: "golang", Version: "1.22", Type: "base-image", Resolved: "sha256:abc"}},
+	}}}
+	docker := NewSBOM(SBOMSubject{Name: "acme/app"}, deps, nil, nil)
+	if len(docker.Components) != 1 || docker.Components[0].PURL != "pkg:docker/golang@sha256%3Aabc" {
+		t.Errorf("base image components = %+v", docker.Components)
+	}
+}
+
+func TestSBOM_CycloneDX(t *testing.T) {
+	data, err := sampleSBOM().CycloneDX()
+	if err != nil {
+		t.Fatalf("CycloneDX() error = %v", err)
+	}
+
+	var bom struct {
+		BOMFormat    string `json:"bomFormat"`
+		SpecVersion  string `json:"specVersion"`
+		SerialNumber string `json:"serialNumber"`
+		Components   []struct {
+			BOMRef   string `json:"bom-ref"`
+			PURL     string `json:"purl"`
+			Scope    string `json:"scope"`
+			Licenses []struct {
+				License struct {
+					ID string `json:"id"`
+				} `json:"license"`
+				Expression string `json:"expression"`
+			} `json:"licenses"`
+		} `json:"components"`
+		Dependencies []struct {
+			Ref       string   `json:"ref"`
+			DependsOn []string `json:"dependsOn"`
+		} `json:"dependencies"`
+		Vulnerabilities []struct {
+			ID      string `json:"id"`
+			Ratings []struct {
+				Score    float64 `json:"score"`
+				Severity string  `json:"severity"`
+				Method   string  `json:"method"`
+			} `json:"ratings"`
+			Recommendation string `json:"recommendation"`
+			Analysis       struct {
+				State string `json:"state"`
+			} `json:"analysis"`
+			Affects []struct {
+				Ref string `json:"ref"`
+			} `json:"affects"`
+		} `json:"vulnerabilities"`
+	}
+	if err := json.Unmarshal(data, &bom); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	if bom.BOMFormat != "CycloneDX" || bom.SpecVersion != "1.5" {
+		t.Errorf("format = %s %s, want CycloneDX 1.5", bom.BOMFormat, bom.SpecVersion)
+	}
+	if bom.SerialNumber != "urn:uuid:3e671687-395b-41f5-a30f-a58921a69b79" {
+		t.Errorf("serialNumber = %q", bom.SerialNumber)
+	}
+	if got := bom.Components[0].Licenses; len(got) != 1 || got[0].License.ID != "MIT" {
+		t.Errorf("express licenses = %+v, want MIT id", got)
+	}
+	if got := bom.Components[1].Licenses; len(got) != 1 || got[0].Expression != "MIT OR Apache-2.0" {
+		t.Errorf("mocha licenses = %+v, want expression", got)
+	}
+	if bom.Components[1].Scope != "optional" {
+		t.Errorf("mocha scope = %q, want optional", bom.Components[1].Scope)
+	}
+
+	root := bom.Dependencies[0]
+	if root.Ref != "octocat/app" || len(root.DependsOn) != 3 {
+		t.Errorf("root dependency = %+v, want express, mocha and django-rest", root)
+	}
+
+	if len(bom.Vulnerabilities) != 1 {
+		t.Fatalf("len(vulnerabilities) = %d, want 1", len(bom.Vulnerabilities))
+	}
+	v := bom.Vulnerabilities[0]
+	if v.Ratings[0].Method != "CVSSv31" || v.Ratings[0].Severity != "medium" || v.Ratings[0].Score != 5.3 {
+		t.Errorf("rating = %+v", v.Ratings[0])
+	}
+	if v.Analysis.State != "in_triage" || v.Recommendation != "Upgrade to 3.1.0 or later" {
+		t.Errorf("analysis = %q, recommendation = %q", v.Analysis.State, v.Recommendation)
+	}
+	if len(v.Affects) != 1 || v.Affects[0].Ref != "npm/debug@2.6.9" {
+		t.Errorf("affects = %+v, want npm/debug@2.6.9", v.Affects)
+	}
+}
+
+func TestSBOM_SPDX(t *testing.T) {
+	sbom := sampleSBOM()
+	data, err := sbom.SPDXJSON()
+	if err != nil {
+		t.Fatalf("SPDXJSON() error = %v", err)
+	}
+
+	var doc spdxDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if doc.SPDXVersion != "SPDX-2.3" || doc.DataLicense != "CC0-1.0" {
+		t.Errorf("header = %s %s", doc.SPDXVersion, doc.DataLicense)
+	}
+	if len(doc.Packages) != 8 {
+		t.Fatalf("len(packages) = %d, want repository plus 7", len(doc.Packages))
+	}
+
+	debug := doc.Packages[3]
+	if debug.Name != "debug" || debug.LicenseDeclared != "NOASSERTION" {
+		t.Errorf("debug package = %+v", debug)
+	}
+	if len(debug.ExternalRefs) != 2 || debug.ExternalRefs[1].ReferenceLocator != "https://osv.dev/vulnerability/GHSA-gxpj-cx7g-858c" {
+		t.Errorf("debug externalRefs = %+v, want purl and advisory", debug.ExternalRefs)
+	}
+
+	var dependsOn, devOf int
+	for _, rel := range doc.Relationships {
+		switch rel.RelationshipType {
+		case "DEPENDS_ON":
+			dependsOn++
+		case "DEV_DEPENDENCY_OF":
+			devOf++
+		}
+	}
+	if dependsOn != 6 || devOf != 1 {
+		t.Errorf("relationships: %d DEPENDS_ON, %d DEV_DEPENDENCY_OF; want 6 and 1", dependsOn, devOf)
+	}
+
+	tv := sbom.SPDXTagValue()
+	for _, want := range []string{
+		"SPDXVersion: SPDX-2.3\n",
+		"PackageName: express\n",
+		"PackageLicenseDeclared: MIT\n",
+		"ExternalRef: PACKAGE-MANAGER purl pkg:npm/express@4.18.2\n",
+		"Relationship: SPDXRef-DOCUMENT DESCRIBES SPDXRef-Repository\n",
+	} {
+		if !strings.Contains(tv, want) {
+			t.Errorf("tag-value output missing %q", want)
+		}
+	}
+}
+
+func TestSBOM_RenderUnsupported(t *testing.T) {
+	if _, err := sampleSBOM().Render("swid"); err == nil {
+		t.Error("Render(swid) error = nil, want unsupported format")
+	}
+}
@@ -0,0 +1,664 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// Scorecard check names, as used by OpenSSF Scorecard
+const (
+	CheckMaintained           = "Maintained"
+	CheckCodeReview           = "Code-Review"
+	CheckBranchProtection     = "Branch-Protection"
+	CheckPinnedDependencies   = "Pinned-Dependencies"
+	CheckDangerousWorkflow    = "Dangerous-Workflow"
+	CheckSecurityPolicy       = "Security-Policy"
+	CheckSignedReleases       = "Signed-Releases"
+	CheckLicense              = "License"
+	CheckCITests              = "CI-Tests"
+	CheckFuzzing              = "Fuzzing"
+	CheckSAST                 = "SAST"
+	CheckDependencyUpdateTool = "Dependency-Update-Tool"
+)
+
+// InconclusiveScore marks a check that could not be evaluated, for example
+// because the repository has no releases or the data needs admin access
+const InconclusiveScore = -1
+
+// ScorecardCheck is the result of one check, scored 0-10
+type ScorecardCheck struct {
+	Name    string   `json:"name"`
+	Score   int      `json:"score"` // 0-10, or InconclusiveScore
+	Risk    string   `json:"risk"`  // Critical, High, Medium, Low
+	Reason  string   `json:"reason"`
+	Details []string `json:"details,omitempty"`
+}
+
+// ScorecardResult is the full check suite with its risk-weighted aggregate
+type ScorecardResult struct {
+	Repo   string           `json:"repo"`
+	Score  float64          `json:"score"` // 0-10, one decimal
+	Checks []ScorecardCheck `json:"checks"`
+	Date   time.Time        `json:"date"`
+}
+
+// ReviewedPullRequest is a merged pull request with the people who reviewed it
+type ReviewedPullRequest struct {
+	Number    int      `json:"number"`
+	Author    string   `json:"author"`
+	Reviewers []string `json:"reviewers"`
+	Approved  bool     `json:"approved"`
+}
+
+// ScorecardInputs gathers what the checks look at. Most of it comes from
+// the other analyzers; FetchScorecardData fills in the rest.
+type ScorecardInputs struct {
+	Repo         *github.Repo
+	Commits      []github.Commit
+	FileTree     []github.TreeEntry
+	Workflows    *WorkflowAuditResult
+	Dependencies *DependencyAnalysis
+	License      *LicenseAnalysis
+	CodeQuality  *CodeQualityMetrics
+
+	Branch       *github.Branch           // default branch; nil when it could not be fetched
+	Protection   *github.BranchProtection // nil without admin access
+	Releases     []github.Release
+	PullRequests []ReviewedPullRequest // recently merged pull requests
+	GoFuzzTests  []string              // Go test files declaring func Fuzz targets
+}
+
+// scorecardRiskWeights weighs checks in the aggregate score by risk
+var scorecardRiskWeights = map[string]float64{
+	"Critical": 10,
+	"High":     7.5,
+	"Medium":   5,
+	"Low":      2.5,
+}
+
+// maintainedWindowWeeks is how far back Maintained looks for activity
+const maintainedWindowWeeks = 13
+
+// scorecardSignatureExts are release assets that count as signatures
+var scorecardSignatureExts = []string{".sig", ".asc", ".minisig", ".sigstore", ".sigstore.json"}
+
+// SAST tools are recognized by the actions that run them or by their
+// configuration files
+var (
+	scorecardSASTActions = []string{"github/codeql-action", "returntocorp/semgrep-action", "semgrep/semgrep-action", "snyk/actions", "sonarsource/", "securego/gosec"}
+	scorecardSASTConfigs = []string{".semgrep.yml", ".semgrep.yaml", "sonar-project.properties", ".snyk"}
+)
+
+// maxFuzzTestFiles caps how many Go test files are fetched to look for
+// native fuzz targets when no fuzzing harness is recognized by its path
+const (
+	maxFuzzTestFiles        = 10
+	maxFuzzTestFilesNoToken = 3
+)
+
+// FetchScorecardData fetches the branch, release and pull request data the
+// checks need beyond what the other analyzers collect. Errors leave the
+// corresponding fields empty, which makes the affected checks inconclusive.
+// Reviews are fetched for at most maxPRs merged pull requests.
+func FetchScorecardData(client *github.Client, owner, repo string, in *ScorecardInputs, maxPRs int) {
+	maxFuzzFiles := maxFuzzTestFiles
+	if !client.HasToken() {
+		maxFuzzFiles = maxFuzzTestFilesNoToken
+	}
+	in.GoFuzzTests = findGoFuzzTests(in.FileTree, contentFetcher(client, owner, repo), maxFuzzFiles)
+
+	if in.Repo != nil && in.Repo.DefaultBranch != "" {
+		if branch, err := client.GetBranch(owner, repo, in.Repo.DefaultBranch); err == nil {
+			in.Branch = branch
+			if branch.Protected {
+				if protection, err := client.GetBranchProtection(owner, repo, in.Repo.DefaultBranch); err == nil {
+					in.Protection = protection
+				}
+			}
+		}
+	}
+
+	if releases, err := client.GetReleases(owner, repo, 5); err == nil {
+		in.Releases = releases
+	}
+
+	if maxPRs <= 0 {
+		return
+	}
+	prs, err := client.GetPullRequests(owner, repo, "closed", 3*maxPRs)
+	if err != nil {
+		return
+	}
+	for _, pr := range prs {
+		if len(in.PullRequests) >= maxPRs {
+			break
+		}
+		if pr.MergedAt == nil {
+			continue
+		}
+		reviews, err := client.GetPullRequestReviews(owner, repo, pr.Number)
+		if err != nil {
+			continue
+		}
+		in.PullRequests = append(in.PullRequests, reviewedPullRequest(pr, reviews))
+	}
+}
+
+func reviewedPullRequest(pr github.PullRequest, reviews []github.Review) ReviewedPullRequest {
+	r := ReviewedPullRequest{Number: pr.Number, Reviewers: []string{}}
+	if pr.User != nil {
+		r.Author = pr.User.Login
+	}
+	for _, review := range reviews {
+		if review.User == nil {
+			continue
+		}
+		if review.State == "APPROVED" {
+			r.Approved = true
+		}
+		if !contains(r.Reviewers, review.User.Login) {
+			r.Reviewers = append(r.Reviewers, review.User.Login)
+		}
+	}
+	return r
+}
+
+// ComputeScorecard runs every check and combines them into a risk-weighted
+// score. Inconclusive checks are left out of the aggregate.
+func ComputeScorecard(in *ScorecardInputs) *ScorecardResult {
+	return computeScorecard(in, time.Now())
+}
+
+func computeScorecard(in *ScorecardInputs, now time.Time) *ScorecardResult {
+	result := &ScorecardResult{Date: now}
+	if in.Repo != nil {
+		result.Repo = in.Repo.FullName
+	}
+
+	result.Checks = []ScorecardCheck{
+		checkDangerousWorkflow(in),
+		checkMaintained(in, now),
+		checkCodeReview(in),
+		checkBranchProtection(in),
+		checkSignedReleases(in),
+		checkDependencyUpdateTool(in),
+		checkPinnedDependencies(in),
+		checkSAST(in),
+		checkFuzzing(in),
+		checkSecurityPolicy(in),
+		checkCITests(in),
+		checkLicense(in),
+	}
+
+	var total, weights float64
+	for _, c := range result.Checks {
+		if c.Score == InconclusiveScore {
+			continue
+		}
+		w := scorecardRiskWeights[c.Risk]
+		total += float64(c.Score) * w
+		weights += w
+	}
+	if weights > 0 {
+		result.Score = math.Round(total/weights*10) / 10
+	}
+	return result
+}
+
+func checkMaintained(in *ScorecardInputs, now time.Time) ScorecardCheck {
+	c := ScorecardCheck{Name: CheckMaintained, Risk: "High"}
+	if in.Repo != nil && in.Repo.Archived {
+		c.Reason = "repository is archived"
+		return c
+	}
+	if in.Repo != nil && now.Sub(in.Repo.CreatedAt) < maintainedWindowWeeks*7*24*time.Hour {
+		c.Reason = "repository was created in the last 90 days"
+		return c
+	}
+
+	weeks := make(map[int]bool)
+	for _, commit := range in.Commits {
+		age := now.Sub(commit.Commit.Author.Date)
+		if age < 0 {
+			continue
+		}
+		if week := int(age.Hours() / (24 * 7)); week < maintainedWindowWeeks {
+			weeks[week] = true
+		}
+	}
+	c.Score = len(weeks) * 10 / maintainedWindowWeeks
+	c.Reason = fmt.Sprintf("%d of the last %d weeks had commits", len(weeks), maintainedWindowWeeks)
+	return c
+}
+
+func checkCodeReview(in *ScorecardInputs) ScorecardCheck {
+	c := ScorecardCheck{Name: CheckCodeReview, Risk: "High"}
+	if len(in.PullRequests) == 0 {
+		c.Score = InconclusiveScore
+		c.Reason = "no merged pull requests found"
+		return c
+	}
+
+	reviewed := 0
+	for _, pr := range in.PullRequests {
+		if isReviewed(pr) {
+			reviewed++
+		} else {
+			c.Details = append(c.Details, fmt.Sprintf("#%d merged without review", pr.Number))
+		}
+	}
+	c.Score = reviewed * 10 / len(in.PullRequests)
+	c.Reason = fmt.Sprintf("%d of the last %d merged pull requests were reviewed", reviewed, len(in.PullRequests))
+	return c
+}
+
+// isReviewed reports whether a pull request was approved or reviewed by
+// someone other than its author
+func isReviewed(pr ReviewedPullRequest) bool {
+	if pr.Approved {
+		return true
+	}
+	for _, r := range pr.Reviewers {
+		if !strings.EqualFold(r, pr.Author) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkBranchProtection(in *ScorecardInputs) ScorecardCheck {
+	c := ScorecardCheck{Name: CheckBranchProtection, Risk: "High"}
+	if in.Branch == nil {
+		c.Score = InconclusiveScore
+		c.Reason = "default branch could not be read"
+		return c
+	}
+	if !in.Branch.Protected {
+		c.Reason = fmt.Sprintf("branch %s is not protected", in.Branch.Name)
+		return c
+	}
+
+	c.Score = 3
+	c.Details = append(c.Details, "branch is protected")
+	p := in.Protection
+	if p == nil {
+		// The branch summary still tells us about required status checks
+		if len(in.Branch.Protection.RequiredStatusChecks.Contexts) > 0 {
+			c.Score += 2
+			c.Details = append(c.Details, "status checks are required")
+		}
+		c.Reason = "branch is protected; review rules need admin access to read"
+		return c
+	}
+
+	if reviews := p.RequiredPullRequestReviews; reviews != nil && reviews.RequiredApprovingReviewCount > 0 {
+		c.Score += 3
+		if reviews.RequiredApprovingReviewCount >= 2 {
+			c.Score++
+		}
+		c.Details = append(c.Details, fmt.Sprintf("%d approving review(s) required", reviews.RequiredApprovingReviewCount))
+	} else {
+		c.Details = append(c.Details, "pull request reviews are not required")
+	}
+	if p.RequiredStatusChecks != nil {
+		c.Score += 2
+		c.Details = append(c.Details, "status checks are required")
+	}
+	if p.EnforceAdmins.Enabled {
+		c.Score++
+		c.Details = append(c.Details, "rules apply to administrators")
+	}
+	if c.Score > 10 {
+		c.Score = 10
+	}
+	c.Reason = fmt.Sprintf("branch %s is protected", in.Branch.Name)
+	return c
+}
+
+func checkPinnedDependencies(in *ScorecardInputs) ScorecardCheck {
+	c := ScorecardCheck{Name: CheckPinnedDependencies, Risk: "Medium"}
+	var scores []int
+
+	if in.Workflows != nil && len(in.Workflows.Actions) > 0 {
+		pinned := 0
+		for _, uses := range in.Workflows.Actions {
+			ref := uses[strings.LastIndex(uses, "@")+1:]
+			if fullSHAPattern.MatchString(ref) || strings.Contains(ref, "sha256:") {
+				pinned++
+			} else {
+				c.Details = append(c.Details, "unpinned action "+uses)
+			}
+		}
+		scores = append(scores, pinned*10/len(in.Workflows.Actions))
+	}
+	if in.Dependencies != nil {
+		// Base images count as pinned only by digest; package manifests
+		// are pinned by a lock file
+		images, pinned, manifests := 0, 0, 0
+		for _, file := range in.Dependencies.Files {
+			if file.FileType != "docker" {
+				manifests++
+				continue
+			}
+			for _, dep := range file.Dependencies {
+				images++
+				if strings.HasPrefix(dep.Resolved, "sha256:") {
+					pinned++
+				} else {
+					c.Details = append(c.Details, fmt.Sprintf("unpinned base image %s:%s in %s", dep.Name, dep.Version, file.Filename))
+				}
+			}
+		}
+		if images > 0 {
+			scores = append(scores, pinned*10/images)
+		}
+		if manifests > 0 {
+			if in.Dependencies.HasLockFile {
+				scores = append(scores, 10)
+			} else {
+				scores = append(scores, 0)
+				c.Details = append(c.Details, "dependency manifests have no lock file")
+			}
+		}
+	}
+
+	if len(scores) == 0 {
+		c.Score = InconclusiveScore
+		c.Reason = "no actions, base images or dependency manifests found"
+		return c
+	}
+	sum := 0
+	for _, s := range scores {
+		sum += s
+	}
+	c.Score = sum / len(scores)
+	c.Reason = "actions and base images pinned by hash, dependencies locked"
+	if c.Score < 10 {
+		c.Reason = fmt.Sprintf("%d pinning issues", len(c.Details))
+	}
+	return c
+}
+
+func checkDangerousWorkflow(in *ScorecardInputs) ScorecardCheck {
+	c := ScorecardCheck{Name: CheckDangerousWorkflow, Risk: "Critical"}
+	if in.Workflows == nil || in.Workflows.Workflows == 0 {
+		c.Score = InconclusiveScore
+		c.Reason = "no workflows found"
+		return c
+	}
+
+	for _, f := range in.Workflows.Findings {
+		dangerous := f.Rule == RulePRTargetCheckout ||
+			(f.Rule == RuleScriptInjection && (f.Severity == "CRITICAL" || f.Severity == "HIGH"))
+		if dangerous {
+			c.Details = append(c.Details, fmt.Sprintf("%s:%d %s", f.Workflow, f.Line, f.Description))
+		}
+	}
+	if len(c.Details) > 0 {
+		c.Reason = fmt.Sprintf("%d dangerous workflow patterns", len(c.Details))
+		return c
+	}
+	c.Score = 10
+	c.Reason = "no dangerous workflow patterns detected"
+	return c
+}
+
+func checkSecurityPolicy(in *ScorecardInputs) ScorecardCheck {
+	c := ScorecardCheck{Name: CheckSecurityPolicy, Risk: "Medium"}
+	for _, entry := range in.FileTree {
+		if entry.Type != "blob" {
+			continue
+		}
+		dir, name := path.Split(entry.Path)
+		if dir != "" && dir != ".github/" && dir != "docs/" {
+			continue
+		}
+		switch strings.ToLower(name) {
+		case "security.md", "security.rst", "security.txt", "security":
+			c.Score = 10
+			c.Reason = "security policy found in " + entry.Path
+			return c
+		}
+	}
+	c.Reason = "no SECURITY.md found"
+	return c
+}
+
+func checkSignedReleases(in *ScorecardInputs) ScorecardCheck {
+	c := ScorecardCheck{Name: CheckSignedReleases, Risk: "High"}
+	var releases []github.Release
+	for _, r := range in.Releases {
+		if !r.Draft {
+			releases = append(releases, r)
+		}
+	}
+	if len(releases) == 0 {
+		c.Score = InconclusiveScore
+		c.Reason = "no releases found"
+		return c
+	}
+
+	total, signed := 0, 0
+	for _, r := range releases {
+		score := 0
+		for _, a := range r.Assets {
+			name := strings.ToLower(a.Name)
+			if strings.HasSuffix(name, ".intoto.jsonl") {
+				score = 10
+				break
+			}
+			for _, ext := range scorecardSignatureExts {
+				if strings.HasSuffix(name, ext) {
+					score = 8
+				}
+			}
+		}
+		if score > 0 {
+			signed++
+		} else {
+			c.Details = append(c.Details, r.TagName+" has no signature or provenance")
+		}
+		total += score
+	}
+	c.Score = total / len(releases)
+	c.Reason = fmt.Sprintf("%d of the last %d releases are signed", signed, len(releases))
+	return c
+}
+
+func checkLicense(in *ScorecardInputs) ScorecardCheck {
+	c := ScorecardCheck{Name: CheckLicense, Risk: "Low"}
+	if in.License == nil || in.License.MainLicense == nil {
+		c.Reason = "no license file found"
+		return c
+	}
+
+	main := in.License.MainLicense
+	c.Score = 6
+	if main.SourceFile != "" && !strings.Contains(main.SourceFile, "/") {
+		c.Score += 3
+	}
+	if _, ok := lookupLicense(main.SPDX); ok {
+		c.Score++
+	}
+	c.Reason = fmt.Sprintf("%s license found", main.Name)
+	if main.SourceFile != "" {
+		c.Reason += " in " + main.SourceFile
+	}
+	return c
+}
+
+func checkCITests(in *ScorecardInputs) ScorecardCheck {
+	c := ScorecardCheck{Name: CheckCITests, Risk: "Low"}
+	q := in.CodeQuality
+	switch {
+	case q == nil:
+		c.Score = InconclusiveScore
+		c.Reason = "code quality was not analyzed"
+	case !q.HasCI:
+		c.Reason = "no CI configuration found"
+	case !q.HasTests:
+		c.Score = 3
+		c.Reason = "CI runs but no tests were found"
+	default:
+		c.Score = 10
+		c.Reason = fmt.Sprintf("tests run in %s", strings.Join(q.CIProviders, ", "))
+	}
+	return c
+}
+
+func checkFuzzing(in *ScorecardInputs) ScorecardCheck {
+	c := ScorecardCheck{Name: CheckFuzzing, Risk: "Medium"}
+	for _, entry := range in.FileTree {
+		if entry.Type == "blob" && isFuzzHarness(entry.Path) {
+			c.Details = append(c.Details, entry.Path)
+		}
+	}
+	c.Details = append(c.Details, in.GoFuzzTests...)
+	for _, uses := range workflowActions(in) {
+		if strings.Contains(strings.ToLower(uses), "cifuzz") || strings.Contains(strings.ToLower(uses), "oss-fuzz") {
+			c.Details = append(c.Details, uses)
+		}
+	}
+	if len(c.Details) == 0 {
+		c.Reason = "no fuzzing found"
+		return c
+	}
+	c.Score = 10
+	c.Reason = "fuzzing found in " + c.Details[0]
+	return c
+}
+
+// isFuzzHarness recognizes fuzzing harnesses by path: OSS-Fuzz and
+// ClusterFuzzLite setups, fuzz/ and fuzzers/ directories, and the naming
+// conventions of Go, Python (atheris) and libFuzzer targets. Names that merely
+// contain "fuzz", such as fuzzy_search.go, do not count.
+func isFuzzHarness(p string) bool {
+	p = strings.ToLower(p)
+	if strings.HasPrefix(p, ".clusterfuzzlite/") || strings.Contains(p, "oss-fuzz") {
+		return true
+	}
+	dirs := strings.Split(path.Dir(p), "/")
+	for _, dir := range dirs {
+		if dir == "fuzz" || dir == "fuzzers" {
+			return true
+		}
+	}
+
+	base := path.Base(p)
+	ext := path.Ext(base)
+	name := strings.TrimSuffix(base, ext)
+	switch ext {
+	case ".go":
+		return strings.HasSuffix(name, "_fuzz_test") || name == "fuzz_test"
+	case ".py":
+		return strings.HasPrefix(name, "fuzz_")
+	case ".c", ".cc", ".cpp", ".cxx":
+		return strings.HasSuffix(name, "_fuzzer")
+	}
+	return false
+}
+
+// goFuzzTargetPattern matches a Go native fuzz target declaration
+var goFuzzTargetPattern = regexp.MustCompile(`(?m)^func Fuzz[A-Z0-9_]\w*\(\s*\w+ \*testing\.F\s*\)`)
+
+// findGoFuzzTests looks for native fuzz targets in up to limit Go test files.
+// It only fetches when no harness is recognized by path, since one is
+// enough to pass the check.
+func findGoFuzzTests(tree []github.TreeEntry, fetch func(string) (string, bool), limit int) []string {
+	var candidates []string
+	for _, entry := range tree {
+		if entry.Type != "blob" {
+			continue
+		}
+		if isFuzzHarness(entry.Path) {
+			return nil
+		}
+		if strings.HasSuffix(entry.Path, "_test.go") && !strings.HasPrefix(entry.Path, "vendor/") {
+			candidates = append(candidates, entry.Path)
+		}
+	}
+
+	var found []string
+	for i, p := range candidates {
+		if i >= limit {
+			break
+		}
+		if content, ok := fetch(p); ok && goFuzzTargetPattern.MatchString(content) {
+			found = append(found, p)
+		}
+	}
+	return found
+}
+
+func checkSAST(in *ScorecardInputs) ScorecardCheck {
+	c := ScorecardCheck{Name: CheckSAST, Risk: "Medium"}
+	for _, uses := range workflowActions(in) {
+		for _, tool := range scorecardSASTActions {
+			if strings.HasPrefix(strings.ToLower(uses), tool) {
+				c.Details = append(c.Details, uses)
+			}
+		}
+	}
+	for _, entry := range in.FileTree {
+		if entry.Type == "blob" && contains(scorecardSASTConfigs, entry.Path) {
+			c.Details = append(c.Details, entry.Path)
+		}
+	}
+	if len(c.Details) == 0 {
+		c.Reason = "no SAST tool found"
+		return c
+	}
+	c.Score = 10
+	c.Reason = "SAST tool found: " + c.Details[0]
+	return c
+}
+
+func checkDependencyUpdateTool(in *ScorecardInputs) ScorecardCheck {
+	c := ScorecardCheck{Name: CheckDependencyUpdateTool, Risk: "High"}
+	tools := map[string]string{
+		".github/dependabot.yml":  "Dependabot",
+		".github/dependabot.yaml": "Dependabot",
+		"renovate.json":           "Renovate",
+		"renovate.json5":          "Renovate",
+		".renovaterc":             "Renovate",
+		".renovaterc.json":        "Renovate",
+		".github/renovate.json":   "Renovate",
+		".github/renovate.json5":  "Renovate",
+	}
+	for _, entry := range in.FileTree {
+		if tool, ok := tools[entry.Path]; ok && entry.Type == "blob" {
+			c.Score = 10
+			c.Reason = fmt.Sprintf("%s is configured in %s", tool, entry.Path)
+			return c
+		}
+	}
+	c.Reason = "no dependency update tool configured"
+	return c
+}
+
+func workflowActions(in *ScorecardInputs) []string {
+	if in.Workflows == nil {
+		return nil
+	}
+	return in.Workflows.Actions
+}
+
+// GetScorecardEmoji returns an indicator for a check score
+func GetScorecardEmoji(score int) string {
+	switch {
+	case score == InconclusiveScore:
+		return "❔"
+	case score >= 8:
+		return "✅"
+	case score >= 5:
+		return "⚠️"
+	default:
+		return "❌"
+	}
+}
@@ -0,0 +1,177 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+func TestComputeScorecard(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	var commits []github.Commit
+	for week := 0; week < 13; week += 2 {
+		c := github.Commit{}
+		c.Commit.Author.Date = now.AddDate(0, 0, -7*week-1)
+		commits = append(commits, c)
+	}
+	var tree []github.TreeEntry
+	for _, p := range []string{"SECURITY.md", ".github/dependabot.yml", "fuzz/fuzz_parser.go", "main.go"} {
+		tree = append(tree, github.TreeEntry{Path: p, Type: "blob"})
+	}
+
+	branch := &github.Branch{Name: "main", Protected: true}
+	protection := &github.BranchProtection{EnforceAdmins: github.ProtectionSetting{Enabled: true}}
+
+	in := &ScorecardInputs{
+		Repo:     &github.Repo{FullName: "acme/widget", CreatedAt: now.AddDate(-2, 0, 0), DefaultBranch: "main"},
+		Commits:  commits,
+		FileTree: tree,
+		Workflows: &WorkflowAuditResult{
+			Workflows: 1,
+			Actions:   []string{"actions/checkout@971e284b6050e8a5849b72094c50ab08da042db8", "github/codeql-action/analyze@v3"},
+		},
+		Dependencies: &DependencyAnalysis{Files: []DependencyFile{{Filename: "go.mod"}}, HasLockFile: true},
+		License:      &LicenseAnalysis{MainLicense: &LicenseInfo{Name: "MIT", SPDX: "MIT", SourceFile: "LICENSE"}},
+		CodeQuality:  &CodeQualityMetrics{HasCI: true, CIProviders: []string{"GitHub Actions"}},
+		Branch:       branch,
+		Protection:   protection,
+		Releases: []github.Release{
+			{TagName: "v1.1.0", Assets: []github.ReleaseAsset{{Name: "widget.tar.gz"}, {Name: "widget.intoto.jsonl"}}},
+			{TagName: "v1.0.0", Assets: []github.ReleaseAsset{{Name: "widget.tar.gz"}}},
+			{TagName: "v2.0.0-draft", Draft: true},
+		},
+		PullRequests: []ReviewedPullRequest{
+			{Number: 3, Author: "alice", Reviewers: []string{"bob"}},
+			{Number: 2, Author: "alice", Reviewers: []string{"alice"}},
+			{Number: 1, Author: "carol", Approved: true},
+		},
+	}
+
+	result := computeScorecard(in, now)
+
+	want := map[string]int{
+		CheckMaintained:           5,  // 7 of 13 weeks
+		CheckCodeReview:           6,  // 2 of 3 reviewed
+		CheckBranchProtection:     4,  // protected, admins enforced, nothing else
+		CheckPinnedDependencies:   7,  // half the actions pinned, lock file present
+		CheckDangerousWorkflow:    10, // no findings
+		CheckSecurityPolicy:       10,
+		CheckSignedReleases:       5, // one of two releases has provenance
+		CheckLicense:              10,
+		CheckCITests:              3, // CI without tests
+		CheckFuzzing:              10,
+		CheckSAST:                 10,
+		CheckDependencyUpdateTool: 10,
+	}
+	if len(result.Checks) != len(want) {
+		t.Fatalf("got %d checks, want %d", len(result.Checks), len(want))
+	}
+	for _, c := range result.Checks {
+		if c.Score != want[c.Name] {
+			t.Errorf("%s = %d (%s), want %d", c.Name, c.Score, c.Reason, want[c.Name])
+		}
+	}
+	if result.Repo != "acme/widget" || result.Score < 7 || result.Score > 8 {
+		t.Errorf("Repo, Score = %q, %.1f; want acme/widget, between 7 and 8", result.Repo, result.Score)
+	}
+
+	// Missing data makes checks inconclusive and leaves them out of the score
+	empty := computeScorecard(&ScorecardInputs{}, now)
+	inconclusive := 0
+	for _, c := range empty.Checks {
+		if c.Score == InconclusiveScore {
+			inconclusive++
+		}
+	}
+	if inconclusive != 6 {
+		t.Errorf("inconclusive checks without data = %d, want 6", inconclusive)
+	}
+}
+
+func TestCheckDangerousWorkflow(t *testing.T) {
+	in := &ScorecardInputs{Workflows: &WorkflowAuditResult{
+		Workflows: 1,
+		Findings: []WorkflowFinding{
+			{Workflow: "ci.yml", Line: 4, Rule: RuleScriptInjection, Severity: "MEDIUM"},
+			{Workflow: "ci.yml", Line: 9, Rule: RuleUnpinnedAction, Severity: "HIGH"},
+		},
+	}}
+	if c := checkDangerousWorkflow(in); c.Score != 10 {
+		t.Errorf("medium injections and unpinned actions should not fail the check: %+v", c)
+	}
+
+	in.Workflows.Findings = append(in.Workflows.Findings, WorkflowFinding{Workflow: "pr.yml", Line: 12, Rule: RulePRTargetCheckout, Severity: "CRITICAL"})
+	if c := checkDangerousWorkflow(in); c.Score != 0 || len(c.Details) != 1 {
+		t.Errorf("pull_request_target checkout: %+v, want score 0 with one detail", c)
+	}
+}
+
+func TestIsFuzzHarness(t *testing.T) {
+	tests := map[string]bool{
+		"parser/parser_fuzz_test.go":     true,
+		"fuzz/parse.go":                  true,
+		"test/fuzzers/decode.rs":         true,
+		"tests/fuzz_decoder.py":          true,
+		"src/png_fuzzer.cc":              true,
+		".clusterfuzzlite/build.sh":      true,
+		"search/fuzzy_search.go":         false,
+		"web/src/fuzzy.ts":               false,
+		"internal/fuzzymatch/match.go":   false,
+		"docs/fuzzing-strategies.md":     false,
+		"pkg/parser/parser_test.go":      false,
+		"scripts/fuzz_report_summary.sh": false,
+	}
+	for p, want := range tests {
+		if got := isFuzzHarness(p); got != want {
+			t.Errorf("isFuzzHarness(%q) = %v, want %v", p, got, want)
+		}
+	}
+}
+
+func TestFindGoFuzzTests(t *testing.T) {
+	files := map[string]string{
+		"parser/parser_test.go": "package parser\n\nfunc FuzzParse(f *testing.F) {\n}\n",
+		"search/search_test.go": "package search\n\nfunc TestFuzzy(t *testing.T) {}\n",
+	}
+	fetch := func(p string) (string, bool) {
+		content, ok := files[p]
+		return content, ok
+	}
+	tree := []github.TreeEntry{
+		{Path: "search/fuzzy_search.go", Type: "blob"},
+		{Path: "search/search_test.go", Type: "blob"},
+		{Path: "parser/parser_test.go", Type: "blob"},
+	}
+
+	got := findGoFuzzTests(tree, fetch, 10)
+	if len(got) != 1 || got[0] != "parser/parser_test.go" {
+		t.Errorf("findGoFuzzTests() = %v, want parser/parser_test.go", got)
+	}
+	if got := findGoFuzzTests(tree, fetch, 1); len(got) != 0 {
+		t.Errorf("findGoFuzzTests() with limit 1 = %v, want nothing", got)
+	}
+
+	c := checkFuzzing(&ScorecardInputs{FileTree: tree})
+	if c.Score != 0 {
+		t.Errorf("fuzzy_search.go alone: %+v, want score 0", c)
+	}
+	c = checkFuzzing(&ScorecardInputs{FileTree: tree, GoFuzzTests: got})
+	if c.Score != 10 {
+		t.Errorf("with a native fuzz target: %+v, want score 10", c)
+	}
+}
+
+func TestReviewedPullRequest(t *testing.T) {
+	pr := github.PullRequest{Number: 7, User: &github.User{Login: "alice"}}
+	reviews := []github.Review{
+		{User: &github.User{Login: "bob"}, State: "COMMENTED"},
+		{User: &github.User{Login: "bob"}, State: "APPROVED"},
+		{User: nil, State: "APPROVED"},
+	}
+	r := reviewedPullRequest(pr, reviews)
+	if r.Author != "alice" || len(r.Reviewers) != 1 || !r.Approved {
+		t.Errorf("reviewedPullRequest() = %+v", r)
+	}
+}
@@ -0,0 +1,565 @@
+// Package analyzer provides analysis functions for GitHub repositories.
+// This file scans repository contents for leaked credentials.
+package analyzer
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// SecretFinding is a credential found in a repository file
+type SecretFinding struct {
+	Path        string  `json:"path"`
+	Line        int     `json:"line"`
+	Rule        string  `json:"rule"`        // Rule ID (e.g., "aws-access-key-id")
+	Description string  `json:"description"` // Human-readable rule name
+	Severity    string  `json:"severity"`    // "CRITICAL", "HIGH" or "MEDIUM"
+	Secret      string  `json:"secret"`      // Masked secret, only the first characters are kept
+	Entropy     float64 `json:"entropy"`     // Shannon entropy of the secret in bits per character
+	Fingerprint string  `json:"fingerprint"` // Hash of the secret, usable in the allowlist
+}
+
+// SecretScanResult holds the secret scan results
+type SecretScanResult struct {
+	Findings      []SecretFinding `json:"findings"`
+	FilesScanned  int             `json:"files_scanned"`
+	FilesSkipped  int             `json:"files_skipped"` // Binary, vendored, generated or oversized files
+	Truncated     bool            `json:"truncated"`     // More candidate files than MaxFiles
+	CriticalCount int             `json:"critical_count"`
+	HighCount     int             `json:"high_count"`
+	MediumCount   int             `json:"medium_count"`
+	Allowlist     string          `json:"allowlist,omitempty"` // Allowlist file that was applied
+	Suppressed    int             `json:"suppressed"`          // Findings hidden by the allowlist
+}
+
+// SecretScanOptions controls which files are fetched and scanned
+type SecretScanOptions struct {
+	MaxFiles      int    // Files fetched at most; defaults to 100
+	MaxFileSize   int    // Larger files are skipped; defaults to 256 KiB
+	Concurrency   int    // Parallel content requests; defaults to 8
+	AllowlistPath string // Allowlist file; defaults to ~/.repo-lyzer/secrets-allowlist
+}
+
+const (
+	defaultSecretMaxFiles    = 100
+	defaultSecretMaxFileSize = 256 << 10
+
+	// Random tokens score above these entropies while words and
+	// identifiers stay below them
+	base64EntropyThreshold = 4.0
+	hexEntropyThreshold    = 3.0
+)
+
+// secretRule matches one kind of credential. The secret is the group'th
+// submatch of pattern, or the whole match when group is 0.
+type secretRule struct {
+	id          string
+	description string
+	severity    string
+	pattern     *regexp.Regexp
+	group       int
+	minEntropy  float64 // Required entropy of the secret; 0 disables the check
+}
+
+// secretRules are the provider-specific patterns, checked before the
+// generic entropy rule
+var secretRules = []secretRule{
+	{
+		id: "private-key", description: "Private key", severity: "CRITICAL",
+		pattern: regexp.MustCompile(`-----BEGIN ((RSA|DSA|EC|OPENSSH|PGP|ENCRYPTED) )?PRIVATE KEY( BLOCK)?-----`),
+	},
+	{
+		id: "aws-access-key-id", description: "AWS access key ID", severity: "CRITICAL",
+		pattern: regexp.MustCompile(`\b((AKIA|ASIA|ABIA|ACCA)[0-9A-Z]{16})\b`), group: 1,
+	},
+	{
+		id: "aws-secret-access-key", description: "AWS secret access key", severity: "CRITICAL",
+		pattern:    regexp.MustCompile(`(?i)aws.{0,20}?(?:secret|private).{0,20}?\s*[:=]\s*['"]?([A-Za-z0-9/+]{40})(?:[^A-Za-z0-9/+]|$)`),
+		group:      1,
+		minEntropy: base64EntropyThreshold,
+	},
+	{
+		id: "github-token", description: "GitHub token", severity: "CRITICAL",
+		pattern: regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,255}|github_pat_[A-Za-z0-9_]{82})\b`), group: 1,
+	},
+	{
+		id: "slack-webhook", description: "Slack webhook URL", severity: "HIGH",
+		pattern: regexp.MustCompile(`https://hooks\.slack\.com/(services|workflows|triggers)/[A-Za-z0-9/_-]{20,}`),
+	},
+	{
+		id: "slack-token", description: "Slack token", severity: "HIGH",
+		pattern: regexp.MustCompile(`\b(xox[abposr]-[0-9A-Za-z-]{10,})\b`), group: 1,
+	},
+	{
+		id: "stripe-secret-key", description: "Stripe secret key", severity: "CRITICAL",
+		pattern: regexp.MustCompile(`\b((sk|rk)_live_[0-9A-Za-z]{24,})\b`), group: 1,
+	},
+	{
+		id: "google-api-key", description: "Google API key", severity: "HIGH",
+		pattern: regexp.MustCompile(`\b(AIza[0-9A-Za-z_-]{35})\b`), group: 1,
+	},
+}
+
+// genericSecretRule reports high-entropy values assigned to names that
+// suggest a credential
+var genericSecretRule = secretRule{
+	id: "generic-secret", description: "High-entropy secret", severity: "MEDIUM",
+	pattern: regexp.MustCompile(`(?i)(api[_-]?key|secret|token|passw(or)?d|credential|auth[_-]?key)[\w.-]{0,20}['"]?\s*(:=|=>|[:=])\s*['"]([A-Za-z0-9+/=_.~-]{16,})['"]`),
+	group:   4,
+}
+
+// secretPriorityExts are scanned before other text files since
+// credentials mostly end up in configuration
+var secretPriorityExts = []string{
+	".env", ".pem", ".key", ".yml", ".yaml", ".json", ".properties", ".ini", ".cfg", ".conf",
+	".toml", ".tf", ".tfvars", ".xml", ".sh", ".bash", ".npmrc", ".pypirc", ".netrc",
+}
+
+// secretSkipExts are binary or generated files that are never scanned
+var secretSkipExts = []string{
+	".png", ".jpg", ".jpeg", ".gif", ".bmp", ".ico", ".webp", ".svg", ".pdf", ".zip", ".gz", ".tgz",
+	".tar", ".bz2", ".xz", ".7z", ".jar", ".war", ".class", ".exe", ".dll", ".so", ".dylib", ".a",
+	".o", ".bin", ".woff", ".woff2", ".ttf", ".otf", ".eot", ".mp3", ".mp4", ".mov", ".avi", ".wasm",
+	".pyc", ".lock", ".sum", ".map", ".min.js", ".min.css", ".psd", ".sketch",
+}
+
+// secretSkipFiles are lock files whose integrity hashes look like secrets
+var secretSkipFiles = []string{"package-lock.json", "yarn.lock", "pnpm-lock.yaml", "composer.lock", "Cargo.lock", "poetry.lock", "Pipfile.lock", "Gemfile.lock", "go.sum"}
+
+// ScanSecrets fetches text files from the repository tree and reports the
+// credentials found in them. Binary, vendored and oversized files are
+// skipped, configuration files are scanned first, and findings matching the
+// allowlist are suppressed.
+func ScanSecrets(client *github.Client, owner, repo string, fileTree []github.TreeEntry, opts SecretScanOptions) (*SecretScanResult, error) {
+	allowlist, err := LoadSecretAllowlist(opts.AllowlistPath)
+	if err != nil {
+		return nil, err
+	}
+	fetch := contentFetcher(client, owner, repo)
+	allowlist.merge(repoSecretAllowlist(fileTree, fetch))
+	return scanSecrets(fileTree, fetch, allowlist, opts), nil
+}
+
+// scanSecrets selects candidate files, fetches them concurrently and scans
+// their contents
+func scanSecrets(fileTree []github.TreeEntry, fetch func(string) (string, bool), allowlist *SecretAllowlist, opts SecretScanOptions) *SecretScanResult {
+	if opts.MaxFiles <= 0 {
+		opts.MaxFiles = defaultSecretMaxFiles
+	}
+	if opts.MaxFileSize <= 0 {
+		opts.MaxFileSize = defaultSecretMaxFileSize
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 8
+	}
+
+	result := &SecretScanResult{Findings: []SecretFinding{}, Allowlist: allowlist.Source}
+
+	var candidates []string
+	for _, entry := range fileTree {
+		if entry.Type != "blob" || allowlist.allowsPath(entry.Path) {
+			continue
+		}
+		if entry.Size > opts.MaxFileSize || isSecretSkipped(entry.Path) {
+			result.FilesSkipped++
+			continue
+		}
+		candidates = append(candidates, entry.Path)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		pi, pj := isSecretPriority(candidates[i]), isSecretPriority(candidates[j])
+		if pi != pj {
+			return pi
+		}
+		return strings.Count(candidates[i], "/") < strings.Count(candidates[j], "/")
+	})
+	if len(candidates) > opts.MaxFiles {
+		candidates = candidates[:opts.MaxFiles]
+		result.Truncated = true
+	}
+
+	findings := make([][]SecretFinding, len(candidates))
+	scanned := make([]bool, len(candidates))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, opts.Concurrency)
+	for i, p := range candidates {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, p string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			content, ok := fetch(p)
+			if !ok || isBinaryContent(content) {
+				return
+			}
+			scanned[i] = true
+			findings[i] = scanSecretContent(p, content)
+		}(i, p)
+	}
+	wg.Wait()
+
+	for i := range candidates {
+		if !scanned[i] {
+			result.FilesSkipped++
+			continue
+		}
+		result.FilesScanned++
+		for _, f := range findings[i] {
+			if allowlist.allows(f) {
+				result.Suppressed++
+				continue
+			}
+			result.Findings = append(result.Findings, f)
+			switch f.Severity {
+			case "CRITICAL":
+				result.CriticalCount++
+			case "HIGH":
+				result.HighCount++
+			default:
+				result.MediumCount++
+			}
+		}
+	}
+
+	sort.SliceStable(result.Findings, func(i, j int) bool {
+		a, b := result.Findings[i], result.Findings[j]
+		if secretSeverityRank[a.Severity] != secretSeverityRank[b.Severity] {
+			return secretSeverityRank[a.Severity] > secretSeverityRank[b.Severity]
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Line < b.Line
+	})
+	return result
+}
+
+var secretSeverityRank = map[string]int{"CRITICAL": 3, "HIGH": 2, "MEDIUM": 1}
+
+// scanSecretContent runs every rule over each line of a file. A line
+// reported by a provider rule is not reported again by the generic rule.
+func scanSecretContent(filePath, content string) []SecretFinding {
+	var findings []SecretFinding
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Text()
+		matched := false
+		for _, rule := range secretRules {
+			if f, ok := rule.match(filePath, lineNo, line); ok {
+				findings = append(findings, f)
+				matched = true
+			}
+		}
+		if matched {
+			continue
+		}
+		if f, ok := genericSecretRule.match(filePath, lineNo, line); ok && !isPlaceholderSecret(line) {
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
+
+// match reports the first secret the rule finds on a line
+func (r secretRule) match(filePath string, lineNo int, line string) (SecretFinding, bool) {
+	m := r.pattern.FindStringSubmatch(line)
+	if m == nil {
+		return SecretFinding{}, false
+	}
+	secret := m[r.group]
+
+	entropy := shannonEntropy(secret)
+	minEntropy := r.minEntropy
+	if r.id == genericSecretRule.id {
+		minEntropy = base64EntropyThreshold
+		if isHexString(secret) {
+			minEntropy = hexEntropyThreshold
+		}
+	}
+	if entropy < minEntropy {
+		return SecretFinding{}, false
+	}
+
+	sum := sha256.Sum256([]byte(secret))
+	return SecretFinding{
+		Path:        filePath,
+		Line:        lineNo,
+		Rule:        r.id,
+		Description: r.description,
+		Severity:    r.severity,
+		Secret:      maskSecret(secret),
+		Entropy:     math.Round(entropy*100) / 100,
+		Fingerprint: hex.EncodeToString(sum[:8]),
+	}, true
+}
+
+// shannonEntropy returns the entropy of s in bits per character
+func shannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	counts := make(map[rune]int)
+	n := 0
+	for _, r := range s {
+		counts[r]++
+		n++
+	}
+	var entropy float64
+	for _, c := range counts {
+		p := float64(c) / float64(n)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+func isHexString(s string) bool {
+	for _, r := range s {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'f' || r >= 'A' && r <= 'F') {
+			return false
+		}
+	}
+	return true
+}
+
+// maskSecret keeps the first four characters of a secret, enough to tell
+// findings apart without repeating the credential
+func maskSecret(secret string) string {
+	if len(secret) <= 8 {
+		return strings.Repeat("*", len(secret))
+	}
+	stars := len(secret) - 4
+	if stars > 16 {
+		stars = 16
+	}
+	return secret[:4] + strings.Repeat("*", stars)
+}
+
+// isPlaceholderSecret filters generic matches that are documentation
+// examples or references to values defined elsewhere
+func isPlaceholderSecret(line string) bool {
+	lower := strings.ToLower(line)
+	for _, marker := range []string{"example", "placeholder", "changeme", "your_", "your-", "xxxx", "${", "{{", "process.env", "os.getenv", "os.environ"} {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSecretSkipped(p string) bool {
+	if isVendoredPath(p) || strings.HasPrefix(p, ".git/") {
+		return true
+	}
+	base := path.Base(p)
+	if contains(secretSkipFiles, base) {
+		return true
+	}
+	lower := strings.ToLower(base)
+	for _, ext := range secretSkipExts {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSecretPriority(p string) bool {
+	base := strings.ToLower(path.Base(p))
+	if strings.HasPrefix(base, ".env") || strings.HasPrefix(base, "id_rsa") || strings.HasPrefix(base, "credentials") {
+		return true
+	}
+	for _, ext := range secretPriorityExts {
+		if strings.HasSuffix(base, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// isBinaryContent treats content with a NUL byte in its first 8 KiB as binary
+func isBinaryContent(content string) bool {
+	head := content
+	if len(head) > 8192 {
+		head = head[:8192]
+	}
+	return strings.IndexByte(head, 0) >= 0
+}
+
+// SecretAllowlist suppresses known or accepted findings. Each line of the
+// allowlist file holds one entry; "#" starts a comment.
+//
+//	testdata/**            skip files matching a path pattern
+//	rule:generic-secret    disable a rule
+//	3f2a9c0d5e6b7a81       suppress a finding by its fingerprint
+//	docs/setup.md:42       suppress findings on a line
+type SecretAllowlist struct {
+	Paths        []string
+	Rules        []string
+	Fingerprints []string
+	Lines        []string
+	Source       string
+}
+
+// DefaultSecretAllowlistPath returns ~/.repo-lyzer/secrets-allowlist
+func DefaultSecretAllowlistPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".repo-lyzer", "secrets-allowlist"), nil
+}
+
+// LoadSecretAllowlist reads an allowlist file. With an empty path it reads
+// the default location, where a missing file means an empty allowlist.
+func LoadSecretAllowlist(p string) (*SecretAllowlist, error) {
+	explicit := p != ""
+	if !explicit {
+		var err error
+		if p, err = DefaultSecretAllowlistPath(); err != nil {
+			return &SecretAllowlist{}, nil
+		}
+	}
+
+	content, err := os.ReadFile(p)
+	if err != nil {
+		if !explicit && os.IsNotExist(err) {
+			return &SecretAllowlist{}, nil
+		}
+		return nil, err
+	}
+	allowlist := parseSecretAllowlist(string(content))
+	allowlist.Source = p
+	return allowlist, nil
+}
+
+// RepoSecretAllowlistFile is an allowlist kept in the scanned repository's
+// root, applied together with the local one
+const RepoSecretAllowlistFile = ".repo-lyzer-secrets-allowlist"
+
+// repoSecretAllowlist fetches the repository's own allowlist, or returns
+// nil when it has none
+func repoSecretAllowlist(fileTree []github.TreeEntry, fetch func(string) (string, bool)) *SecretAllowlist {
+	for _, entry := range fileTree {
+		if entry.Type != "blob" || entry.Path != RepoSecretAllowlistFile {
+			continue
+		}
+		content, ok := fetch(entry.Path)
+		if !ok {
+			return nil
+		}
+		allowlist := parseSecretAllowlist(content)
+		allowlist.Source = entry.Path
+		return allowlist
+	}
+	return nil
+}
+
+// merge adds the entries of other, keeping track of both sources
+func (a *SecretAllowlist) merge(other *SecretAllowlist) {
+	if other == nil {
+		return
+	}
+	a.Paths = append(a.Paths, other.Paths...)
+	a.Rules = append(a.Rules, other.Rules...)
+	a.Fingerprints = append(a.Fingerprints, other.Fingerprints...)
+	a.Lines = append(a.Lines, other.Lines...)
+	switch {
+	case a.Source == "":
+		a.Source = other.Source
+	case other.Source != "":
+		a.Source += ", " + other.Source
+	}
+}
+
+var fingerprintPattern = regexp.MustCompile(`^[0-9a-f]{16}$`)
+var allowLinePattern = regexp.MustCompile(`^(.+):(\d+)$`)
+
+func parseSecretAllowlist(content string) *SecretAllowlist {
+	allowlist := &SecretAllowlist{}
+	for _, line := range strings.Split(content, "\n") {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "rule:"):
+			allowlist.Rules = append(allowlist.Rules, strings.TrimSpace(strings.TrimPrefix(line, "rule:")))
+		case fingerprintPattern.MatchString(line):
+			allowlist.Fingerprints = append(allowlist.Fingerprints, line)
+		case allowLinePattern.MatchString(line):
+			allowlist.Lines = append(allowlist.Lines, line)
+		default:
+			allowlist.Paths = append(allowlist.Paths, line)
+		}
+	}
+	return allowlist
+}
+
+// allowsPath reports whether a file is excluded from the scan
+func (a *SecretAllowlist) allowsPath(p string) bool {
+	for _, pattern := range a.Paths {
+		if matchAllowPath(pattern, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// allows reports whether a finding is suppressed
+func (a *SecretAllowlist) allows(f SecretFinding) bool {
+	return contains(a.Rules, f.Rule) || contains(a.Fingerprints, f.Fingerprint) ||
+		contains(a.Lines, fmt.Sprintf("%s:%d", f.Path, f.Line))
+}
+
+// matchAllowPath matches a path against a glob. "**" matches any number of
+// directories, a trailing "/" matches everything below a directory, and
+// patterns without a slash match the file name in any directory.
+func matchAllowPath(pattern, p string) bool {
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "**"
+	}
+	if !strings.Contains(pattern, "/") && pattern != "**" {
+		ok, _ := path.Match(pattern, path.Base(p))
+		return ok
+	}
+	return matchPathSegments(strings.Split(strings.TrimPrefix(pattern, "/"), "/"), strings.Split(p, "/"))
+}
+
+// matchPathSegments matches path segments one by one, letting a "**"
+// segment consume zero or more of them
+func matchPathSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := len(segments); i >= 0; i-- {
+				if matchPathSegments(pattern[1:], segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segments) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+			return false
+		}
+		pattern, segments = pattern[1:], segments[1:]
+	}
+	return len(segments) == 0
+}
@@ -2,10 +2,14 @@
 package analyzer
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -14,11 +18,15 @@ type Vulnerability struct {
 	ID          string   `json:"id"`
 	Summary     string   `json:"summary"`
 	Severity    string   `json:"severity"`
+	Score       float64  `json:"score,omitempty"`  // CVSS score computed from Vector
+	Vector      string   `json:"vector,omitempty"` // CVSS vector string, when the advisory has one
 	Package     string   `json:"package"`
+	Ecosystem   string   `json:"ecosystem,omitempty"` // OSV ecosystem of the package (e.g. "npm", "PyPI")
 	Version     string   `json:"version"`
-	FixedIn     string   `json:"fixed_in"`
+	FixedIn     string   `json:"fixed_in"` // Release fixing the affected range the version falls in
 	References  []string `json:"references"`
 	PublishedAt string   `json:"published_at"`
+	Direct      bool     `json:"direct"` // Declared in a manifest rather than pulled in transitively
 }
 
 // SecurityScanResult holds security scan results
@@ -32,6 +40,20 @@ type SecurityScanResult struct {
 	ScannedPackages int             `json:"scanned_packages"`
 	ScanTime        time.Time       `json:"scan_time"`
 	SecurityScore   int             `json:"security_score"`
+	Source          string          `json:"source"`             // "osv.dev" or "offline"
+	Failures        []ScanFailure   `json:"failures,omitempty"` // Packages that could not be checked
+	Upgrades        []UpgradeAdvice `json:"upgrades,omitempty"` // Minimal safe upgrade per vulnerable package
+}
+
+// UpgradeAdvice is the smallest upgrade of a vulnerable package that no
+// known vulnerability affects.
+type UpgradeAdvice struct {
+	Package     string   `json:"package"`
+	Ecosystem   string   `json:"ecosystem"`
+	Current     string   `json:"current"`
+	SafeVersion string   `json:"safe_version"` // Empty when no fixed release is known
+	Fixes       []string `json:"fixes"`        // Vulnerability IDs resolved by the upgrade
+	Direct      bool     `json:"direct"`
 }
 
 type osvQuery struct {
@@ -39,75 +61,195 @@ type osvQuery struct {
 		Name      string `json:"name"`
 		Ecosystem string `json:"ecosystem"`
 	} `json:"package"`
-	Version string `json:"version,omitempty"`
+	Version   string `json:"version,omitempty"`
+	PageToken string `json:"page_token,omitempty"`
+}
+
+type osvBatchRequest struct {
+	Queries []osvQuery `json:"queries"`
 }
 
-type osvResponse struct {
-	Vulns []osvVuln `json:"vulns"`
+type osvBatchResponse struct {
+	Results []struct {
+		Vulns []struct {
+			ID string `json:"id"`
+		} `json:"vulns"`
+		NextPageToken string `json:"next_page_token"`
+	} `json:"results"`
 }
 
+// osvVuln is a vulnerability record in the OSV schema
+// (https://ossf.github.io/osv-schema/), as returned by the API and stored
+// in the offline database.
 type osvVuln struct {
-	ID       string `json:"id"`
-	Summary  string `json:"summary"`
-	Severity []struct {
-		Type  string `json:"type"`
-		Score string `json:"score"`
-	} `json:"severity"`
-	Affected []struct {
-		Ranges []struct {
-			Events []struct {
-				Fixed string `json:"fixed,omitempty"`
-			} `json:"events"`
-		} `json:"ranges"`
-	} `json:"affected"`
-	References []struct {
-		URL string `json:"url"`
-	} `json:"references"`
-	Published string `json:"published"`
-}
-
-// ScanDependencies scans dependencies for vulnerabilities
+	ID         string         `json:"id"`
+	Summary    string         `json:"summary,omitempty"`
+	Withdrawn  string         `json:"withdrawn,omitempty"`
+	Severity   []osvSeverity  `json:"severity,omitempty"`
+	Affected   []osvAffected  `json:"affected,omitempty"`
+	References []osvReference `json:"references,omitempty"`
+	Published  string         `json:"published,omitempty"`
+
+	// DatabaseSpecific carries the source database's own rating, such as
+	// GitHub's LOW/MODERATE/HIGH/CRITICAL
+	DatabaseSpecific struct {
+		Severity string `json:"severity,omitempty"`
+	} `json:"database_specific,omitempty"`
+}
+
+type osvSeverity struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+type osvAffected struct {
+	Package struct {
+		Name      string `json:"name"`
+		Ecosystem string `json:"ecosystem"`
+	} `json:"package"`
+	Ranges   []osvRange    `json:"ranges,omitempty"`
+	Versions []string      `json:"versions,omitempty"`
+	Severity []osvSeverity `json:"severity,omitempty"`
+}
+
+type osvRange struct {
+	Type   string     `json:"type"`
+	Events []osvEvent `json:"events"`
+}
+
+type osvEvent struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+	Limit        string `json:"limit,omitempty"`
+}
+
+type osvReference struct {
+	URL string `json:"url"`
+}
+
+// ScanFailure records a package that could not be checked for vulnerabilities.
+type ScanFailure struct {
+	Package   string `json:"package"`
+	Version   string `json:"version"`
+	Ecosystem string `json:"ecosystem"`
+	Error     string `json:"error"`
+}
+
+// ScanOptions controls how dependencies are checked for vulnerabilities.
+type ScanOptions struct {
+	Offline     bool   // Match against the local vulnerability database instead of osv.dev
+	DBDir       string // Local database directory; defaults to ~/.repo-lyzer/vulndb
+	Concurrency int    // Parallel requests to osv.dev; defaults to 8
+}
+
+// osvAPIBase is the OSV API endpoint, overridden in tests
+var osvAPIBase = "https://api.osv.dev"
+
+const (
+	// osvBatchSize is the maximum number of queries per querybatch request
+	osvBatchSize = 1000
+	// osvMaxPages caps how many result pages are followed per batch
+	osvMaxPages = 10
+
+	defaultScanConcurrency = 8
+)
+
+// ScanDependencies scans dependencies for vulnerabilities using osv.dev
 func ScanDependencies(deps *DependencyAnalysis) (*SecurityScanResult, error) {
-	if deps == nil || len(deps.Files) == 0 {
-		return &SecurityScanResult{
-			Vulnerabilities: []Vulnerability{},
-			ScanTime:        time.Now(),
-			SecurityScore:   100,
-		}, nil
-	}
+	return ScanDependenciesWithOptions(deps, ScanOptions{})
+}
 
+// ScanDependenciesWithOptions scans dependencies for vulnerabilities, either
+// online through OSV's batch API or offline against the local database
+// imported with "repo-lyzer vulndb update". Packages that could not be
+// checked, including unpinned ones with known advisories, are listed in the
+// result's Failures rather than silently skipped or counted as affected.
+func ScanDependenciesWithOptions(deps *DependencyAnalysis, opts ScanOptions) (*SecurityScanResult, error) {
 	result := &SecurityScanResult{
 		Vulnerabilities: []Vulnerability{},
 		ScanTime:        time.Now(),
+		Source:          "osv.dev",
+	}
+	if opts.Offline {
+		result.Source = "offline"
+	}
+	if deps == nil || (len(deps.Files) == 0 && len(deps.LockFiles) == 0) {
+		result.SecurityScore = 100
+		return result, nil
 	}
 
-	client := &http.Client{Timeout: 10 * time.Second}
+	targets := scanTargets(deps)
+	var found [][]osvVuln
+	if opts.Offline {
+		db, err := OpenVulnDB(opts.DBDir)
+		if err != nil {
+			return nil, err
+		}
+		found, result.Failures = db.scan(targets)
+	} else {
+		if opts.Concurrency <= 0 {
+			opts.Concurrency = defaultScanConcurrency
+		}
+		client := &http.Client{Timeout: 30 * time.Second}
+		found, result.Failures = scanOSV(client, targets, opts.Concurrency)
+	}
 
-	for _, file := range deps.Files {
-		ecosystem := mapEcosystem(file.FileType)
-		if ecosystem == "" {
+	for i, target := range targets {
+		result.ScannedPackages++
+
+		// Ranges are evaluated locally with the ecosystem's version scheme.
+		// Constraints are checked at their lowest allowed version; records
+		// that cannot be evaluated are trusted as reported.
+		version := constraintBaseVersion(target.version)
+		if version == "" {
+			// Without a lower bound ("*", "<2.0", latest) every advisory in
+			// the package's history would match, so report it as unresolved
+			if len(found[i]) > 0 {
+				result.Failures = append(result.Failures, ScanFailure{
+					Package:   target.name,
+					Version:   target.version,
+					Ecosystem: target.ecosystem,
+					Error:     fmt.Sprintf("version is not pinned; %d advisories not evaluated", len(found[i])),
+				})
+			}
 			continue
 		}
 
-		for _, dep := range file.Dependencies {
-			result.ScannedPackages++
-			vulns, _ := queryOSV(client, dep.Name, dep.Version, ecosystem)
-
-			for _, v := range vulns {
-				vuln := convertVuln(v, dep.Name, dep.Version)
-				result.Vulnerabilities = append(result.Vulnerabilities, vuln)
-
-				switch vuln.Severity {
-				case "CRITICAL":
-					result.CriticalCount++
-				case "HIGH":
-					result.HighCount++
-				case "MEDIUM":
-					result.MediumCount++
-				case "LOW":
-					result.LowCount++
-				}
+		var affecting []osvVuln
+		for _, v := range found[i] {
+			if affected, known := osvAffects(v, target.ecosystem, target.name, version); known && !affected {
+				continue
+			}
+			affecting = append(affecting, v)
+
+			vuln := convertVuln(v, target, version)
+			result.Vulnerabilities = append(result.Vulnerabilities, vuln)
+
+			switch vuln.Severity {
+			case "CRITICAL":
+				result.CriticalCount++
+			case "HIGH":
+				result.HighCount++
+			case "MEDIUM":
+				result.MediumCount++
+			case "LOW":
+				result.LowCount++
+			}
+		}
+
+		if len(affecting) > 0 {
+			advice := UpgradeAdvice{
+				Package:     target.name,
+				Ecosystem:   target.ecosystem,
+				Current:     target.version,
+				SafeVersion: osvSafeUpgrade(affecting, target.ecosystem, target.name, version),
+				Direct:      target.direct,
+			}
+			for _, v := range affecting {
+				advice.Fixes = append(advice.Fixes, v.ID)
 			}
+			result.Upgrades = append(result.Upgrades, advice)
 		}
 	}
 
@@ -116,44 +258,273 @@ func ScanDependencies(deps *DependencyAnalysis) (*SecurityScanResult, error) {
 	return result, nil
 }
 
+// scanTarget is a single package version to check against OSV
+type scanTarget struct {
+	name      string
+	version   string
+	ecosystem string
+	direct    bool
+}
+
+// scanTargets lists the packages to scan. Locked packages are scanned at
+// their exact versions, including transitive ones; manifest dependencies
+// use their resolved version when a lock file provides one and fall back
+// to the declared constraint otherwise.
+func scanTargets(deps *DependencyAnalysis) []scanTarget {
+	var targets []scanTarget
+	index := make(map[string]int)
+	add := func(t scanTarget) {
+		key := t.ecosystem + "|" + strings.ToLower(t.name) + "|" + t.version
+		if i, ok := index[key]; ok {
+			targets[i].direct = targets[i].direct || t.direct
+			return
+		}
+		index[key] = len(targets)
+		targets = append(targets, t)
+	}
+
+	for _, lf := range deps.LockFiles {
+		ecosystem := mapEcosystem(lf.FileType)
+		if ecosystem == "" {
+			continue
+		}
+		for _, p := range lf.Packages {
+			add(scanTarget{name: p.Name, version: p.Version, ecosystem: ecosystem, direct: p.Direct})
+		}
+	}
+
+	for _, file := range deps.Files {
+		ecosystem := mapEcosystem(file.FileType)
+		if ecosystem == "" {
+			continue
+		}
+		for _, dep := range file.Dependencies {
+			version := dep.Resolved
+			if version == "" {
+				version = dep.Version
+			}
+			add(scanTarget{name: dep.Name, version: version, ecosystem: ecosystem, direct: dep.Type != "indirect"})
+		}
+	}
+	return targets
+}
+
 func mapEcosystem(fileType string) string {
-	m := map[string]string{"npm": "npm", "go": "Go", "python": "PyPI", "rust": "crates.io", "ruby": "RubyGems"}
+	m := map[string]string{
+		"npm":    "npm",
+		"go":     "Go",
+		"python": "PyPI",
+		"rust":   "crates.io",
+		"ruby":   "RubyGems",
+		"maven":  "Maven",
+		"gradle": "Maven",
+		"nuget":  "NuGet",
+		"php":    "Packagist",
+		"dart":   "Pub",
+		"swift":  "SwiftURL",
+		"elixir": "Hex",
+	}
 	return m[fileType]
 }
 
-func queryOSV(client *http.Client, pkg, ver, eco string) ([]osvVuln, error) {
-	query := osvQuery{}
-	query.Package.Name = pkg
-	query.Package.Ecosystem = eco
-	ver = strings.TrimPrefix(strings.TrimPrefix(ver, "^"), "~")
-	if ver != "" && ver != "*" {
-		query.Version = ver
+// queryVersion turns a dependency version into an OSV query version. Only
+// exact versions are sent; for constraints such as "^1.2.3" every record of
+// the package is requested and the ranges are evaluated locally.
+func queryVersion(ver string) string {
+	if !isExactVersion(ver) {
+		return ""
 	}
+	return strings.TrimPrefix(strings.TrimSpace(ver), "==")
+}
 
-	data, _ := json.Marshal(query)
-	resp, err := client.Post("https://api.osv.dev/v1/query", "application/json", strings.NewReader(string(data)))
-	if err != nil {
-		return nil, err
+// scanOSV checks targets against osv.dev. Queries are sent in batches of
+// up to osvBatchSize, then the full record of every distinct vulnerability
+// is fetched, both with bounded concurrency. found[i] holds the
+// vulnerabilities affecting targets[i].
+func scanOSV(client *http.Client, targets []scanTarget, concurrency int) ([][]osvVuln, []ScanFailure) {
+	ids := make([][]string, len(targets))
+	failed := make([]error, len(targets))
+	incomplete := make([]bool, len(targets))
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+
+	for start := 0; start < len(targets); start += osvBatchSize {
+		end := start + osvBatchSize
+		if end > len(targets) {
+			end = len(targets)
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(start, end int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			batchIDs, truncated, err := queryOSVBatch(client, targets[start:end])
+			mu.Lock()
+			defer mu.Unlock()
+			for i := start; i < end; i++ {
+				if err != nil {
+					failed[i] = err
+				} else {
+					ids[i] = batchIDs[i-start]
+				}
+			}
+			for _, i := range truncated {
+				incomplete[start+i] = true
+			}
+		}(start, end)
 	}
-	defer resp.Body.Close()
+	wg.Wait()
 
-	var r osvResponse
-	json.NewDecoder(resp.Body).Decode(&r)
-	return r.Vulns, nil
+	// Fetch each distinct vulnerability once
+	var unique []string
+	seen := make(map[string]bool)
+	for _, list := range ids {
+		for _, id := range list {
+			if !seen[id] {
+				seen[id] = true
+				unique = append(unique, id)
+			}
+		}
+	}
+
+	records := make(map[string]osvVuln, len(unique))
+	recordErrs := make(map[string]error)
+	for _, id := range unique {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(id string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			v, err := fetchOSVVuln(client, id)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				recordErrs[id] = err
+				v = osvVuln{ID: id}
+			}
+			records[id] = v
+		}(id)
+	}
+	wg.Wait()
+
+	found := make([][]osvVuln, len(targets))
+	var failures []ScanFailure
+	for i, t := range targets {
+		if failed[i] != nil {
+			failures = append(failures, ScanFailure{Package: t.name, Version: t.version, Ecosystem: t.ecosystem, Error: failed[i].Error()})
+			continue
+		}
+		if incomplete[i] {
+			// The advisories found so far are still reported
+			failures = append(failures, ScanFailure{Package: t.name, Version: t.version, Ecosystem: t.ecosystem, Error: fmt.Sprintf("more than %d pages of advisories; the rest were not fetched", osvMaxPages)})
+		}
+		for _, id := range ids[i] {
+			if err := recordErrs[id]; err != nil {
+				// The package is known to be affected even without details
+				failures = append(failures, ScanFailure{Package: t.name, Version: t.version, Ecosystem: t.ecosystem, Error: fmt.Sprintf("fetching %s: %v", id, err)})
+			}
+			found[i] = append(found[i], records[id])
+		}
+	}
+	return found, failures
 }
 
-func convertVuln(o osvVuln, pkg, ver string) Vulnerability {
-	v := Vulnerability{ID: o.ID, Summary: o.Summary, Package: pkg, Version: ver, PublishedAt: o.Published}
-	v.Severity = getSeverity(o)
-	for _, a := range o.Affected {
-		for _, r := range a.Ranges {
-			for _, e := range r.Events {
-				if e.Fixed != "" {
-					v.FixedIn = e.Fixed
-				}
+// queryOSVBatch sends one querybatch request and follows per-query
+// pagination for up to osvMaxPages pages. It returns the vulnerability IDs
+// for each target and the indexes of targets that still had more pages.
+func queryOSVBatch(client *http.Client, targets []scanTarget) ([][]string, []int, error) {
+	ids := make([][]string, len(targets))
+	pending := make([]int, len(targets))
+	for i := range targets {
+		pending[i] = i
+	}
+	tokens := make([]string, len(targets))
+
+	for page := 0; len(pending) > 0 && page < osvMaxPages; page++ {
+		req := osvBatchRequest{Queries: make([]osvQuery, len(pending))}
+		for j, i := range pending {
+			q := &req.Queries[j]
+			q.Package.Name = targets[i].name
+			q.Package.Ecosystem = targets[i].ecosystem
+			q.Version = queryVersion(targets[i].version)
+			q.PageToken = tokens[i]
+		}
+
+		var resp osvBatchResponse
+		if err := postOSV(client, "/v1/querybatch", req, &resp); err != nil {
+			return nil, nil, err
+		}
+		if len(resp.Results) != len(pending) {
+			return nil, nil, fmt.Errorf("osv.dev returned %d results for %d queries", len(resp.Results), len(pending))
+		}
+
+		var next []int
+		for j, r := range resp.Results {
+			i := pending[j]
+			for _, v := range r.Vulns {
+				ids[i] = append(ids[i], v.ID)
+			}
+			if r.NextPageToken != "" {
+				tokens[i] = r.NextPageToken
+				next = append(next, i)
 			}
 		}
+		pending = next
+	}
+	return ids, pending, nil
+}
+
+// fetchOSVVuln fetches the full record of a single vulnerability
+func fetchOSVVuln(client *http.Client, id string) (osvVuln, error) {
+	var v osvVuln
+	resp, err := client.Get(osvAPIBase + "/v1/vulns/" + url.PathEscape(id))
+	if err != nil {
+		return v, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return v, fmt.Errorf("osv.dev returned %s", resp.Status)
+	}
+	err = json.NewDecoder(resp.Body).Decode(&v)
+	return v, err
+}
+
+func postOSV(client *http.Client, path string, body, target interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
 	}
+	resp, err := client.Post(osvAPIBase+path, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("osv.dev returned %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// convertVuln builds a Vulnerability for a target checked at version
+func convertVuln(o osvVuln, target scanTarget, version string) Vulnerability {
+	v := Vulnerability{
+		ID:          o.ID,
+		Summary:     o.Summary,
+		Package:     target.name,
+		Ecosystem:   target.ecosystem,
+		Version:     target.version,
+		PublishedAt: o.Published,
+		Direct:      target.direct,
+	}
+	v.Severity, v.Score, v.Vector = assessSeverity(o, target.ecosystem, target.name)
+	v.FixedIn = osvFixedVersion(o, target.ecosystem, target.name, version)
 	for _, ref := range o.References {
 		if ref.URL != "" && len(v.References) < 3 {
 			v.References = append(v.References, ref.URL)
@@ -162,22 +533,50 @@ func convertVuln(o osvVuln, pkg, ver string) Vulnerability {
 	return v
 }
 
-func getSeverity(o osvVuln) string {
-	for _, s := range o.Severity {
-		if s.Type == "CVSS_V3" {
-			var score float64
-			fmt.Sscanf(s.Score, "%f", &score)
-			if score >= 9.0 {
-				return "CRITICAL"
-			} else if score >= 7.0 {
-				return "HIGH"
-			} else if score >= 4.0 {
-				return "MEDIUM"
+// assessSeverity rates a vulnerability from its CVSS vectors, preferring
+// v4.0 over v3.x and package-specific ratings over record-wide ones. The
+// source database's rating is used when no vector can be scored, and
+// MEDIUM when there is no rating at all.
+func assessSeverity(o osvVuln, ecosystem, name string) (severity string, score float64, vector string) {
+	var candidates []osvSeverity
+	for _, a := range osvMatchingAffected(o, ecosystem, name) {
+		candidates = append(candidates, a.Severity...)
+	}
+	candidates = append(candidates, o.Severity...)
+
+	for _, typ := range []string{"CVSS_V4", "CVSS_V3"} {
+		for _, s := range candidates {
+			if s.Type != typ {
+				continue
+			}
+			if cvss, err := CVSSScore(s.Score); err == nil {
+				return cvssRating(cvss), cvss, s.Score
+			}
+			// Some sources publish the numeric score instead of the vector
+			if n, err := strconv.ParseFloat(s.Score, 64); err == nil {
+				return cvssRating(n), n, ""
 			}
-			return "LOW"
 		}
 	}
-	return "MEDIUM"
+
+	switch strings.ToUpper(o.DatabaseSpecific.Severity) {
+	case "CRITICAL":
+		return "CRITICAL", 0, ""
+	case "HIGH":
+		return "HIGH", 0, ""
+	case "LOW":
+		return "LOW", 0, ""
+	}
+	return "MEDIUM", 0, ""
+}
+
+// cvssRating maps a CVSS score to the severities counted in scan results;
+// scores of zero count as LOW
+func cvssRating(score float64) string {
+	if sev := CVSSSeverity(score); sev != "NONE" {
+		return sev
+	}
+	return "LOW"
 }
 
 func calcSecurityScore(r *SecurityScanResult) int {
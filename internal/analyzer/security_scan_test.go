@@ -0,0 +1,209 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// fakeOSV serves querybatch and vulns endpoints. lodash is vulnerable with
+// a second page of results, and "broken" makes the detail request fail.
+func fakeOSV(t *testing.T, batchCalls *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v1/querybatch":
+			atomic.AddInt32(batchCalls, 1)
+			var req osvBatchRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("invalid batch request: %v", err)
+			}
+			var results []map[string]interface{}
+			for _, q := range req.Queries {
+				res := map[string]interface{}{}
+				switch {
+				case q.Package.Name == "lodash" && q.PageToken == "":
+					res["vulns"] = []map[string]string{{"id": "GHSA-1"}}
+					res["next_page_token"] = "page2"
+				case q.Package.Name == "lodash":
+					res["vulns"] = []map[string]string{{"id": "GHSA-2"}}
+				case q.Package.Name == "broken":
+					res["vulns"] = []map[string]string{{"id": "GHSA-BROKEN"}}
+				}
+				results = append(results, res)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": results})
+		case strings.HasPrefix(r.URL.Path, "/v1/vulns/GHSA-BROKEN"):
+			http.Error(w, "boom", http.StatusInternalServerError)
+		case strings.HasPrefix(r.URL.Path, "/v1/vulns/"):
+			id := strings.TrimPrefix(r.URL.Path, "/v1/vulns/")
+			json.NewEncoder(w).Encode(osvVuln{
+				ID:       id,
+				Summary:  "Prototype pollution",
+				Severity: []osvSeverity{{Type: "CVSS_V3", Score: "9.8"}},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestScanDependenciesWithOptions_Batch(t *testing.T) {
+	var batchCalls int32
+	server := fakeOSV(t, &batchCalls)
+	defer server.Close()
+
+	defer func(base string) { osvAPIBase = base }(osvAPIBase)
+	osvAPIBase = server.URL
+
+	deps := &DependencyAnalysis{Files: []DependencyFile{{
+		Filename: "package.json",
+		FileType: "npm",
+		Dependencies: []Dependency{
+			{Name: "lodash", Version: "^4.17.0", Resolved: "4.17.15", Type: "production"},
+			{Name: "react", Version: "^18.0.0", Type: "production"},
+			{Name: "broken", Version: "1.0.0", Type: "production"},
+		},
+	}}}
+
+	result, err := ScanDependenciesWithOptions(deps, ScanOptions{Concurrency: 2})
+	if err != nil {
+		t.Fatalf("ScanDependenciesWithOptions() error = %v", err)
+	}
+	if result.ScannedPackages != 3 {
+		t.Errorf("ScannedPackages = %d, want 3", result.ScannedPackages)
+	}
+	if result.TotalCount != 3 {
+		t.Fatalf("TotalCount = %d, want 3 (two pages for lodash plus broken): %+v", result.TotalCount, result.Vulnerabilities)
+	}
+	if v := result.Vulnerabilities[0]; v.Package != "lodash" || v.Version != "4.17.15" || v.Severity != "CRITICAL" {
+		t.Errorf("first vulnerability = %+v, want critical lodash at resolved version", v)
+	}
+	if got := atomic.LoadInt32(&batchCalls); got != 2 {
+		t.Errorf("querybatch calls = %d, want 2 (initial page plus lodash's second page)", got)
+	}
+	if len(result.Failures) != 1 || result.Failures[0].Package != "broken" {
+		t.Errorf("Failures = %+v, want one failure for broken", result.Failures)
+	}
+}
+
+func TestScanDependenciesWithOptions_BatchFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	defer func(base string) { osvAPIBase = base }(osvAPIBase)
+	osvAPIBase = server.URL
+
+	deps := &DependencyAnalysis{Files: []DependencyFile{{
+		Filename:     "go.mod",
+		FileType:     "go",
+		Dependencies: []Dependency{{Name: "github.com/pkg/errors", Version: "v0.9.1", Type: "production"}},
+	}}}
+
+	result, err := ScanDependenciesWithOptions(deps, ScanOptions{})
+	if err != nil {
+		t.Fatalf("ScanDependenciesWithOptions() error = %v", err)
+	}
+	if len(result.Failures) != 1 || !strings.Contains(result.Failures[0].Error, "503") {
+		t.Errorf("Failures = %+v, want one failure mentioning the HTTP status", result.Failures)
+	}
+}
+
+func TestScanDependenciesWithOptions_Unpinned(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v1/querybatch":
+			var req osvBatchRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			var results []map[string]interface{}
+			for range req.Queries {
+				results = append(results, map[string]interface{}{"vulns": []map[string]string{{"id": "GHSA-OLD"}}})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": results})
+		case r.URL.Path == "/v1/vulns/GHSA-OLD":
+			v := osvVuln{ID: "GHSA-OLD", Severity: []osvSeverity{{Type: "CVSS_V3", Score: "9.8"}}}
+			affected := osvAffected{Ranges: []osvRange{{Type: "SEMVER", Events: []osvEvent{{Introduced: "0"}, {Fixed: "1.2.6"}}}}}
+			affected.Package.Name = "minimist"
+			affected.Package.Ecosystem = "npm"
+			v.Affected = []osvAffected{affected}
+			json.NewEncoder(w).Encode(v)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	defer func(base string) { osvAPIBase = base }(osvAPIBase)
+	osvAPIBase = server.URL
+
+	deps := &DependencyAnalysis{Files: []DependencyFile{{
+		Filename:     "package.json",
+		FileType:     "npm",
+		Dependencies: []Dependency{{Name: "minimist", Version: "*", Type: "production"}},
+	}}}
+
+	result, err := ScanDependenciesWithOptions(deps, ScanOptions{})
+	if err != nil {
+		t.Fatalf("ScanDependenciesWithOptions() error = %v", err)
+	}
+	if result.TotalCount != 0 || result.CriticalCount != 0 {
+		t.Errorf("TotalCount = %d, CriticalCount = %d, want 0 for an unpinned dependency", result.TotalCount, result.CriticalCount)
+	}
+	if result.SecurityScore != 100 {
+		t.Errorf("SecurityScore = %d, want 100", result.SecurityScore)
+	}
+	if len(result.Failures) != 1 || result.Failures[0].Package != "minimist" {
+		t.Errorf("Failures = %+v, want minimist reported as unresolved", result.Failures)
+	}
+}
+
+func TestQueryVersion(t *testing.T) {
+	tests := map[string]string{"^1.2.3": "", "~1.2": "", "*": "", "": "", "v1.9.1": "v1.9.1", "==2.31.0": "2.31.0", "1.0.0rc1": "1.0.0rc1"}
+	for in, want := range tests {
+		if got := queryVersion(in); got != want {
+			t.Errorf("queryVersion(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestQueryOSVBatch_PageLimit(t *testing.T) {
+	var batchCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&batchCalls, 1)
+		var req osvBatchRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		var results []map[string]interface{}
+		for _, q := range req.Queries {
+			res := map[string]interface{}{}
+			if q.Package.Name == "endless" {
+				res["vulns"] = []map[string]string{{"id": "GHSA-" + strings.Repeat("x", int(n))}}
+				res["next_page_token"] = "more"
+			}
+			results = append(results, res)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"results": results})
+	}))
+	defer server.Close()
+
+	defer func(base string) { osvAPIBase = base }(osvAPIBase)
+	osvAPIBase = server.URL
+
+	targets := []scanTarget{
+		{name: "quiet", version: "1.0.0", ecosystem: "npm"},
+		{name: "endless", version: "1.0.0", ecosystem: "npm"},
+	}
+	ids, truncated, err := queryOSVBatch(server.Client(), targets)
+	if err != nil {
+		t.Fatalf("queryOSVBatch() error = %v", err)
+	}
+	if len(ids[1]) != osvMaxPages || len(ids[0]) != 0 {
+		t.Errorf("ids = %v, want %d pages for endless", ids, osvMaxPages)
+	}
+	if len(truncated) != 1 || truncated[0] != 1 {
+		t.Errorf("truncated = %v, want [1]", truncated)
+	}
+}
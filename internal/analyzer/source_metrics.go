@@ -0,0 +1,704 @@
+// Package analyzer provides functions for analyzing GitHub repository data.
+// This file computes source-level metrics: lines of code, comment and blank
+// lines per language, function counts and cyclomatic complexity.
+package analyzer
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// SourceMetrics aggregates the metrics of the analyzed source files
+type SourceMetrics struct {
+	Source           string            `json:"source"` // "local clone" or "github api"
+	FilesAnalyzed    int               `json:"files_analyzed"`
+	Truncated        bool              `json:"truncated"` // More source files than MaxFiles
+	TotalLines       int               `json:"total_lines"`
+	CodeLines        int               `json:"code_lines"`
+	CommentLines     int               `json:"comment_lines"`
+	BlankLines       int               `json:"blank_lines"`
+	CommentRatio     float64           `json:"comment_ratio"` // comment lines / code lines
+	Functions        int               `json:"functions"`
+	AvgComplexity    float64           `json:"avg_complexity"` // average cyclomatic complexity per function
+	MaxComplexity    int               `json:"max_complexity"`
+	Languages        []LanguageLOC     `json:"languages"`
+	ComplexFiles     []FileMetrics     `json:"complex_files"`     // files with the highest total complexity
+	LargeFiles       []FileMetrics     `json:"large_files"`       // files above largeFileThreshold code lines, largest first
+	LargeFileCount   int               `json:"large_file_count"`  // all files above largeFileThreshold, including those beyond LargeFiles
+	ComplexFunctions []FunctionMetrics `json:"complex_functions"` // functions with the highest complexity
+}
+
+// LanguageLOC holds line counts for one language
+type LanguageLOC struct {
+	Language string `json:"language"`
+	Files    int    `json:"files"`
+	Code     int    `json:"code"`
+	Comments int    `json:"comments"`
+	Blank    int    `json:"blank"`
+}
+
+// FileMetrics holds the metrics of a single source file
+type FileMetrics struct {
+	Path          string `json:"path"`
+	Language      string `json:"language"`
+	Lines         int    `json:"lines"`
+	Code          int    `json:"code"`
+	Comments      int    `json:"comments"`
+	Blank         int    `json:"blank"`
+	Functions     int    `json:"functions"`
+	Complexity    int    `json:"complexity"`     // sum over the file's functions and top-level code
+	MaxComplexity int    `json:"max_complexity"` // most complex function
+}
+
+// FunctionMetrics holds the complexity of a single function
+type FunctionMetrics struct {
+	Path       string `json:"path"`
+	Name       string `json:"name"`
+	Line       int    `json:"line"`
+	Complexity int    `json:"complexity"`
+}
+
+// SourceMetricsOptions controls where sources are read from and how many
+type SourceMetricsOptions struct {
+	LocalDir    string // Read files from this checkout instead of the GitHub API
+	MaxFiles    int    // Files analyzed at most; defaults to 100 over the API and 2000 locally
+	MaxFileSize int    // Larger files are skipped; defaults to 512 KiB
+	Concurrency int    // Parallel content requests; defaults to 8
+}
+
+const (
+	maxComplexEntries = 10
+
+	// Functions above this complexity are reported as a code smell
+	highComplexityThreshold = 15
+	// Files above this many code lines are reported as a code smell
+	largeFileThreshold = 1000
+)
+
+// sourceLanguages maps source file extensions to language names
+var sourceLanguages = map[string]string{
+	".go": "Go", ".js": "JavaScript", ".jsx": "JavaScript", ".ts": "TypeScript", ".tsx": "TypeScript",
+	".py": "Python", ".java": "Java", ".rb": "Ruby", ".rs": "Rust", ".c": "C", ".h": "C",
+	".cpp": "C++", ".cs": "C#", ".php": "PHP", ".swift": "Swift", ".kt": "Kotlin",
+	".scala": "Scala", ".ex": "Elixir", ".exs": "Elixir",
+}
+
+// AnalyzeSourceMetrics reads the repository's source files, from a local
+// checkout when opts.LocalDir is set and through the GitHub API otherwise,
+// and computes line counts and complexity. Over the API the largest files
+// are analyzed first, since they dominate both size and complexity.
+func AnalyzeSourceMetrics(client *github.Client, owner, repo string, fileTree []github.TreeEntry, opts SourceMetricsOptions) *SourceMetrics {
+	source := "github api"
+	fetch := contentFetcher(client, owner, repo)
+
+	if opts.LocalDir != "" {
+		source = "local clone"
+		if opts.MaxFiles <= 0 {
+			opts.MaxFiles = 2000
+		}
+		fetch = func(p string) (string, bool) {
+			data, err := os.ReadFile(filepath.Join(opts.LocalDir, filepath.FromSlash(p)))
+			if err != nil {
+				return "", false
+			}
+			return string(data), true
+		}
+	}
+
+	metrics := computeSourceMetrics(fileTree, fetch, opts)
+	metrics.Source = source
+	return metrics
+}
+
+// computeSourceMetrics selects source files, reads them concurrently and
+// aggregates their metrics
+func computeSourceMetrics(fileTree []github.TreeEntry, fetch func(string) (string, bool), opts SourceMetricsOptions) *SourceMetrics {
+	if opts.MaxFiles <= 0 {
+		opts.MaxFiles = 100
+	}
+	if opts.MaxFileSize <= 0 {
+		opts.MaxFileSize = 512 << 10
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 8
+	}
+
+	var candidates []github.TreeEntry
+	for _, entry := range fileTree {
+		if entry.Type != "blob" || entry.Size > opts.MaxFileSize || isVendoredPath(entry.Path) || isGeneratedSource(entry.Path) {
+			continue
+		}
+		if _, ok := sourceLanguages[strings.ToLower(filepath.Ext(entry.Path))]; !ok || !isSourceFile(strings.ToLower(entry.Path)) {
+			continue
+		}
+		candidates = append(candidates, entry)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Size > candidates[j].Size })
+
+	metrics := &SourceMetrics{
+		Languages:        []LanguageLOC{},
+		ComplexFiles:     []FileMetrics{},
+		LargeFiles:       []FileMetrics{},
+		ComplexFunctions: []FunctionMetrics{},
+	}
+	if len(candidates) > opts.MaxFiles {
+		candidates = candidates[:opts.MaxFiles]
+		metrics.Truncated = true
+	}
+
+	files := make([]*FileMetrics, len(candidates))
+	funcs := make([][]FunctionMetrics, len(candidates))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, opts.Concurrency)
+	for i, entry := range candidates {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, p string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			content, ok := fetch(p)
+			if !ok || isBinaryContent(content) {
+				return
+			}
+			fm, fns := analyzeSourceFile(p, content)
+			files[i], funcs[i] = &fm, fns
+		}(i, entry.Path)
+	}
+	wg.Wait()
+
+	byLanguage := make(map[string]*LanguageLOC)
+	var allFuncs []FunctionMetrics
+	var allFiles []FileMetrics
+	totalComplexity := 0
+	for i, fm := range files {
+		if fm == nil {
+			continue
+		}
+		metrics.FilesAnalyzed++
+		metrics.TotalLines += fm.Lines
+		metrics.CodeLines += fm.Code
+		metrics.CommentLines += fm.Comments
+		metrics.BlankLines += fm.Blank
+		metrics.Functions += fm.Functions
+
+		loc := byLanguage[fm.Language]
+		if loc == nil {
+			loc = &LanguageLOC{Language: fm.Language}
+			byLanguage[fm.Language] = loc
+		}
+		loc.Files++
+		loc.Code += fm.Code
+		loc.Comments += fm.Comments
+		loc.Blank += fm.Blank
+
+		for _, fn := range funcs[i] {
+			totalComplexity += fn.Complexity
+			if fn.Complexity > metrics.MaxComplexity {
+				metrics.MaxComplexity = fn.Complexity
+			}
+		}
+		allFuncs = append(allFuncs, funcs[i]...)
+		allFiles = append(allFiles, *fm)
+	}
+
+	if metrics.CodeLines > 0 {
+		metrics.CommentRatio = float64(metrics.CommentLines) / float64(metrics.CodeLines)
+	}
+	if metrics.Functions > 0 {
+		metrics.AvgComplexity = float64(totalComplexity) / float64(metrics.Functions)
+	}
+
+	for _, loc := range byLanguage {
+		metrics.Languages = append(metrics.Languages, *loc)
+	}
+	sort.Slice(metrics.Languages, func(i, j int) bool {
+		if metrics.Languages[i].Code != metrics.Languages[j].Code {
+			return metrics.Languages[i].Code > metrics.Languages[j].Code
+		}
+		return metrics.Languages[i].Language < metrics.Languages[j].Language
+	})
+
+	for _, fm := range allFiles {
+		if fm.Code > largeFileThreshold {
+			metrics.LargeFiles = append(metrics.LargeFiles, fm)
+		}
+	}
+	metrics.LargeFileCount = len(metrics.LargeFiles)
+	sort.SliceStable(metrics.LargeFiles, func(i, j int) bool {
+		if metrics.LargeFiles[i].Code != metrics.LargeFiles[j].Code {
+			return metrics.LargeFiles[i].Code > metrics.LargeFiles[j].Code
+		}
+		return metrics.LargeFiles[i].Path < metrics.LargeFiles[j].Path
+	})
+	if len(metrics.LargeFiles) > maxComplexEntries {
+		metrics.LargeFiles = metrics.LargeFiles[:maxComplexEntries]
+	}
+
+	sort.SliceStable(allFiles, func(i, j int) bool {
+		if allFiles[i].Complexity != allFiles[j].Complexity {
+			return allFiles[i].Complexity > allFiles[j].Complexity
+		}
+		return allFiles[i].Path < allFiles[j].Path
+	})
+	if len(allFiles) > maxComplexEntries {
+		allFiles = allFiles[:maxComplexEntries]
+	}
+	metrics.ComplexFiles = append(metrics.ComplexFiles, allFiles...)
+
+	sort.SliceStable(allFuncs, func(i, j int) bool {
+		if allFuncs[i].Complexity != allFuncs[j].Complexity {
+			return allFuncs[i].Complexity > allFuncs[j].Complexity
+		}
+		if allFuncs[i].Path != allFuncs[j].Path {
+			return allFuncs[i].Path < allFuncs[j].Path
+		}
+		return allFuncs[i].Line < allFuncs[j].Line
+	})
+	if len(allFuncs) > maxComplexEntries {
+		allFuncs = allFuncs[:maxComplexEntries]
+	}
+	metrics.ComplexFunctions = append(metrics.ComplexFunctions, allFuncs...)
+
+	return metrics
+}
+
+// isGeneratedSource skips minified bundles and generated code by file name
+func isGeneratedSource(p string) bool {
+	lower := strings.ToLower(p)
+	for _, suffix := range []string{".min.js", ".pb.go", "_generated.go", ".gen.go", "_pb2.py", ".g.dart", ".designer.cs", ".bundle.js"} {
+		if strings.HasSuffix(lower, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// analyzeSourceFile counts the lines of a file and measures the complexity
+// of its functions
+func analyzeSourceFile(p, content string) (FileMetrics, []FunctionMetrics) {
+	ext := strings.ToLower(filepath.Ext(p))
+	fm := FileMetrics{Path: p, Language: sourceLanguages[ext]}
+	fm.Lines, fm.Code, fm.Comments, fm.Blank = countSourceLines(content, commentSyntaxFor(ext))
+
+	var funcs []FunctionMetrics
+	var topLevel int
+	switch ext {
+	case ".go":
+		var ok bool
+		if funcs, topLevel, ok = goComplexity(content); !ok {
+			funcs, topLevel = braceComplexity(content, commentSyntaxFor(ext), true)
+		}
+	case ".py", ".rb", ".ex", ".exs":
+		funcs, topLevel = indentComplexity(content, ext)
+	default:
+		funcs, topLevel = braceComplexity(content, commentSyntaxFor(ext), !nullableTypeLanguages[ext])
+	}
+
+	fm.Complexity = topLevel
+	for i := range funcs {
+		funcs[i].Path = p
+		fm.Complexity += funcs[i].Complexity
+		if funcs[i].Complexity > fm.MaxComplexity {
+			fm.MaxComplexity = funcs[i].Complexity
+		}
+	}
+	fm.Functions = len(funcs)
+	return fm, funcs
+}
+
+// commentSyntax describes how a language writes comments
+type commentSyntax struct {
+	line       []string // line comment markers
+	blockStart string
+	blockEnd   string
+}
+
+func commentSyntaxFor(ext string) commentSyntax {
+	switch ext {
+	case ".py":
+		return commentSyntax{line: []string{"#"}, blockStart: `"""`, blockEnd: `"""`}
+	case ".rb":
+		return commentSyntax{line: []string{"#"}, blockStart: "=begin", blockEnd: "=end"}
+	case ".ex", ".exs":
+		return commentSyntax{line: []string{"#"}}
+	case ".php":
+		return commentSyntax{line: []string{"//", "#"}, blockStart: "/*", blockEnd: "*/"}
+	}
+	return commentSyntax{line: []string{"//"}, blockStart: "/*", blockEnd: "*/"}
+}
+
+// countSourceLines classifies every line as code, comment or blank. A line
+// holding both code and a comment counts as code.
+func countSourceLines(content string, syntax commentSyntax) (lines, code, comments, blank int) {
+	if content == "" {
+		return 0, 0, 0, 0
+	}
+	inBlock := false
+	for _, line := range strings.Split(strings.TrimSuffix(content, "\n"), "\n") {
+		lines++
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case inBlock:
+			comments++
+			if idx := strings.Index(trimmed, syntax.blockEnd); idx >= 0 {
+				inBlock = false
+				if rest := strings.TrimSpace(trimmed[idx+len(syntax.blockEnd):]); rest != "" {
+					comments--
+					code++
+				}
+			}
+		case trimmed == "":
+			blank++
+		case hasAnyPrefix(trimmed, syntax.line):
+			comments++
+		case syntax.blockStart != "" && strings.HasPrefix(trimmed, syntax.blockStart):
+			rest := trimmed[len(syntax.blockStart):]
+			if idx := strings.Index(rest, syntax.blockEnd); idx >= 0 {
+				if strings.TrimSpace(rest[idx+len(syntax.blockEnd):]) != "" {
+					code++
+				} else {
+					comments++
+				}
+			} else {
+				comments++
+				inBlock = true
+			}
+		default:
+			code++
+			// A block comment opened after code continues on the next lines
+			if syntax.blockStart != "" && syntax.blockStart != syntax.blockEnd {
+				if start := strings.LastIndex(trimmed, syntax.blockStart); start >= 0 &&
+					!strings.Contains(trimmed[start:], syntax.blockEnd) && !inStringLiteral(trimmed[:start]) {
+					inBlock = true
+				}
+			}
+		}
+	}
+	return lines, code, comments, blank
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// inStringLiteral reports whether the end of s lies inside a string, by
+// counting unescaped double quotes
+func inStringLiteral(s string) bool {
+	quotes := strings.Count(s, `"`) - strings.Count(s, `\"`)
+	return quotes%2 == 1
+}
+
+// goComplexity measures each function and method with go/parser. ok is
+// false when the file does not parse.
+func goComplexity(content string) (funcs []FunctionMetrics, topLevel int, ok bool) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", content, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, 0, false
+	}
+
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			name := d.Name.Name
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				name = goReceiverName(d.Recv.List[0].Type) + "." + name
+			}
+			complexity := 1
+			if d.Body != nil {
+				complexity += goDecisionPoints(d.Body)
+			}
+			funcs = append(funcs, FunctionMetrics{Name: name, Line: fset.Position(d.Pos()).Line, Complexity: complexity})
+		default:
+			// Function literals in package-level variables
+			topLevel += goDecisionPoints(d)
+		}
+	}
+	return funcs, topLevel, true
+}
+
+func goReceiverName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return goReceiverName(t.X)
+	case *ast.Ident:
+		return t.Name
+	case *ast.IndexExpr:
+		return goReceiverName(t.X)
+	case *ast.IndexListExpr:
+		return goReceiverName(t.X)
+	}
+	return "?"
+}
+
+// goDecisionPoints counts the branches in a node: if, for, range, non-default
+// case and select clauses, and the && and || operators
+func goDecisionPoints(node ast.Node) int {
+	count := 0
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			count++
+		case *ast.CaseClause:
+			if n.List != nil {
+				count++
+			}
+		case *ast.CommClause:
+			if n.Comm != nil {
+				count++
+			}
+		case *ast.BinaryExpr:
+			if n.Op == token.LAND || n.Op == token.LOR {
+				count++
+			}
+		}
+		return true
+	})
+	return count
+}
+
+// braceDecisionKeywords are the branching keywords of C-like languages
+var braceDecisionKeywords = map[string]bool{
+	"if": true, "for": true, "foreach": true, "while": true, "case": true, "catch": true,
+	"when": true, "guard": true, "elif": true,
+}
+
+// braceControlKeywords precede parenthesized conditions rather than
+// function parameters
+var braceControlKeywords = map[string]bool{
+	"if": true, "for": true, "foreach": true, "while": true, "switch": true, "catch": true,
+	"when": true, "synchronized": true, "using": true, "lock": true, "return": true, "match": true,
+	"fixed": true, "sizeof": true, "typeof": true, "with": true,
+}
+
+// nullableTypeLanguages write "?" after nullable types, so it can't be
+// counted as a ternary operator
+var nullableTypeLanguages = map[string]bool{".kt": true, ".swift": true, ".cs": true, ".ts": true, ".tsx": true}
+
+var codeTokenPattern = regexp.MustCompile(`[A-Za-z_$][A-Za-z0-9_$]*|&&|\|\||\?\?|\?\.|=>|->|[{}()\[\];?=:]`)
+
+// braceComplexity estimates complexity from tokens for C-like languages.
+// A "{" opens a function body when it follows a parameter list whose name
+// is not a control keyword, or an arrow; branches are attributed to the
+// innermost open function. The "?" of ternaries is only counted when
+// countTernary is set.
+func braceComplexity(content string, syntax commentSyntax, countTernary bool) (funcs []FunctionMetrics, topLevel int) {
+	code := stripCommentsAndStrings(content, syntax)
+
+	type openFunc struct {
+		index int // into funcs
+		depth int // brace depth of the body
+	}
+	var stack []openFunc
+	var tokens []string
+	depth := 0
+
+	for lineNo, line := range strings.Split(code, "\n") {
+		for _, tok := range codeTokenPattern.FindAllString(line, -1) {
+			switch {
+			case tok == "{":
+				depth++
+				if name, ok := functionHeader(tokens); ok {
+					funcs = append(funcs, FunctionMetrics{Name: name, Line: lineNo + 1, Complexity: 1})
+					stack = append(stack, openFunc{index: len(funcs) - 1, depth: depth})
+				}
+			case tok == "}":
+				if len(stack) > 0 && stack[len(stack)-1].depth == depth {
+					stack = stack[:len(stack)-1]
+				}
+				depth--
+			case braceDecisionKeywords[tok] || tok == "&&" || tok == "||" || tok == "?" && countTernary:
+				if len(stack) > 0 {
+					funcs[stack[len(stack)-1].index].Complexity++
+				} else {
+					topLevel++
+				}
+			}
+			tokens = append(tokens, tok)
+			if len(tokens) > 64 {
+				tokens = tokens[len(tokens)-32:]
+			}
+		}
+	}
+	return funcs, topLevel
+}
+
+// functionHeader inspects the tokens before a "{" and returns the function
+// name when they form a function signature
+func functionHeader(tokens []string) (string, bool) {
+	if len(tokens) == 0 {
+		return "", false
+	}
+	if last := tokens[len(tokens)-1]; last == "=>" {
+		return "<anonymous>", true
+	}
+
+	// Skip a return type or throws clause after the parameter list
+	i := len(tokens) - 1
+	for steps := 0; i >= 0 && tokens[i] != ")" && steps < 8; i, steps = i-1, steps+1 {
+		switch tokens[i] {
+		case ";", "{", "}", "=", "(", "=>":
+			return "", false
+		}
+	}
+	if i < 0 || tokens[i] != ")" {
+		return "", false
+	}
+
+	// Find the matching "("
+	nesting := 0
+	for ; i >= 0; i-- {
+		if tokens[i] == ")" {
+			nesting++
+		} else if tokens[i] == "(" {
+			nesting--
+			if nesting == 0 {
+				break
+			}
+		}
+	}
+	if i <= 0 {
+		return "", false
+	}
+
+	name := tokens[i-1]
+	if braceControlKeywords[name] || !isIdentifierToken(name) {
+		return "", false
+	}
+	if name == "function" || name == "func" {
+		return "<anonymous>", true
+	}
+	return name, true
+}
+
+func isIdentifierToken(tok string) bool {
+	if tok == "" {
+		return false
+	}
+	c := tok[0]
+	return c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// stripCommentsAndStrings blanks out comments and string literals, keeping
+// newlines so token positions map to the original lines
+func stripCommentsAndStrings(content string, syntax commentSyntax) string {
+	var b strings.Builder
+	b.Grow(len(content))
+	for i := 0; i < len(content); {
+		c := content[i]
+		switch {
+		case syntax.blockStart != "" && strings.HasPrefix(content[i:], syntax.blockStart):
+			end := strings.Index(content[i+len(syntax.blockStart):], syntax.blockEnd)
+			if end < 0 {
+				end = len(content) - i
+			} else {
+				end += len(syntax.blockStart) + len(syntax.blockEnd)
+			}
+			blankOut(&b, content[i:i+end])
+			i += end
+		case hasAnyPrefix(content[i:], syntax.line):
+			end := strings.IndexByte(content[i:], '\n')
+			if end < 0 {
+				end = len(content) - i
+			}
+			blankOut(&b, content[i:i+end])
+			i += end
+		case c == '"' || c == '\'' || c == '`':
+			end := i + 1
+			for end < len(content) && content[end] != c {
+				if content[end] == '\\' {
+					end++
+				} else if content[end] == '\n' && c != '`' {
+					break
+				}
+				end++
+			}
+			if end >= len(content) || content[end] != c {
+				// Unterminated on this line: a Rust lifetime or a stray quote
+				b.WriteByte(' ')
+				i++
+				continue
+			}
+			end++
+			blankOut(&b, content[i:end])
+			i = end
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String()
+}
+
+func blankOut(b *strings.Builder, s string) {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\n' {
+			b.WriteByte('\n')
+		} else {
+			b.WriteByte(' ')
+		}
+	}
+}
+
+var (
+	indentFuncPattern     = regexp.MustCompile(`^\s*(async\s+)?(def|defp|defmacro)\s+([A-Za-z_][A-Za-z0-9_.!?]*)`)
+	indentDecisionPattern = regexp.MustCompile(`\b(if|elif|elsif|unless|for|while|until|except|rescue|when|and|or)\b|&&|\|\|`)
+)
+
+// indentComplexity estimates complexity for Python, Ruby and Elixir. A
+// function spans the lines indented deeper than its def; branches are
+// attributed to the innermost enclosing function.
+func indentComplexity(content, ext string) (funcs []FunctionMetrics, topLevel int) {
+	code := stripCommentsAndStrings(content, commentSyntaxFor(ext))
+
+	type openFunc struct {
+		index  int
+		indent int
+	}
+	var stack []openFunc
+
+	for lineNo, line := range strings.Split(code, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		for len(stack) > 0 && indent <= stack[len(stack)-1].indent && !isBlockEnd(line) {
+			stack = stack[:len(stack)-1]
+		}
+
+		if m := indentFuncPattern.FindStringSubmatch(line); m != nil {
+			funcs = append(funcs, FunctionMetrics{Name: m[3], Line: lineNo + 1, Complexity: 1})
+			stack = append(stack, openFunc{index: len(funcs) - 1, indent: indent})
+			continue
+		}
+
+		n := len(indentDecisionPattern.FindAllString(line, -1))
+		if len(stack) > 0 {
+			funcs[stack[len(stack)-1].index].Complexity += n
+		} else {
+			topLevel += n
+		}
+	}
+	return funcs, topLevel
+}
+
+// isBlockEnd reports a Ruby or Elixir "end", which sits at its def's indent
+func isBlockEnd(line string) bool {
+	return strings.TrimSpace(line) == "end"
+}
@@ -0,0 +1,229 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+const sampleGoSource = `// Package sample is used by the tests.
+package sample
+
+/*
+Block comment
+*/
+
+type T struct{}
+
+func simple() int {
+	return 1
+}
+
+// Classify has complexity 7: if, &&, range, two cases, ||
+func (t *T) Classify(xs []int) string {
+	if len(xs) == 0 && t != nil {
+		return "empty"
+	}
+	for _, x := range xs {
+		switch x {
+		case 1:
+			return "one"
+		case 2:
+			return "two"
+		default:
+		}
+		_ = x > 0 || x < -10
+	}
+	return "many"
+}
+`
+
+func TestAnalyzeSourceFile_Go(t *testing.T) {
+	fm, funcs := analyzeSourceFile("pkg/sample.go", sampleGoSource)
+
+	if fm.Language != "Go" || fm.Lines != 30 {
+		t.Errorf("Language = %q, Lines = %d; want Go, 30", fm.Language, fm.Lines)
+	}
+	if fm.Comments != 5 || fm.Blank != 4 || fm.Code != 21 {
+		t.Errorf("code/comments/blank = %d/%d/%d, want 21/5/4", fm.Code, fm.Comments, fm.Blank)
+	}
+	if len(funcs) != 2 {
+		t.Fatalf("len(funcs) = %d, want 2", len(funcs))
+	}
+	if funcs[0].Name != "simple" || funcs[0].Complexity != 1 {
+		t.Errorf("funcs[0] = %+v, want simple with complexity 1", funcs[0])
+	}
+	if funcs[1].Name != "T.Classify" || funcs[1].Complexity != 7 || funcs[1].Line != 15 {
+		t.Errorf("funcs[1] = %+v, want T.Classify at line 15 with complexity 7", funcs[1])
+	}
+	if fm.Complexity != 8 || fm.MaxComplexity != 7 {
+		t.Errorf("Complexity = %d, MaxComplexity = %d; want 8, 7", fm.Complexity, fm.MaxComplexity)
+	}
+}
+
+func TestAnalyzeSourceFile_CLike(t *testing.T) {
+	src := strings.Join([]string{
+		"// Utilities",
+		"function parse(input) {",
+		"  if (input === '{' || input === '') {", // braces in strings are ignored
+		"    return null;",
+		"  }",
+		"  const ok = input ? true : false;",
+		"  items.forEach((item) => {",
+		"    while (item.next) { item = item.next; }",
+		"  });",
+		"  return ok;",
+		"}",
+		"",
+		"class Parser {",
+		"  run() {",
+		"    try { this.go(); } catch (e) { /* ignored */ }",
+		"  }",
+		"}",
+	}, "\n")
+
+	fm, funcs := analyzeSourceFile("src/parse.js", src)
+	if fm.Code != 15 || fm.Comments != 1 || fm.Blank != 1 {
+		t.Errorf("code/comments/blank = %d/%d/%d, want 15/1/1", fm.Code, fm.Comments, fm.Blank)
+	}
+
+	got := make(map[string]int)
+	for _, fn := range funcs {
+		got[fn.Name] = fn.Complexity
+	}
+	// parse: if, ||, ?; the arrow function: while; run: catch
+	want := map[string]int{"parse": 4, "<anonymous>": 2, "run": 2}
+	for name, complexity := range want {
+		if got[name] != complexity {
+			t.Errorf("complexity of %s = %d, want %d (all: %v)", name, got[name], complexity, got)
+		}
+	}
+	if len(funcs) != 3 {
+		t.Errorf("len(funcs) = %d, want 3: %+v", len(funcs), funcs)
+	}
+}
+
+func TestAnalyzeSourceFile_Python(t *testing.T) {
+	src := strings.Join([]string{
+		`"""Module docstring."""`,
+		"import os",
+		"",
+		"def load(path):",
+		"    # read the file",
+		"    if not path or not os.path.exists(path):",
+		"        return None",
+		"    for line in open(path):",
+		"        pass",
+		"",
+		"class Config:",
+		"    def get(self, key):",
+		"        try:",
+		"            return self.data[key]",
+		"        except KeyError:",
+		"            return None",
+	}, "\n")
+
+	fm, funcs := analyzeSourceFile("config.py", src)
+	if fm.Comments != 2 || fm.Blank != 2 || fm.Code != 12 {
+		t.Errorf("code/comments/blank = %d/%d/%d, want 12/2/2", fm.Code, fm.Comments, fm.Blank)
+	}
+	if len(funcs) != 2 || funcs[0].Name != "load" || funcs[0].Complexity != 4 || funcs[1].Name != "get" || funcs[1].Complexity != 2 {
+		t.Errorf("funcs = %+v, want load (4) and get (2)", funcs)
+	}
+}
+
+func TestComputeSourceMetrics(t *testing.T) {
+	files := map[string]string{
+		"pkg/sample.go":      sampleGoSource,
+		"pkg/sample_test.go": "package sample\n",
+		"web/app.ts":         "export function f(a: string) {\n  return a && a.length;\n}\n",
+		"vendor/x/x.go":      "package x\n",
+		"api/types.pb.go":    "package api\n",
+		"README.md":          "# Sample\n",
+	}
+	var tree []github.TreeEntry
+	for p, content := range files {
+		tree = append(tree, github.TreeEntry{Path: p, Type: "blob", Size: len(content)})
+	}
+	fetch := func(p string) (string, bool) {
+		content, ok := files[p]
+		return content, ok
+	}
+
+	metrics := computeSourceMetrics(tree, fetch, SourceMetricsOptions{})
+
+	if metrics.FilesAnalyzed != 2 {
+		t.Errorf("FilesAnalyzed = %d, want 2 (tests, vendored, generated and docs skipped)", metrics.FilesAnalyzed)
+	}
+	if metrics.Functions != 3 || metrics.MaxComplexity != 7 {
+		t.Errorf("Functions = %d, MaxComplexity = %d; want 3, 7", metrics.Functions, metrics.MaxComplexity)
+	}
+	if len(metrics.Languages) != 2 || metrics.Languages[0].Language != "Go" {
+		t.Errorf("Languages = %+v, want Go first", metrics.Languages)
+	}
+	if metrics.ComplexFunctions[0].Name != "T.Classify" || metrics.ComplexFunctions[0].Path != "pkg/sample.go" {
+		t.Errorf("most complex function = %+v", metrics.ComplexFunctions[0])
+	}
+
+	if len(metrics.LargeFiles) != 0 {
+		t.Errorf("LargeFiles = %+v, want none", metrics.LargeFiles)
+	}
+
+	limited := computeSourceMetrics(tree, fetch, SourceMetricsOptions{MaxFiles: 1})
+	if !limited.Truncated || limited.FilesAnalyzed != 1 || limited.ComplexFiles[0].Path != "pkg/sample.go" {
+		t.Errorf("with MaxFiles 1: Truncated = %v, files = %+v; want the largest file only", limited.Truncated, limited.ComplexFiles)
+	}
+
+	// A long file of simple code is large without being complex
+	files["pkg/table.go"] = "package sample\n\nvar table = []int{\n" + strings.Repeat("\t1,\n", largeFileThreshold) + "}\n"
+	tree = append(tree, github.TreeEntry{Path: "pkg/table.go", Type: "blob", Size: len(files["pkg/table.go"])})
+	withTable := computeSourceMetrics(tree, fetch, SourceMetricsOptions{})
+	if len(withTable.LargeFiles) != 1 || withTable.LargeFiles[0].Path != "pkg/table.go" {
+		t.Errorf("LargeFiles = %+v, want pkg/table.go", withTable.LargeFiles)
+	}
+	if withTable.LargeFileCount != 1 {
+		t.Errorf("LargeFileCount = %d, want 1", withTable.LargeFileCount)
+	}
+}
+
+func TestAnalyzeCodeQualityWithSource(t *testing.T) {
+	fileTree := []github.TreeEntry{
+		{Path: "README.md", Type: "blob", Size: 100},
+		{Path: "main.go", Type: "blob", Size: 4000},
+		{Path: "util.go", Type: "blob", Size: 9000},
+	}
+	simple := &SourceMetrics{Functions: 10, AvgComplexity: 2, MaxComplexity: 4, CommentRatio: 0.2}
+	complicated := &SourceMetrics{
+		Functions: 10, AvgComplexity: 12, MaxComplexity: 40,
+		ComplexFunctions: []FunctionMetrics{{Path: "util.go", Name: "process", Line: 10, Complexity: 40}},
+		ComplexFiles:     []FileMetrics{{Path: "util.go", Code: 1500}},
+		LargeFiles:       []FileMetrics{{Path: "util.go", Code: 1500}},
+		LargeFileCount:   14,
+	}
+
+	base := AnalyzeCodeQuality(nil, fileTree, nil)
+	good := AnalyzeCodeQualityWithSource(nil, fileTree, nil, simple)
+	bad := AnalyzeCodeQualityWithSource(nil, fileTree, nil, complicated)
+
+	if good.StructureScore <= base.StructureScore || bad.StructureScore >= base.StructureScore {
+		t.Errorf("StructureScore: simple %d, none %d, complex %d; want simple > none > complex",
+			good.StructureScore, base.StructureScore, bad.StructureScore)
+	}
+	if strings.Join(base.FileStats.LargestFiles, ",") != "util.go,main.go" {
+		t.Errorf("LargestFiles = %v, want util.go, main.go", base.FileStats.LargestFiles)
+	}
+
+	smells := make(map[string]CodeSmell)
+	for _, smell := range bad.CodeSmells {
+		smells[smell.Type] = smell
+	}
+	if smell := smells["Complex Functions"]; smell.Severity != "High" || smell.Location != "util.go:10" {
+		t.Errorf("Complex Functions smell = %+v", smell)
+	}
+	if smell, ok := smells["Large File"]; !ok {
+		t.Error("missing Large File smell")
+	} else if !strings.Contains(smell.Description, "14 files exceed") {
+		t.Errorf("Large File smell = %q, want the uncapped count of 14 files", smell.Description)
+	}
+}
@@ -0,0 +1,359 @@
+// Package analyzer provides license detection and compliance analysis.
+// This file parses SPDX license expressions and SPDX-License-Identifier headers.
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SPDXExpression is a parsed SPDX license expression.
+// Leaf nodes name a single license; compound nodes join their operands with
+// AND or OR.
+//
+// Example: "(MIT OR Apache-2.0) AND GPL-2.0-or-later WITH Classpath-exception-2.0"
+type SPDXExpression struct {
+	Op        string            `json:"op,omitempty"`        // "AND", "OR", or empty for a single license
+	License   string            `json:"license,omitempty"`   // SPDX identifier or LicenseRef
+	OrLater   bool              `json:"or_later,omitempty"`  // "+" suffix
+	Exception string            `json:"exception,omitempty"` // Exception named with WITH
+	Operands  []*SPDXExpression `json:"operands,omitempty"`
+}
+
+// spdxLicenseIDs lists the SPDX identifiers recognized regardless of case.
+// Unknown identifiers are kept as written.
+var spdxLicenseIDs = []string{
+	"0BSD", "AFL-3.0", "AGPL-1.0-only", "AGPL-1.0-or-later", "AGPL-3.0-only", "AGPL-3.0-or-later",
+	"Apache-1.0", "Apache-1.1", "Apache-2.0", "Artistic-1.0", "Artistic-2.0", "BlueOak-1.0.0",
+	"BSD-1-Clause", "BSD-2-Clause", "BSD-2-Clause-Patent", "BSD-3-Clause", "BSD-3-Clause-Clear",
+	"BSD-4-Clause", "BSL-1.0", "CC-BY-4.0", "CC-BY-SA-4.0", "CC0-1.0", "CDDL-1.0", "CDDL-1.1",
+	"ECL-2.0", "EPL-1.0", "EPL-2.0", "EUPL-1.1", "EUPL-1.2", "GPL-1.0-only", "GPL-1.0-or-later",
+	"GPL-2.0-only", "GPL-2.0-or-later", "GPL-3.0-only", "GPL-3.0-or-later", "ISC",
+	"LGPL-2.0-only", "LGPL-2.0-or-later", "LGPL-2.1-only", "LGPL-2.1-or-later",
+	"LGPL-3.0-only", "LGPL-3.0-or-later", "MIT", "MIT-0", "MPL-1.1", "MPL-2.0",
+	"MPL-2.0-no-copyleft-exception", "MS-PL", "MS-RL", "MulanPSL-2.0", "NCSA", "OFL-1.1",
+	"OpenSSL", "PostgreSQL", "PSF-2.0", "Python-2.0", "Ruby", "Unicode-3.0", "Unicode-DFS-2016",
+	"Unlicense", "UPL-1.0", "Vim", "W3C", "WTFPL", "X11", "Zlib", "ZPL-2.1",
+}
+
+// spdxExceptionIDs lists the license exceptions recognized regardless of case
+var spdxExceptionIDs = []string{
+	"Autoconf-exception-3.0", "Bison-exception-2.2", "Classpath-exception-2.0",
+	"Font-exception-2.0", "GCC-exception-3.1", "LLVM-exception", "OCaml-LGPL-linking-exception",
+	"Qt-GPL-exception-1.0", "Qt-LGPL-exception-1.1", "Universal-FOSS-exception-1.0",
+}
+
+// spdxDeprecatedGNU lists GNU identifiers deprecated in favour of explicit
+// -only and -or-later suffixes
+var spdxDeprecatedGNU = []string{
+	"AGPL-1.0", "AGPL-3.0", "GPL-1.0", "GPL-2.0", "GPL-3.0", "LGPL-2.0", "LGPL-2.1", "LGPL-3.0",
+}
+
+var (
+	spdxLicenseLookup   = lowerLookup(spdxLicenseIDs)
+	spdxExceptionLookup = lowerLookup(spdxExceptionIDs)
+	spdxDeprecatedGNUs  = lowerLookup(spdxDeprecatedGNU)
+)
+
+func lowerLookup(ids []string) map[string]string {
+	m := make(map[string]string, len(ids))
+	for _, id := range ids {
+		m[strings.ToLower(id)] = id
+	}
+	return m
+}
+
+// ParseSPDXExpression parses an SPDX license expression such as
+// "MIT OR Apache-2.0". Operators are case-insensitive, WITH binds tighter
+// than AND, and AND binds tighter than OR. Known identifiers are returned in
+// their canonical case, and deprecated GNU identifiers are rewritten to their
+// -only or -or-later form ("GPL-2.0+" becomes "GPL-2.0-or-later").
+func ParseSPDXExpression(expr string) (*SPDXExpression, error) {
+	p := &spdxParser{tokens: tokenizeSPDX(expr)}
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("empty SPDX expression")
+	}
+
+	result, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, fmt.Errorf("unexpected %q in SPDX expression %q", p.peek(), expr)
+	}
+	return result, nil
+}
+
+// tokenizeSPDX splits an expression into identifiers, operators and parentheses
+func tokenizeSPDX(expr string) []string {
+	var tokens []string
+	var current strings.Builder
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, r := range expr {
+		switch {
+		case r == '(' || r == ')':
+			flush()
+			tokens = append(tokens, string(r))
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+	return tokens
+}
+
+type spdxParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *spdxParser) done() bool { return p.pos >= len(p.tokens) }
+
+func (p *spdxParser) peek() string {
+	if p.done() {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+// acceptOperator consumes the next token if it is the given operator
+func (p *spdxParser) acceptOperator(op string) bool {
+	if strings.EqualFold(p.peek(), op) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *spdxParser) parseOr() (*SPDXExpression, error) {
+	return p.parseBinary("OR", p.parseAnd)
+}
+
+func (p *spdxParser) parseAnd() (*SPDXExpression, error) {
+	return p.parseBinary("AND", p.parseTerm)
+}
+
+// parseBinary parses a chain of operands joined by op, flattening
+// "A OR B OR C" into a single node with three operands
+func (p *spdxParser) parseBinary(op string, operand func() (*SPDXExpression, error)) (*SPDXExpression, error) {
+	first, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	node := &SPDXExpression{Op: op, Operands: []*SPDXExpression{first}}
+	for p.acceptOperator(op) {
+		next, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		if next.Op == op {
+			node.Operands = append(node.Operands, next.Operands...)
+		} else {
+			node.Operands = append(node.Operands, next)
+		}
+	}
+
+	if len(node.Operands) == 1 {
+		return first, nil
+	}
+	return node, nil
+}
+
+func (p *spdxParser) parseTerm() (*SPDXExpression, error) {
+	token := p.peek()
+	switch {
+	case token == "":
+		return nil, fmt.Errorf("SPDX expression ends unexpectedly")
+	case token == "(":
+		p.pos++
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis in SPDX expression")
+		}
+		p.pos++
+		return inner, nil
+	case token == ")" || isSPDXOperator(token):
+		return nil, fmt.Errorf("unexpected %q in SPDX expression", token)
+	}
+	p.pos++
+
+	leaf, err := spdxLicense(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.acceptOperator("WITH") {
+		exception := p.peek()
+		if exception == "" || exception == "(" || exception == ")" || isSPDXOperator(exception) {
+			return nil, fmt.Errorf("missing exception after WITH in SPDX expression")
+		}
+		p.pos++
+		if canonical, ok := spdxExceptionLookup[strings.ToLower(exception)]; ok {
+			exception = canonical
+		}
+		leaf.Exception = exception
+	}
+	return leaf, nil
+}
+
+func isSPDXOperator(token string) bool {
+	return strings.EqualFold(token, "AND") || strings.EqualFold(token, "OR") || strings.EqualFold(token, "WITH")
+}
+
+// spdxLicense builds a leaf node from a license token, canonicalizing its ID
+func spdxLicense(token string) (*SPDXExpression, error) {
+	leaf := &SPDXExpression{}
+	if strings.HasSuffix(token, "+") {
+		leaf.OrLater = true
+		token = strings.TrimSuffix(token, "+")
+	}
+	if !isSPDXIDString(token) {
+		return nil, fmt.Errorf("invalid license identifier %q in SPDX expression", token)
+	}
+
+	lower := strings.ToLower(token)
+	switch {
+	case strings.HasPrefix(lower, "licenseref-") || strings.HasPrefix(lower, "documentref-"):
+		// User-defined references are kept verbatim
+	case spdxDeprecatedGNUs[lower] != "":
+		suffix := "-only"
+		if leaf.OrLater {
+			suffix = "-or-later"
+			leaf.OrLater = false
+		}
+		token = spdxDeprecatedGNUs[lower] + suffix
+	case spdxLicenseLookup[lower] != "":
+		token = spdxLicenseLookup[lower]
+	}
+	leaf.License = token
+	return leaf, nil
+}
+
+// isSPDXIDString reports whether s only contains the characters allowed in
+// SPDX identifiers. A colon is allowed for DocumentRef-x:LicenseRef-y.
+func isSPDXIDString(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-' || r == '.' || r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// String renders the expression in canonical form
+func (e *SPDXExpression) String() string {
+	if e == nil {
+		return ""
+	}
+	if e.Op == "" {
+		s := e.License
+		if e.OrLater {
+			s += "+"
+		}
+		if e.Exception != "" {
+			s += " WITH " + e.Exception
+		}
+		return s
+	}
+
+	parts := make([]string, len(e.Operands))
+	for i, operand := range e.Operands {
+		parts[i] = operand.String()
+		if operand.Op != "" && operand.Op != e.Op {
+			parts[i] = "(" + parts[i] + ")"
+		}
+	}
+	return strings.Join(parts, " "+e.Op+" ")
+}
+
+// Licenses returns the distinct license identifiers in the expression, in
+// the order they appear
+func (e *SPDXExpression) Licenses() []string {
+	var ids []string
+	var walk func(*SPDXExpression)
+	walk = func(node *SPDXExpression) {
+		if node == nil {
+			return
+		}
+		if node.Op == "" {
+			if !contains(ids, node.License) {
+				ids = append(ids, node.License)
+			}
+			return
+		}
+		for _, operand := range node.Operands {
+			walk(operand)
+		}
+	}
+	walk(e)
+	return ids
+}
+
+// Permits reports whether the expression can be satisfied by using the given
+// license alone: it names the license directly, or offers it as one side of
+// an OR.
+func (e *SPDXExpression) Permits(id string) bool {
+	if e == nil {
+		return false
+	}
+	switch e.Op {
+	case "":
+		return strings.EqualFold(e.License, id)
+	case "OR":
+		for _, operand := range e.Operands {
+			if operand.Permits(id) {
+				return true
+			}
+		}
+	case "AND":
+		for _, operand := range e.Operands {
+			if !operand.Permits(id) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// extractSPDXHeader returns the license expression from an
+// "SPDX-License-Identifier:" comment near the top of a source file
+func extractSPDXHeader(content string) string {
+	const marker = "spdx-license-identifier:"
+	lines := strings.SplitN(content, "\n", 31)
+	if len(lines) > 30 {
+		lines = lines[:30]
+	}
+
+	for _, line := range lines {
+		idx := strings.Index(strings.ToLower(line), marker)
+		if idx < 0 {
+			continue
+		}
+		expr := strings.TrimSpace(line[idx+len(marker):])
+		// Drop block comment terminators that share the line
+		for _, closer := range []string{"*/", "-->", "*)", "#}", "--}}"} {
+			expr = strings.TrimSpace(strings.TrimSuffix(expr, closer))
+		}
+		return expr
+	}
+	return ""
+}
@@ -0,0 +1,108 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSPDXExpression(t *testing.T) {
+	tests := []struct {
+		expr     string
+		want     string
+		licenses []string
+	}{
+		{"MIT", "MIT", []string{"MIT"}},
+		{"mit or apache-2.0", "MIT OR Apache-2.0", []string{"MIT", "Apache-2.0"}},
+		{"GPL-2.0+", "GPL-2.0-or-later", []string{"GPL-2.0-or-later"}},
+		{"GPL-3.0", "GPL-3.0-only", []string{"GPL-3.0-only"}},
+		{"Apache-2.0+", "Apache-2.0+", []string{"Apache-2.0"}},
+		{"MIT AND (LGPL-2.1-or-later OR BSD-3-Clause)", "MIT AND (LGPL-2.1-or-later OR BSD-3-Clause)", []string{"MIT", "LGPL-2.1-or-later", "BSD-3-Clause"}},
+		{"MIT OR Apache-2.0 AND BSD-2-Clause", "MIT OR (Apache-2.0 AND BSD-2-Clause)", []string{"MIT", "Apache-2.0", "BSD-2-Clause"}},
+		{"(MIT OR (ISC OR 0BSD))", "MIT OR ISC OR 0BSD", []string{"MIT", "ISC", "0BSD"}},
+		{"GPL-2.0-only with classpath-exception-2.0", "GPL-2.0-only WITH Classpath-exception-2.0", []string{"GPL-2.0-only"}},
+		{"LicenseRef-Proprietary", "LicenseRef-Proprietary", []string{"LicenseRef-Proprietary"}},
+		{"DocumentRef-spdx-tool:LicenseRef-MIT-Style", "DocumentRef-spdx-tool:LicenseRef-MIT-Style", []string{"DocumentRef-spdx-tool:LicenseRef-MIT-Style"}},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSPDXExpression(tt.expr)
+		if err != nil {
+			t.Errorf("ParseSPDXExpression(%q) error = %v", tt.expr, err)
+			continue
+		}
+		if got.String() != tt.want {
+			t.Errorf("ParseSPDXExpression(%q) = %q, want %q", tt.expr, got.String(), tt.want)
+		}
+		if !reflect.DeepEqual(got.Licenses(), tt.licenses) {
+			t.Errorf("ParseSPDXExpression(%q).Licenses() = %v, want %v", tt.expr, got.Licenses(), tt.licenses)
+		}
+	}
+}
+
+func TestParseSPDXExpression_Precedence(t *testing.T) {
+	got, err := ParseSPDXExpression("MIT OR Apache-2.0 AND BSD-2-Clause")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Op != "OR" || len(got.Operands) != 2 || got.Operands[1].Op != "AND" {
+		t.Errorf("AND should bind tighter than OR, got %+v", got)
+	}
+}
+
+func TestParseSPDXExpression_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"MIT OR",
+		"(MIT",
+		"MIT)",
+		"AND MIT",
+		"MIT Apache-2.0",
+		"GPL-2.0 WITH",
+		"MIT/Apache-2.0",
+		"Apache License 2.0",
+	} {
+		if _, err := ParseSPDXExpression(expr); err == nil {
+			t.Errorf("ParseSPDXExpression(%q) expected an error", expr)
+		}
+	}
+}
+
+func TestSPDXExpressionPermits(t *testing.T) {
+	tests := []struct {
+		expr string
+		id   string
+		want bool
+	}{
+		{"MIT OR Apache-2.0", "Apache-2.0", true},
+		{"MIT AND Apache-2.0", "MIT", false},
+		{"GPL-3.0-or-later", "GPL-3.0-only", false},
+		{"(MIT OR ISC) AND MIT", "MIT", true},
+	}
+	for _, tt := range tests {
+		parsed, err := ParseSPDXExpression(tt.expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := parsed.Permits(tt.id); got != tt.want {
+			t.Errorf("%q.Permits(%q) = %v, want %v", tt.expr, tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestExtractSPDXHeader(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{"// SPDX-License-Identifier: Apache-2.0\npackage main\n", "Apache-2.0"},
+		{"#!/usr/bin/env python3\n# SPDX-License-Identifier: MIT OR Apache-2.0\n", "MIT OR Apache-2.0"},
+		{"/* SPDX-License-Identifier: GPL-2.0-only WITH Linux-syscall-note */\n", "GPL-2.0-only WITH Linux-syscall-note"},
+		{"<!-- spdx-license-identifier: CC0-1.0 -->\n", "CC0-1.0"},
+		{"package main\n\nfunc main() {}\n", ""},
+	}
+	for _, tt := range tests {
+		if got := extractSPDXHeader(tt.content); got != tt.want {
+			t.Errorf("extractSPDXHeader(%q) = %q, want %q", tt.content, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package analyzer
+
+import (
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// TestMapping pairs source files with their tests using each language's
+// naming conventions. Coverage here means "has a test file by convention",
+// not line coverage.
+type TestMapping struct {
+	SourceFiles      int                     `json:"source_files"`
+	TestFiles        int                     `json:"test_files"`
+	TestedSources    int                     `json:"tested_sources"`
+	Coverage         float64                 `json:"coverage"`          // tested sources / source files, 0-1
+	Directories      []DirectoryTestCoverage `json:"directories"`       // every directory with source files, largest first
+	UntestedPackages []DirectoryTestCoverage `json:"untested_packages"` // largest directories without any tested file
+	OrphanedTests    []string                `json:"orphaned_tests"`    // tests whose subject matches no source file
+}
+
+// DirectoryTestCoverage is the convention-based test coverage of one directory
+type DirectoryTestCoverage struct {
+	Dir         string  `json:"dir"`
+	SourceFiles int     `json:"source_files"`
+	TestedFiles int     `json:"tested_files"`
+	Coverage    float64 `json:"coverage"`
+}
+
+// maxUntestedPackages caps the untested package list
+const maxUntestedPackages = 10
+
+// testFamilies groups extensions whose tests and sources may be written in
+// different files of the same language family, such as x.ts and x.spec.js
+var testFamilies = map[string]string{
+	".go":    "go",
+	".js":    "js",
+	".jsx":   "js",
+	".ts":    "js",
+	".tsx":   "js",
+	".mjs":   "js",
+	".cjs":   "js",
+	".py":    "py",
+	".rb":    "rb",
+	".java":  "jvm",
+	".kt":    "jvm",
+	".scala": "jvm",
+	".rs":    "rs",
+	".cs":    "cs",
+	".php":   "php",
+	".swift": "swift",
+	".ex":    "ex",
+	".exs":   "ex",
+	".c":     "c",
+	".cpp":   "c",
+	".cc":    "c",
+	".h":     "c",
+}
+
+// testNameSuffixes and testNamePrefixes mark a file as a test of the file
+// named by the rest of its base name, e.g. foo_test.go, x.spec.ts,
+// test_x.py, FooTests.cs
+var (
+	testNameSuffixes = []string{"_test", "_tests", ".test", ".spec", "_spec", "-test", "-spec", "Tests", "Test", "Spec"}
+	testNamePrefixes = []string{"test_"}
+)
+
+// neutralTestDirs are path segments that don't say anything about which
+// package a file belongs to, so they are ignored when matching a test in
+// tests/foo/ to a source in src/foo/
+var neutralTestDirs = map[string]bool{
+	"src": true, "lib": true, "main": true, "app": true, "test": true, "tests": true,
+	"spec": true, "__tests__": true, "java": true, "kotlin": true, "scala": true,
+}
+
+// MapTestsToSources maps every test file in the tree to the source file it
+// tests and reports coverage by convention per directory, the largest
+// untested packages and tests that match no source file
+func MapTestsToSources(fileTree []github.TreeEntry) *TestMapping {
+	mapping := &TestMapping{
+		Directories:      []DirectoryTestCoverage{},
+		UntestedPackages: []DirectoryTestCoverage{},
+		OrphanedTests:    []string{},
+	}
+
+	sources := make(map[string][]string) // family:name -> source paths
+	var sourcePaths []string
+	type testFile struct{ path, key string }
+	var tests []testFile
+
+	for _, entry := range fileTree {
+		if entry.Type != "blob" || isVendoredPath(entry.Path) || isGeneratedSource(entry.Path) {
+			continue
+		}
+		ext := strings.ToLower(path.Ext(entry.Path))
+		family, ok := testFamilies[ext]
+		if !ok {
+			continue
+		}
+		lowerPath := strings.ToLower(entry.Path)
+
+		// The naming conventions decide first, since isTestFile doesn't
+		// know test_x.py or FooTest.java
+		if subject, ok := testSubject(entry.Path, family); ok {
+			tests = append(tests, testFile{entry.Path, family + ":" + strings.ToLower(subject)})
+			continue
+		}
+		// Fixtures, helpers and conftest.py live next to tests but don't
+		// test anything themselves
+		if isTestFile(lowerPath) {
+			continue
+		}
+		if isSourceFile(lowerPath) {
+			name := strings.TrimSuffix(path.Base(entry.Path), path.Ext(entry.Path))
+			key := family + ":" + strings.ToLower(name)
+			sources[key] = append(sources[key], entry.Path)
+			sourcePaths = append(sourcePaths, entry.Path)
+		}
+	}
+
+	tested := make(map[string]bool)
+	for _, t := range tests {
+		mapping.TestFiles++
+		if src := matchTestSource(t.path, sources[t.key]); src != "" {
+			tested[src] = true
+		} else {
+			mapping.OrphanedTests = append(mapping.OrphanedTests, t.path)
+		}
+	}
+	sort.Strings(mapping.OrphanedTests)
+
+	dirs := make(map[string]*DirectoryTestCoverage)
+	for _, p := range sourcePaths {
+		dir := path.Dir(p)
+		d, ok := dirs[dir]
+		if !ok {
+			d = &DirectoryTestCoverage{Dir: dir}
+			dirs[dir] = d
+		}
+		d.SourceFiles++
+		mapping.SourceFiles++
+		if tested[p] {
+			d.TestedFiles++
+			mapping.TestedSources++
+		}
+	}
+	if mapping.SourceFiles > 0 {
+		mapping.Coverage = float64(mapping.TestedSources) / float64(mapping.SourceFiles)
+	}
+
+	for _, d := range dirs {
+		d.Coverage = float64(d.TestedFiles) / float64(d.SourceFiles)
+		mapping.Directories = append(mapping.Directories, *d)
+	}
+	sort.Slice(mapping.Directories, func(i, j int) bool {
+		a, b := mapping.Directories[i], mapping.Directories[j]
+		if a.SourceFiles != b.SourceFiles {
+			return a.SourceFiles > b.SourceFiles
+		}
+		return a.Dir < b.Dir
+	})
+	for _, d := range mapping.Directories {
+		if d.TestedFiles == 0 && len(mapping.UntestedPackages) < maxUntestedPackages {
+			mapping.UntestedPackages = append(mapping.UntestedPackages, d)
+		}
+	}
+
+	return mapping
+}
+
+// testSubject returns the base name of the source file a test covers, or
+// false when the file doesn't follow a test naming convention
+func testSubject(p, family string) (string, bool) {
+	base := path.Base(p)
+	name := strings.TrimSuffix(base, path.Ext(base))
+
+	for _, suffix := range testNameSuffixes {
+		if len(name) > len(suffix) && strings.HasSuffix(name, suffix) {
+			return strings.TrimSuffix(name, suffix), true
+		}
+	}
+	for _, prefix := range testNamePrefixes {
+		if len(name) > len(prefix) && strings.HasPrefix(name, prefix) {
+			return strings.TrimPrefix(name, prefix), true
+		}
+	}
+	// TestFoo.java, but not Testing.java
+	if rest := strings.TrimPrefix(name, "Test"); rest != name && rest != "" && unicode.IsUpper(rune(rest[0])) {
+		return rest, true
+	}
+
+	// Jest's __tests__/x.js and Rust's tests/x.rs name the subject directly
+	dir := "/" + path.Dir(p) + "/"
+	if (family == "js" && strings.Contains(dir, "/__tests__/")) || (family == "rs" && strings.Contains(dir, "/tests/")) {
+		return name, true
+	}
+	return "", false
+}
+
+// matchTestSource picks the candidate source for a test: Go tests must sit
+// in the same directory, other languages prefer the source whose directory
+// shares the most meaningful path segments with the test's
+func matchTestSource(testPath string, candidates []string) string {
+	testDir := path.Dir(testPath)
+	segments := make(map[string]bool)
+	for _, seg := range strings.Split(testDir, "/") {
+		if !neutralTestDirs[seg] {
+			segments[seg] = true
+		}
+	}
+
+	best, bestScore := "", -1
+	for _, src := range candidates {
+		srcDir := path.Dir(src)
+		if srcDir == testDir {
+			return src
+		}
+		if strings.HasSuffix(testPath, ".go") {
+			continue
+		}
+		score := 0
+		for _, seg := range strings.Split(srcDir, "/") {
+			if segments[seg] {
+				score++
+			}
+		}
+		if score > bestScore || (score == bestScore && src < best) {
+			best, bestScore = src, score
+		}
+	}
+	return best
+}
@@ -0,0 +1,99 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+func TestTestSubject(t *testing.T) {
+	tests := []struct {
+		path, family string
+		want         string
+		ok           bool
+	}{
+		{"pkg/foo_test.go", "go", "foo", true},
+		{"src/x.spec.ts", "js", "x", true},
+		{"src/x.test.jsx", "js", "x", true},
+		{"src/__tests__/widget.js", "js", "widget", true},
+		{"tests/test_parser.py", "py", "parser", true},
+		{"tests/conftest.py", "py", "", false},
+		{"spec/models/user_spec.rb", "rb", "user", true},
+		{"src/test/java/com/acme/FooTest.java", "jvm", "Foo", true},
+		{"src/test/java/com/acme/TestBar.java", "jvm", "Bar", true},
+		{"src/test/java/com/acme/Testing.java", "jvm", "", false},
+		{"Acme.Tests/ParserTests.cs", "cs", "Parser", true},
+		{"tests/cli.rs", "rs", "cli", true},
+	}
+	for _, tt := range tests {
+		got, ok := testSubject(tt.path, tt.family)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("testSubject(%q) = %q, %v; want %q, %v", tt.path, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestMapTestsToSources(t *testing.T) {
+	var tree []github.TreeEntry
+	for _, p := range []string{
+		"pkg/api/client.go",
+		"pkg/api/client_test.go",
+		"pkg/api/server.go",
+		"pkg/store/db.go",
+		"pkg/store/cache.go",
+		"pkg/store/index.go",
+		"pkg/util/strings.go",
+		"pkg/util/bytes_test.go", // no bytes.go: orphaned
+		"cmd/tool/main_test.go",  // Go tests must sit next to their source
+		"web/src/app/button.tsx",
+		"web/src/lib/button.tsx",
+		"web/tests/app/button.spec.ts",
+		"python/acme/parser.py",
+		"python/tests/test_parser.py",
+		"python/tests/conftest.py",
+		"foo.py",
+		"test_foo.py", // outside a tests directory
+		"src/Bar.java",
+		"src/BarTest.java",
+		"main.go",
+		"vendor/github.com/x/y/y.go",
+		"README.md",
+	} {
+		tree = append(tree, github.TreeEntry{Path: p, Type: "blob"})
+	}
+
+	m := MapTestsToSources(tree)
+
+	if m.SourceFiles != 12 || m.TestFiles != 7 || m.TestedSources != 5 {
+		t.Errorf("SourceFiles, TestFiles, TestedSources = %d, %d, %d; want 12, 7, 5", m.SourceFiles, m.TestFiles, m.TestedSources)
+	}
+	if got := strings.Join(m.OrphanedTests, ","); got != "cmd/tool/main_test.go,pkg/util/bytes_test.go" {
+		t.Errorf("OrphanedTests = %v", m.OrphanedTests)
+	}
+
+	dirs := make(map[string]DirectoryTestCoverage)
+	for _, d := range m.Directories {
+		dirs[d.Dir] = d
+	}
+	if d := dirs["pkg/api"]; d.SourceFiles != 2 || d.TestedFiles != 1 || d.Coverage != 0.5 {
+		t.Errorf("pkg/api = %+v, want 1 of 2 tested", d)
+	}
+	// The spec in web/tests/app matches web/src/app, not web/src/lib
+	if dirs["web/src/app"].TestedFiles != 1 || dirs["web/src/lib"].TestedFiles != 0 {
+		t.Errorf("button.spec.ts mapped to the wrong source: app %+v, lib %+v", dirs["web/src/app"], dirs["web/src/lib"])
+	}
+	if dirs["."].TestedFiles != 1 || dirs["src"].SourceFiles != 1 || dirs["src"].TestedFiles != 1 {
+		t.Errorf("test_foo.py and BarTest.java should test their sources: . %+v, src %+v", dirs["."], dirs["src"])
+	}
+	if dirs["python/acme"].TestedFiles != 1 {
+		t.Errorf("python/acme = %+v, want parser.py tested", dirs["python/acme"])
+	}
+
+	if len(m.UntestedPackages) == 0 || m.UntestedPackages[0].Dir != "pkg/store" {
+		t.Errorf("UntestedPackages = %+v, want pkg/store first", m.UntestedPackages)
+	}
+	if _, ok := dirs["vendor/github.com/x/y"]; ok {
+		t.Error("vendored sources should be skipped")
+	}
+}
@@ -0,0 +1,510 @@
+// Package analyzer provides analysis functions for GitHub repositories.
+// This file contains a small TOML reader used by the dependency parsers.
+package analyzer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseTOML decodes a TOML document into nested maps.
+// It supports the subset of TOML used by package manifests and lock files:
+// tables, arrays of tables, dotted and quoted keys, basic/literal/multi-line
+// strings, arrays, inline tables, booleans and numbers. Dates and times are
+// kept as strings.
+func parseTOML(content []byte) (map[string]interface{}, error) {
+	p := &tomlParser{src: []rune(string(content))}
+	return p.parse()
+}
+
+type tomlParser struct {
+	src  []rune
+	pos  int
+	line int
+}
+
+func (p *tomlParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("toml line %d: %s", p.line+1, fmt.Sprintf(format, args...))
+}
+
+func (p *tomlParser) parse() (map[string]interface{}, error) {
+	root := make(map[string]interface{})
+	current := root
+
+	for {
+		p.skipWhitespaceAndComments(true)
+		if p.eof() {
+			return root, nil
+		}
+
+		if p.peek() == '[' {
+			isArray := p.peekAt(1) == '['
+			if isArray {
+				p.pos += 2
+			} else {
+				p.pos++
+			}
+			keys, err := p.parseKey()
+			if err != nil {
+				return nil, err
+			}
+			p.skipInlineSpace()
+			closing := "]"
+			if isArray {
+				closing = "]]"
+			}
+			if !p.consume(closing) {
+				return nil, p.errorf("expected %q after table header", closing)
+			}
+
+			table, err := tomlTableForHeader(root, keys, isArray)
+			if err != nil {
+				return nil, p.errorf("%v", err)
+			}
+			current = table
+			continue
+		}
+
+		keys, err := p.parseKey()
+		if err != nil {
+			return nil, err
+		}
+		p.skipInlineSpace()
+		if !p.consume("=") {
+			return nil, p.errorf("expected '=' after key %q", strings.Join(keys, "."))
+		}
+		p.skipInlineSpace()
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		if err := tomlSetDotted(current, keys, value); err != nil {
+			return nil, p.errorf("%v", err)
+		}
+	}
+}
+
+// tomlTableForHeader returns the table a [header] or [[header]] refers to
+func tomlTableForHeader(root map[string]interface{}, keys []string, isArray bool) (map[string]interface{}, error) {
+	parent, err := tomlDescend(root, keys[:len(keys)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := keys[len(keys)-1]
+
+	if isArray {
+		table := make(map[string]interface{})
+		switch existing := parent[last].(type) {
+		case nil:
+			parent[last] = []interface{}{table}
+		case []interface{}:
+			parent[last] = append(existing, table)
+		default:
+			return nil, fmt.Errorf("key %q is not an array of tables", last)
+		}
+		return table, nil
+	}
+
+	switch existing := parent[last].(type) {
+	case nil:
+		table := make(map[string]interface{})
+		parent[last] = table
+		return table, nil
+	case map[string]interface{}:
+		return existing, nil
+	default:
+		return nil, fmt.Errorf("key %q is not a table", last)
+	}
+}
+
+// tomlDescend walks (and creates) intermediate tables along keys.
+// When it meets an array of tables it continues in the most recent entry.
+func tomlDescend(table map[string]interface{}, keys []string) (map[string]interface{}, error) {
+	for _, key := range keys {
+		switch next := table[key].(type) {
+		case nil:
+			child := make(map[string]interface{})
+			table[key] = child
+			table = child
+		case map[string]interface{}:
+			table = next
+		case []interface{}:
+			if len(next) == 0 {
+				return nil, fmt.Errorf("key %q is an empty array", key)
+			}
+			last, ok := next[len(next)-1].(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("key %q is not a table", key)
+			}
+			table = last
+		default:
+			return nil, fmt.Errorf("key %q is not a table", key)
+		}
+	}
+	return table, nil
+}
+
+func tomlSetDotted(table map[string]interface{}, keys []string, value interface{}) error {
+	parent, err := tomlDescend(table, keys[:len(keys)-1])
+	if err != nil {
+		return err
+	}
+	parent[keys[len(keys)-1]] = value
+	return nil
+}
+
+func (p *tomlParser) eof() bool { return p.pos >= len(p.src) }
+
+func (p *tomlParser) peek() rune { return p.peekAt(0) }
+
+func (p *tomlParser) peekAt(offset int) rune {
+	if p.pos+offset >= len(p.src) {
+		return 0
+	}
+	return p.src[p.pos+offset]
+}
+
+func (p *tomlParser) consume(s string) bool {
+	r := []rune(s)
+	if p.pos+len(r) > len(p.src) {
+		return false
+	}
+	for i, c := range r {
+		if p.src[p.pos+i] != c {
+			return false
+		}
+	}
+	p.pos += len(r)
+	return true
+}
+
+func (p *tomlParser) skipInlineSpace() {
+	for !p.eof() && (p.peek() == ' ' || p.peek() == '\t') {
+		p.pos++
+	}
+}
+
+// skipWhitespaceAndComments skips spaces, comments and (optionally) newlines
+func (p *tomlParser) skipWhitespaceAndComments(newlines bool) {
+	for !p.eof() {
+		switch c := p.peek(); {
+		case c == ' ' || c == '\t' || c == '\r':
+			p.pos++
+		case c == '\n' && newlines:
+			p.line++
+			p.pos++
+		case c == '#':
+			for !p.eof() && p.peek() != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// parseKey parses a possibly dotted key made of bare and quoted parts
+func (p *tomlParser) parseKey() ([]string, error) {
+	var keys []string
+	for {
+		p.skipInlineSpace()
+		var key string
+		switch p.peek() {
+		case '"':
+			s, err := p.parseBasicString()
+			if err != nil {
+				return nil, err
+			}
+			key = s
+		case '\'':
+			s, err := p.parseLiteralString()
+			if err != nil {
+				return nil, err
+			}
+			key = s
+		default:
+			start := p.pos
+			for !p.eof() && isTOMLBareKeyChar(p.peek()) {
+				p.pos++
+			}
+			if start == p.pos {
+				return nil, p.errorf("invalid key")
+			}
+			key = string(p.src[start:p.pos])
+		}
+		keys = append(keys, key)
+
+		p.skipInlineSpace()
+		if p.peek() != '.' {
+			return keys, nil
+		}
+		p.pos++
+	}
+}
+
+func isTOMLBareKeyChar(c rune) bool {
+	return c == '_' || c == '-' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func (p *tomlParser) parseValue() (interface{}, error) {
+	switch c := p.peek(); {
+	case c == '"':
+		if p.consume(`"""`) {
+			return p.parseMultilineString(`"""`, true)
+		}
+		return p.parseBasicString()
+	case c == '\'':
+		if p.consume(`'''`) {
+			return p.parseMultilineString(`'''`, false)
+		}
+		return p.parseLiteralString()
+	case c == '[':
+		return p.parseArray()
+	case c == '{':
+		return p.parseInlineTable()
+	case c == 't' && p.consume("true"):
+		return true, nil
+	case c == 'f' && p.consume("false"):
+		return false, nil
+	default:
+		start := p.pos
+		for !p.eof() {
+			c := p.peek()
+			if c == ',' || c == ']' || c == '}' || c == '\n' || c == '#' || c == '\r' {
+				break
+			}
+			p.pos++
+		}
+		raw := strings.TrimSpace(string(p.src[start:p.pos]))
+		if raw == "" {
+			return nil, p.errorf("missing value")
+		}
+		clean := strings.ReplaceAll(raw, "_", "")
+		if i, err := strconv.ParseInt(clean, 0, 64); err == nil {
+			return i, nil
+		}
+		if f, err := strconv.ParseFloat(clean, 64); err == nil {
+			return f, nil
+		}
+		// Dates, times and anything else are kept verbatim
+		return raw, nil
+	}
+}
+
+func (p *tomlParser) parseBasicString() (string, error) {
+	p.pos++ // opening quote
+	var sb strings.Builder
+	for !p.eof() {
+		c := p.peek()
+		switch c {
+		case '"':
+			p.pos++
+			return sb.String(), nil
+		case '\n':
+			return "", p.errorf("unterminated string")
+		case '\\':
+			r, err := p.parseEscape()
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(r)
+		default:
+			sb.WriteRune(c)
+			p.pos++
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+func (p *tomlParser) parseEscape() (string, error) {
+	p.pos++ // backslash
+	c := p.peek()
+	p.pos++
+	switch c {
+	case 'n':
+		return "\n", nil
+	case 't':
+		return "\t", nil
+	case 'r':
+		return "\r", nil
+	case 'b':
+		return "\b", nil
+	case 'f':
+		return "\f", nil
+	case '"', '\\':
+		return string(c), nil
+	case 'u', 'U':
+		n := 4
+		if c == 'U' {
+			n = 8
+		}
+		if p.pos+n > len(p.src) {
+			return "", p.errorf("invalid unicode escape")
+		}
+		code, err := strconv.ParseUint(string(p.src[p.pos:p.pos+n]), 16, 32)
+		if err != nil {
+			return "", p.errorf("invalid unicode escape")
+		}
+		p.pos += n
+		return string(rune(code)), nil
+	}
+	return "", p.errorf("invalid escape \\%c", c)
+}
+
+func (p *tomlParser) parseLiteralString() (string, error) {
+	p.pos++ // opening quote
+	start := p.pos
+	for !p.eof() {
+		switch p.peek() {
+		case '\'':
+			s := string(p.src[start:p.pos])
+			p.pos++
+			return s, nil
+		case '\n':
+			return "", p.errorf("unterminated string")
+		}
+		p.pos++
+	}
+	return "", p.errorf("unterminated string")
+}
+
+func (p *tomlParser) parseMultilineString(delim string, escapes bool) (string, error) {
+	// A newline immediately after the opening delimiter is trimmed
+	if p.peek() == '\r' {
+		p.pos++
+	}
+	if p.peek() == '\n' {
+		p.line++
+		p.pos++
+	}
+
+	var sb strings.Builder
+	for !p.eof() {
+		if p.consume(delim) {
+			return sb.String(), nil
+		}
+		c := p.peek()
+		if c == '\n' {
+			p.line++
+		}
+		if escapes && c == '\\' {
+			// Line-ending backslash trims following whitespace
+			next := p.peekAt(1)
+			if next == '\n' || next == ' ' || next == '\t' || next == '\r' {
+				p.pos++
+				for !p.eof() && (p.peek() == ' ' || p.peek() == '\t' || p.peek() == '\n' || p.peek() == '\r') {
+					if p.peek() == '\n' {
+						p.line++
+					}
+					p.pos++
+				}
+				continue
+			}
+			r, err := p.parseEscape()
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(r)
+			continue
+		}
+		sb.WriteRune(c)
+		p.pos++
+	}
+	return "", p.errorf("unterminated multi-line string")
+}
+
+func (p *tomlParser) parseArray() ([]interface{}, error) {
+	p.pos++ // [
+	values := []interface{}{}
+	for {
+		p.skipWhitespaceAndComments(true)
+		if p.eof() {
+			return nil, p.errorf("unterminated array")
+		}
+		if p.peek() == ']' {
+			p.pos++
+			return values, nil
+		}
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+		p.skipWhitespaceAndComments(true)
+		if p.peek() == ',' {
+			p.pos++
+		}
+	}
+}
+
+func (p *tomlParser) parseInlineTable() (map[string]interface{}, error) {
+	p.pos++ // {
+	table := make(map[string]interface{})
+	for {
+		p.skipWhitespaceAndComments(true)
+		if p.eof() {
+			return nil, p.errorf("unterminated inline table")
+		}
+		if p.peek() == '}' {
+			p.pos++
+			return table, nil
+		}
+		keys, err := p.parseKey()
+		if err != nil {
+			return nil, err
+		}
+		p.skipInlineSpace()
+		if !p.consume("=") {
+			return nil, p.errorf("expected '=' in inline table")
+		}
+		p.skipInlineSpace()
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		if err := tomlSetDotted(table, keys, v); err != nil {
+			return nil, p.errorf("%v", err)
+		}
+		p.skipWhitespaceAndComments(true)
+		if p.peek() == ',' {
+			p.pos++
+		}
+	}
+}
+
+// tomlTable returns the nested table at the given path, or nil
+func tomlTable(doc map[string]interface{}, path ...string) map[string]interface{} {
+	current := doc
+	for _, key := range path {
+		next, ok := current[key].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = next
+	}
+	return current
+}
+
+// tomlString returns a string value from a table, or "" if absent
+func tomlString(table map[string]interface{}, key string) string {
+	if s, ok := table[key].(string); ok {
+		return s
+	}
+	return ""
+}
+
+// tomlStrings returns an array of strings from a table
+func tomlStrings(table map[string]interface{}, key string) []string {
+	arr, ok := table[key].([]interface{})
+	if !ok {
+		return nil
+	}
+	var out []string
+	for _, v := range arr {
+		if s, ok := v.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,223 @@
+package analyzer
+
+import (
+	"testing"
+)
+
+func TestParseTOML(t *testing.T) {
+	doc, err := parseTOML([]byte(`
+# comment
+title = "demo" # trailing comment
+"quoted key" = 'literal \n'
+count = 1_000
+ratio = 0.5
+enabled = true
+released = 1979-05-27T07:32:00Z
+site.owner = "me"
+notes = """
+multi
+line"""
+
+[server]
+ports = [
+  8000,
+  8001, # trailing comma
+]
+limits = { cpu = "2", memory.max = "1Gi" }
+
+[[plugins]]
+name = "a"
+
+[[plugins]]
+name = "b"
+`))
+	if err != nil {
+		t.Fatalf("parseTOML() error = %v", err)
+	}
+
+	if got := tomlString(doc, "title"); got != "demo" {
+		t.Errorf("title = %q, want demo", got)
+	}
+	if got := tomlString(doc, "quoted key"); got != `literal \n` {
+		t.Errorf("quoted key = %q, want literal string", got)
+	}
+	if got, _ := doc["count"].(int64); got != 1000 {
+		t.Errorf("count = %v, want 1000", doc["count"])
+	}
+	if got, _ := doc["enabled"].(bool); !got {
+		t.Error("enabled should be true")
+	}
+	if got := tomlString(doc, "released"); got != "1979-05-27T07:32:00Z" {
+		t.Errorf("released = %q", got)
+	}
+	if got := tomlString(tomlTable(doc, "site"), "owner"); got != "me" {
+		t.Errorf("site.owner = %q, want me", got)
+	}
+	if got := tomlString(doc, "notes"); got != "multi\nline" {
+		t.Errorf("notes = %q", got)
+	}
+	if ports, _ := tomlTable(doc, "server")["ports"].([]interface{}); len(ports) != 2 {
+		t.Errorf("ports = %v, want 2 entries", ports)
+	}
+	if got := tomlString(tomlTable(doc, "server", "limits", "memory"), "max"); got != "1Gi" {
+		t.Errorf("limits.memory.max = %q, want 1Gi", got)
+	}
+	if plugins, _ := doc["plugins"].([]interface{}); len(plugins) != 2 {
+		t.Errorf("plugins = %v, want 2 tables", plugins)
+	}
+}
+
+func TestParseTOML_Invalid(t *testing.T) {
+	if _, err := parseTOML([]byte("key = \"unterminated\n")); err == nil {
+		t.Error("expected error for unterminated string")
+	}
+}
+
+func TestParseTOMLTables(t *testing.T) {
+	doc, err := parseTOML([]byte(`
+# comment at the top
+name = "demo" # trailing comment
+"quoted key" = 'C:\path'
+count = 1_000
+ratio = 0.5
+enabled = true
+released = 2024-01-02
+hash = "a#b" # a # inside a string is not a comment
+
+[package]
+version = "1.2.3"
+authors = [
+  "Ada", # comment inside an array
+  "Linus",
+]
+
+[dependencies]
+serde = { version = "1.0", features = ["derive"] }
+tokio.version = "1"
+tokio.features = ["full"]
+"quoted.name" = "2"
+
+[target.'cfg(unix)'.dependencies]
+libc = "0.2"
+
+[[package.metadata]]
+id = 1
+
+[[package.metadata]]
+id = 2
+nested = { a = { b = "deep" } }
+
+[[package.metadata.links]]
+url = "https://example.com"
+`))
+	if err != nil {
+		t.Fatalf("parseTOML() error = %v", err)
+	}
+
+	if got := tomlString(doc, "name"); got != "demo" {
+		t.Errorf("name = %q, want demo", got)
+	}
+	if got := tomlString(doc, "quoted key"); got != `C:\path` {
+		t.Errorf("quoted key = %q, want the literal string", got)
+	}
+	if got := tomlString(doc, "hash"); got != "a#b" {
+		t.Errorf("hash = %q, want a#b", got)
+	}
+	if doc["count"] != int64(1000) || doc["ratio"] != 0.5 || doc["enabled"] != true {
+		t.Errorf("count, ratio, enabled = %v, %v, %v", doc["count"], doc["ratio"], doc["enabled"])
+	}
+	if doc["released"] != "2024-01-02" {
+		t.Errorf("released = %v, want the date kept as a string", doc["released"])
+	}
+
+	pkg := tomlTable(doc, "package")
+	if got := tomlString(pkg, "version"); got != "1.2.3" {
+		t.Errorf("package.version = %q", got)
+	}
+	if authors := tomlStrings(pkg, "authors"); len(authors) != 2 || authors[1] != "Linus" {
+		t.Errorf("package.authors = %v, want [Ada Linus]", authors)
+	}
+
+	// Inline tables
+	deps := tomlTable(doc, "dependencies")
+	serde := tomlTable(deps, "serde")
+	if tomlString(serde, "version") != "1.0" || len(tomlStrings(serde, "features")) != 1 {
+		t.Errorf("dependencies.serde = %v", serde)
+	}
+
+	// Dotted keys, in key/value pairs and in headers
+	if got := tomlString(tomlTable(deps, "tokio"), "version"); got != "1" {
+		t.Errorf("dependencies.tokio.version = %q, want 1", got)
+	}
+	if got := tomlString(deps, "quoted.name"); got != "2" {
+		t.Errorf("quoted dotted key = %q, want 2", got)
+	}
+	if got := tomlString(tomlTable(doc, "target", "cfg(unix)", "dependencies"), "libc"); got != "0.2" {
+		t.Errorf("target.'cfg(unix)'.dependencies.libc = %q, want 0.2", got)
+	}
+
+	// Arrays of tables, with a sub-array attached to the latest entry
+	metadata, _ := pkg["metadata"].([]interface{})
+	if len(metadata) != 2 {
+		t.Fatalf("len(package.metadata) = %d, want 2", len(metadata))
+	}
+	second, _ := metadata[1].(map[string]interface{})
+	if second["id"] != int64(2) || tomlString(tomlTable(second, "nested", "a"), "b") != "deep" {
+		t.Errorf("package.metadata[1] = %v", second)
+	}
+	links, _ := second["links"].([]interface{})
+	if len(links) != 1 {
+		t.Errorf("package.metadata[1].links = %v, want one entry", second["links"])
+	}
+	if first, _ := metadata[0].(map[string]interface{}); first["links"] != nil {
+		t.Errorf("package.metadata[0] = %v, want no links", first)
+	}
+}
+
+func TestParseTOMLMultilineStrings(t *testing.T) {
+	doc, err := parseTOML([]byte(`
+basic = """
+first line
+second "quoted" line"""
+folded = """\
+    one \
+    two"""
+escaped = """tab\there"""
+literal = '''
+C:\no\escapes
+'''
+after = "still parsed"
+`))
+	if err != nil {
+		t.Fatalf("parseTOML() error = %v", err)
+	}
+
+	tests := map[string]string{
+		"basic":   "first line\nsecond \"quoted\" line",
+		"folded":  "one two",
+		"escaped": "tab\there",
+		"literal": "C:\\no\\escapes\n",
+		"after":   "still parsed",
+	}
+	for key, want := range tests {
+		if got := tomlString(doc, key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestParseTOMLErrors(t *testing.T) {
+	tests := map[string]string{
+		"unterminated string":    `name = "demo`,
+		"unterminated multiline": "doc = \"\"\"\nnever closed",
+		"missing value":          "name =",
+		"unclosed header":        "[package\nname = 1",
+		"unclosed inline table":  `dep = { version = "1"`,
+		"unclosed array":         `list = [1, 2`,
+	}
+	for name, input := range tests {
+		if _, err := parseTOML([]byte(input)); err == nil {
+			t.Errorf("%s: parseTOML(%q) succeeded, want an error", name, input)
+		}
+	}
+}
@@ -0,0 +1,370 @@
+// Package analyzer provides functions for analyzing GitHub repository data.
+// This file implements a file-ownership based truck factor calculation.
+package analyzer
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os/exec"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// Degree-of-authorship model constants (Fritz et al., as used by Avelino et al.
+// in "A novel approach for estimating truck factors").
+const (
+	doaBase            = 3.293
+	doaFirstAuthor     = 1.098
+	doaDeliveries      = 0.164
+	doaAcceptances     = 0.321
+	doaAuthorThreshold = 0.75 // normalized DOA required to count as a file author
+	orphanThreshold    = 0.5  // share of files that must be orphaned
+)
+
+// FileChange records a single file touched by a single commit.
+// Changes must be supplied oldest first so file creators can be identified.
+type FileChange struct {
+	Path    string `json:"path"`
+	Author  string `json:"author"`
+	Created bool   `json:"created"` // true if this commit added the file
+}
+
+// FileAuthorship summarizes how many files a developer is an author of
+type FileAuthorship struct {
+	Author string `json:"author"`
+	Files  int    `json:"files"`
+}
+
+// TruckFactorResult holds the outcome of the truck factor computation
+type TruckFactorResult struct {
+	TruckFactor         int              `json:"truck_factor"`
+	KeyDevelopers       []string         `json:"key_developers"`       // Minimal set whose departure orphans >50% of files
+	TotalFiles          int              `json:"total_files"`          // Files with authorship data
+	OrphanedFiles       int              `json:"orphaned_files"`       // Files left without an author once key developers leave
+	OrphanedPercent     float64          `json:"orphaned_percent"`     // OrphanedFiles / TotalFiles * 100
+	OrphanedDirectories []string         `json:"orphaned_directories"` // Directories whose files would all be orphaned
+	TopAuthors          []FileAuthorship `json:"top_authors"`
+	CommitsAnalyzed     int              `json:"commits_analyzed"`
+	Source              string           `json:"source"` // "local clone" or "github api"
+	Risk                string           `json:"risk"`
+	Note                string           `json:"note,omitempty"` // Why the history is partial, if it is
+}
+
+// fileHistory accumulates per-author change counts for a single file
+type fileHistory struct {
+	creator string
+	changes map[string]int
+	total   int
+}
+
+// CalculateTruckFactor computes the truck factor from per-file change history.
+// Each developer's degree of authorship (DOA) over each file is computed as
+//
+//	DOA = 3.293 + 1.098*FA + 0.164*DL - 0.321*ln(1 + AC)
+//
+// where FA is 1 if the developer created the file, DL is the number of changes
+// they made to it and AC the number of changes by others. Developers whose
+// normalized DOA exceeds 0.75 are the file's authors. Top authors are then
+// removed greedily until more than half of the files have no author left.
+//
+// Parameters:
+//   - changes: File changes ordered oldest first
+//   - files: Files present at HEAD; when empty every file seen in changes is used
+func CalculateTruckFactor(changes []FileChange, files []string) *TruckFactorResult {
+	result := &TruckFactorResult{
+		KeyDevelopers:       []string{},
+		OrphanedDirectories: []string{},
+		TopAuthors:          []FileAuthorship{},
+		Risk:                "Unknown",
+	}
+
+	histories := buildFileHistories(changes, files)
+	if len(histories) == 0 {
+		return result
+	}
+
+	authors := computeFileAuthors(histories)
+	result.TotalFiles = len(authors)
+
+	// Count authored files per developer
+	authored := make(map[string]int)
+	for _, devs := range authors {
+		for _, dev := range devs {
+			authored[dev]++
+		}
+	}
+	for dev, n := range authored {
+		result.TopAuthors = append(result.TopAuthors, FileAuthorship{Author: dev, Files: n})
+	}
+	sortAuthorships(result.TopAuthors)
+
+	// Greedily remove the developer with the most authored files
+	removed := make(map[string]bool)
+	orphaned := countOrphaned(authors, removed)
+	for float64(orphaned) <= orphanThreshold*float64(result.TotalFiles) {
+		next := topRemainingAuthor(authors, removed)
+		if next == "" {
+			break
+		}
+		removed[next] = true
+		result.KeyDevelopers = append(result.KeyDevelopers, next)
+		orphaned = countOrphaned(authors, removed)
+	}
+
+	result.TruckFactor = len(result.KeyDevelopers)
+	result.OrphanedFiles = orphaned
+	result.OrphanedPercent = float64(orphaned) / float64(result.TotalFiles) * 100
+	result.OrphanedDirectories = orphanedDirectories(authors, removed)
+	result.Risk = truckFactorRisk(result.TruckFactor)
+
+	if len(result.TopAuthors) > 10 {
+		result.TopAuthors = result.TopAuthors[:10]
+	}
+
+	return result
+}
+
+// buildFileHistories groups changes per file, restricted to files at HEAD
+func buildFileHistories(changes []FileChange, files []string) map[string]*fileHistory {
+	var keep map[string]bool
+	if len(files) > 0 {
+		keep = make(map[string]bool, len(files))
+		for _, f := range files {
+			keep[f] = true
+		}
+	}
+
+	histories := make(map[string]*fileHistory)
+	for _, ch := range changes {
+		if ch.Path == "" || ch.Author == "" {
+			continue
+		}
+		if keep != nil && !keep[ch.Path] {
+			continue
+		}
+
+		h, ok := histories[ch.Path]
+		if !ok {
+			h = &fileHistory{changes: make(map[string]int)}
+			histories[ch.Path] = h
+		}
+		if ch.Created && h.creator == "" {
+			h.creator = ch.Author
+		}
+		h.changes[ch.Author]++
+		h.total++
+	}
+	return histories
+}
+
+// computeFileAuthors returns the authors of each file according to the DOA model
+func computeFileAuthors(histories map[string]*fileHistory) map[string][]string {
+	authors := make(map[string][]string, len(histories))
+
+	for file, h := range histories {
+		doas := make(map[string]float64, len(h.changes))
+		maxDOA := 0.0
+		for dev, dl := range h.changes {
+			fa := 0.0
+			if dev == h.creator {
+				fa = 1
+			}
+			ac := h.total - dl
+			doa := doaBase + doaFirstAuthor*fa + doaDeliveries*float64(dl) - doaAcceptances*math.Log(1+float64(ac))
+			doas[dev] = doa
+			if doa > maxDOA {
+				maxDOA = doa
+			}
+		}
+
+		var devs []string
+		for dev, doa := range doas {
+			if maxDOA > 0 && doa/maxDOA > doaAuthorThreshold && doa >= doaBase {
+				devs = append(devs, dev)
+			}
+		}
+		sort.Strings(devs)
+		authors[file] = devs
+	}
+
+	return authors
+}
+
+// countOrphaned counts files whose authors have all been removed
+func countOrphaned(authors map[string][]string, removed map[string]bool) int {
+	count := 0
+	for _, devs := range authors {
+		if isOrphaned(devs, removed) {
+			count++
+		}
+	}
+	return count
+}
+
+func isOrphaned(devs []string, removed map[string]bool) bool {
+	for _, dev := range devs {
+		if !removed[dev] {
+			return false
+		}
+	}
+	return true
+}
+
+// topRemainingAuthor finds the not-yet-removed developer authoring the most files
+func topRemainingAuthor(authors map[string][]string, removed map[string]bool) string {
+	counts := make(map[string]int)
+	for _, devs := range authors {
+		for _, dev := range devs {
+			if !removed[dev] {
+				counts[dev]++
+			}
+		}
+	}
+
+	var ranked []FileAuthorship
+	for dev, n := range counts {
+		ranked = append(ranked, FileAuthorship{Author: dev, Files: n})
+	}
+	if len(ranked) == 0 {
+		return ""
+	}
+	sortAuthorships(ranked)
+	return ranked[0].Author
+}
+
+// orphanedDirectories lists the outermost directories in which every file is orphaned
+func orphanedDirectories(authors map[string][]string, removed map[string]bool) []string {
+	total := make(map[string]int)
+	orphans := make(map[string]int)
+
+	for file, devs := range authors {
+		orphaned := isOrphaned(devs, removed)
+		for dir := path.Dir(file); dir != "." && dir != "/"; dir = path.Dir(dir) {
+			total[dir]++
+			if orphaned {
+				orphans[dir]++
+			}
+		}
+	}
+
+	var dirs []string
+	for dir, n := range total {
+		if orphans[dir] != n {
+			continue
+		}
+		// Only report the outermost fully orphaned directory
+		parent := path.Dir(dir)
+		if parent != "." && total[parent] > 0 && orphans[parent] == total[parent] {
+			continue
+		}
+		dirs = append(dirs, dir)
+	}
+
+	sort.Slice(dirs, func(i, j int) bool {
+		if total[dirs[i]] != total[dirs[j]] {
+			return total[dirs[i]] > total[dirs[j]]
+		}
+		return dirs[i] < dirs[j]
+	})
+	if len(dirs) > 10 {
+		dirs = dirs[:10]
+	}
+	if dirs == nil {
+		dirs = []string{}
+	}
+	return dirs
+}
+
+func sortAuthorships(a []FileAuthorship) {
+	sort.Slice(a, func(i, j int) bool {
+		if a[i].Files != a[j].Files {
+			return a[i].Files > a[j].Files
+		}
+		return a[i].Author < a[j].Author
+	})
+}
+
+func truckFactorRisk(tf int) string {
+	switch {
+	case tf <= 0:
+		return "Unknown"
+	case tf == 1:
+		return "High Risk"
+	case tf == 2:
+		return "Medium Risk"
+	default:
+		return "Low Risk"
+	}
+}
+
+// FileChangesFromCommits converts GitHub commit details into file changes.
+// The GitHub API returns commits newest first, so the order is reversed.
+func FileChangesFromCommits(details []github.CommitDetail) []FileChange {
+	var changes []FileChange
+	for i := len(details) - 1; i >= 0; i-- {
+		d := details[i]
+		author := d.AuthorName()
+		for _, f := range d.Files {
+			if f.Status == "removed" {
+				continue
+			}
+			changes = append(changes, FileChange{
+				Path:    f.Filename,
+				Author:  author,
+				Created: f.Status == "added",
+			})
+		}
+	}
+	return changes
+}
+
+// ReadLocalFileChanges reads the full file change history of a local git clone.
+// It shells out to `git log` and returns changes ordered oldest first.
+func ReadLocalFileChanges(repoPath string) ([]FileChange, int, error) {
+	cmd := exec.Command("git", "-C", repoPath, "log", "--reverse", "--no-merges",
+		"--name-status", "--no-renames", "--format=@@%an")
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, 0, fmt.Errorf("git log failed: %w", err)
+	}
+	changes, commits := parseGitLogNameStatus(string(out))
+	return changes, commits, nil
+}
+
+// parseGitLogNameStatus parses `git log --name-status --format=@@%an` output
+func parseGitLogNameStatus(out string) ([]FileChange, int) {
+	var changes []FileChange
+	author := ""
+	commits := 0
+
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "@@") {
+			author = strings.TrimPrefix(line, "@@")
+			commits++
+			continue
+		}
+
+		parts := strings.Split(line, "\t")
+		if len(parts) < 2 || author == "" {
+			continue
+		}
+		status := parts[0]
+		if strings.HasPrefix(status, "D") {
+			continue
+		}
+		changes = append(changes, FileChange{
+			Path:    parts[len(parts)-1],
+			Author:  author,
+			Created: strings.HasPrefix(status, "A"),
+		})
+	}
+
+	return changes, commits
+}
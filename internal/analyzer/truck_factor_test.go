@@ -0,0 +1,115 @@
+package analyzer
+
+import (
+	"testing"
+)
+
+func TestCalculateTruckFactor_Empty(t *testing.T) {
+	result := CalculateTruckFactor(nil, nil)
+
+	if result.TruckFactor != 0 {
+		t.Errorf("TruckFactor = %d, want 0", result.TruckFactor)
+	}
+	if result.Risk != "Unknown" {
+		t.Errorf("Risk = %s, want Unknown", result.Risk)
+	}
+}
+
+func TestCalculateTruckFactor_SingleOwner(t *testing.T) {
+	changes := []FileChange{
+		{Path: "main.go", Author: "alice", Created: true},
+		{Path: "util.go", Author: "alice", Created: true},
+		{Path: "README.md", Author: "alice", Created: true},
+		{Path: "README.md", Author: "bob"},
+	}
+
+	result := CalculateTruckFactor(changes, nil)
+
+	if result.TruckFactor != 1 {
+		t.Errorf("TruckFactor = %d, want 1", result.TruckFactor)
+	}
+	if len(result.KeyDevelopers) != 1 || result.KeyDevelopers[0] != "alice" {
+		t.Errorf("KeyDevelopers = %v, want [alice]", result.KeyDevelopers)
+	}
+	if result.OrphanedFiles != 3 {
+		t.Errorf("OrphanedFiles = %d, want 3", result.OrphanedFiles)
+	}
+	if result.Risk != "High Risk" {
+		t.Errorf("Risk = %s, want High Risk", result.Risk)
+	}
+}
+
+func TestCalculateTruckFactor_SharedOwnership(t *testing.T) {
+	changes := []FileChange{
+		{Path: "api/a.go", Author: "alice", Created: true},
+		{Path: "api/b.go", Author: "alice", Created: true},
+		{Path: "web/c.js", Author: "bob", Created: true},
+		{Path: "web/d.js", Author: "bob", Created: true},
+		{Path: "db/e.sql", Author: "carol", Created: true},
+		{Path: "db/f.sql", Author: "carol", Created: true},
+	}
+
+	result := CalculateTruckFactor(changes, nil)
+
+	// Removing two developers orphans 4/6 files
+	if result.TruckFactor != 2 {
+		t.Errorf("TruckFactor = %d, want 2", result.TruckFactor)
+	}
+	if result.OrphanedFiles != 4 {
+		t.Errorf("OrphanedFiles = %d, want 4", result.OrphanedFiles)
+	}
+	if len(result.OrphanedDirectories) != 2 {
+		t.Errorf("OrphanedDirectories = %v, want 2 entries", result.OrphanedDirectories)
+	}
+}
+
+func TestCalculateTruckFactor_RestrictsToHeadFiles(t *testing.T) {
+	changes := []FileChange{
+		{Path: "old.go", Author: "alice", Created: true},
+		{Path: "new.go", Author: "bob", Created: true},
+	}
+
+	result := CalculateTruckFactor(changes, []string{"new.go"})
+
+	if result.TotalFiles != 1 {
+		t.Errorf("TotalFiles = %d, want 1", result.TotalFiles)
+	}
+	if len(result.KeyDevelopers) != 1 || result.KeyDevelopers[0] != "bob" {
+		t.Errorf("KeyDevelopers = %v, want [bob]", result.KeyDevelopers)
+	}
+}
+
+func TestComputeFileAuthors_MinorContributorIsNotAuthor(t *testing.T) {
+	histories := buildFileHistories([]FileChange{
+		{Path: "core.go", Author: "alice", Created: true},
+		{Path: "core.go", Author: "alice"},
+		{Path: "core.go", Author: "alice"},
+		{Path: "core.go", Author: "alice"},
+		{Path: "core.go", Author: "bob"},
+	}, nil)
+
+	authors := computeFileAuthors(histories)
+
+	if len(authors["core.go"]) != 1 || authors["core.go"][0] != "alice" {
+		t.Errorf("authors = %v, want [alice]", authors["core.go"])
+	}
+}
+
+func TestParseGitLogNameStatus(t *testing.T) {
+	out := "@@Alice\n\nA\tmain.go\nA\tgo.mod\n@@Bob\n\nM\tmain.go\nD\tgo.mod\n"
+
+	changes, commits := parseGitLogNameStatus(out)
+
+	if commits != 2 {
+		t.Errorf("commits = %d, want 2", commits)
+	}
+	if len(changes) != 3 {
+		t.Fatalf("len(changes) = %d, want 3", len(changes))
+	}
+	if !changes[0].Created || changes[0].Author != "Alice" {
+		t.Errorf("changes[0] = %+v, want created by Alice", changes[0])
+	}
+	if changes[2].Created || changes[2].Author != "Bob" {
+		t.Errorf("changes[2] = %+v, want modification by Bob", changes[2])
+	}
+}
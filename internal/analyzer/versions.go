@@ -0,0 +1,522 @@
+// Package analyzer provides analysis functions for GitHub repositories.
+// This file implements version parsing and comparison for the version
+// schemes used by package ecosystems: Semantic Versioning (npm, Cargo, Go
+// including pseudo-versions, Pub, Hex, NuGet), PEP 440 (PyPI) and RubyGems.
+package analyzer
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// compareVersions compares two versions using the rules of an OSV
+// ecosystem. It returns -1, 0 or 1. Versions that cannot be parsed under
+// the ecosystem's scheme fall back to a numeric dot-separated comparison.
+func compareVersions(ecosystem, a, b string) int {
+	switch ecosystem {
+	case "PyPI":
+		return comparePEP440(a, b)
+	case "RubyGems":
+		return compareGemVersions(a, b)
+	case "npm", "crates.io", "Go", "Pub", "Hex", "NuGet", "SwiftURL":
+		return compareSemver(a, b)
+	}
+	return compareLooseVersions(a, b)
+}
+
+// semver is a parsed semantic version. Up to four numeric components are
+// accepted for NuGet; build metadata is ignored as the spec requires.
+type semver struct {
+	nums []int
+	pre  []string
+}
+
+var semverPattern = regexp.MustCompile(`^v?(\d+(?:\.\d+){0,3})(?:-([0-9A-Za-z.\-]+))?(?:\+[0-9A-Za-z.\-]+)?$`)
+
+func parseSemver(v string) (semver, bool) {
+	m := semverPattern.FindStringSubmatch(strings.TrimSpace(v))
+	if m == nil {
+		return semver{}, false
+	}
+	var sv semver
+	for _, p := range strings.Split(m[1], ".") {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return semver{}, false
+		}
+		sv.nums = append(sv.nums, n)
+	}
+	if m[2] != "" {
+		sv.pre = strings.Split(m[2], ".")
+	}
+	return sv, true
+}
+
+// canonicalSemver spells out a semantic version as major.minor.patch plus
+// any pre-release, so that "1.2", "v1.2.0" and "1.2.0+build" are the same
+// map key. Other version strings are returned unchanged.
+func canonicalSemver(v string) string {
+	sv, ok := parseSemver(v)
+	if !ok {
+		return v
+	}
+	nums := sv.nums
+	for len(nums) < 3 {
+		nums = append(nums, 0)
+	}
+	if len(nums) == 4 && nums[3] == 0 {
+		nums = nums[:3]
+	}
+	parts := make([]string, len(nums))
+	for i, n := range nums {
+		parts[i] = strconv.Itoa(n)
+	}
+	canonical := strings.Join(parts, ".")
+	if len(sv.pre) > 0 {
+		canonical += "-" + strings.Join(sv.pre, ".")
+	}
+	return canonical
+}
+
+// compareSemver compares semantic versions. Go pseudo-versions such as
+// v0.0.0-20191109021931-daa7c04131f5 are pre-releases whose timestamp
+// orders them correctly. "+incompatible" suffixes are ignored.
+func compareSemver(a, b string) int {
+	va, okA := parseSemver(a)
+	vb, okB := parseSemver(b)
+	if !okA || !okB {
+		return compareLooseVersions(a, b)
+	}
+
+	if c := compareIntSlices(va.nums, vb.nums); c != 0 {
+		return c
+	}
+
+	// A version without pre-release identifiers has higher precedence
+	switch {
+	case len(va.pre) == 0 && len(vb.pre) == 0:
+		return 0
+	case len(va.pre) == 0:
+		return 1
+	case len(vb.pre) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(va.pre) && i < len(vb.pre); i++ {
+		if c := comparePrereleaseIdentifier(va.pre[i], vb.pre[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInts(len(va.pre), len(vb.pre))
+}
+
+// comparePrereleaseIdentifier orders numeric identifiers numerically and
+// below alphanumeric ones, which compare lexically
+func comparePrereleaseIdentifier(a, b string) int {
+	na, errA := strconv.Atoi(a)
+	nb, errB := strconv.Atoi(b)
+	switch {
+	case errA == nil && errB == nil:
+		return compareInts(na, nb)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// pep440Version is a parsed PEP 440 version
+type pep440Version struct {
+	epoch   int
+	release []int
+	pre     [2]int // phase (0=a, 1=b, 2=rc) and number; see sortKey
+	hasPre  bool
+	post    int // -1 when absent
+	dev     int // -1 when absent
+	local   string
+}
+
+var pep440Pattern = regexp.MustCompile(`(?i)^v?(?:(\d+)!)?(\d+(?:\.\d+)*)` +
+	`(?:[-_.]?(a|alpha|b|beta|c|rc|pre|preview)[-_.]?(\d*))?` +
+	`(?:-(\d+)|[-_.]?(post|rev|r)[-_.]?(\d*))?` +
+	`(?:[-_.]?(dev)[-_.]?(\d*))?` +
+	`(?:\+([a-z0-9]+(?:[-_.][a-z0-9]+)*))?$`)
+
+func parsePEP440(v string) (pep440Version, bool) {
+	m := pep440Pattern.FindStringSubmatch(strings.TrimSpace(v))
+	if m == nil {
+		return pep440Version{}, false
+	}
+
+	p := pep440Version{post: -1, dev: -1, local: strings.ToLower(m[10])}
+	p.epoch, _ = strconv.Atoi(m[1])
+	for _, part := range strings.Split(m[2], ".") {
+		n, _ := strconv.Atoi(part)
+		p.release = append(p.release, n)
+	}
+
+	if m[3] != "" {
+		p.hasPre = true
+		switch strings.ToLower(m[3]) {
+		case "a", "alpha":
+			p.pre[0] = 0
+		case "b", "beta":
+			p.pre[0] = 1
+		default: // c, rc, pre, preview
+			p.pre[0] = 2
+		}
+		p.pre[1], _ = strconv.Atoi(m[4])
+	}
+	switch {
+	case m[5] != "":
+		p.post, _ = strconv.Atoi(m[5])
+	case m[6] != "":
+		p.post, _ = strconv.Atoi(m[7])
+	}
+	if m[8] != "" {
+		p.dev, _ = strconv.Atoi(m[9])
+	}
+	return p, true
+}
+
+// sortKey returns the pre-release, post-release and dev-release keys in
+// PEP 440 order: X.devN < X.aN < X.bN < X.rcN < X < X.postN
+func (p pep440Version) sortKey() [4]int {
+	var key [4]int
+	switch {
+	case p.hasPre:
+		key[0], key[1] = p.pre[0], p.pre[1]
+	case p.post < 0 && p.dev >= 0:
+		key[0] = -1 // Bare dev releases sort before all pre-releases
+	default:
+		key[0] = 3 // Final release
+	}
+	key[2] = p.post
+	key[3] = math.MaxInt32
+	if p.dev >= 0 {
+		key[3] = p.dev
+	}
+	return key
+}
+
+// comparePEP440 compares Python package versions following PEP 440
+func comparePEP440(a, b string) int {
+	pa, okA := parsePEP440(a)
+	pb, okB := parsePEP440(b)
+	if !okA || !okB {
+		return compareLooseVersions(a, b)
+	}
+
+	if c := compareInts(pa.epoch, pb.epoch); c != 0 {
+		return c
+	}
+	if c := compareIntSlices(pa.release, pb.release); c != 0 {
+		return c
+	}
+	ka, kb := pa.sortKey(), pb.sortKey()
+	for i := range ka {
+		if c := compareInts(ka[i], kb[i]); c != 0 {
+			return c
+		}
+	}
+
+	// A local version sorts after the same public version
+	switch {
+	case pa.local == pb.local:
+		return 0
+	case pa.local == "":
+		return -1
+	case pb.local == "":
+		return 1
+	}
+	return strings.Compare(pa.local, pb.local)
+}
+
+var gemSegmentPattern = regexp.MustCompile(`[0-9]+|[a-zA-Z]+`)
+
+// compareGemVersions compares RubyGems versions like Gem::Version: segments
+// are split at dots and digit/letter boundaries, letters mark a prerelease
+// (sorting below numbers) and missing segments count as zero.
+func compareGemVersions(a, b string) int {
+	segments := func(v string) []string {
+		v = strings.TrimSpace(v)
+		v = strings.ReplaceAll(v, "-", ".pre.")
+		return gemSegmentPattern.FindAllString(v, -1)
+	}
+	sa, sb := segments(a), segments(b)
+
+	for i := 0; i < len(sa) || i < len(sb); i++ {
+		x, y := "0", "0"
+		if i < len(sa) {
+			x = sa[i]
+		}
+		if i < len(sb) {
+			y = sb[i]
+		}
+		nx, errX := strconv.Atoi(x)
+		ny, errY := strconv.Atoi(y)
+		switch {
+		case errX == nil && errY == nil:
+			if c := compareInts(nx, ny); c != 0 {
+				return c
+			}
+		case errX == nil:
+			return 1
+		case errY == nil:
+			return -1
+		default:
+			if c := strings.Compare(x, y); c != 0 {
+				return c
+			}
+		}
+	}
+	return 0
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// compareIntSlices compares numeric components, padding the shorter with zeros
+func compareIntSlices(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if c := compareInts(x, y); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+var exactVersionPattern = regexp.MustCompile(`^v?\d+(\.\d+)*[0-9A-Za-z.\-+_!]*$`)
+
+// isExactVersion reports whether a version string pins a single version
+// rather than a constraint such as "^1.2", ">=2,<3" or "1.x".
+func isExactVersion(v string) bool {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "==")
+	if !exactVersionPattern.MatchString(v) {
+		return false
+	}
+	return !strings.Contains(v, ".x") && !strings.Contains(v, ".X") && !strings.Contains(v, "*")
+}
+
+// constraintBaseVersion returns the lowest version allowed by a constraint,
+// which is the version checked when no lock file pins the dependency.
+// It returns "" when the constraint has no lower bound.
+//
+// Examples:
+//
+//	"^1.2.3"     -> "1.2.3"
+//	">=2.28,<3"  -> "2.28"
+//	"~> 7.0"     -> "7.0"
+//	"1.2.x"      -> "1.2"
+//	"<2.0", "*"  -> ""
+func constraintBaseVersion(constraint string) string {
+	c := strings.TrimSpace(constraint)
+	if isExactVersion(c) {
+		return strings.TrimPrefix(c, "==")
+	}
+
+	// Only the first clause of a compound constraint is considered
+	if idx := strings.IndexAny(c, ",|"); idx >= 0 {
+		c = strings.TrimSpace(c[:idx])
+	}
+	if strings.HasPrefix(c, "<") || strings.HasPrefix(c, "!=") {
+		return ""
+	}
+	c = strings.TrimLeft(c, "^~=>! v")
+	if idx := strings.IndexAny(c, " )"); idx >= 0 {
+		c = c[:idx]
+	}
+	for _, wildcard := range []string{".x", ".X", ".*"} {
+		if idx := strings.Index(c, wildcard); idx >= 0 {
+			c = c[:idx]
+		}
+	}
+	if c == "" || c == "*" || !exactVersionPattern.MatchString(c) {
+		return ""
+	}
+	return c
+}
+
+// osvMatchingAffected returns the affected entries of a vulnerability that
+// describe the given package
+func osvMatchingAffected(v osvVuln, ecosystem, name string) []osvAffected {
+	key := vulnDBPackageKey(ecosystem, name)
+	var matches []osvAffected
+	for _, a := range v.Affected {
+		if a.Package.Ecosystem == ecosystem && vulnDBPackageKey(ecosystem, a.Package.Name) == key {
+			matches = append(matches, a)
+		}
+	}
+	return matches
+}
+
+// osvAffects reports whether a vulnerability affects the given package
+// version, using the explicit version list and SEMVER/ECOSYSTEM ranges of
+// the matching affected entries. known is false when the record has nothing
+// that can be evaluated locally: GIT ranges need the repository itself.
+func osvAffects(v osvVuln, ecosystem, name, version string) (affected, known bool) {
+	for _, a := range osvMatchingAffected(v, ecosystem, name) {
+		for _, listed := range a.Versions {
+			known = true
+			if listed == version || compareVersions(ecosystem, listed, version) == 0 {
+				return true, true
+			}
+		}
+		for _, r := range a.Ranges {
+			if r.Type == "GIT" {
+				continue
+			}
+			known = true
+			if versionInOSVRange(version, r.Events, osvRangeComparator(r.Type, ecosystem)) {
+				return true, true
+			}
+		}
+	}
+	return false, known
+}
+
+// osvFixedVersion returns the release that fixes the range containing
+// version: the smallest fixed event above it. Without a version the
+// highest fixed event of the package is returned.
+func osvFixedVersion(v osvVuln, ecosystem, name, version string) string {
+	best := ""
+	for _, a := range osvMatchingAffected(v, ecosystem, name) {
+		for _, r := range a.Ranges {
+			if r.Type == "GIT" {
+				continue
+			}
+			cmp := osvRangeComparator(r.Type, ecosystem)
+			if version != "" && !versionInOSVRange(version, r.Events, cmp) {
+				continue
+			}
+			for _, e := range r.Events {
+				switch {
+				case e.Fixed == "":
+				case version == "":
+					if best == "" || cmp(e.Fixed, best) > 0 {
+						best = e.Fixed
+					}
+				case cmp(e.Fixed, version) > 0 && (best == "" || cmp(e.Fixed, best) < 0):
+					best = e.Fixed
+				}
+			}
+		}
+	}
+	return best
+}
+
+// osvSafeUpgrade returns the smallest fixed release above version that none
+// of the vulnerabilities affect, or "" when no such release is known.
+func osvSafeUpgrade(vulns []osvVuln, ecosystem, name, version string) string {
+	var candidates []string
+	for _, v := range vulns {
+		for _, a := range osvMatchingAffected(v, ecosystem, name) {
+			for _, r := range a.Ranges {
+				for _, e := range r.Events {
+					if e.Fixed != "" && r.Type != "GIT" && (version == "" || compareVersions(ecosystem, e.Fixed, version) > 0) {
+						candidates = append(candidates, e.Fixed)
+					}
+				}
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return compareVersions(ecosystem, candidates[i], candidates[j]) < 0
+	})
+
+	for _, candidate := range candidates {
+		safe := true
+		for _, v := range vulns {
+			if affected, _ := osvAffects(v, ecosystem, name, candidate); affected {
+				safe = false
+				break
+			}
+		}
+		if safe {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// osvRangeComparator returns the version ordering for an OSV range type.
+// SEMVER ranges always use Semantic Versioning; ECOSYSTEM ranges use the
+// ecosystem's own scheme. "0" is the lowest version in both.
+func osvRangeComparator(rangeType, ecosystem string) func(a, b string) int {
+	return func(a, b string) int {
+		switch {
+		case a == b:
+			return 0
+		case a == "0":
+			return -1
+		case b == "0":
+			return 1
+		case rangeType == "SEMVER":
+			return compareSemver(a, b)
+		}
+		return compareVersions(ecosystem, a, b)
+	}
+}
+
+// versionInOSVRange evaluates OSV range events: events are sorted by
+// version and replayed, toggling the affected state at each boundary.
+func versionInOSVRange(version string, events []osvEvent, cmp func(a, b string) int) bool {
+	type boundary struct {
+		version string
+		kind    string
+	}
+	var bounds []boundary
+	for _, e := range events {
+		switch {
+		case e.Introduced != "":
+			bounds = append(bounds, boundary{e.Introduced, "introduced"})
+		case e.Fixed != "":
+			bounds = append(bounds, boundary{e.Fixed, "fixed"})
+		case e.LastAffected != "":
+			bounds = append(bounds, boundary{e.LastAffected, "last_affected"})
+		case e.Limit != "":
+			bounds = append(bounds, boundary{e.Limit, "limit"})
+		}
+	}
+	sort.SliceStable(bounds, func(i, j int) bool {
+		return cmp(bounds[i].version, bounds[j].version) < 0
+	})
+
+	affected := false
+	for _, b := range bounds {
+		c := cmp(version, b.version)
+		switch b.kind {
+		case "introduced":
+			if c >= 0 {
+				affected = true
+			}
+		case "fixed", "limit":
+			if c >= 0 {
+				affected = false
+			}
+		case "last_affected":
+			if c > 0 {
+				affected = false
+			}
+		}
+	}
+	return affected
+}
@@ -0,0 +1,164 @@
+package analyzer
+
+import "testing"
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		ecosystem string
+		a, b      string
+		want      int
+	}{
+		// Semantic Versioning
+		{"npm", "1.2.3", "1.2.10", -1},
+		{"npm", "1.0.0-alpha", "1.0.0", -1},
+		{"npm", "1.0.0-alpha.1", "1.0.0-alpha.beta", -1},
+		{"npm", "1.0.0-beta.2", "1.0.0-beta.11", -1},
+		{"npm", "1.0.0+build.5", "1.0.0", 0},
+		{"crates.io", "0.10.0", "0.9.9", 1},
+		{"NuGet", "4.0.0.1", "4.0.0", 1},
+
+		// Go, including pseudo-versions and +incompatible
+		{"Go", "v1.9.1", "1.9.1", 0},
+		{"Go", "v0.0.0-20191109021931-daa7c04131f5", "v0.0.0-20210101000000-abcdefabcdef", -1},
+		{"Go", "v1.2.4-0.20191109021931-daa7c04131f5", "v1.2.3", 1},
+		{"Go", "v1.2.4-0.20191109021931-daa7c04131f5", "v1.2.4", -1},
+		{"Go", "v2.0.0+incompatible", "v2.0.0", 0},
+
+		// PEP 440
+		{"PyPI", "1.0.dev0", "1.0a1", -1},
+		{"PyPI", "1.0a1", "1.0b1", -1},
+		{"PyPI", "1.0rc1", "1.0", -1},
+		{"PyPI", "1.0", "1.0.post1", -1},
+		{"PyPI", "1.0.post1.dev1", "1.0.post1", -1},
+		{"PyPI", "1.0", "1.0.0", 0},
+		{"PyPI", "1!0.1", "2.0", 1},
+		{"PyPI", "2.0-1", "2.0.post1", 0},
+		{"PyPI", "1.0+local", "1.0", 1},
+		{"PyPI", "4.2.10", "4.2.9", 1},
+
+		// RubyGems
+		{"RubyGems", "1.0.0.pre1", "1.0.0", -1},
+		{"RubyGems", "1.0.0-rc1", "1.0.0", -1},
+		{"RubyGems", "1.0.a", "1.0.b", -1},
+		{"RubyGems", "1.10", "1.9", 1},
+		{"RubyGems", "2.0", "2", 0},
+
+		// Unknown ecosystems use the loose comparison
+		{"Maven", "2.10", "2.9", 1},
+	}
+
+	for _, tt := range tests {
+		if got := compareVersions(tt.ecosystem, tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%s, %q, %q) = %d, want %d", tt.ecosystem, tt.a, tt.b, got, tt.want)
+		}
+		if got := compareVersions(tt.ecosystem, tt.b, tt.a); got != -tt.want {
+			t.Errorf("compareVersions(%s, %q, %q) = %d, want %d", tt.ecosystem, tt.b, tt.a, got, -tt.want)
+		}
+	}
+}
+
+func TestConstraintBaseVersion(t *testing.T) {
+	tests := map[string]string{
+		"1.2.3":      "1.2.3",
+		"==2.31.0":   "2.31.0",
+		"^1.2.3":     "1.2.3",
+		"~1.2":       "1.2",
+		">=2.28,<3":  "2.28",
+		"~> 7.0":     "7.0",
+		"1.2.x":      "1.2",
+		">= 1.0 < 2": "1.0",
+		"<2.0":       "",
+		"*":          "",
+		"latest":     "",
+	}
+	for in, want := range tests {
+		if got := constraintBaseVersion(in); got != want {
+			t.Errorf("constraintBaseVersion(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestVersionInOSVRange(t *testing.T) {
+	events := []osvEvent{
+		{Introduced: "1.0.0"}, {Fixed: "1.2.0"},
+		{Introduced: "2.0.0"}, {LastAffected: "2.1.0"},
+	}
+	tests := map[string]bool{
+		"0.9.0":        false,
+		"1.0.0":        true,
+		"1.1.9":        true,
+		"1.2.0-beta.1": true,
+		"1.2.0":        false,
+		"2.1.0":        true,
+		"2.1.1":        false,
+	}
+	cmp := osvRangeComparator("SEMVER", "npm")
+	for version, want := range tests {
+		if got := versionInOSVRange(version, events, cmp); got != want {
+			t.Errorf("versionInOSVRange(%s) = %v, want %v", version, got, want)
+		}
+	}
+}
+
+func TestOSVFixedVersionAndSafeUpgrade(t *testing.T) {
+	// Two fixed branches: 1.x fixed in 1.4.2, 2.x fixed in 2.0.5
+	branches := osvRecord("PYSEC-A", "PyPI", "Django",
+		osvEvent{Introduced: "0"}, osvEvent{Fixed: "1.4.2"},
+		osvEvent{Introduced: "2.0"}, osvEvent{Fixed: "2.0.5"})
+	// A later issue affecting 2.0.5 but fixed in 2.0.7
+	later := osvRecord("PYSEC-B", "PyPI", "django",
+		osvEvent{Introduced: "2.0.3"}, osvEvent{Fixed: "2.0.7"})
+
+	if got := osvFixedVersion(branches, "PyPI", "django", "1.4.1"); got != "1.4.2" {
+		t.Errorf("FixedIn for 1.4.1 = %q, want 1.4.2 (the range it falls in)", got)
+	}
+	if got := osvFixedVersion(branches, "PyPI", "django", "2.0.1"); got != "2.0.5" {
+		t.Errorf("FixedIn for 2.0.1 = %q, want 2.0.5", got)
+	}
+	if got := osvFixedVersion(branches, "PyPI", "django", ""); got != "2.0.5" {
+		t.Errorf("FixedIn without a version = %q, want highest fix 2.0.5", got)
+	}
+
+	if affected, known := osvAffects(branches, "PyPI", "Django", "1.4.2"); affected || !known {
+		t.Errorf("osvAffects(1.4.2) = %v, %v, want false, true", affected, known)
+	}
+	if affected, known := osvAffects(branches, "PyPI", "Django", "2.0rc1"); affected || !known {
+		t.Errorf("osvAffects(2.0rc1) = %v, %v, want false (pre-release precedes 2.0)", affected, known)
+	}
+
+	vulns := []osvVuln{branches, later}
+	if got := osvSafeUpgrade(vulns, "PyPI", "django", "2.0.4"); got != "2.0.7" {
+		t.Errorf("osvSafeUpgrade(2.0.4) = %q, want 2.0.7 (2.0.5 is still affected by PYSEC-B)", got)
+	}
+	unfixed := osvRecord("PYSEC-C", "PyPI", "django", osvEvent{Introduced: "0"})
+	if got := osvSafeUpgrade(append(vulns, unfixed), "PyPI", "django", "2.0.4"); got != "" {
+		t.Errorf("osvSafeUpgrade with an unfixed vulnerability = %q, want empty", got)
+	}
+}
+
+func TestOSVAffects_GitRangesUnknown(t *testing.T) {
+	v := osvVuln{ID: "OSV-GIT"}
+	a := osvAffected{Ranges: []osvRange{{Type: "GIT", Events: []osvEvent{{Introduced: "abc123"}}}}}
+	a.Package.Ecosystem = "npm"
+	a.Package.Name = "left-pad"
+	v.Affected = []osvAffected{a}
+
+	if _, known := osvAffects(v, "npm", "left-pad", "1.0.0"); known {
+		t.Error("osvAffects() known = true for a record with only GIT ranges")
+	}
+}
+
+func TestCanonicalSemver(t *testing.T) {
+	for v, want := range map[string]string{
+		"1.2":          "1.2.0",
+		"v1.2.0":       "1.2.0",
+		"1.2.0+build5": "1.2.0",
+		"2.0-rc.1":     "2.0.0-rc.1",
+		"1.2.3.0":      "1.2.3",
+		"nightly":      "nightly",
+	} {
+		if got := canonicalSemver(v); got != want {
+			t.Errorf("canonicalSemver(%q) = %q, want %q", v, got, want)
+		}
+	}
+}
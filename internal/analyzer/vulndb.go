@@ -0,0 +1,299 @@
+// Package analyzer provides analysis functions for GitHub repositories.
+// This file implements the offline vulnerability database built from
+// OSV's per-ecosystem zip exports, for scanning on air-gapped machines.
+//
+// Database Structure:
+//
+//	~/.repo-lyzer/vulndb/
+//	├── index.json        # Import metadata and per-ecosystem counts
+//	├── npm.json.gz       # Vulnerabilities keyed by package name
+//	└── PyPI.json.gz
+package analyzer
+
+import (
+	"archive/zip"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// VulnDBIndex describes the contents of the offline vulnerability database.
+type VulnDBIndex struct {
+	UpdatedAt  time.Time                  `json:"updated_at"`
+	Source     string                     `json:"source"` // Directory the exports were imported from
+	Ecosystems map[string]VulnDBEcosystem `json:"ecosystems"`
+}
+
+// VulnDBEcosystem holds import statistics for one ecosystem.
+type VulnDBEcosystem struct {
+	Vulnerabilities int    `json:"vulnerabilities"`
+	Packages        int    `json:"packages"`
+	File            string `json:"file"`
+}
+
+// VulnDB is an opened offline vulnerability database. Ecosystem files are
+// loaded lazily on first use.
+type VulnDB struct {
+	dir    string
+	Index  VulnDBIndex
+	loaded map[string]map[string][]osvVuln // ecosystem -> package -> vulns
+}
+
+// DefaultVulnDBDir returns the default database location, ~/.repo-lyzer/vulndb
+func DefaultVulnDBDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".repo-lyzer", "vulndb"), nil
+}
+
+// OpenVulnDB opens the offline vulnerability database in dir, or in the
+// default location when dir is empty.
+func OpenVulnDB(dir string) (*VulnDB, error) {
+	if dir == "" {
+		var err error
+		if dir, err = DefaultVulnDBDir(); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "index.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("offline vulnerability database not found in %s: run 'repo-lyzer vulndb update --from <dir>' first", dir)
+		}
+		return nil, err
+	}
+
+	db := &VulnDB{dir: dir, loaded: make(map[string]map[string][]osvVuln)}
+	if err := json.Unmarshal(data, &db.Index); err != nil {
+		return nil, fmt.Errorf("corrupt vulnerability database index: %w", err)
+	}
+	return db, nil
+}
+
+// ImportOSVExports builds the offline database in dbDir from OSV zip
+// exports found anywhere under srcDir (for example npm/all.zip and
+// PyPI/all.zip as downloaded from osv-vulnerabilities.storage.googleapis.com).
+// The previous index in dbDir is replaced.
+func ImportOSVExports(srcDir, dbDir string) (*VulnDBIndex, error) {
+	if dbDir == "" {
+		var err error
+		if dbDir, err = DefaultVulnDBDir(); err != nil {
+			return nil, err
+		}
+	}
+
+	var zips []string
+	err := filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && strings.EqualFold(filepath.Ext(path), ".zip") {
+			zips = append(zips, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(zips) == 0 {
+		return nil, fmt.Errorf("no OSV zip exports found in %s", srcDir)
+	}
+
+	// ecosystem -> package -> vulns
+	entries := make(map[string]map[string][]osvVuln)
+	for _, path := range zips {
+		if err := readOSVZip(path, entries); err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+	}
+
+	if err := os.MkdirAll(dbDir, 0755); err != nil {
+		return nil, err
+	}
+
+	index := &VulnDBIndex{
+		UpdatedAt:  time.Now(),
+		Source:     srcDir,
+		Ecosystems: make(map[string]VulnDBEcosystem),
+	}
+	for ecosystem, pkgs := range entries {
+		file := vulnDBFilename(ecosystem)
+		if err := writeGzipJSON(filepath.Join(dbDir, file), pkgs); err != nil {
+			return nil, err
+		}
+
+		ids := make(map[string]bool)
+		for _, vulns := range pkgs {
+			for _, v := range vulns {
+				ids[v.ID] = true
+			}
+		}
+		index.Ecosystems[ecosystem] = VulnDBEcosystem{Vulnerabilities: len(ids), Packages: len(pkgs), File: file}
+	}
+
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dbDir, "index.json"), data, 0644); err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+// readOSVZip adds every non-withdrawn record in an OSV zip export to entries
+func readOSVZip(path string, entries map[string]map[string][]osvVuln) error {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		if !strings.HasSuffix(f.Name, ".json") {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		var v osvVuln
+		err = json.NewDecoder(rc).Decode(&v)
+		rc.Close()
+		if err != nil || v.ID == "" || v.Withdrawn != "" {
+			continue // Skip malformed and withdrawn records
+		}
+
+		added := make(map[string]bool)
+		for _, a := range v.Affected {
+			eco, name := a.Package.Ecosystem, a.Package.Name
+			if eco == "" || name == "" {
+				continue
+			}
+			pkgKey := vulnDBPackageKey(eco, name)
+			if added[eco+"|"+pkgKey] {
+				continue // Several affected entries for the same package
+			}
+			added[eco+"|"+pkgKey] = true
+
+			if entries[eco] == nil {
+				entries[eco] = make(map[string][]osvVuln)
+			}
+			entries[eco][pkgKey] = append(entries[eco][pkgKey], v)
+		}
+	}
+	return nil
+}
+
+// vulnDBPackageKey normalizes package names that are case-insensitive
+func vulnDBPackageKey(ecosystem, name string) string {
+	switch ecosystem {
+	case "PyPI":
+		return normalizePythonName(name)
+	case "npm", "Packagist", "NuGet", "RubyGems", "crates.io", "Hex", "Pub":
+		return strings.ToLower(name)
+	}
+	return name
+}
+
+// vulnDBFilename maps an ecosystem such as "Debian:11" to a safe file name
+func vulnDBFilename(ecosystem string) string {
+	safe := strings.NewReplacer(":", "_", "/", "_", " ", "_").Replace(ecosystem)
+	return safe + ".json.gz"
+}
+
+func writeGzipJSON(path string, v interface{}) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(f)
+	if err := json.NewEncoder(gz).Encode(v); err != nil {
+		gz.Close()
+		f.Close()
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ecosystem loads the package map for an ecosystem, or nil if not imported
+func (db *VulnDB) ecosystem(name string) (map[string][]osvVuln, error) {
+	if pkgs, ok := db.loaded[name]; ok {
+		return pkgs, nil
+	}
+	meta, ok := db.Index.Ecosystems[name]
+	if !ok {
+		db.loaded[name] = nil
+		return nil, nil
+	}
+
+	f, err := os.Open(filepath.Join(db.dir, meta.File))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	var pkgs map[string][]osvVuln
+	if err := json.NewDecoder(gz).Decode(&pkgs); err != nil {
+		return nil, err
+	}
+	db.loaded[name] = pkgs
+	return pkgs, nil
+}
+
+// Ecosystems returns the imported ecosystem names, sorted
+func (db *VulnDB) Ecosystems() []string {
+	names := make([]string, 0, len(db.Index.Ecosystems))
+	for name := range db.Index.Ecosystems {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// query returns every vulnerability recorded for a package. Whether the
+// scanned version is affected is decided by the caller, as for online scans.
+func (db *VulnDB) query(ecosystem, name string) ([]osvVuln, error) {
+	pkgs, err := db.ecosystem(ecosystem)
+	if err != nil || pkgs == nil {
+		return nil, err
+	}
+	return pkgs[vulnDBPackageKey(ecosystem, name)], nil
+}
+
+// scan checks every target against the database. Ecosystems missing from
+// the database are reported as failures so gaps are visible.
+func (db *VulnDB) scan(targets []scanTarget) ([][]osvVuln, []ScanFailure) {
+	found := make([][]osvVuln, len(targets))
+	var failures []ScanFailure
+	for i, t := range targets {
+		if _, ok := db.Index.Ecosystems[t.ecosystem]; !ok {
+			failures = append(failures, ScanFailure{Package: t.name, Version: t.version, Ecosystem: t.ecosystem, Error: "ecosystem not in offline database"})
+			continue
+		}
+		vulns, err := db.query(t.ecosystem, t.name)
+		if err != nil {
+			failures = append(failures, ScanFailure{Package: t.name, Version: t.version, Ecosystem: t.ecosystem, Error: err.Error()})
+			continue
+		}
+		found[i] = vulns
+	}
+	return found, failures
+}
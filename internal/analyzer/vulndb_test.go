@@ -0,0 +1,109 @@
+package analyzer
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeOSVZip creates an OSV export zip with the given records
+func writeOSVZip(t *testing.T, path string, vulns ...osvVuln) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	for _, v := range vulns {
+		w, err := zw.Create(v.ID + ".json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		json.NewEncoder(w).Encode(v)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func osvRecord(id, ecosystem, name string, events ...osvEvent) osvVuln {
+	v := osvVuln{ID: id, Summary: id + " summary"}
+	a := osvAffected{Ranges: []osvRange{{Type: "ECOSYSTEM", Events: events}}}
+	a.Package.Ecosystem = ecosystem
+	a.Package.Name = name
+	v.Affected = []osvAffected{a}
+	return v
+}
+
+func TestImportOSVExportsAndScan(t *testing.T) {
+	src := t.TempDir()
+	dbDir := filepath.Join(t.TempDir(), "vulndb")
+
+	writeOSVZip(t, filepath.Join(src, "npm", "all.zip"),
+		osvRecord("GHSA-lodash", "npm", "lodash", osvEvent{Introduced: "0"}, osvEvent{Fixed: "4.17.21"}),
+		osvRecord("GHSA-old", "npm", "lodash", osvEvent{Introduced: "1.0.0"}, osvEvent{LastAffected: "2.4.2"}),
+	)
+	withdrawn := osvRecord("GHSA-withdrawn", "PyPI", "Django", osvEvent{Introduced: "0"})
+	withdrawn.Withdrawn = "2023-01-01T00:00:00Z"
+	writeOSVZip(t, filepath.Join(src, "PyPI.zip"),
+		osvRecord("PYSEC-1", "PyPI", "Django", osvEvent{Introduced: "4.0"}, osvEvent{Fixed: "4.2.7"}),
+		withdrawn,
+	)
+
+	index, err := ImportOSVExports(src, dbDir)
+	if err != nil {
+		t.Fatalf("ImportOSVExports() error = %v", err)
+	}
+	if got := index.Ecosystems["npm"].Vulnerabilities; got != 2 {
+		t.Errorf("npm vulnerabilities = %d, want 2", got)
+	}
+	if got := index.Ecosystems["PyPI"].Vulnerabilities; got != 1 {
+		t.Errorf("PyPI vulnerabilities = %d, want 1 (withdrawn record skipped)", got)
+	}
+
+	deps := &DependencyAnalysis{Files: []DependencyFile{
+		{Filename: "package.json", FileType: "npm", Dependencies: []Dependency{
+			{Name: "lodash", Version: "^4.17.0", Resolved: "4.17.15", Type: "production"},
+		}},
+		{Filename: "requirements.txt", FileType: "python", Dependencies: []Dependency{
+			{Name: "django", Version: "4.2.8", Type: "production"},
+		}},
+		{Filename: "Cargo.toml", FileType: "rust", Dependencies: []Dependency{
+			{Name: "serde", Version: "1.0", Type: "production"},
+		}},
+	}}
+
+	result, err := ScanDependenciesWithOptions(deps, ScanOptions{Offline: true, DBDir: dbDir})
+	if err != nil {
+		t.Fatalf("offline scan error = %v", err)
+	}
+	if result.Source != "offline" {
+		t.Errorf("Source = %q, want offline", result.Source)
+	}
+	if result.TotalCount != 1 || result.Vulnerabilities[0].ID != "GHSA-lodash" {
+		t.Errorf("Vulnerabilities = %+v, want only GHSA-lodash", result.Vulnerabilities)
+	} else if got := result.Vulnerabilities[0].FixedIn; got != "4.17.21" {
+		t.Errorf("FixedIn = %q, want 4.17.21", got)
+	}
+	if len(result.Upgrades) != 1 || result.Upgrades[0].SafeVersion != "4.17.21" || result.Upgrades[0].Current != "4.17.15" {
+		t.Errorf("Upgrades = %+v, want lodash 4.17.15 -> 4.17.21", result.Upgrades)
+	}
+	if len(result.Failures) != 1 || result.Failures[0].Ecosystem != "crates.io" {
+		t.Errorf("Failures = %+v, want crates.io reported as missing", result.Failures)
+	}
+}
+
+func TestOpenVulnDB_Missing(t *testing.T) {
+	_, err := OpenVulnDB(t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "vulndb update") {
+		t.Errorf("OpenVulnDB() error = %v, want hint to run vulndb update", err)
+	}
+}
@@ -0,0 +1,406 @@
+package analyzer
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// WorkflowFinding is a supply-chain risk in a GitHub Actions workflow
+type WorkflowFinding struct {
+	Workflow    string `json:"workflow"` // path of the workflow file
+	Job         string `json:"job,omitempty"`
+	Step        string `json:"step,omitempty"` // step name, or its index when unnamed
+	Line        int    `json:"line,omitempty"`
+	Rule        string `json:"rule"`
+	Severity    string `json:"severity"` // CRITICAL, HIGH, MEDIUM, LOW
+	Description string `json:"description"`
+}
+
+// WorkflowAuditResult holds the findings for all workflows of a repository
+type WorkflowAuditResult struct {
+	Workflows     int               `json:"workflows"`
+	Findings      []WorkflowFinding `json:"findings"`
+	Errors        []string          `json:"errors,omitempty"` // workflows that could not be read or parsed
+	Actions       []string          `json:"actions"`          // remote actions and reusable workflows referenced by uses:
+	CriticalCount int               `json:"critical_count"`
+	HighCount     int               `json:"high_count"`
+	MediumCount   int               `json:"medium_count"`
+	LowCount      int               `json:"low_count"`
+	Truncated     bool              `json:"truncated"`      // more workflows exist than were fetched
+	Skipped       int               `json:"skipped"`        // workflows left out by the file cap
+	Note          string            `json:"note,omitempty"` // explains a partial audit
+}
+
+// Workflow audit rules
+const (
+	RuleUnpinnedAction      = "unpinned-action"
+	RuleMissingPermissions  = "missing-permissions"
+	RuleWriteAllPermissions = "write-all-permissions"
+	RulePRTargetCheckout    = "pull-request-target-checkout"
+	RuleScriptInjection     = "script-injection"
+	RuleSelfHostedRunner    = "self-hosted-runner"
+)
+
+// maxAuditedWorkflows caps how many workflow files are fetched
+const (
+	maxAuditedWorkflows        = 50
+	maxAuditedWorkflowsNoToken = 10
+)
+
+var (
+	fullSHAPattern    = regexp.MustCompile(`^[0-9a-f]{40}$`)
+	expressionPattern = regexp.MustCompile(`\$\{\{\s*([^}]*?)\s*\}\}`)
+	// untrustedContexts are event fields an outside contributor controls
+	untrustedContexts = regexp.MustCompile(`^github\.(head_ref|event\.(issue\.(title|body)|pull_request\.(title|body|head\.(ref|label)|head\.repo\.default_branch)|comment\.body|review\.body|review_comment\.body|pages\.[^ ]*\.page_name|commits\.[^ ]*\.(message|author\.(email|name))|head_commit\.(message|author\.(email|name))|discussion\.(title|body)|workflow_run\.(head_branch|head_commit\.message)))`)
+)
+
+// firstPartyActionOwners publish the actions maintained by GitHub itself
+var firstPartyActionOwners = map[string]bool{"actions": true, "github": true}
+
+var workflowSeverityRank = map[string]int{"CRITICAL": 4, "HIGH": 3, "MEDIUM": 2, "LOW": 1}
+
+// AuditWorkflows fetches .github/workflows/*.yml and checks them for
+// supply-chain risks. public enables the self-hosted runner check, which
+// only matters when anyone can open a pull request.
+func AuditWorkflows(client *github.Client, owner, repo string, fileTree []github.TreeEntry, public bool) *WorkflowAuditResult {
+	fetch := contentFetcher(client, owner, repo)
+	result := auditWorkflows(fileTree, fetch, public, fileLimit(client, maxAuditedWorkflows, maxAuditedWorkflowsNoToken))
+	result.Note = skippedNote(client, result.Skipped, "workflows")
+	return result
+}
+
+func auditWorkflows(fileTree []github.TreeEntry, fetch func(string) (string, bool), public bool, maxFiles int) *WorkflowAuditResult {
+	result := &WorkflowAuditResult{Findings: []WorkflowFinding{}, Actions: []string{}}
+
+	var files []string
+	for _, entry := range fileTree {
+		if entry.Type == "blob" && isWorkflowFile(entry.Path) {
+			files = append(files, entry.Path)
+		}
+	}
+	sort.Strings(files)
+	if len(files) > maxFiles {
+		result.Truncated = true
+		result.Skipped = len(files) - maxFiles
+		files = files[:maxFiles]
+	}
+
+	for _, file := range files {
+		content, ok := fetch(file)
+		if !ok {
+			result.Errors = append(result.Errors, file+": could not be fetched")
+			continue
+		}
+		findings, actions, err := auditWorkflow(file, content, public)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", file, err))
+			continue
+		}
+		result.Workflows++
+		result.Findings = append(result.Findings, findings...)
+		for _, a := range actions {
+			if !contains(result.Actions, a) {
+				result.Actions = append(result.Actions, a)
+			}
+		}
+	}
+	sort.Strings(result.Actions)
+
+	sort.SliceStable(result.Findings, func(i, j int) bool {
+		a, b := result.Findings[i], result.Findings[j]
+		if workflowSeverityRank[a.Severity] != workflowSeverityRank[b.Severity] {
+			return workflowSeverityRank[a.Severity] > workflowSeverityRank[b.Severity]
+		}
+		if a.Workflow != b.Workflow {
+			return a.Workflow < b.Workflow
+		}
+		return a.Line < b.Line
+	})
+	for _, f := range result.Findings {
+		switch f.Severity {
+		case "CRITICAL":
+			result.CriticalCount++
+		case "HIGH":
+			result.HighCount++
+		case "MEDIUM":
+			result.MediumCount++
+		default:
+			result.LowCount++
+		}
+	}
+	return result
+}
+
+// isWorkflowFile matches top-level YAML files in .github/workflows
+func isWorkflowFile(p string) bool {
+	if path.Dir(p) != ".github/workflows" {
+		return false
+	}
+	ext := strings.ToLower(path.Ext(p))
+	return ext == ".yml" || ext == ".yaml"
+}
+
+// auditWorkflow checks a single workflow file and returns its findings and
+// the remote actions it uses
+func auditWorkflow(file, content string, public bool) ([]WorkflowFinding, []string, error) {
+	doc, err := parseYAML([]byte(content))
+	if err != nil {
+		return nil, nil, err
+	}
+	root := yamlMap(doc)
+	if root == nil {
+		return nil, nil, fmt.Errorf("expected a mapping at the top level")
+	}
+
+	var findings []WorkflowFinding
+	var actions []string
+	// A needle repeated in a job is looked up after its previous match, so
+	// each finding points at its own occurrence
+	next := make(map[string]int)
+	add := func(f WorkflowFinding, needle string) {
+		f.Workflow = file
+		key := f.Rule + "\x00" + f.Job + "\x00" + needle
+		offset, ok := next[key]
+		if !ok {
+			offset = jobOffset(content, f.Job)
+		}
+		if i := indexFrom(content, needle, offset); i >= 0 {
+			f.Line = strings.Count(content[:i], "\n") + 1
+			next[key] = i + len(needle)
+		}
+		findings = append(findings, f)
+	}
+
+	jobs := yamlMap(root, "jobs")
+	jobNames := make([]string, 0, len(jobs))
+	for name := range jobs {
+		jobNames = append(jobNames, name)
+	}
+	sort.Strings(jobNames)
+
+	// Top-level permissions scope the GITHUB_TOKEN for every job
+	switch perms := root["permissions"].(type) {
+	case nil:
+		allScoped := len(jobNames) > 0
+		for _, name := range jobNames {
+			if _, ok := yamlMap(jobs, name)["permissions"]; !ok {
+				allScoped = false
+			}
+		}
+		severity := "MEDIUM"
+		if allScoped {
+			severity = "LOW"
+		}
+		add(WorkflowFinding{
+			Rule:        RuleMissingPermissions,
+			Severity:    severity,
+			Description: "No top-level permissions: the GITHUB_TOKEN gets the repository's default scopes",
+		}, "")
+	case string:
+		if perms == "write-all" {
+			add(WorkflowFinding{
+				Rule:        RuleWriteAllPermissions,
+				Severity:    "HIGH",
+				Description: "permissions: write-all grants the GITHUB_TOKEN write access to everything",
+			}, "write-all")
+		}
+	}
+
+	prTarget := contains(workflowTriggers(root["on"]), "pull_request_target")
+
+	for _, jobName := range jobNames {
+		job := yamlMap(jobs, jobName)
+
+		if public && isSelfHosted(job["runs-on"]) {
+			add(WorkflowFinding{
+				Job:         jobName,
+				Rule:        RuleSelfHostedRunner,
+				Severity:    "HIGH",
+				Description: "Self-hosted runner in a public repository can run code from untrusted pull requests",
+			}, "self-hosted")
+		}
+
+		// Reusable workflow calls are pinned like actions
+		if uses := yamlString(job, "uses"); uses != "" {
+			actions = append(actions, uses)
+			if f, ok := checkActionPin(uses); ok {
+				f.Job = jobName
+				add(f, uses)
+			}
+		}
+
+		steps, _ := job["steps"].([]interface{})
+		for i, item := range steps {
+			step := yamlMap(item)
+			if step == nil {
+				continue
+			}
+			stepName := yamlString(step, "name")
+			if stepName == "" {
+				stepName = fmt.Sprintf("#%d", i+1)
+			}
+
+			uses := yamlString(step, "uses")
+			if uses != "" && !strings.HasPrefix(uses, "./") {
+				actions = append(actions, uses)
+			}
+			if uses != "" {
+				if f, ok := checkActionPin(uses); ok {
+					f.Job, f.Step = jobName, stepName
+					add(f, uses)
+				}
+			}
+
+			if prTarget && strings.HasPrefix(uses, "actions/checkout@") {
+				if ref := yamlString(yamlMap(step, "with"), "ref"); checksOutPRHead(ref) {
+					add(WorkflowFinding{
+						Job:         jobName,
+						Step:        stepName,
+						Rule:        RulePRTargetCheckout,
+						Severity:    "CRITICAL",
+						Description: "pull_request_target checks out the pull request head, running untrusted code with a privileged token",
+					}, ref)
+				}
+			}
+
+			scripts := []string{yamlString(step, "run")}
+			if strings.HasPrefix(uses, "actions/github-script@") {
+				scripts = append(scripts, yamlString(yamlMap(step, "with"), "script"))
+			}
+			for _, script := range scripts {
+				for _, m := range expressionPattern.FindAllStringSubmatch(script, -1) {
+					expr := m[1]
+					if !strings.HasPrefix(expr, "github.event.") && expr != "github.head_ref" {
+						continue
+					}
+					severity := "MEDIUM"
+					if untrustedContexts.MatchString(expr) {
+						severity = "HIGH"
+					}
+					add(WorkflowFinding{
+						Job:         jobName,
+						Step:        stepName,
+						Rule:        RuleScriptInjection,
+						Severity:    severity,
+						Description: fmt.Sprintf("${{ %s }} is expanded into a script; pass it through an environment variable instead", expr),
+					}, m[0])
+				}
+			}
+		}
+	}
+
+	return findings, actions, nil
+}
+
+// checkActionPin flags third-party actions and reusable workflows whose
+// ref is not a full commit SHA. Local actions and GitHub's own actions are
+// not flagged.
+func checkActionPin(uses string) (WorkflowFinding, bool) {
+	if strings.HasPrefix(uses, "./") {
+		return WorkflowFinding{}, false
+	}
+	if strings.HasPrefix(uses, "docker://") {
+		if strings.Contains(uses, "@sha256:") {
+			return WorkflowFinding{}, false
+		}
+		return WorkflowFinding{
+			Rule:        RuleUnpinnedAction,
+			Severity:    "MEDIUM",
+			Description: uses + " is not pinned to an image digest",
+		}, true
+	}
+
+	name, ref, _ := strings.Cut(uses, "@")
+	if firstPartyActionOwners[strings.SplitN(name, "/", 2)[0]] || fullSHAPattern.MatchString(ref) {
+		return WorkflowFinding{}, false
+	}
+	return WorkflowFinding{
+		Rule:        RuleUnpinnedAction,
+		Severity:    "MEDIUM",
+		Description: fmt.Sprintf("%s is pinned to %q instead of a full commit SHA", name, ref),
+	}, true
+}
+
+// workflowTriggers lists the events of an on: value, which may be a
+// string, a sequence or a mapping
+func workflowTriggers(on interface{}) []string {
+	switch v := on.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var events []string
+		for _, e := range v {
+			if s, ok := e.(string); ok {
+				events = append(events, s)
+			}
+		}
+		return events
+	case map[string]interface{}:
+		events := make([]string, 0, len(v))
+		for e := range v {
+			events = append(events, e)
+		}
+		return events
+	}
+	return nil
+}
+
+// checksOutPRHead reports whether a checkout ref points at the pull
+// request's code rather than the base branch. The merge commit and the
+// refs/pull/N/merge ref contain that code too.
+func checksOutPRHead(ref string) bool {
+	for _, head := range []string{"github.event.pull_request.head", "github.event.pull_request.merge_commit_sha", "github.head_ref", "refs/pull/"} {
+		if strings.Contains(ref, head) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSelfHosted reports whether a runs-on value selects a self-hosted runner
+func isSelfHosted(runsOn interface{}) bool {
+	switch v := runsOn.(type) {
+	case string:
+		return v == "self-hosted"
+	case []interface{}:
+		for _, label := range v {
+			if label == "self-hosted" {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		return contains(yamlStrings(v, "labels"), "self-hosted")
+	}
+	return false
+}
+
+// indexFrom returns the byte offset of the first occurrence of needle at or
+// after offset, or -1 when it can't be found
+func indexFrom(content, needle string, offset int) int {
+	if needle == "" || offset > len(content) {
+		return -1
+	}
+	i := strings.Index(content[offset:], needle)
+	if i < 0 {
+		return -1
+	}
+	return offset + i
+}
+
+// jobOffset returns the byte offset of a job's key so that findings in
+// different jobs using the same action point at the right line
+func jobOffset(content, job string) int {
+	if job == "" {
+		return 0
+	}
+	re := regexp.MustCompile(`(?m)^\s+` + regexp.QuoteMeta(job) + `\s*:`)
+	if loc := re.FindStringIndex(content); loc != nil {
+		return loc[0]
+	}
+	return 0
+}
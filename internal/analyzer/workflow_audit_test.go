@@ -0,0 +1,196 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+const riskyWorkflow = `name: PR
+on:
+  pull_request_target:
+    types: [opened, synchronize]
+jobs:
+  build:
+    runs-on: [self-hosted, linux]
+    steps:
+      - uses: actions/checkout@v4
+        with:
+          ref: ${{ github.event.pull_request.head.sha }}
+      - name: Lint
+        uses: golangci/golangci-lint-action@v6
+      - name: Greet
+        run: |
+          echo "Title: ${{ github.event.pull_request.title }}"
+          echo "Number: ${{ github.event.number }}"
+      - uses: ./.github/actions/local
+  release:
+    uses: acme/workflows/.github/workflows/release.yml@main
+`
+
+const safeWorkflow = `name: CI
+on: [push, pull_request]
+permissions:
+  contents: read
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      - uses: golangci/golangci-lint-action@971e284b6050e8a5849b72094c50ab08da042db8
+      - run: go test ./...
+        env:
+          TITLE: ${{ github.event.pull_request.title }}
+`
+
+func TestAuditWorkflow(t *testing.T) {
+	findings, actions, err := auditWorkflow(".github/workflows/pr.yml", riskyWorkflow, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Join(actions, ","); got != "actions/checkout@v4,golangci/golangci-lint-action@v6,acme/workflows/.github/workflows/release.yml@main" {
+		t.Errorf("actions = %v, want the reusable workflow, checkout and lint, without the local action", actions)
+	}
+
+	byRule := make(map[string][]WorkflowFinding)
+	for _, f := range findings {
+		byRule[f.Rule] = append(byRule[f.Rule], f)
+	}
+
+	if got := byRule[RuleMissingPermissions]; len(got) != 1 || got[0].Severity != "MEDIUM" {
+		t.Errorf("missing permissions = %+v", got)
+	}
+	if got := byRule[RulePRTargetCheckout]; len(got) != 1 || got[0].Severity != "CRITICAL" || got[0].Line != 11 {
+		t.Errorf("pull_request_target checkout = %+v, want one CRITICAL finding on line 11", got)
+	}
+	if got := byRule[RuleSelfHostedRunner]; len(got) != 1 || got[0].Job != "build" {
+		t.Errorf("self-hosted runner = %+v", got)
+	}
+
+	unpinned := byRule[RuleUnpinnedAction]
+	if len(unpinned) != 2 {
+		t.Fatalf("unpinned actions = %+v, want the lint action and the reusable workflow", unpinned)
+	}
+	if unpinned[0].Step != "Lint" || unpinned[0].Line != 13 || unpinned[1].Job != "release" {
+		t.Errorf("unpinned actions = %+v", unpinned)
+	}
+
+	injections := byRule[RuleScriptInjection]
+	if len(injections) != 2 || injections[0].Severity != "HIGH" || injections[1].Severity != "MEDIUM" {
+		t.Errorf("script injections = %+v, want HIGH for the title and MEDIUM for the number", injections)
+	}
+
+	// The same workflow in a private repository has no runner finding
+	private, _, _ := auditWorkflow(".github/workflows/pr.yml", riskyWorkflow, false)
+	for _, f := range private {
+		if f.Rule == RuleSelfHostedRunner {
+			t.Error("self-hosted runners should only be flagged in public repositories")
+		}
+	}
+
+	safe, _, err := auditWorkflow(".github/workflows/ci.yml", safeWorkflow, true)
+	if err != nil || len(safe) != 0 {
+		t.Errorf("safe workflow findings = %+v, err = %v; want none", safe, err)
+	}
+}
+
+func TestCheckActionPin(t *testing.T) {
+	tests := map[string]bool{
+		"actions/setup-go@v5":       false,
+		"github/codeql-action@v3":   false,
+		"./local-action":            false,
+		"docker://alpine@sha256:ab": false,
+		"docker://alpine:3.19":      true,
+		"owner/action@v1.2.3":       true,
+		"owner/action@971e284b6050e8a5849b72094c50ab08da042db8": false,
+		"owner/action@971e284": true,
+	}
+	for uses, flagged := range tests {
+		if _, ok := checkActionPin(uses); ok != flagged {
+			t.Errorf("checkActionPin(%q) flagged = %v, want %v", uses, ok, flagged)
+		}
+	}
+}
+
+func TestAuditWorkflow_RepeatedNeedles(t *testing.T) {
+	workflow := `on: pull_request_target
+permissions: {}
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: acme/setup@v1
+      - run: echo "${{ github.event.issue.title }}"
+      - uses: acme/setup@v1
+      - run: echo "${{ github.event.issue.title }}"
+`
+	findings, _, err := auditWorkflow(".github/workflows/x.yml", workflow, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := make(map[string][]int)
+	for _, f := range findings {
+		lines[f.Rule] = append(lines[f.Rule], f.Line)
+	}
+	if got := lines[RuleUnpinnedAction]; len(got) != 2 || got[0] != 7 || got[1] != 9 {
+		t.Errorf("unpinned action lines = %v, want [7 9]", got)
+	}
+	if got := lines[RuleScriptInjection]; len(got) != 2 || got[0] != 8 || got[1] != 10 {
+		t.Errorf("script injection lines = %v, want [8 10]", got)
+	}
+}
+
+func TestChecksOutPRHead(t *testing.T) {
+	tests := map[string]bool{
+		"${{ github.event.pull_request.head.sha }}":               true,
+		"${{ github.event.pull_request.merge_commit_sha }}":       true,
+		"refs/pull/${{ github.event.pull_request.number }}/merge": true,
+		"${{ github.head_ref }}":                                  true,
+		"${{ github.event.pull_request.base.ref }}":               false,
+		"": false,
+	}
+	for ref, want := range tests {
+		if got := checksOutPRHead(ref); got != want {
+			t.Errorf("checksOutPRHead(%q) = %v, want %v", ref, got, want)
+		}
+	}
+}
+
+func TestAuditWorkflows(t *testing.T) {
+	files := map[string]string{
+		".github/workflows/pr.yml":       riskyWorkflow,
+		".github/workflows/ci.yaml":      safeWorkflow,
+		".github/workflows/broken.yml":   "jobs:\n  - [unclosed\n",
+		".github/workflows/sub/skip.yml": riskyWorkflow,
+		"docs/workflow.yml":              riskyWorkflow,
+	}
+	var tree []github.TreeEntry
+	for p := range files {
+		tree = append(tree, github.TreeEntry{Path: p, Type: "blob"})
+	}
+	fetch := func(p string) (string, bool) {
+		content, ok := files[p]
+		return content, ok
+	}
+
+	result := auditWorkflows(tree, fetch, true, maxAuditedWorkflows)
+	if result.Workflows != 2 {
+		t.Errorf("Workflows = %d, want 2", result.Workflows)
+	}
+	if len(result.Errors) != 1 || !strings.HasPrefix(result.Errors[0], ".github/workflows/broken.yml") {
+		t.Errorf("Errors = %v, want broken.yml", result.Errors)
+	}
+	if result.CriticalCount != 1 || result.Findings[0].Severity != "CRITICAL" {
+		t.Errorf("CriticalCount = %d, first finding %+v; want critical findings first", result.CriticalCount, result.Findings[0])
+	}
+	if result.Truncated {
+		t.Error("Truncated = true, want false below the cap")
+	}
+
+	capped := auditWorkflows(tree, fetch, true, 1)
+	if !capped.Truncated || capped.Skipped != 2 || capped.Workflows+len(capped.Errors) != 1 {
+		t.Errorf("capped audit = truncated %v, skipped %d, %d read; want 1 of 3 workflows read", capped.Truncated, capped.Skipped, capped.Workflows+len(capped.Errors))
+	}
+}
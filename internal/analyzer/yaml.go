@@ -0,0 +1,562 @@
+// Package analyzer provides analysis functions for GitHub repositories.
+// This file contains a small YAML reader used for lock files, CI workflows
+// and configuration files.
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// parseYAML decodes a single YAML document into maps, slices and strings.
+// It supports the block-style subset found in lock files and CI workflows:
+// nested mappings, sequences (including sequences of mappings), plain and
+// quoted scalars, literal (|) and folded (>) block scalars, flow sequences
+// and flow mappings, and comments. All scalars are returned as strings;
+// anchors, aliases and tags are kept verbatim.
+func parseYAML(content []byte) (interface{}, error) {
+	p := &yamlParser{}
+	for i, raw := range strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n") {
+		p.lines = append(p.lines, yamlLine{num: i + 1, raw: raw})
+	}
+	p.prepare()
+	if len(p.lines) == 0 {
+		return map[string]interface{}{}, nil
+	}
+	return p.parseBlock(p.lines[0].indent)
+}
+
+type yamlLine struct {
+	num    int
+	raw    string
+	indent int
+	text   string // content without indentation and comments
+}
+
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// prepare strips comments and blank lines while keeping raw text for block scalars
+func (p *yamlParser) prepare() {
+	for i := range p.lines {
+		l := &p.lines[i]
+		trimmed := strings.TrimLeft(l.raw, " ")
+		l.indent = len(l.raw) - len(trimmed)
+		l.text = strings.TrimRight(stripYAMLComment(trimmed), " \t")
+	}
+}
+
+// stripYAMLComment removes a trailing "# comment" outside of quotes
+func stripYAMLComment(s string) string {
+	inSingle, inDouble := false, false
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\'' && !inDouble:
+			inSingle = !inSingle
+		case c == '"' && !inSingle:
+			if i == 0 || s[i-1] != '\\' {
+				inDouble = !inDouble
+			}
+		case c == '#' && !inSingle && !inDouble:
+			if i == 0 || s[i-1] == ' ' || s[i-1] == '\t' {
+				return s[:i]
+			}
+		}
+	}
+	return s
+}
+
+// next skips blank lines, comments and document markers
+func (p *yamlParser) next() (*yamlLine, bool) {
+	for p.pos < len(p.lines) {
+		l := &p.lines[p.pos]
+		if l.text == "" || l.text == "---" || strings.HasPrefix(l.text, "%") {
+			p.pos++
+			continue
+		}
+		if l.text == "..." {
+			p.pos = len(p.lines)
+			return nil, false
+		}
+		return l, true
+	}
+	return nil, false
+}
+
+// parseBlock parses a mapping or sequence whose entries sit at indent
+func (p *yamlParser) parseBlock(indent int) (interface{}, error) {
+	l, ok := p.next()
+	if !ok {
+		return nil, nil
+	}
+	if isYAMLSequenceItem(l.text) {
+		return p.parseSequence(l.indent)
+	}
+	if _, _, isMap := splitYAMLMappingEntry(l.text); isMap {
+		return p.parseMapping(l.indent)
+	}
+	// A bare scalar document
+	p.pos++
+	return parseYAMLScalar(l.text), nil
+}
+
+func isYAMLSequenceItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+func (p *yamlParser) parseMapping(indent int) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	for {
+		l, ok := p.next()
+		if !ok || l.indent < indent {
+			return result, nil
+		}
+		if l.indent > indent {
+			return nil, fmt.Errorf("yaml line %d: unexpected indentation", l.num)
+		}
+		if isYAMLSequenceItem(l.text) {
+			// A sequence at the same indent as its parent key ends the mapping
+			return result, nil
+		}
+
+		key, rest, isMap := splitYAMLMappingEntry(l.text)
+		if !isMap {
+			return nil, fmt.Errorf("yaml line %d: expected mapping entry", l.num)
+		}
+		p.pos++
+
+		value, err := p.parseValue(rest, indent, l.num)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = value
+	}
+}
+
+func (p *yamlParser) parseSequence(indent int) ([]interface{}, error) {
+	result := []interface{}{}
+	for {
+		l, ok := p.next()
+		if !ok || l.indent < indent || !isYAMLSequenceItem(l.text) {
+			return result, nil
+		}
+		if l.indent > indent {
+			return nil, fmt.Errorf("yaml line %d: unexpected indentation", l.num)
+		}
+
+		rest := strings.TrimSpace(strings.TrimPrefix(l.text, "-"))
+		if rest == "" {
+			p.pos++
+			child, err := p.parseNested(indent, l.num)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, child)
+			continue
+		}
+
+		// "- key: value" starts an inline mapping whose further keys align
+		// with the first key
+		if _, _, isMap := splitYAMLMappingEntry(rest); isMap && !strings.HasPrefix(rest, "{") && !strings.HasPrefix(rest, "[") {
+			itemIndent := l.indent + (len(l.text) - len(rest))
+			p.lines[p.pos].indent = itemIndent
+			p.lines[p.pos].text = rest
+			child, err := p.parseMapping(itemIndent)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, child)
+			continue
+		}
+
+		p.pos++
+		value, err := p.parseValue(rest, indent, l.num)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, value)
+	}
+}
+
+// parseValue parses the value following "key:" or "- " on a line
+func (p *yamlParser) parseValue(rest string, parentIndent, lineNum int) (interface{}, error) {
+	rest = stripYAMLProperties(rest)
+
+	switch {
+	case rest == "":
+		return p.parseNested(parentIndent, lineNum)
+	case rest[0] == '|' || rest[0] == '>':
+		return p.parseBlockScalar(rest, parentIndent), nil
+	case rest[0] == '[' || rest[0] == '{':
+		// Flow collections may span multiple lines
+		text := rest
+		for !yamlFlowBalanced(text) && p.pos < len(p.lines) {
+			text += " " + strings.TrimSpace(p.lines[p.pos].text)
+			p.pos++
+		}
+		v, _, err := parseYAMLFlow(text, 0)
+		if err != nil {
+			return nil, fmt.Errorf("yaml line %d: %v", lineNum, err)
+		}
+		return v, nil
+	case rest[0] == '"' || rest[0] == '\'':
+		// Quoted scalars may continue on following lines
+		text := rest
+		for !yamlQuoteClosed(text) && p.pos < len(p.lines) {
+			text += " " + strings.TrimSpace(p.lines[p.pos].raw)
+			p.pos++
+		}
+		return parseYAMLScalar(text), nil
+	default:
+		// Plain multi-line scalars continue on more-indented lines
+		text := rest
+		for p.pos < len(p.lines) {
+			l := p.lines[p.pos]
+			if l.text == "" || l.indent <= parentIndent {
+				break
+			}
+			if _, _, isMap := splitYAMLMappingEntry(l.text); isMap || isYAMLSequenceItem(l.text) {
+				break
+			}
+			text += " " + l.text
+			p.pos++
+		}
+		return parseYAMLScalar(text), nil
+	}
+}
+
+// parseNested parses a block nested under a key or dash, or returns nil
+func (p *yamlParser) parseNested(parentIndent, lineNum int) (interface{}, error) {
+	l, ok := p.next()
+	if !ok {
+		return nil, nil
+	}
+	// Sequences may be indented at the same level as their parent key
+	if l.indent > parentIndent || (l.indent == parentIndent && isYAMLSequenceItem(l.text)) {
+		return p.parseBlock(l.indent)
+	}
+	return nil, nil
+}
+
+// parseBlockScalar reads a | or > block scalar
+func (p *yamlParser) parseBlockScalar(header string, parentIndent int) string {
+	folded := header[0] == '>'
+	chomp := "clip"
+	if strings.Contains(header, "-") {
+		chomp = "strip"
+	} else if strings.Contains(header, "+") {
+		chomp = "keep"
+	}
+
+	var lines []string
+	blockIndent := -1
+	for p.pos < len(p.lines) {
+		raw := p.lines[p.pos].raw
+		trimmed := strings.TrimLeft(raw, " ")
+		indent := len(raw) - len(trimmed)
+		if strings.TrimSpace(raw) == "" {
+			lines = append(lines, "")
+			p.pos++
+			continue
+		}
+		if indent <= parentIndent {
+			break
+		}
+		if blockIndent < 0 {
+			blockIndent = indent
+		}
+		if indent < blockIndent {
+			break
+		}
+		lines = append(lines, raw[blockIndent:])
+		p.pos++
+	}
+
+	// Trailing blank lines belong to the chomping indicator
+	trailing := 0
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+		trailing++
+	}
+
+	var text string
+	if folded {
+		var sb strings.Builder
+		for i, line := range lines {
+			if i > 0 {
+				if line == "" || lines[i-1] == "" || strings.HasPrefix(line, " ") {
+					sb.WriteString("\n")
+				} else {
+					sb.WriteString(" ")
+				}
+			}
+			sb.WriteString(line)
+		}
+		text = sb.String()
+	} else {
+		text = strings.Join(lines, "\n")
+	}
+
+	switch chomp {
+	case "strip":
+		return text
+	case "keep":
+		return text + "\n" + strings.Repeat("\n", trailing)
+	default:
+		if text == "" {
+			return ""
+		}
+		return text + "\n"
+	}
+}
+
+// splitYAMLMappingEntry splits "key: value" respecting quoted keys
+func splitYAMLMappingEntry(text string) (string, string, bool) {
+	if text == "" || isYAMLSequenceItem(text) {
+		return "", "", false
+	}
+
+	if text[0] == '"' || text[0] == '\'' {
+		q := text[0]
+		for i := 1; i < len(text); i++ {
+			if text[i] == q && (q == '\'' || text[i-1] != '\\') {
+				rest := text[i+1:]
+				if strings.HasPrefix(rest, ":") && (len(rest) == 1 || rest[1] == ' ') {
+					return parseYAMLScalar(text[:i+1]), strings.TrimSpace(rest[1:]), true
+				}
+				return "", "", false
+			}
+		}
+		return "", "", false
+	}
+	if text[0] == '{' || text[0] == '[' {
+		return "", "", false
+	}
+
+	for i := 0; i < len(text); i++ {
+		if text[i] == ':' && (i == len(text)-1 || text[i+1] == ' ' || text[i+1] == '\t') {
+			key := strings.TrimSpace(text[:i])
+			if strings.HasPrefix(key, "? ") {
+				key = strings.TrimSpace(key[2:])
+			}
+			return key, strings.TrimSpace(text[i+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+// stripYAMLProperties drops anchors (&a) and tags (!!str) preceding a value
+func stripYAMLProperties(s string) string {
+	for len(s) > 0 && (s[0] == '&' || s[0] == '!') {
+		idx := strings.IndexAny(s, " \t")
+		if idx < 0 {
+			return ""
+		}
+		s = strings.TrimSpace(s[idx:])
+	}
+	return s
+}
+
+// parseYAMLScalar unquotes a scalar value
+func parseYAMLScalar(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		inner := s[1 : len(s)-1]
+		r := strings.NewReplacer(`\"`, `"`, `\\`, `\`, `\n`, "\n", `\t`, "\t", `\/`, "/")
+		return r.Replace(inner)
+	}
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'")
+	}
+	return s
+}
+
+func yamlQuoteClosed(s string) bool {
+	if len(s) < 2 {
+		return false
+	}
+	q := s[0]
+	for i := 1; i < len(s); i++ {
+		if s[i] == q {
+			if q == '\'' && i+1 < len(s) && s[i+1] == '\'' {
+				i++
+				continue
+			}
+			if q == '"' && s[i-1] == '\\' {
+				continue
+			}
+			return true
+		}
+	}
+	return false
+}
+
+func yamlFlowBalanced(s string) bool {
+	depth := 0
+	inSingle, inDouble := false, false
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\'' && !inDouble:
+			inSingle = !inSingle
+		case c == '"' && !inSingle:
+			inDouble = !inDouble
+		case inSingle || inDouble:
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		}
+	}
+	return depth <= 0
+}
+
+// parseYAMLFlow parses a flow sequence or mapping starting at s[i]
+func parseYAMLFlow(s string, i int) (interface{}, int, error) {
+	i = skipYAMLFlowSpace(s, i)
+	if i >= len(s) {
+		return "", i, nil
+	}
+
+	switch s[i] {
+	case '[':
+		var items []interface{}
+		i++
+		for {
+			i = skipYAMLFlowSpace(s, i)
+			if i >= len(s) {
+				return nil, i, fmt.Errorf("unterminated flow sequence")
+			}
+			if s[i] == ']' {
+				if items == nil {
+					items = []interface{}{}
+				}
+				return items, i + 1, nil
+			}
+			if s[i] == '}' {
+				return nil, i, fmt.Errorf("unexpected '}' in flow sequence")
+			}
+			v, next, err := parseYAMLFlow(s, i)
+			if err != nil {
+				return nil, next, err
+			}
+			if next == i && (next >= len(s) || s[next] != ',') {
+				return nil, i, fmt.Errorf("unexpected %q in flow sequence", s[i])
+			}
+			items = append(items, v)
+			i = skipYAMLFlowSpace(s, next)
+			if i < len(s) && s[i] == ',' {
+				i++
+			}
+		}
+	case '{':
+		m := make(map[string]interface{})
+		i++
+		for {
+			i = skipYAMLFlowSpace(s, i)
+			if i >= len(s) {
+				return nil, i, fmt.Errorf("unterminated flow mapping")
+			}
+			if s[i] == '}' {
+				return m, i + 1, nil
+			}
+			if s[i] == ']' {
+				return nil, i, fmt.Errorf("unexpected ']' in flow mapping")
+			}
+			keyEnd := yamlFlowScalarEnd(s, i, true)
+			key := parseYAMLScalar(s[i:keyEnd])
+			i = skipYAMLFlowSpace(s, keyEnd)
+			var value interface{} = ""
+			if i < len(s) && s[i] == ':' {
+				v, next, err := parseYAMLFlow(s, i+1)
+				if err != nil {
+					return nil, next, err
+				}
+				value = v
+				i = next
+			}
+			m[key] = value
+			i = skipYAMLFlowSpace(s, i)
+			if i < len(s) && s[i] == ',' {
+				i++
+			}
+		}
+	default:
+		end := yamlFlowScalarEnd(s, i, false)
+		return parseYAMLScalar(s[i:end]), end, nil
+	}
+}
+
+func skipYAMLFlowSpace(s string, i int) int {
+	for i < len(s) && (s[i] == ' ' || s[i] == '\t' || s[i] == '\n') {
+		i++
+	}
+	return i
+}
+
+// yamlFlowScalarEnd finds the end of a scalar inside a flow collection
+func yamlFlowScalarEnd(s string, i int, isKey bool) int {
+	if i < len(s) && (s[i] == '"' || s[i] == '\'') {
+		q := s[i]
+		for j := i + 1; j < len(s); j++ {
+			if s[j] == q && (q == '\'' || s[j-1] != '\\') {
+				return j + 1
+			}
+		}
+		return len(s)
+	}
+	for j := i; j < len(s); j++ {
+		switch s[j] {
+		case ',', ']', '}':
+			return j
+		case ':':
+			if isKey && (j+1 >= len(s) || s[j+1] == ' ' || s[j+1] == ',' || s[j+1] == '}') {
+				return j
+			}
+		}
+	}
+	return len(s)
+}
+
+// yamlMap returns the mapping at the given path, or nil
+func yamlMap(v interface{}, path ...string) map[string]interface{} {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	for _, key := range path {
+		next, ok := m[key].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		m = next
+	}
+	return m
+}
+
+// yamlString returns the string value of a key, or ""
+func yamlString(m map[string]interface{}, key string) string {
+	if s, ok := m[key].(string); ok {
+		return s
+	}
+	return ""
+}
+
+// yamlStrings returns the scalars of a sequence value, or a single scalar
+// as a one-element slice
+func yamlStrings(m map[string]interface{}, key string) []string {
+	switch v := m[key].(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var result []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package analyzer
+
+import (
+	"testing"
+)
+
+func TestParseYAML(t *testing.T) {
+	doc, err := parseYAML([]byte(`
+# comment
+name: CI # trailing comment
+"quoted key": 'it''s'
+on:
+  push:
+    branches: [main, "release/*"]
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    env: { GO: "1.21", CGO: 0 }
+    steps:
+      - uses: actions/checkout@v4
+        with:
+          fetch-depth: 0
+      - name: Test
+        run: |
+          go build ./...
+          go test ./...
+      - run: >-
+          echo one
+          two
+list:
+- a
+- b
+`))
+	if err != nil {
+		t.Fatalf("parseYAML() error = %v", err)
+	}
+	root := yamlMap(doc)
+
+	if got := yamlString(root, "name"); got != "CI" {
+		t.Errorf("name = %q, want CI", got)
+	}
+	if got := yamlString(root, "quoted key"); got != "it's" {
+		t.Errorf("quoted key = %q, want it's", got)
+	}
+	branches, _ := yamlMap(root, "on", "push")["branches"].([]interface{})
+	if len(branches) != 2 || branches[1] != "release/*" {
+		t.Errorf("branches = %v, want [main release/*]", branches)
+	}
+
+	build := yamlMap(root, "jobs", "build")
+	if got := yamlString(yamlMap(build, "env"), "GO"); got != "1.21" {
+		t.Errorf("env.GO = %q, want 1.21", got)
+	}
+	steps, _ := build["steps"].([]interface{})
+	if len(steps) != 3 {
+		t.Fatalf("len(steps) = %d, want 3: %v", len(steps), steps)
+	}
+	first := yamlMap(steps[0])
+	if got := yamlString(first, "uses"); got != "actions/checkout@v4" {
+		t.Errorf("steps[0].uses = %q", got)
+	}
+	if got := yamlString(yamlMap(first, "with"), "fetch-depth"); got != "0" {
+		t.Errorf("steps[0].with.fetch-depth = %q, want 0", got)
+	}
+	if got := yamlString(yamlMap(steps[1]), "run"); got != "go build ./...\ngo test ./...\n" {
+		t.Errorf("steps[1].run = %q", got)
+	}
+	if got := yamlString(yamlMap(steps[2]), "run"); got != "echo one two" {
+		t.Errorf("steps[2].run = %q, want folded text", got)
+	}
+	if list, _ := root["list"].([]interface{}); len(list) != 2 {
+		t.Errorf("list = %v, want 2 items", root["list"])
+	}
+}
+
+func TestParseYAML_Invalid(t *testing.T) {
+	if _, err := parseYAML([]byte("a: 1\n   b: 2\n  c: 3\n")); err == nil {
+		t.Error("expected error for inconsistent indentation")
+	}
+	for _, doc := range []string{"on: [}", "on: {]", "on: [a, }]", "on: {a: [b}]"} {
+		if _, err := parseYAML([]byte(doc)); err == nil {
+			t.Errorf("parseYAML(%q): expected error for mismatched flow brackets", doc)
+		}
+	}
+}
+
+func FuzzParseYAML(f *testing.F) {
+	for _, seed := range []string{"a: 1\nb: [x, y]\n", "on: {push: {branches: [main]}}", "on: [}", "- {a: [b, {c: d}]}"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, doc string) {
+		parseYAML([]byte(doc))
+	})
+}
@@ -21,11 +21,17 @@ const (
 	ExportCSV      ExportFormat = "csv"
 	ExportHTML     ExportFormat = "html"
 	ExportPDF      ExportFormat = "pdf"
+
+	// SBOM formats, built from the dependency analysis
+	ExportCycloneDX    ExportFormat = "cyclonedx"
+	ExportSPDX         ExportFormat = "spdx"
+	ExportSPDXTagValue ExportFormat = "spdx-tv"
 )
 
 // AllExportFormats returns all available export formats
 func AllExportFormats() []ExportFormat {
-	return []ExportFormat{ExportJSON, ExportMarkdown, ExportCSV, ExportHTML, ExportPDF}
+	return []ExportFormat{ExportJSON, ExportMarkdown, ExportCSV, ExportHTML, ExportPDF,
+		ExportCycloneDX, ExportSPDX, ExportSPDXTagValue}
 }
 
 // AppSettings holds all user-configurable application settings
@@ -42,6 +48,9 @@ type AppSettings struct {
 
 	// Analysis settings
 	DefaultAnalysisType string `json:"default_analysis_type"` // "quick", "detailed", "custom"
+
+	// Security settings
+	OfflineVulnScan bool `json:"offline_vuln_scan"` // Scan against ~/.repo-lyzer/vulndb instead of osv.dev
 }
 
 // DefaultSettings returns the default application settings
@@ -150,6 +159,12 @@ func (s *AppSettings) SetGitHubToken(token string) error {
 	return s.SaveSettings()
 }
 
+// SetOfflineVulnScan enables or disables offline vulnerability scanning and saves
+func (s *AppSettings) SetOfflineVulnScan(offline bool) error {
+	s.OfflineVulnScan = offline
+	return s.SaveSettings()
+}
+
 // ClearGitHubToken removes the GitHub token and saves
 func (s *AppSettings) ClearGitHubToken() error {
 	s.GitHubToken = ""
@@ -203,6 +218,12 @@ func (f ExportFormat) DisplayName() string {
 		return "HTML"
 	case ExportPDF:
 		return "PDF"
+	case ExportCycloneDX:
+		return "CycloneDX SBOM"
+	case ExportSPDX:
+		return "SPDX SBOM (JSON)"
+	case ExportSPDXTagValue:
+		return "SPDX SBOM (tag-value)"
 	default:
 		return string(f)
 	}
@@ -0,0 +1,55 @@
+package github
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// Branch is a repository branch with its protection summary
+type Branch struct {
+	Name       string `json:"name"`
+	Protected  bool   `json:"protected"`
+	Protection struct {
+		Enabled              bool `json:"enabled"`
+		RequiredStatusChecks struct {
+			EnforcementLevel string   `json:"enforcement_level"` // off, non_admins, everyone
+			Contexts         []string `json:"contexts"`
+		} `json:"required_status_checks"`
+	} `json:"protection"`
+}
+
+// BranchProtection holds the detailed protection rules of a branch. Reading
+// them requires admin access to the repository.
+type BranchProtection struct {
+	RequiredPullRequestReviews *struct {
+		RequiredApprovingReviewCount int  `json:"required_approving_review_count"`
+		DismissStaleReviews          bool `json:"dismiss_stale_reviews"`
+		RequireCodeOwnerReviews      bool `json:"require_code_owner_reviews"`
+	} `json:"required_pull_request_reviews"`
+	RequiredStatusChecks *struct {
+		Strict   bool     `json:"strict"`
+		Contexts []string `json:"contexts"`
+	} `json:"required_status_checks"`
+	EnforceAdmins    ProtectionSetting `json:"enforce_admins"`
+	AllowForcePushes ProtectionSetting `json:"allow_force_pushes"`
+	AllowDeletions   ProtectionSetting `json:"allow_deletions"`
+}
+
+// ProtectionSetting is an on/off branch protection rule
+type ProtectionSetting struct {
+	Enabled bool `json:"enabled"`
+}
+
+// GetBranch fetches a branch and its protection summary
+func (c *Client) GetBranch(owner, repo, branch string) (*Branch, error) {
+	var b Branch
+	err := c.get(fmt.Sprintf("https://api.github.com/repos/%s/%s/branches/%s", owner, repo, url.PathEscape(branch)), &b)
+	return &b, err
+}
+
+// GetBranchProtection fetches the detailed protection rules of a branch
+func (c *Client) GetBranchProtection(owner, repo, branch string) (*BranchProtection, error) {
+	var p BranchProtection
+	err := c.get(fmt.Sprintf("https://api.github.com/repos/%s/%s/branches/%s/protection", owner, repo, url.PathEscape(branch)), &p)
+	return &p, err
+}
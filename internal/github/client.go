@@ -2,6 +2,7 @@ package github
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -10,6 +11,10 @@ import (
 	"time"
 )
 
+// ErrStatsPending is returned by statistics endpoints while GitHub is still
+// computing the numbers; retrying a moment later usually succeeds
+var ErrStatsPending = errors.New("GitHub is still computing repository statistics")
+
 // Client handles GitHub API requests
 type Client struct {
 	http  *http.Client
@@ -80,6 +85,10 @@ func (c *Client) get(url string, target interface{}) error {
 		}
 	}
 
+	if resp.StatusCode == http.StatusAccepted {
+		return ErrStatsPending
+	}
+
 	if resp.StatusCode == http.StatusNotFound {
 		return fmt.Errorf("repository not found or inaccessible — it may be private or you may not have permission")
 	}
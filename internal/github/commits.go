@@ -1,21 +1,123 @@
 package github
 
-import "time"
+import (
+	"net/url"
+	"strconv"
+	"time"
+)
 
 type Commit struct {
-	SHA    string `json:"sha"`
-	Commit struct {
-		Author struct {
-			Date time.Time `json:"date"`
-		} `json:"author"`
-	} `json:"commit"`
+	SHA     string         `json:"sha"`
+	Commit  CommitInfo     `json:"commit"`
+	Author  *User          `json:"author,omitempty"`
+	Parents []CommitParent `json:"parents,omitempty"`
 }
 
+// CommitInfo holds the git-level data of a commit
+type CommitInfo struct {
+	Author  CommitAuthor `json:"author"`
+	Message string       `json:"message"`
+}
+
+// CommitParent references a parent commit; merge commits have several
+type CommitParent struct {
+	SHA string `json:"sha"`
+}
+
+// CommitAuthor is the git author signature of a commit
+type CommitAuthor struct {
+	Name  string    `json:"name"`
+	Email string    `json:"email"`
+	Date  time.Time `json:"date"`
+}
+
+// CommitFile is a single file touched by a commit
+type CommitFile struct {
+	Filename         string `json:"filename"`
+	Status           string `json:"status"` // added, modified, removed, renamed
+	PreviousFilename string `json:"previous_filename,omitempty"`
+}
+
+// CommitDetail is a commit together with the list of files it touched
+type CommitDetail struct {
+	Commit
+	Files []CommitFile `json:"files"`
+}
+
+// CommitsPerPage is how many commits GetCommits returns at most; a full
+// page means older commits in the window were not fetched
+const CommitsPerPage = 100
+
 func (c *Client) GetCommits(owner, repo string, days int) ([]Commit, error) {
 	var commits []Commit
 	since := time.Now().AddDate(0, 0, -days).Format(time.RFC3339)
 
-	url := "https://api.github.com/repos/" + owner + "/" + repo + "/commits?since=" + since
+	url := "https://api.github.com/repos/" + owner + "/" + repo + "/commits?per_page=" + strconv.Itoa(CommitsPerPage) + "&since=" + since
 	err := c.get(url, &commits)
 	return commits, err
 }
+
+// CommitActivityWeek is one week of /stats/commit_activity
+type CommitActivityWeek struct {
+	Week  int64  `json:"week"` // Unix time of the Sunday that starts the week
+	Days  [7]int `json:"days"` // commits per day, Sunday first
+	Total int    `json:"total"`
+}
+
+// GetCommitActivity fetches default-branch commit counts per day for the
+// last 52 weeks in a single request, retrying while GitHub computes them
+func (c *Client) GetCommitActivity(owner, repo string) ([]CommitActivityWeek, error) {
+	var weeks []CommitActivityWeek
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = c.get("https://api.github.com/repos/"+owner+"/"+repo+"/stats/commit_activity", &weeks)
+		if err != ErrStatsPending || attempt == 3 {
+			break
+		}
+		time.Sleep(2 * time.Second)
+	}
+	return weeks, err
+}
+
+// GetCommitsForPath fetches up to 100 commits from the last days that
+// touched a file or directory
+func (c *Client) GetCommitsForPath(owner, repo, path string, days int) ([]Commit, error) {
+	var commits []Commit
+	since := time.Now().AddDate(0, 0, -days).Format(time.RFC3339)
+
+	u := "https://api.github.com/repos/" + owner + "/" + repo + "/commits?per_page=100&since=" + since + "&path=" + url.QueryEscape(path)
+	err := c.get(u, &commits)
+	return commits, err
+}
+
+// GetCommitDetail fetches a single commit including the files it changed
+func (c *Client) GetCommitDetail(owner, repo, sha string) (*CommitDetail, error) {
+	var d CommitDetail
+	err := c.get("https://api.github.com/repos/"+owner+"/"+repo+"/commits/"+sha, &d)
+	return &d, err
+}
+
+// GetCommitDetails fetches file lists for up to max commits, skipping any that fail
+func (c *Client) GetCommitDetails(owner, repo string, commits []Commit, max int) []CommitDetail {
+	if max > len(commits) {
+		max = len(commits)
+	}
+
+	details := make([]CommitDetail, 0, max)
+	for i := 0; i < max; i++ {
+		d, err := c.GetCommitDetail(owner, repo, commits[i].SHA)
+		if err != nil {
+			continue
+		}
+		details = append(details, *d)
+	}
+	return details
+}
+
+// AuthorName returns the GitHub login of the commit author, falling back to the git author name
+func (c Commit) AuthorName() string {
+	if c.Author != nil && c.Author.Login != "" {
+		return c.Author.Login
+	}
+	return c.Commit.Author.Name
+}
@@ -0,0 +1,117 @@
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// graphql runs a GraphQL query and decodes its data into target. The
+// GraphQL API requires authentication.
+func (c *Client) graphql(query string, variables map[string]interface{}, target interface{}) error {
+	if c.token == "" {
+		return fmt.Errorf("the GraphQL API requires GITHUB_TOKEN")
+	}
+	body, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", "https://api.github.com/graphql", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("network error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return fmt.Errorf("authentication failed (check your GITHUB_TOKEN)")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GitHub GraphQL error: %s", resp.Status)
+	}
+
+	var result struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return err
+	}
+	if len(result.Errors) > 0 {
+		return fmt.Errorf("GitHub GraphQL error: %s", result.Errors[0].Message)
+	}
+	return json.Unmarshal(result.Data, target)
+}
+
+const commitDatesQuery = `query($owner: String!, $name: String!, $since: GitTimestamp!, $after: String) {
+  repository(owner: $owner, name: $name) {
+    defaultBranchRef {
+      target {
+        ... on Commit {
+          history(first: 100, since: $since, after: $after) {
+            nodes { oid author { date } }
+            pageInfo { hasNextPage endCursor }
+          }
+        }
+      }
+    }
+  }
+}`
+
+// GetCommitDates returns the author date of up to max default-branch commits
+// since the given time, keyed by SHA. Unlike the REST API, which reports
+// every date in UTC, these keep the author's own UTC offset.
+func (c *Client) GetCommitDates(owner, repo string, since time.Time, max int) (map[string]time.Time, error) {
+	dates := make(map[string]time.Time)
+	var after interface{}
+	for len(dates) < max {
+		var data struct {
+			Repository struct {
+				DefaultBranchRef *struct {
+					Target struct {
+						History struct {
+							Nodes []struct {
+								OID    string `json:"oid"`
+								Author struct {
+									Date string `json:"date"`
+								} `json:"author"`
+							} `json:"nodes"`
+							PageInfo struct {
+								HasNextPage bool   `json:"hasNextPage"`
+								EndCursor   string `json:"endCursor"`
+							} `json:"pageInfo"`
+						} `json:"history"`
+					} `json:"target"`
+				} `json:"defaultBranchRef"`
+			} `json:"repository"`
+		}
+		vars := map[string]interface{}{"owner": owner, "name": repo, "since": since.Format(time.RFC3339), "after": after}
+		if err := c.graphql(commitDatesQuery, vars, &data); err != nil {
+			return dates, err
+		}
+		if data.Repository.DefaultBranchRef == nil {
+			break
+		}
+		history := data.Repository.DefaultBranchRef.Target.History
+		for _, n := range history.Nodes {
+			if date, err := time.Parse(time.RFC3339, n.Author.Date); err == nil {
+				dates[n.OID] = date
+			}
+		}
+		if !history.PageInfo.HasNextPage {
+			break
+		}
+		after = history.PageInfo.EndCursor
+	}
+	return dates, nil
+}
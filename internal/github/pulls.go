@@ -0,0 +1,38 @@
+package github
+
+import (
+	"fmt"
+	"time"
+)
+
+// PullRequest is a pull request as returned by the list endpoint
+type PullRequest struct {
+	Number    int        `json:"number"`
+	Title     string     `json:"title"`
+	State     string     `json:"state"`
+	User      *User      `json:"user"`
+	CreatedAt time.Time  `json:"created_at"`
+	MergedAt  *time.Time `json:"merged_at"`
+}
+
+// Review is a pull request review
+type Review struct {
+	User  *User  `json:"user"`
+	State string `json:"state"` // APPROVED, CHANGES_REQUESTED, COMMENTED, DISMISSED
+}
+
+// GetPullRequests fetches the most recently updated pull requests in a state
+// (open, closed or all)
+func (c *Client) GetPullRequests(owner, repo, state string, perPage int) ([]PullRequest, error) {
+	var prs []PullRequest
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls?state=%s&sort=updated&direction=desc&per_page=%d", owner, repo, state, perPage)
+	err := c.get(url, &prs)
+	return prs, err
+}
+
+// GetPullRequestReviews fetches the reviews of a pull request
+func (c *Client) GetPullRequestReviews(owner, repo string, number int) ([]Review, error) {
+	var reviews []Review
+	err := c.get(fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d/reviews", owner, repo, number), &reviews)
+	return reviews, err
+}
@@ -0,0 +1,46 @@
+package github
+
+import (
+	"fmt"
+	"time"
+)
+
+// Release is a published GitHub release
+type Release struct {
+	TagName     string         `json:"tag_name"`
+	Name        string         `json:"name"`
+	Body        string         `json:"body"`
+	Draft       bool           `json:"draft"`
+	Prerelease  bool           `json:"prerelease"`
+	CreatedAt   time.Time      `json:"created_at"`
+	PublishedAt time.Time      `json:"published_at"`
+	Assets      []ReleaseAsset `json:"assets"`
+}
+
+// ReleaseAsset is a file attached to a release
+type ReleaseAsset struct {
+	Name string `json:"name"`
+	Size int    `json:"size"`
+}
+
+// GetReleases fetches the most recent releases, newest first
+func (c *Client) GetReleases(owner, repo string, perPage int) ([]Release, error) {
+	var releases []Release
+	err := c.get(fmt.Sprintf("https://api.github.com/repos/%s/%s/releases?per_page=%d", owner, repo, perPage), &releases)
+	return releases, err
+}
+
+// Tag is a git tag as listed by the tags API
+type Tag struct {
+	Name   string `json:"name"`
+	Commit struct {
+		SHA string `json:"sha"`
+	} `json:"commit"`
+}
+
+// GetTags fetches up to perPage tags, most recently created first
+func (c *Client) GetTags(owner, repo string, perPage int) ([]Tag, error) {
+	var tags []Tag
+	err := c.get(fmt.Sprintf("https://api.github.com/repos/%s/%s/tags?per_page=%d", owner, repo, perPage), &tags)
+	return tags, err
+}
@@ -0,0 +1,84 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
+	"github.com/olekukonko/tablewriter"
+)
+
+// PrintCodeQuality prints the code quality scores, source metrics and any
+// code smells that were detected
+func PrintCodeQuality(cq *analyzer.CodeQualityMetrics) {
+	fmt.Println(SectionStyle.Render("\n🧪 Code Quality"))
+	fmt.Printf("Overall: %d/100 (Grade: %s)\n", cq.OverallScore, cq.Grade)
+	fmt.Printf("Documentation %d · Testing %d · Structure %d · Maintenance %d\n",
+		cq.DocumentationScore, cq.TestingScore, cq.StructureScore, cq.MaintenanceScore)
+	fmt.Printf("CI providers: %s\n", orNone(cq.CIProviders))
+	fmt.Printf("Test frameworks: %s\n", orNone(cq.TestFrameworks))
+
+	if src := cq.Source; src != nil && src.FilesAnalyzed > 0 {
+		fmt.Printf("Lines of code: %d (%d comments, %d blank) in %d files\n",
+			src.CodeLines, src.CommentLines, src.BlankLines, src.FilesAnalyzed)
+		fmt.Printf("Cyclomatic complexity: avg %.1f, max %d across %d functions\n",
+			src.AvgComplexity, src.MaxComplexity, src.Functions)
+	}
+
+	if tm := cq.TestMapping; tm != nil && tm.SourceFiles > 0 {
+		fmt.Printf("Tests by convention: %d/%d source files (%.0f%%), %d orphaned tests\n",
+			tm.TestedSources, tm.SourceFiles, tm.Coverage*100, len(tm.OrphanedTests))
+		for _, d := range tm.UntestedPackages {
+			fmt.Printf("  untested: %s (%d files)\n", d.Dir, d.SourceFiles)
+		}
+	}
+
+	if co := cq.CodeOwners; co != nil && co.File != "" {
+		fmt.Printf("Code owners: %d/%d files owned (%s)\n", co.OwnedFiles, co.TotalFiles, co.File)
+		if len(co.InactiveOwners) > 0 {
+			fmt.Println(WarningStyle.Render("⚠️ Inactive owners: " + strings.Join(co.InactiveOwners, ", ")))
+		}
+	}
+
+	if cc := cq.CommitConventions; cc != nil && cc.TotalCommits > 0 {
+		fmt.Printf("Commits: %.0f%% conventional, %.0f%% reference issues, %d short/WIP, %.0f%% merges, %.0f%% signed off\n",
+			cc.ConventionalRatio*100, cc.IssueRefRatio*100, cc.ShortMessages+cc.WIPMessages, cc.MergeRatio*100, cc.SignedOffRatio*100)
+	}
+
+	if doc := cq.Documentation; doc != nil && doc.Readme != "" {
+		fmt.Printf("README: %s (install %s, usage %s, examples %s, %d code blocks, %d badges)\n",
+			doc.Readme, permission(doc.HasInstall), permission(doc.HasUsage), permission(doc.HasExamples), doc.CodeBlocks, doc.Badges)
+		for _, link := range doc.BrokenLinks {
+			fmt.Println(WarningStyle.Render(fmt.Sprintf("⚠️ Broken link: %s (%s:%d)", link.Target, link.File, link.Line)))
+		}
+		if n := len(doc.ImagesWithoutAlt); n > 0 {
+			fmt.Printf("Images without alt text: %d\n", n)
+		}
+		if doc.StaleDays >= 0 {
+			fmt.Printf("README lags the latest commit by %d days\n", doc.StaleDays)
+		}
+	}
+
+	if len(cq.CodeSmells) == 0 {
+		fmt.Println(SuccessStyle.Render("✅ No code smells detected"))
+	} else {
+		table := tablewriter.NewWriter(os.Stdout)
+		table.Header([]string{"Severity", "Smell", "Description", "Location"})
+		for _, smell := range cq.CodeSmells {
+			table.Append([]string{smell.Severity, smell.Type, smell.Description, smell.Location})
+		}
+		table.Render()
+	}
+
+	for _, rec := range cq.Recommendations {
+		fmt.Printf("💡 %s\n", rec)
+	}
+}
+
+func orNone(values []string) string {
+	if len(values) == 0 {
+		return "none"
+	}
+	return strings.Join(values, ", ")
+}
@@ -6,6 +6,7 @@ import (
 	"sort"
 	"time"
 
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
 	"github.com/agnivo988/Repo-lyzer/internal/github"
 )
 
@@ -21,6 +22,8 @@ type CompactConfig struct {
 	Contributors    int
 	Duration        time.Duration
 	Languages       map[string]int
+	CodeQuality     *analyzer.CodeQualityMetrics
+	License         *analyzer.LicenseAnalysis
 }
 
 // PrintCompactJSON writes the compact analysis summary to stdout.
@@ -60,6 +63,14 @@ func PrintCompactJSON(cfg CompactConfig) error {
 		},
 	}
 
+	if cq := cfg.CodeQuality; cq != nil {
+		summary.Metrics.CodeQualityScore = cq.OverallScore
+		summary.Metrics.CodeQualityGrade = cq.Grade
+	}
+	if la := cfg.License; la != nil && la.MainLicense != nil {
+		summary.Repository.License = la.MainLicense.SPDX
+	}
+
 	encoder := json.NewEncoder(os.Stdout)
 	encoder.SetIndent("", "  ")
 	encoder.SetEscapeHTML(false)
@@ -80,16 +91,19 @@ type compactRepository struct {
 	Stars           int    `json:"stars"`
 	Forks           int    `json:"forks"`
 	OpenIssues      int    `json:"open_issues"`
+	License         string `json:"license,omitempty"`
 }
 
 type compactMetrics struct {
-	HealthScore     int    `json:"health_score"`
-	BusFactor       int    `json:"bus_factor"`
-	BusRisk         string `json:"bus_risk"`
-	MaturityScore   int    `json:"maturity_score"`
-	MaturityLevel   string `json:"maturity_level"`
-	CommitsLastYear int    `json:"commit_count_1y"`
-	Contributors    int    `json:"contributors"`
+	HealthScore      int    `json:"health_score"`
+	BusFactor        int    `json:"bus_factor"`
+	BusRisk          string `json:"bus_risk"`
+	MaturityScore    int    `json:"maturity_score"`
+	MaturityLevel    string `json:"maturity_level"`
+	CommitsLastYear  int    `json:"commit_count_1y"`
+	Contributors     int    `json:"contributors"`
+	CodeQualityScore int    `json:"code_quality_score,omitempty"`
+	CodeQualityGrade string `json:"code_quality_grade,omitempty"`
 }
 
 type compactMetadata struct {
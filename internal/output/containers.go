@@ -0,0 +1,65 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
+	"github.com/olekukonko/tablewriter"
+)
+
+// PrintContainers prints the Dockerfiles found in the repository, the
+// base images they build on and their best-practice issues
+func PrintContainers(c *analyzer.ContainerAnalysis) {
+	fmt.Println(SectionStyle.Render("\n🐳 Containers"))
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header([]string{"Dockerfile", "Stages", "User", "Healthcheck", "Base Images"})
+	for _, df := range c.Dockerfiles {
+		user := df.User
+		if user == "" {
+			user = "root"
+		}
+		table.Append([]string{df.Path, strconv.Itoa(df.Stages), user, permission(df.HasHealthcheck), strings.Join(df.BaseImages, ", ")})
+	}
+	table.Render()
+
+	if len(c.BaseImages) > 0 {
+		fmt.Println("Base images:")
+		for _, img := range c.BaseImages {
+			pin := "⚠️ not pinned by digest"
+			if img.Pinned {
+				pin = "📌 " + img.Digest
+			}
+			name := img.Image
+			if img.Tag != "" {
+				name += ":" + img.Tag
+			}
+			fmt.Printf("  • %s (%s) used by %d file(s)\n", name, pin, len(img.Files))
+		}
+	}
+	for _, e := range c.Errors {
+		fmt.Println(WarningStyle.Render("⚠️ " + e))
+	}
+
+	if len(c.Findings) == 0 {
+		fmt.Println(SuccessStyle.Render("✅ No Dockerfile issues found"))
+		return
+	}
+
+	findings := tablewriter.NewWriter(os.Stdout)
+	findings.Header([]string{"Severity", "File", "Line", "Rule", "Description"})
+	for _, f := range c.Findings {
+		line := "-"
+		if f.Line > 0 {
+			line = strconv.Itoa(f.Line)
+		}
+		findings.Append([]string{f.Severity, f.File, line, f.Rule, f.Description})
+	}
+	findings.Render()
+
+	fmt.Println(WarningStyle.Render(fmt.Sprintf("⚠️ %d Dockerfile issues (🟠 %d high, 🟡 %d medium, 🟢 %d low)",
+		len(c.Findings), c.HighCount, c.MediumCount, c.LowCount)))
+}
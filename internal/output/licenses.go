@@ -0,0 +1,77 @@
+package output
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
+	"github.com/olekukonko/tablewriter"
+)
+
+// PrintLicenseCompliance prints the dependency license report, listing
+// every dependency that is not allowed by the policy
+func PrintLicenseCompliance(r *analyzer.LicenseComplianceReport) {
+	fmt.Println(SectionStyle.Render("\n⚖️ Dependency License Compliance"))
+	fmt.Printf("Policy: %s\n", r.Policy)
+	fmt.Printf("Dependencies: %d (🟢 %d allowed, 🟡 %d flagged, 🔴 %d denied, %d unknown)\n",
+		len(r.Dependencies), r.Allowed, r.Flagged, r.Denied, r.Unresolved)
+
+	if r.Flagged+r.Denied == 0 {
+		fmt.Println(SuccessStyle.Render("✅ All dependency licenses are allowed by the policy"))
+		return
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header([]string{"Status", "Package", "Version", "Ecosystem", "License", "Reason"})
+	for _, dep := range r.Dependencies {
+		if dep.Status == analyzer.LicenseAllowed {
+			continue
+		}
+		license := dep.License
+		if license == "" {
+			license = "unknown"
+		}
+		table.Append([]string{dep.Status, dep.Package, dep.Version, dep.Ecosystem, license, dep.Reason})
+	}
+	table.Render()
+
+	if r.Compliant {
+		fmt.Println(WarningStyle.Render("⚠️ Flagged licenses need review"))
+	} else {
+		fmt.Println(ErrorStyle.Render(fmt.Sprintf("❌ %d dependencies use licenses denied by the policy", r.Denied)))
+	}
+}
+
+// PrintLicense prints the project's own license with the permissions it
+// grants and any compatibility warnings
+func PrintLicense(la *analyzer.LicenseAnalysis) {
+	fmt.Println(SectionStyle.Render("\n📜 License"))
+
+	lic := la.MainLicense
+	if lic == nil {
+		fmt.Println(ErrorStyle.Render("❌ No license detected"))
+	} else {
+		fmt.Printf("%s (%s), %s, found in %s (%d%% confidence)\n",
+			lic.Name, lic.SPDX, lic.Category, lic.SourceFile, lic.Confidence)
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.Header([]string{"Commercial use", "Modification", "Distribution", "Patent grant"})
+		table.Append([]string{permission(lic.Commercial), permission(lic.Modify), permission(lic.Distribute), permission(lic.Patent)})
+		table.Render()
+	}
+
+	for _, dl := range la.DeclaredLicenses {
+		fmt.Printf("Declared in %s: %s\n", dl.Source, dl.Expression)
+	}
+	fmt.Printf("License score: %d/100, compatibility: %s\n", la.LicenseScore, la.Compatibility)
+	for _, w := range la.Warnings {
+		fmt.Println(WarningStyle.Render("⚠️ " + w))
+	}
+}
+
+func permission(ok bool) string {
+	if ok {
+		return "✅"
+	}
+	return "❌"
+}
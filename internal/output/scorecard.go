@@ -0,0 +1,47 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
+	"github.com/olekukonko/tablewriter"
+)
+
+// PrintScorecard prints every Scorecard check with its score, followed by
+// the details of the checks that did not score full marks
+func PrintScorecard(r *analyzer.ScorecardResult) {
+	fmt.Println(SectionStyle.Render("\n🛡️ Scorecard"))
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header([]string{"", "Check", "Score", "Risk", "Reason"})
+	for _, c := range r.Checks {
+		score := "?"
+		if c.Score != analyzer.InconclusiveScore {
+			score = strconv.Itoa(c.Score) + "/10"
+		}
+		table.Append([]string{analyzer.GetScorecardEmoji(c.Score), c.Name, score, c.Risk, c.Reason})
+	}
+	table.Render()
+
+	for _, c := range r.Checks {
+		if c.Score == 10 || len(c.Details) == 0 {
+			continue
+		}
+		fmt.Printf("\n%s:\n", c.Name)
+		for _, d := range c.Details {
+			fmt.Printf("  - %s\n", d)
+		}
+	}
+
+	summary := fmt.Sprintf("\nAggregate score: %.1f/10", r.Score)
+	switch {
+	case r.Score >= 8:
+		fmt.Println(SuccessStyle.Render(summary))
+	case r.Score >= 5:
+		fmt.Println(WarningStyle.Render(summary))
+	default:
+		fmt.Println(ErrorStyle.Render(summary))
+	}
+}
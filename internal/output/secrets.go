@@ -0,0 +1,38 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
+	"github.com/olekukonko/tablewriter"
+)
+
+// PrintSecretFindings prints the leaked credentials found in repository
+// files, with the secrets masked
+func PrintSecretFindings(r *analyzer.SecretScanResult) {
+	fmt.Println(SectionStyle.Render("\n🔑 Secret Scan"))
+	fmt.Printf("Files scanned: %d (%d skipped)\n", r.FilesScanned, r.FilesSkipped)
+	if r.Truncated {
+		fmt.Println(WarningStyle.Render("⚠️ File limit reached; not every file was scanned"))
+	}
+	if r.Allowlist != "" {
+		fmt.Printf("Allowlist: %s (%d findings suppressed)\n", r.Allowlist, r.Suppressed)
+	}
+
+	if len(r.Findings) == 0 {
+		fmt.Println(SuccessStyle.Render("✅ No leaked credentials found"))
+		return
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header([]string{"Severity", "File", "Line", "Rule", "Secret", "Fingerprint"})
+	for _, f := range r.Findings {
+		table.Append([]string{f.Severity, f.Path, strconv.Itoa(f.Line), f.Rule, f.Secret, f.Fingerprint})
+	}
+	table.Render()
+
+	fmt.Println(ErrorStyle.Render(fmt.Sprintf("❌ %d leaked credentials found (🔴 %d critical, 🟠 %d high, 🟡 %d medium)",
+		len(r.Findings), r.CriticalCount, r.HighCount, r.MediumCount)))
+}
@@ -626,6 +626,16 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.appConfig.ClearGitHubToken()
 					m.err = fmt.Errorf("GitHub token cleared")
 				}
+			case "o":
+				// Toggle offline vulnerability scanning (cache settings)
+				if m.settingsOption == "cache" && m.appConfig != nil {
+					m.appConfig.SetOfflineVulnScan(!m.appConfig.OfflineVulnScan)
+					if m.appConfig.OfflineVulnScan {
+						m.err = fmt.Errorf("Offline vulnerability scan enabled")
+					} else {
+						m.err = fmt.Errorf("Offline vulnerability scan disabled")
+					}
+				}
 			case "x":
 				// Clean expired entries (cache settings)
 				if m.settingsOption == "cache" && m.cache != nil {
@@ -950,6 +960,15 @@ func (m MainModel) analyzeRepo(repoName string) tea.Cmd {
 		if err != nil {
 			return fmt.Errorf("failed to get commits: %w", err)
 		}
+		// Only the punch card reads the authors' local times
+		localCommits, offsetsRestored := restoreCommitOffsets(client, parts[0], parts[1], commits, time.Now().AddDate(0, 0, -365))
+
+		// A year of daily commit counts for the contribution calendar; the
+		// commit list above is only the latest page
+		var calendar *analyzer.ContributionCalendar
+		if activity, err := client.GetCommitActivity(parts[0], parts[1]); err == nil {
+			calendar = analyzer.CalendarFromCommitActivity(activity, time.Now())
+		}
 		tracker.NextStage()
 
 		// Stage 3: Analyze contributors
@@ -977,10 +996,97 @@ func (m MainModel) analyzeRepo(repoName string) tea.Cmd {
 
 		// Stage 6: Analyze dependencies and contributor insights
 		deps, _ := analyzer.AnalyzeDependencies(client, parts[0], parts[1], repo.DefaultBranch, fileTree)
+		containers := analyzer.AnalyzeContainers(client, parts[0], parts[1], fileTree)
+		analyzer.AddBaseImageDependencies(deps, containers)
+		depGraph := analyzer.BuildDependencyGraph(deps)
 		contributorInsights := analyzer.AnalyzeContributors(contributors)
 
 		// Stage 7: Security vulnerability scan
-		security, _ := analyzer.ScanDependencies(deps)
+		scanOpts := analyzer.ScanOptions{}
+		if m.appConfig != nil {
+			scanOpts.Offline = m.appConfig.OfflineVulnScan
+		}
+		security, err := analyzer.ScanDependenciesWithOptions(deps, scanOpts)
+		securityError := ""
+		if err != nil {
+			securityError = err.Error()
+		}
+
+		// Dependency licenses against ~/.repo-lyzer/license-policy.yml, or the default policy
+		var licenseCompliance *analyzer.LicenseComplianceReport
+		licenseError := ""
+		if policy, err := analyzer.LoadLicensePolicy(""); err != nil {
+			licenseError = err.Error()
+		} else {
+			licenseCompliance = analyzer.CheckLicenseCompliance(deps, policy)
+		}
+
+		// Leaked credentials in repository files; unauthenticated clients get a
+		// smaller file budget to stay within the API rate limit
+		secretOpts := analyzer.SecretScanOptions{}
+		if !client.HasToken() {
+			secretOpts.MaxFiles = 20
+		}
+		secrets, _ := analyzer.ScanSecrets(client, parts[0], parts[1], fileTree, secretOpts)
+
+		// Supply-chain risks in GitHub Actions workflows
+		workflows := analyzer.AuditWorkflows(client, parts[0], parts[1], fileTree, !repo.Private)
+
+		// Code quality with source metrics from a local clone, or a sample of
+		// files fetched through the API
+		sourceOpts := analyzer.SourceMetricsOptions{LocalDir: localClonePath(parts[0], parts[1])}
+		if sourceOpts.LocalDir == "" && !client.HasToken() {
+			sourceOpts.MaxFiles = 20
+		}
+		sourceMetrics := analyzer.AnalyzeSourceMetrics(client, parts[0], parts[1], fileTree, sourceOpts)
+		codeQuality := analyzer.AnalyzeCodeQualityWithSource(repo, fileTree, languages, sourceMetrics)
+		license, _ := analyzer.AnalyzeLicense(client, parts[0], parts[1], fileTree)
+
+		// CODEOWNERS coverage feeds the quality recommendations and the bus-factor risk
+		codeOwners, _ := analyzer.AnalyzeCodeOwners(client, parts[0], parts[1], fileTree, commits)
+		codeQuality.ApplyCodeOwners(codeOwners)
+		busFactor, busRisk = analyzer.AdjustBusRiskForOwnership(busFactor, busRisk, codeOwners)
+
+		// README and docs content rescore the documentation
+		codeQuality.ApplyDocumentation(analyzer.AnalyzeDocumentation(client, parts[0], parts[1], fileTree, commits))
+
+		// Commit message conventions feed the maintenance score
+		commitConventions := analyzer.AnalyzeCommitConventions(commits)
+		codeQuality.ApplyCommitConventions(commitConventions)
+
+		// Workspace packages of a monorepo, each with its own metrics; manifest
+		// names and path commit lookups cost one request per package
+		maxNamedPackages, maxActivityPackages := 50, 20
+		if !client.HasToken() {
+			maxNamedPackages, maxActivityPackages = 5, 5
+		}
+		monorepo := analyzer.DetectWorkspaces(client, parts[0], parts[1], fileTree, maxNamedPackages)
+		analyzer.AnalyzeWorkspacePackages(monorepo, fileTree, deps, security, codeOwners)
+		analyzer.FetchWorkspaceActivity(client, parts[0], parts[1], monorepo, 90, maxActivityPackages)
+
+		// OpenSSF Scorecard-style checks; reviews are fetched per pull request,
+		// so unauthenticated clients look at fewer of them
+		scorecardInputs := &analyzer.ScorecardInputs{
+			Repo:         repo,
+			Commits:      commits,
+			FileTree:     fileTree,
+			Workflows:    workflows,
+			Dependencies: deps,
+			License:      license,
+			CodeQuality:  codeQuality,
+		}
+		maxScorecardPRs := 20
+		if !client.HasToken() {
+			maxScorecardPRs = 5
+		}
+		analyzer.FetchScorecardData(client, parts[0], parts[1], scorecardInputs, maxScorecardPRs)
+		scorecard := analyzer.ComputeScorecard(scorecardInputs)
+
+		// The changelog is checked against the releases the scorecard fetched
+		changelog := analyzer.AnalyzeChangelog(client, parts[0], parts[1], fileTree, scorecardInputs.Releases)
+
+		// File-ownership truck factor, preferring a local clone's full history
+		truckFactor := computeTruckFactor(client, parts[0], parts[1], commits, fileTree)
 		tracker.NextStage()
 
 		// Mark complete
@@ -998,6 +1104,7 @@ func (m MainModel) analyzeRepo(repoName string) tea.Cmd {
 			score,
 			commitsLast90Days,
 			security != nil && security.CriticalCount > 0,
+			leakedSecretCount(secrets),
 		)
 
 		// Generate quality dashboard
@@ -1010,7 +1117,7 @@ func (m MainModel) analyzeRepo(repoName string) tea.Cmd {
 			maturityLevel,
 			maturityScore,
 			security,
-			nil, // codeQuality - not implemented yet
+			codeQuality,
 			deps,
 		)
 
@@ -1026,11 +1133,28 @@ func (m MainModel) analyzeRepo(repoName string) tea.Cmd {
 			MaturityScore:       maturityScore,
 			MaturityLevel:       maturityLevel,
 			Dependencies:        deps,
+			Monorepo:            monorepo,
+			DependencyGraph:     depGraph,
 			ContributorInsights: contributorInsights,
 			Security:            security,
+			Secrets:             secrets,
+			Workflows:           workflows,
+			Containers:          containers,
+			CodeQuality:         codeQuality,
+			License:             license,
+			LicenseCompliance:   licenseCompliance,
 			ContributorActivity: analyzer.AnalyzeContributorActivity(commits),
+			CommitConventions:   commitConventions,
+			PunchCard:           analyzer.AnalyzePunchCard(localCommits, offsetsRestored > 0),
+			Calendar:            calendar,
 			RiskAlerts:          riskAlerts,
 			QualityDashboard:    qualityDashboard,
+			TruckFactor:         truckFactor,
+			CodeOwners:          codeOwners,
+			Scorecard:           scorecard,
+			Changelog:           changelog,
+			SecurityError:       securityError,
+			LicenseError:        licenseError,
 		}
 
 		// Save to cache
@@ -1042,6 +1166,107 @@ func (m MainModel) analyzeRepo(repoName string) tea.Cmd {
 	}
 }
 
+// maxTruckFactorCommits caps per-commit API requests when no local clone
+// exists; unauthenticated clients share 60 requests an hour with every other
+// stage, so they only sample a few commits
+const (
+	maxTruckFactorCommits        = 100
+	maxTruckFactorCommitsNoToken = 10
+)
+
+// leakedSecretCount returns the number of secret findings, or 0 when the
+// scan did not run
+func leakedSecretCount(secrets *analyzer.SecretScanResult) int {
+	if secrets == nil {
+		return 0
+	}
+	return len(secrets.Findings)
+}
+
+// computeTruckFactor builds file change history from a local clone on the
+// Desktop (as created by cloneRepo) or, failing that, from the GitHub API.
+func computeTruckFactor(client *github.Client, owner, repo string, commits []github.Commit, fileTree []github.TreeEntry) *analyzer.TruckFactorResult {
+	var files []string
+	for _, entry := range fileTree {
+		if entry.Type == "blob" {
+			files = append(files, entry.Path)
+		}
+	}
+
+	if clonePath := localClonePath(owner, repo); clonePath != "" {
+		if changes, n, err := analyzer.ReadLocalFileChanges(clonePath); err == nil && len(changes) > 0 {
+			result := analyzer.CalculateTruckFactor(changes, files)
+			result.CommitsAnalyzed = n
+			result.Source = "local clone"
+			return result
+		}
+	}
+
+	limit := maxTruckFactorCommits
+	if !client.HasToken() {
+		limit = maxTruckFactorCommitsNoToken
+	}
+	details := client.GetCommitDetails(owner, repo, commits, limit)
+	result := analyzer.CalculateTruckFactor(analyzer.FileChangesFromCommits(details), files)
+	result.CommitsAnalyzed = len(details)
+	result.Source = "github api"
+	if !client.HasToken() {
+		result.Note = fmt.Sprintf("Only the latest %d commits were sampled; set GITHUB_TOKEN or clone the repository for full history", limit)
+	}
+	return result
+}
+
+// restoreCommitOffsets returns a copy of commits with the authors' UTC
+// offsets, which the REST API drops, restored from a local clone or else the
+// GraphQL API, and the number of commits whose dates were restored. commits
+// itself keeps the API's UTC dates the other analyzers and the cache use.
+func restoreCommitOffsets(client *github.Client, owner, repo string, commits []github.Commit, since time.Time) ([]github.Commit, int) {
+	local := make([]github.Commit, len(commits))
+	copy(local, commits)
+
+	applied := 0
+	if clonePath := localClonePath(owner, repo); clonePath != "" {
+		if dates, err := analyzer.ReadLocalCommitDates(clonePath, since); err == nil {
+			applied = analyzer.ApplyCommitDates(local, dates)
+		}
+	}
+	if applied < len(local) && client.HasToken() {
+		dates, _ := client.GetCommitDates(owner, repo, since, len(local))
+		applied += analyzer.ApplyCommitDates(local, dates)
+	}
+	return local, applied
+}
+
+// localClonePath returns the Desktop clone path for owner/repo if it is a git
+// checkout whose origin points at that repository
+func localClonePath(owner, repo string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	clonePath := filepath.Join(home, "Desktop", repo)
+	if _, err := os.Stat(filepath.Join(clonePath, ".git")); err != nil {
+		return ""
+	}
+	out, err := exec.Command("git", "-C", clonePath, "remote", "get-url", "origin").Output()
+	if err != nil || !remoteMatchesRepo(strings.TrimSpace(string(out)), owner, repo) {
+		return ""
+	}
+	return clonePath
+}
+
+// remoteMatchesRepo reports whether a git remote URL (HTTPS, SSH or scp-style)
+// points at owner/repo on GitHub
+func remoteMatchesRepo(remote, owner, repo string) bool {
+	remote = strings.TrimSuffix(strings.TrimSuffix(remote, "/"), ".git")
+	for _, prefix := range []string{"https://github.com/", "http://github.com/", "ssh://git@github.com/", "git@github.com:", "git://github.com/"} {
+		if rest, ok := strings.CutPrefix(remote, prefix); ok {
+			return strings.EqualFold(rest, owner+"/"+repo)
+		}
+	}
+	return false
+}
+
 func (m MainModel) checkOwnership() bool {
 	client := github.NewClient()
 	user, err := client.GetUser()
@@ -1675,11 +1900,17 @@ Theme changes are applied immediately!
 				autoStr = "On"
 			}
 
+			vulnScan := "Online (osv.dev)"
+			if m.appConfig != nil && m.appConfig.OfflineVulnScan {
+				vulnScan = "Offline (~/.repo-lyzer/vulndb)"
+			}
+
 			cacheInfo = fmt.Sprintf(`
 Status: %s
 Auto-cache: %s
 TTL: %s
 Max Size: %d MB
+Vulnerability scan: %s
 
 Statistics:
   • Total repos cached: %d
@@ -1693,7 +1924,8 @@ Keybindings:
   • Press 'a' to toggle auto-cache
   • Press 'c' to clear all cache
   • Press 'x' to clean expired entries
-`, enabledStr, autoStr, cache.FormatTTL(cfg.TTL), cfg.MaxSize,
+  • Press 'o' to toggle offline vulnerability scan
+`, enabledStr, autoStr, cache.FormatTTL(cfg.TTL), cfg.MaxSize, vulnScan,
 				stats.TotalRepos, stats.ValidRepos, stats.ExpiredRepos,
 				stats.TotalSizeMB, stats.CacheDir)
 		}
@@ -0,0 +1,19 @@
+package ui
+
+import "testing"
+
+func TestRemoteMatchesRepo(t *testing.T) {
+	for remote, want := range map[string]bool{
+		"https://github.com/acme/widget.git":   true,
+		"https://github.com/Acme/Widget":       true,
+		"git@github.com:acme/widget.git":       true,
+		"ssh://git@github.com/acme/widget.git": true,
+		"https://github.com/someone/widget":    false,
+		"https://gitlab.com/acme/widget.git":   false,
+		"git@github.com:acme/widget-fork.git":  false,
+	} {
+		if got := remoteMatchesRepo(remote, "acme", "widget"); got != want {
+			t.Errorf("remoteMatchesRepo(%q) = %v, want %v", remote, got, want)
+		}
+	}
+}
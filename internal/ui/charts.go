@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -68,3 +70,117 @@ func RenderCommitActivity(data map[string]int, maxDays int) string {
 	}
 	return sb.String()
 }
+
+// heatLevel buckets count relative to max into levels, where only a zero
+// count gets level 0
+func heatLevel(count, max, levels int) int {
+	if count <= 0 || max <= 0 {
+		return 0
+	}
+	return 1 + (count*(levels-1)-1)/max
+}
+
+// heatColor picks the active theme's heatmap color for count relative to max
+func heatColor(count, max int) lipgloss.Style {
+	levels := CurrentTheme.HeatLevels()
+	return lipgloss.NewStyle().Foreground(levels[heatLevel(count, max, len(levels))])
+}
+
+// heatLegend renders the heatmap colors from least to most activity
+func heatLegend() string {
+	var sb strings.Builder
+	sb.WriteString("Less ")
+	for _, c := range CurrentTheme.HeatLevels() {
+		sb.WriteString(lipgloss.NewStyle().Foreground(c).Render("■"))
+	}
+	sb.WriteString(" More")
+	return sb.String()
+}
+
+// punchCardMax returns the busiest cell of a punch card
+func punchCardMax(pc *analyzer.PunchCard) int {
+	max := 0
+	for _, hours := range pc.Matrix {
+		for _, n := range hours {
+			if n > max {
+				max = n
+			}
+		}
+	}
+	return max
+}
+
+// RenderPunchCard draws commits by weekday and hour as a colored heatmap
+func RenderPunchCard(pc *analyzer.PunchCard) string {
+	var sb strings.Builder
+	sb.WriteString(TitleStyle.Render("🕒 Commit Punch Card") + "\n")
+
+	max := punchCardMax(pc)
+	sb.WriteString("     ")
+	for hour := 0; hour < 24; hour += 3 {
+		sb.WriteString(fmt.Sprintf("%-6d", hour))
+	}
+	sb.WriteString("\n")
+	for day, hours := range pc.Matrix {
+		sb.WriteString(dateStyle.Render(time.Weekday(day).String()[:3]) + "  ")
+		for _, n := range hours {
+			sb.WriteString(heatColor(n, max).Render("██"))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(heatLegend())
+	return sb.String()
+}
+
+// RenderContributionCalendar draws a contribution calendar with weeks as
+// columns and weekdays as rows, marking the selected week below it
+func RenderContributionCalendar(cal *analyzer.ContributionCalendar, selected int) string {
+	var sb strings.Builder
+	title := "📅 Contributions"
+	if cal.Author != "" {
+		title += " by " + cal.Author
+	}
+	sb.WriteString(TitleStyle.Render(title) + "\n")
+
+	// Month labels over the first week of each month, when they fit
+	months := []rune(strings.Repeat(" ", len(cal.Weeks)+3))
+	next := 0
+	for i, w := range cal.Weeks {
+		if i >= next && (i == 0 || w.Start.Month() != cal.Weeks[i-1].Start.Month()) {
+			label := w.Start.Format("Jan")
+			if i+len(label) > len(cal.Weeks) {
+				break
+			}
+			copy(months[i:], []rune(label))
+			next = i + len(label) + 1
+		}
+	}
+	sb.WriteString("    " + strings.TrimRight(string(months), " ") + "\n")
+
+	for day := 0; day < 7; day++ {
+		label := "   "
+		if day%2 == 1 {
+			label = time.Weekday(day).String()[:3]
+		}
+		sb.WriteString(dateStyle.Render(label) + " ")
+		for _, w := range cal.Weeks {
+			if !cal.Covers(w) {
+				sb.WriteString(dateStyle.Render("·"))
+				continue
+			}
+			sb.WriteString(heatColor(w.Days[day], cal.Max).Render("■"))
+		}
+		sb.WriteString("\n")
+	}
+
+	if selected >= 0 && selected < len(cal.Weeks) {
+		sb.WriteString("    " + strings.Repeat(" ", selected) + countStyle.Render("▲") + "\n")
+	}
+	if cal.Since != nil {
+		sb.WriteString(fmt.Sprintf("%d commits since %s • ", cal.Total, cal.Since.Format("Jan 2")) + heatLegend())
+	} else {
+		sb.WriteString(fmt.Sprintf("%d commits in the last year • ", cal.Total) + heatLegend())
+	}
+	return sb.String()
+}
@@ -18,14 +18,21 @@ type dashboardView int
 const (
 	viewOverview dashboardView = iota
 	viewQualityDashboard
+	viewCodeQuality
 	viewRepo
+	viewReleases
 	viewLanguages
 	viewActivity
 	viewContributors
 	viewContributorInsights
 	viewContributorActivity
+	viewTruckFactor
 	viewDependencies
+	viewPackages
 	viewSecurity
+	viewScorecard
+	viewProjectLicense
+	viewLicenses
 	viewRecruiter
 	viewAPIStatus
 )
@@ -39,12 +46,18 @@ type DashboardModel struct {
 	statusMsg   string
 	currentView dashboardView
 	showHelp    bool
-	cacheStatus string // "fresh", "cached", or ""
+	cacheStatus string          // "fresh", "cached", or ""
+	depCursor   int             // Selected row in the dependency tree
+	depExpanded map[string]bool // Expanded dependency tree rows, keyed by path
+	pkgCursor   int             // Selected monorepo package
+	calWeek     int             // Selected contribution calendar week
+	calAuthor   int             // Calendar contributor: 0 for everyone, else an index into Contributors plus one
 }
 
 func NewDashboardModel() DashboardModel {
 	return DashboardModel{
 		currentView: viewOverview,
+		calWeek:     analyzer.CalendarWeeks - 1,
 	}
 }
 
@@ -52,6 +65,11 @@ func (m DashboardModel) Init() tea.Cmd { return nil }
 
 func (m *DashboardModel) SetData(data AnalysisResult) {
 	m.data = data
+	m.depCursor = 0
+	m.depExpanded = nil
+	m.pkgCursor = 0
+	m.calWeek = analyzer.CalendarWeeks - 1
+	m.calAuthor = 0
 }
 
 func (m *DashboardModel) SetCacheStatus(status string) {
@@ -159,6 +177,94 @@ func (m DashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
+		case "d":
+			if m.showExport {
+				return m, func() tea.Msg {
+					path, err := ExportDependencyGraph(m.data, "dot")
+					if err != nil {
+						return exportMsg{err, ""}
+					}
+					return exportMsg{nil, "✓ Exported to " + path}
+				}
+			}
+
+		case "g":
+			if m.showExport {
+				return m, func() tea.Msg {
+					path, err := ExportDependencyGraph(m.data, "json")
+					if err != nil {
+						return exportMsg{err, ""}
+					}
+					return exportMsg{nil, "✓ Exported to " + path}
+				}
+			}
+
+		case "s":
+			if m.showExport {
+				return m, func() tea.Msg {
+					path, err := ExportSBOM(m.data, "cyclonedx")
+					if err != nil {
+						return exportMsg{err, ""}
+					}
+					return exportMsg{nil, "✓ Exported to " + path}
+				}
+			}
+
+		case "v":
+			if m.showExport {
+				return m, func() tea.Msg {
+					path, err := ExportSBOM(m.data, "spdx")
+					if err != nil {
+						return exportMsg{err, ""}
+					}
+					return exportMsg{nil, "✓ Exported to " + path}
+				}
+			}
+
+		case "up":
+			if m.currentView == viewDependencies && m.depCursor > 0 {
+				m.depCursor--
+			}
+			if m.currentView == viewPackages && m.pkgCursor > 0 {
+				m.pkgCursor--
+			}
+			if m.currentView == viewActivity && m.calAuthor > 0 {
+				m.calAuthor--
+			}
+
+		case "down":
+			if m.currentView == viewDependencies && m.depCursor < len(m.dependencyTreeRows())-1 {
+				m.depCursor++
+			}
+			if m.currentView == viewPackages && m.data.Monorepo != nil && m.pkgCursor < len(m.data.Monorepo.Packages)-1 {
+				m.pkgCursor++
+			}
+			if m.currentView == viewActivity && m.calAuthor < len(m.data.Contributors) {
+				m.calAuthor++
+			}
+
+		case "[":
+			if m.currentView == viewActivity && m.calWeek > 0 {
+				m.calWeek--
+			}
+
+		case "]":
+			if m.currentView == viewActivity && m.calWeek < analyzer.CalendarWeeks-1 {
+				m.calWeek++
+			}
+
+		case "enter", " ":
+			if m.currentView == viewDependencies {
+				rows := m.dependencyTreeRows()
+				if m.depCursor < len(rows) && rows[m.depCursor].hasChildren {
+					if m.depExpanded == nil {
+						m.depExpanded = make(map[string]bool)
+					}
+					key := rows[m.depCursor].path
+					m.depExpanded[key] = !m.depExpanded[key]
+				}
+			}
+
 		case "f":
 			return m, func() tea.Msg { return "switch_to_tree" }
 
@@ -234,8 +340,12 @@ func (m DashboardModel) View() string {
 		content = m.overviewView()
 	case viewQualityDashboard:
 		content = m.qualityDashboardView()
+	case viewCodeQuality:
+		content = m.codeQualityView()
 	case viewRepo:
 		content = m.repoView()
+	case viewReleases:
+		content = m.releasesView()
 	case viewLanguages:
 		content = m.languagesView()
 	case viewActivity:
@@ -248,10 +358,21 @@ func (m DashboardModel) View() string {
 	case viewContributorActivity:
 		content = m.contributorActivityView()
 
+	case viewTruckFactor:
+		content = m.truckFactorView()
+
 	case viewDependencies:
 		content = m.dependenciesView()
+	case viewPackages:
+		content = m.packagesView()
 	case viewSecurity:
 		content = m.securityView()
+	case viewScorecard:
+		content = m.scorecardView()
+	case viewProjectLicense:
+		content = m.projectLicenseView()
+	case viewLicenses:
+		content = m.licensesView()
 	case viewRecruiter:
 		content = m.recruiterView()
 	case viewAPIStatus:
@@ -262,7 +383,7 @@ func (m DashboardModel) View() string {
 		content = lipgloss.JoinVertical(
 			lipgloss.Left,
 			content,
-			CardStyle.Render("📥 Export Options:\n[J] JSON  [M] Markdown  [C] CSV  [X] HTML  [P] PDF"),
+			CardStyle.Render("📥 Export Options:\n[J] JSON  [M] Markdown  [C] CSV  [X] HTML  [P] PDF\n[D] Dependency graph (DOT)  [G] Dependency graph (JSON)\n[S] SBOM (CycloneDX)  [V] SBOM (SPDX)"),
 		)
 	}
 
@@ -296,7 +417,7 @@ func (m DashboardModel) View() string {
 }
 
 func (m DashboardModel) renderTabs() string {
-	views := []string{"Overview", "Quality", "Repo", "Langs", "Activity", "Contribs", "Insights", "Engagement", "Deps", "Security", "Recruiter", "API"}
+	views := []string{"Overview", "Quality", "Code", "Repo", "Releases", "Langs", "Activity", "Contribs", "Insights", "Engagement", "Truck", "Deps", "Packages", "Security", "Scorecard", "License", "Dep Licenses", "Recruiter", "API"}
 
 	var renderedTabs []string
 
@@ -386,6 +507,88 @@ func (m DashboardModel) repoView() string {
 	return lipgloss.JoinVertical(lipgloss.Left, header, CardStyle.Render(info))
 }
 
+// releasesView shows the maturity level next to the parsed changelog and
+// how it lines up with tags and releases
+func (m DashboardModel) releasesView() string {
+	header := TitleStyle.Render(" Releases & Changelog ")
+
+	summary := []string{fmt.Sprintf("Maturity: %s (%d)", m.data.MaturityLevel, m.data.MaturityScore)}
+	cl := m.data.Changelog
+	if cl == nil {
+		summary = append(summary, "", "No release data available")
+		return lipgloss.JoinVertical(lipgloss.Left, header, CardStyle.Render(strings.Join(summary, "\n")))
+	}
+	summary = append(summary,
+		fmt.Sprintf("Latest release:    %s", orDash(cl.LatestRelease)),
+		fmt.Sprintf("Releases:          %d (%d without notes)", cl.Releases, cl.ReleasesWithoutNotes),
+	)
+	if cl.File == "" {
+		summary = append(summary, "", "❌ No changelog found")
+	} else {
+		summary = append(summary,
+			"",
+			fmt.Sprintf("Changelog:         %s (%s)", cl.File, cl.Format),
+			fmt.Sprintf("Latest documented: %s", orDash(cl.LatestVersion)),
+			fmt.Sprintf("Versions:          %d", len(cl.Versions)),
+			fmt.Sprintf("Entries/release:   %.1f", cl.AvgEntriesPerRelease),
+			fmt.Sprintf("Unreleased:        %d entries", cl.UnreleasedEntries),
+		)
+	}
+	content := CardStyle.Render(strings.Join(summary, "\n"))
+
+	if cl.File != "" {
+		checks := []string{"Missing from changelog:"}
+		if len(cl.MissingVersions) == 0 {
+			checks = append(checks, "✅ Every release is documented")
+		}
+		for i, v := range cl.MissingVersions {
+			if i == 5 {
+				checks = append(checks, fmt.Sprintf("... %d more", len(cl.MissingVersions)-5))
+				break
+			}
+			checks = append(checks, "• "+v)
+		}
+		if len(cl.UntaggedVersions) > 0 {
+			checks = append(checks, "", "Never tagged: "+strings.Join(cl.UntaggedVersions, ", "))
+		}
+		if len(cl.Categories) > 0 {
+			var cats []string
+			for cat := range cl.Categories {
+				cats = append(cats, cat)
+			}
+			sort.Slice(cats, func(i, j int) bool {
+				if cl.Categories[cats[i]] != cl.Categories[cats[j]] {
+					return cl.Categories[cats[i]] > cl.Categories[cats[j]]
+				}
+				return cats[i] < cats[j]
+			})
+			checks = append(checks, "", "Entries by category:")
+			for _, cat := range cats {
+				checks = append(checks, fmt.Sprintf("  • %-12s %d", cat, cl.Categories[cat]))
+			}
+		}
+		content = lipgloss.JoinHorizontal(lipgloss.Top, content, CardStyle.Render(strings.Join(checks, "\n")))
+
+		versions := []string{"Recent versions:"}
+		for i, v := range cl.Versions {
+			if i == 6 {
+				break
+			}
+			name := v.Version
+			if v.Unreleased {
+				name = "Unreleased"
+			}
+			versions = append(versions, fmt.Sprintf("• %-12s %-10s %d entries", name, orDash(v.Date), len(v.Entries)))
+		}
+		content += "\n" + CardStyle.Render(strings.Join(versions, "\n"))
+	}
+
+	if len(cl.Recommendations) > 0 {
+		content += "\n" + CardStyle.Render("Recommendations:\n"+strings.Join(cl.Recommendations, "\n"))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, header, content)
+}
+
 func (m DashboardModel) languagesView() string {
 	header := TitleStyle.Render(" Languages ")
 
@@ -431,7 +634,130 @@ func (m DashboardModel) activityView() string {
 	totalCommits := len(m.data.Commits)
 	stats := fmt.Sprintf("\nTotal Commits (Last Year): %d", totalCommits)
 
-	return lipgloss.JoinVertical(lipgloss.Left, header, CardStyle.Render(chart+stats))
+	content := CardStyle.Render(chart + stats)
+	if cc := m.data.CommitConventions; cc != nil && cc.TotalCommits > 0 {
+		content = lipgloss.JoinHorizontal(lipgloss.Top, content, m.commitConventionsCard())
+	}
+	if len(m.data.Commits) > 0 {
+		content += "\n" + m.calendarCard()
+	}
+	if pc := m.data.PunchCard; pc != nil && pc.TotalCommits > 0 {
+		content += "\n" + lipgloss.JoinHorizontal(lipgloss.Top, CardStyle.Render(RenderPunchCard(pc)), m.workPatternsCard())
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, header, content)
+}
+
+// calendarCard shows the contribution calendar of everyone or the selected
+// contributor, with the commits of the selected week
+func (m DashboardModel) calendarCard() string {
+	author := ""
+	if m.calAuthor > 0 && m.calAuthor <= len(m.data.Contributors) {
+		author = m.data.Contributors[m.calAuthor-1].Login
+	}
+	// The repository-wide calendar comes from the activity statistics; a
+	// contributor's is built from the fetched commits only, which stop at
+	// one page
+	cal := m.data.Calendar
+	partial := len(m.data.Commits) >= github.CommitsPerPage
+	if author != "" || cal == nil {
+		cal = analyzer.BuildContributionCalendar(m.data.Commits, author, time.Now())
+		if partial {
+			cal.MarkPartial(m.data.Commits)
+		}
+	}
+	calendar := RenderContributionCalendar(cal, m.calWeek)
+	if cal.Since != nil {
+		calendar += "\n" + SubtleStyle.Render(fmt.Sprintf("⚠️ Partial: only the latest %d commits were fetched, back to %s", len(m.data.Commits), cal.Since.Format("2006-01-02")))
+	} else if author != "" || m.data.Calendar == nil {
+		calendar += "\n" + SubtleStyle.Render(fmt.Sprintf("From the latest %d commits", len(m.data.Commits)))
+	}
+	calendar += "\n" + SubtleStyle.Render("[/] select week • ↑/↓ select contributor")
+
+	week := cal.Weeks[m.calWeek]
+	commits := analyzer.CommitsInWeek(m.data.Commits, author, week.Start)
+	lines := []string{fmt.Sprintf("Week of %s: %d commits", week.Start.Format("2006-01-02"), week.Total)}
+	if !cal.Covers(week) {
+		lines[0] = fmt.Sprintf("Week of %s: unknown", week.Start.Format("2006-01-02"))
+		lines = append(lines, SubtleStyle.Render("Older than the fetched commits"))
+	} else if len(commits) < week.Total {
+		lines = append(lines, SubtleStyle.Render(fmt.Sprintf("%d of them fetched", len(commits))))
+	} else if cal.Since != nil && week.Start.Before(*cal.Since) {
+		lines = append(lines, SubtleStyle.Render("Only partly covered by the fetched commits"))
+	}
+	for i, c := range commits {
+		if i == 8 {
+			lines = append(lines, fmt.Sprintf("... %d more", len(commits)-8))
+			break
+		}
+		subject, _, _ := strings.Cut(c.Commit.Message, "\n")
+		sha := c.SHA
+		if len(sha) > 7 {
+			sha = sha[:7]
+		}
+		who := c.Commit.Author.Name
+		if c.Author != nil && c.Author.Login != "" {
+			who = c.Author.Login
+		}
+		if r := []rune(subject); len(r) > 50 {
+			subject = string(r[:47]) + "..."
+		}
+		lines = append(lines, fmt.Sprintf("• %s %s (%s)", sha, subject, who))
+	}
+
+	return lipgloss.JoinHorizontal(lipgloss.Top, CardStyle.Render(calendar), CardStyle.Render(strings.Join(lines, "\n")))
+}
+
+// workPatternsCard summarizes when and where contributors commit
+func (m DashboardModel) workPatternsCard() string {
+	pc := m.data.PunchCard
+	lines := []string{
+		"Work patterns:",
+		fmt.Sprintf("Peak:         %s %02d:00", pc.PeakDay, pc.PeakHour),
+		fmt.Sprintf("Weekends:     %d (%.0f%%)", pc.WeekendCommits, pc.WeekendRatio*100),
+		fmt.Sprintf("After hours:  %d (%.0f%%)", pc.AfterHoursCommits, pc.AfterHoursRatio*100),
+	}
+	if !pc.KnownOffsets {
+		lines = append(lines, "", "Timestamps are in UTC;", "contributor timezones unknown")
+		return CardStyle.Render(strings.Join(lines, "\n"))
+	}
+	lines = append(lines, fmt.Sprintf("TZ spread:    %d hours", pc.TimezoneSpread), "", "Contributor timezones:")
+	for i, tz := range pc.Timezones {
+		if i == 6 {
+			lines = append(lines, fmt.Sprintf("  ... %d more", len(pc.Timezones)-6))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("  • %-10s %d", tz.Offset, tz.Contributors))
+	}
+	return CardStyle.Render(strings.Join(lines, "\n"))
+}
+
+// commitConventionsCard summarizes commit message hygiene for the activity view
+func (m DashboardModel) commitConventionsCard() string {
+	cc := m.data.CommitConventions
+	lines := []string{
+		"Commit messages:",
+		fmt.Sprintf("Conventional:  %d (%.0f%%)", cc.Conventional, cc.ConventionalRatio*100),
+	}
+	for i, tc := range cc.Types {
+		if i == 5 {
+			break
+		}
+		lines = append(lines, fmt.Sprintf("  • %-9s %d", tc.Type, tc.Count))
+	}
+	lines = append(lines,
+		fmt.Sprintf("Breaking:      %d", cc.BreakingChanges),
+		fmt.Sprintf("Issue refs:    %d (%.0f%%)", cc.IssueReferences, cc.IssueRefRatio*100),
+		fmt.Sprintf("Short / WIP:   %d / %d", cc.ShortMessages, cc.WIPMessages),
+		fmt.Sprintf("Merges:        %d (%.0f%%)", cc.MergeCommits, cc.MergeRatio*100),
+		fmt.Sprintf("Signed off:    %d (%.0f%%)", cc.SignedOff, cc.SignedOffRatio*100),
+	)
+	if len(cc.PoorMessages) > 0 {
+		lines = append(lines, "", "Low-effort messages:")
+		for _, msg := range cc.PoorMessages {
+			lines = append(lines, fmt.Sprintf("  • %q", msg))
+		}
+	}
+	return CardStyle.Render(strings.Join(lines, "\n"))
 }
 
 func (m DashboardModel) contributorsView() string {
@@ -542,6 +868,98 @@ func renderBar(label string, value, max, width int) string {
 	return fmt.Sprintf("%-8s | %-*s %d", label, width, bar, value)
 }
 
+func (m DashboardModel) truckFactorView() string {
+	header := TitleStyle.Render(" Truck Factor (File Ownership) ")
+
+	tf := m.data.TruckFactor
+	if tf == nil || tf.TotalFiles == 0 {
+		msg := "No file authorship data available"
+		if tf != nil && tf.Note != "" {
+			msg += "\n" + tf.Note
+		}
+		return lipgloss.JoinVertical(lipgloss.Left, header, CardStyle.Render(msg), m.codeOwnersCard())
+	}
+
+	summary := fmt.Sprintf(
+		"Truck Factor:   %d (%s)\n"+
+			"Key Developers: %s\n"+
+			"Orphaned Files: %d/%d (%.1f%%)\n"+
+			"Commits:        %d (%s)",
+		tf.TruckFactor, tf.Risk,
+		strings.Join(tf.KeyDevelopers, ", "),
+		tf.OrphanedFiles, tf.TotalFiles, tf.OrphanedPercent,
+		tf.CommitsAnalyzed, tf.Source,
+	)
+	if tf.Note != "" {
+		summary += "\n⚠️ " + tf.Note
+	}
+
+	var authorLines []string
+	authorLines = append(authorLines, "👤 FILE AUTHORS")
+	maxFiles := 0
+	if len(tf.TopAuthors) > 0 {
+		maxFiles = tf.TopAuthors[0].Files
+	}
+	for _, a := range tf.TopAuthors {
+		authorLines = append(authorLines, renderBar(a.Author, a.Files, maxFiles, 25))
+	}
+
+	dirLines := []string{"📂 ORPHANED DIRECTORIES"}
+	if len(tf.OrphanedDirectories) == 0 {
+		dirLines = append(dirLines, "✅ No directory would be fully orphaned")
+	}
+	for _, dir := range tf.OrphanedDirectories {
+		dirLines = append(dirLines, "• "+dir+"/")
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		CardStyle.Render(summary),
+		lipgloss.JoinHorizontal(lipgloss.Top,
+			CardStyle.Render(strings.Join(authorLines, "\n")),
+			CardStyle.Render(strings.Join(dirLines, "\n")),
+		),
+		m.codeOwnersCard(),
+	)
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, content)
+}
+
+// codeOwnersCard summarizes CODEOWNERS coverage for the truck factor view
+func (m DashboardModel) codeOwnersCard() string {
+	co := m.data.CodeOwners
+	if co == nil {
+		return ""
+	}
+	if co.File == "" {
+		return CardStyle.Render("👥 CODEOWNERS\n❌ No CODEOWNERS file")
+	}
+
+	lines := []string{
+		"👥 CODEOWNERS (" + co.File + ")",
+		fmt.Sprintf("Owned files: %d/%d (%.0f%%)", co.OwnedFiles, co.TotalFiles, co.Coverage*100),
+	}
+	if co.OrphanedFiles > 0 {
+		lines = append(lines, fmt.Sprintf("Owned only by inactive owners: %d files", co.OrphanedFiles))
+	}
+	if len(co.InactiveOwners) > 0 {
+		lines = append(lines, "⚠️ Inactive owners: "+strings.Join(co.InactiveOwners, ", "))
+	}
+	if len(co.UnownedDirectories) > 0 {
+		lines = append(lines, "Unowned directories:")
+		for i, d := range co.UnownedDirectories {
+			if i == 5 {
+				lines = append(lines, fmt.Sprintf("... %d more", len(co.UnownedDirectories)-5))
+				break
+			}
+			lines = append(lines, fmt.Sprintf("• %s/ (%d files)", d.Dir, d.Files))
+		}
+	}
+	for _, e := range co.Errors {
+		lines = append(lines, "⚠️ "+e)
+	}
+	return CardStyle.Render(strings.Join(lines, "\n"))
+}
+
 func (m DashboardModel) dependenciesView() string {
 	header := TitleStyle.Render(" Dependencies ")
 
@@ -556,6 +974,17 @@ func (m DashboardModel) dependenciesView() string {
 		strings.Join(deps.Languages, ", "),
 		boolToYesNo(deps.HasLockFile),
 	)
+	if len(deps.LockFiles) > 0 {
+		summary += fmt.Sprintf(
+			"\nResolved:         %d/%d\nTransitive:       %d",
+			deps.ResolvedDeps,
+			deps.TotalDeps,
+			deps.TransitiveDeps,
+		)
+	}
+	if deps.Note != "" {
+		summary += "\n⚠️ " + deps.Note
+	}
 
 	var depLines []string
 	for _, file := range deps.Files {
@@ -566,7 +995,11 @@ func (m DashboardModel) dependenciesView() string {
 		}
 		for i := 0; i < maxShow; i++ {
 			d := file.Dependencies[i]
-			depLines = append(depLines, fmt.Sprintf("  • %s %s", d.Name, d.Version))
+			if d.Resolved != "" && d.Resolved != d.Version {
+				depLines = append(depLines, fmt.Sprintf("  • %s %s → %s", d.Name, d.Version, d.Resolved))
+			} else {
+				depLines = append(depLines, fmt.Sprintf("  • %s %s", d.Name, d.Version))
+			}
 		}
 		if len(file.Dependencies) > maxShow {
 			depLines = append(depLines, fmt.Sprintf("  ... %d more", len(file.Dependencies)-maxShow))
@@ -574,9 +1007,162 @@ func (m DashboardModel) dependenciesView() string {
 	}
 
 	content := CardStyle.Render(summary) + "\n" + CardStyle.Render(strings.Join(depLines, "\n"))
+
+	graph := m.data.DependencyGraph
+	if graph == nil || len(graph.Nodes) == 0 {
+		return lipgloss.JoinVertical(lipgloss.Left, header, content)
+	}
+
+	graphSummary := fmt.Sprintf(
+		"Packages:   %d\nTransitive: %d\nMax Depth:  %d\nDuplicates: %d",
+		len(graph.Nodes),
+		graph.TransitiveCount(),
+		graph.MaxDepth(),
+		len(graph.Duplicates()),
+	)
+	for i, dup := range graph.Duplicates() {
+		if i == 3 {
+			break
+		}
+		graphSummary += fmt.Sprintf("\n  • %s (%s)", dup.Name, strings.Join(dup.Versions, ", "))
+	}
+
+	// Explain why vulnerable packages are installed
+	var whyLines []string
+	if m.data.Security != nil {
+		seen := make(map[string]bool)
+		for _, v := range m.data.Security.Vulnerabilities {
+			id := v.Package + "@" + v.Version
+			if seen[id] || v.Direct || len(whyLines) >= 3 {
+				continue
+			}
+			seen[id] = true
+			if paths := graph.PathsTo(v.Package, v.Version, 1); len(paths) > 0 {
+				labels := make([]string, len(paths[0]))
+				for i, id := range paths[0] {
+					labels[i] = graph.Node(id).Label()
+				}
+				whyLines = append(whyLines, fmt.Sprintf("%s %s", analyzer.GetSeverityEmoji(v.Severity), strings.Join(labels, " → ")))
+			}
+		}
+	}
+	if len(whyLines) > 0 {
+		graphSummary += "\n\nWhy are vulnerable packages here?\n" + strings.Join(whyLines, "\n")
+	}
+
+	content = lipgloss.JoinVertical(
+		lipgloss.Left,
+		lipgloss.JoinHorizontal(lipgloss.Top, CardStyle.Render(summary), CardStyle.Render(graphSummary)),
+		CardStyle.Render(strings.Join(depLines, "\n")),
+		CardStyle.Render(m.dependencyTreeView()),
+	)
 	return lipgloss.JoinVertical(lipgloss.Left, header, content)
 }
 
+// depTreeRow is a visible row of the expandable dependency tree
+type depTreeRow struct {
+	node        *analyzer.GraphNode
+	path        string // IDs from the root joined by ">", unique per row
+	depth       int
+	hasChildren bool
+	cycle       bool
+}
+
+// dependencyTreeRows flattens the expanded parts of the dependency graph,
+// starting from the direct dependencies.
+func (m DashboardModel) dependencyTreeRows() []depTreeRow {
+	graph := m.data.DependencyGraph
+	if graph == nil {
+		return nil
+	}
+
+	var rows []depTreeRow
+	var walk func(node *analyzer.GraphNode, path string, depth int, ancestors map[string]bool)
+	walk = func(node *analyzer.GraphNode, path string, depth int, ancestors map[string]bool) {
+		row := depTreeRow{
+			node:        node,
+			path:        path,
+			depth:       depth,
+			hasChildren: len(node.Dependencies) > 0,
+			cycle:       ancestors[node.ID],
+		}
+		rows = append(rows, row)
+		if row.cycle || !m.depExpanded[path] {
+			return
+		}
+
+		ancestors[node.ID] = true
+		for _, id := range node.Dependencies {
+			if child := graph.Node(id); child != nil {
+				walk(child, path+">"+id, depth+1, ancestors)
+			}
+		}
+		delete(ancestors, node.ID)
+	}
+
+	for _, root := range graph.Roots() {
+		walk(root, root.ID, 0, make(map[string]bool))
+	}
+	return rows
+}
+
+func (m DashboardModel) dependencyTreeView() string {
+	rows := m.dependencyTreeRows()
+	if len(rows) == 0 {
+		return "Dependency Tree\nNo direct dependencies found in lock files"
+	}
+
+	vulnerable := make(map[string]bool)
+	if m.data.Security != nil {
+		for _, v := range m.data.Security.Vulnerabilities {
+			vulnerable[v.Ecosystem+"|"+v.Package+"@"+v.Version] = true
+		}
+	}
+
+	// Keep the cursor inside a scrolling window
+	const maxRows = 12
+	start := 0
+	if m.depCursor >= maxRows {
+		start = m.depCursor - maxRows + 1
+	}
+	end := start + maxRows
+	if end > len(rows) {
+		end = len(rows)
+	}
+
+	lines := []string{fmt.Sprintf("Dependency Tree (%d direct) • ↑/↓ select • enter expand", len(m.data.DependencyGraph.Roots()))}
+	for i := start; i < end; i++ {
+		row := rows[i]
+		marker := "  "
+		switch {
+		case row.cycle:
+			marker = "↺ "
+		case row.hasChildren && m.depExpanded[row.path]:
+			marker = "▾ "
+		case row.hasChildren:
+			marker = "▸ "
+		}
+
+		line := strings.Repeat("  ", row.depth) + marker + row.node.Label()
+		if row.node.Dev {
+			line += " (dev)"
+		}
+		if vulnerable[row.node.Ecosystem()+"|"+row.node.Label()] {
+			line += " ⚠"
+		}
+		if i == m.depCursor {
+			line = SelectedStyle.Render("> " + line)
+		} else {
+			line = "  " + line
+		}
+		lines = append(lines, line)
+	}
+	if end < len(rows) {
+		lines = append(lines, SubtleStyle.Render(fmt.Sprintf("  ... %d more", len(rows)-end)))
+	}
+	return strings.Join(lines, "\n")
+}
+
 func (m DashboardModel) contributorInsightsView() string {
 	header := TitleStyle.Render(" Insights ")
 
@@ -636,11 +1222,99 @@ func (m DashboardModel) contributorInsightsView() string {
 	return lipgloss.JoinVertical(lipgloss.Left, header, content)
 }
 
+func (m DashboardModel) packagesView() string {
+	header := TitleStyle.Render(" Packages ")
+
+	mono := m.data.Monorepo
+	if mono == nil || len(mono.Packages) == 0 {
+		return lipgloss.JoinVertical(lipgloss.Left, header, CardStyle.Render("No workspace packages found"))
+	}
+
+	summary := fmt.Sprintf("Workspaces: %s (%s)\nPackages:   %d",
+		strings.Join(mono.Tools, ", "), strings.Join(mono.Configs, ", "), len(mono.Packages))
+
+	// Package selector, scrolled to keep the cursor visible
+	maxRows := 12
+	start := 0
+	if m.pkgCursor >= maxRows {
+		start = m.pkgCursor - maxRows + 1
+	}
+	end := start + maxRows
+	if end > len(mono.Packages) {
+		end = len(mono.Packages)
+	}
+	var rows []string
+	for i := start; i < end; i++ {
+		p := mono.Packages[i]
+		line := fmt.Sprintf("%s (%s)", p.Name, p.Path)
+		if i == m.pkgCursor {
+			rows = append(rows, ActiveTabStyle.Render("▶ "+line))
+		} else {
+			rows = append(rows, "  "+line)
+		}
+	}
+	rows = append(rows, SubtleStyle.Render("↑/↓ select a package"))
+
+	p := mono.Packages[m.pkgCursor]
+	activity := "not fetched"
+	if p.RecentCommits >= 0 {
+		activity = fmt.Sprintf("%d commits in 90 days", p.RecentCommits)
+		if p.LastCommit != nil {
+			activity += ", last " + p.LastCommit.Format("2006-01-02")
+		}
+	}
+	detail := fmt.Sprintf(
+		"%s\n\n"+
+			"Tool:            %s\n"+
+			"Files:           %d\n"+
+			"Languages:       %s\n"+
+			"Dependencies:    %d in %s\n"+
+			"Vulnerabilities: %d (%d critical)\n"+
+			"Tests:           %d test files, %.0f%% of %d sources tested\n"+
+			"Owners:          %s (%d/%d files)\n"+
+			"Activity:        %s",
+		TitleStyle.Render(" "+p.Name+" "),
+		p.Tool,
+		p.Files,
+		joinOrNone(languagesBySize(p.Languages)),
+		p.Dependencies, joinOrNone(p.DependencyFiles),
+		p.Vulnerabilities, p.CriticalVulns,
+		p.TestFiles, p.TestCoverage*100, p.SourceFiles,
+		joinOrNone(p.Owners), p.OwnedFiles, p.Files,
+		activity,
+	)
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		header,
+		CardStyle.Render(summary),
+		lipgloss.JoinHorizontal(lipgloss.Top, CardStyle.Render(strings.Join(rows, "\n")), CardStyle.Render(detail)),
+	)
+}
+
+// languagesBySize lists languages from the largest to the smallest
+func languagesBySize(langs map[string]int) []string {
+	var names []string
+	for name := range langs {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if langs[names[i]] != langs[names[j]] {
+			return langs[names[i]] > langs[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
 func (m DashboardModel) securityView() string {
 	header := TitleStyle.Render(" Security ")
 
 	if m.data.Security == nil {
-		return lipgloss.JoinVertical(lipgloss.Left, header, CardStyle.Render("No security scan data"))
+		msg := "No security scan data"
+		if m.data.SecurityError != "" {
+			msg = "⚠️ Vulnerability scan failed: " + m.data.SecurityError
+		}
+		return lipgloss.JoinVertical(lipgloss.Left, header, CardStyle.Render(msg))
 	}
 
 	sec := m.data.Security
@@ -651,6 +1325,12 @@ func (m DashboardModel) securityView() string {
 		sec.SecurityScore, grade, sec.ScannedPackages, sec.TotalCount,
 		sec.CriticalCount, sec.HighCount, sec.MediumCount, sec.LowCount,
 	)
+	if sec.Source != "" {
+		summary += fmt.Sprintf("\nSource: %s", sec.Source)
+	}
+	if len(sec.Failures) > 0 {
+		summary += fmt.Sprintf("\n⚠️ %d packages could not be checked", len(sec.Failures))
+	}
 
 	var vulnLines []string
 	if len(sec.Vulnerabilities) == 0 {
@@ -662,7 +1342,14 @@ func (m DashboardModel) securityView() string {
 		}
 		for i := 0; i < maxShow; i++ {
 			v := sec.Vulnerabilities[i]
-			vulnLines = append(vulnLines, fmt.Sprintf("%s %s - %s", analyzer.GetSeverityEmoji(v.Severity), v.ID, v.Package))
+			line := fmt.Sprintf("%s %s - %s@%s", analyzer.GetSeverityEmoji(v.Severity), v.ID, v.Package, v.Version)
+			if v.Score > 0 {
+				line += fmt.Sprintf(" [CVSS %.1f]", v.Score)
+			}
+			if !v.Direct {
+				line += " (transitive)"
+			}
+			vulnLines = append(vulnLines, line)
 		}
 		if len(sec.Vulnerabilities) > maxShow {
 			vulnLines = append(vulnLines, fmt.Sprintf("... %d more", len(sec.Vulnerabilities)-maxShow))
@@ -670,6 +1357,425 @@ func (m DashboardModel) securityView() string {
 	}
 
 	content := CardStyle.Render(summary) + "\n" + CardStyle.Render(strings.Join(vulnLines, "\n"))
+
+	if len(sec.Upgrades) > 0 {
+		upgradeLines := []string{"Minimal safe upgrades:"}
+		maxShow := 5
+		if len(sec.Upgrades) < maxShow {
+			maxShow = len(sec.Upgrades)
+		}
+		for _, u := range sec.Upgrades[:maxShow] {
+			target := u.SafeVersion
+			if target == "" {
+				target = "no fixed release"
+			}
+			upgradeLines = append(upgradeLines, fmt.Sprintf("• %s %s → %s (fixes %d)", u.Package, u.Current, target, len(u.Fixes)))
+		}
+		if len(sec.Upgrades) > maxShow {
+			upgradeLines = append(upgradeLines, fmt.Sprintf("... %d more", len(sec.Upgrades)-maxShow))
+		}
+		content += "\n" + CardStyle.Render(strings.Join(upgradeLines, "\n"))
+	}
+
+	if sc := m.data.Secrets; sc != nil {
+		secretLines := []string{fmt.Sprintf("Secret scan: %d files scanned", sc.FilesScanned)}
+		if sc.Truncated {
+			secretLines[0] += " (limited)"
+		}
+		if len(sc.Findings) == 0 {
+			secretLines = append(secretLines, "✅ No leaked credentials found")
+		} else {
+			maxShow := 5
+			if len(sc.Findings) < maxShow {
+				maxShow = len(sc.Findings)
+			}
+			for _, f := range sc.Findings[:maxShow] {
+				secretLines = append(secretLines, fmt.Sprintf("%s %s:%d %s %s",
+					analyzer.GetSeverityEmoji(f.Severity), f.Path, f.Line, f.Description, f.Secret))
+			}
+			if len(sc.Findings) > maxShow {
+				secretLines = append(secretLines, fmt.Sprintf("... %d more", len(sc.Findings)-maxShow))
+			}
+		}
+		if sc.Suppressed > 0 {
+			secretLines = append(secretLines, fmt.Sprintf("%d findings allowlisted", sc.Suppressed))
+		}
+		content += "\n" + CardStyle.Render(strings.Join(secretLines, "\n"))
+	}
+
+	if wf := m.data.Workflows; wf != nil && (wf.Workflows > 0 || len(wf.Errors) > 0) {
+		wfLines := []string{fmt.Sprintf("Workflow audit: %d workflows", wf.Workflows)}
+		if len(wf.Findings) == 0 {
+			wfLines = append(wfLines, "✅ No workflow risks found")
+		} else {
+			wfLines[0] += fmt.Sprintf("  🔴 %d  🟠 %d  🟡 %d  🟢 %d", wf.CriticalCount, wf.HighCount, wf.MediumCount, wf.LowCount)
+			maxShow := 5
+			if len(wf.Findings) < maxShow {
+				maxShow = len(wf.Findings)
+			}
+			for _, f := range wf.Findings[:maxShow] {
+				location := f.Workflow
+				if f.Line > 0 {
+					location += fmt.Sprintf(":%d", f.Line)
+				}
+				wfLines = append(wfLines, fmt.Sprintf("%s %s %s", analyzer.GetSeverityEmoji(f.Severity), location, f.Description))
+			}
+			if len(wf.Findings) > maxShow {
+				wfLines = append(wfLines, fmt.Sprintf("... %d more", len(wf.Findings)-maxShow))
+			}
+		}
+		for _, e := range wf.Errors {
+			wfLines = append(wfLines, "⚠️ "+e)
+		}
+		if wf.Note != "" {
+			wfLines = append(wfLines, "⚠️ "+wf.Note)
+		}
+		content += "\n" + CardStyle.Render(strings.Join(wfLines, "\n"))
+	}
+
+	if ct := m.data.Containers; ct != nil && len(ct.Dockerfiles) > 0 {
+		multiStage := 0
+		for _, df := range ct.Dockerfiles {
+			if df.MultiStage {
+				multiStage++
+			}
+		}
+		ctLines := []string{fmt.Sprintf("Dockerfiles: %d (%d multi-stage)", len(ct.Dockerfiles), multiStage)}
+		for _, img := range ct.BaseImages {
+			name := img.Image
+			if img.Tag != "" {
+				name += ":" + img.Tag
+			}
+			ctLines = append(ctLines, fmt.Sprintf("%s %s", checkMark(img.Pinned), name))
+		}
+		if len(ct.Findings) == 0 {
+			ctLines = append(ctLines, "✅ No Dockerfile issues found")
+		} else {
+			maxShow := 5
+			if len(ct.Findings) < maxShow {
+				maxShow = len(ct.Findings)
+			}
+			for _, f := range ct.Findings[:maxShow] {
+				location := f.File
+				if f.Line > 0 {
+					location += fmt.Sprintf(":%d", f.Line)
+				}
+				ctLines = append(ctLines, fmt.Sprintf("%s %s %s", analyzer.GetSeverityEmoji(f.Severity), location, f.Description))
+			}
+			if len(ct.Findings) > maxShow {
+				ctLines = append(ctLines, fmt.Sprintf("... %d more", len(ct.Findings)-maxShow))
+			}
+		}
+		if ct.Note != "" {
+			ctLines = append(ctLines, "⚠️ "+ct.Note)
+		}
+		content += "\n" + CardStyle.Render(strings.Join(ctLines, "\n"))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, header, content)
+}
+
+func (m DashboardModel) scorecardView() string {
+	header := TitleStyle.Render(" Scorecard ")
+
+	sc := m.data.Scorecard
+	if sc == nil {
+		return lipgloss.JoinVertical(lipgloss.Left, header, CardStyle.Render("No scorecard data"))
+	}
+
+	summary := fmt.Sprintf("Aggregate score: %.1f / 10\n", sc.Score) +
+		SubtleStyle.Render("Risk-weighted average of the conclusive checks")
+
+	var lines []string
+	for _, c := range sc.Checks {
+		score := " ?"
+		if c.Score != analyzer.InconclusiveScore {
+			score = fmt.Sprintf("%2d", c.Score)
+		}
+		lines = append(lines, fmt.Sprintf("%s %-23s %s/10  %-8s %s",
+			analyzer.GetScorecardEmoji(c.Score), c.Name, score, c.Risk, c.Reason))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		header,
+		CardStyle.Render(summary),
+		CardStyle.Render(strings.Join(lines, "\n")),
+	)
+}
+
+func (m DashboardModel) codeQualityView() string {
+	header := TitleStyle.Render(" Code Quality ")
+
+	cq := m.data.CodeQuality
+	if cq == nil {
+		return lipgloss.JoinVertical(lipgloss.Left, header, CardStyle.Render("No code quality data"))
+	}
+
+	scores := fmt.Sprintf(
+		"Overall:       %d/100 (Grade: %s)\n\n"+
+			"Documentation: %d/100\n"+
+			"Testing:       %d/100\n"+
+			"Structure:     %d/100\n"+
+			"Maintenance:   %d/100",
+		cq.OverallScore, cq.Grade,
+		cq.DocumentationScore, cq.TestingScore, cq.StructureScore, cq.MaintenanceScore,
+	)
+
+	stats := fmt.Sprintf(
+		"Files:  %d total, %d source, %d test\n"+
+			"Test ratio: %.2f\n"+
+			"CI: %s\n"+
+			"Test frameworks: %s",
+		cq.FileStats.TotalFiles, cq.FileStats.SourceFiles, cq.FileStats.TestFiles,
+		cq.FileStats.TestRatio,
+		joinOrNone(cq.CIProviders), joinOrNone(cq.TestFrameworks),
+	)
+	if src := cq.Source; src != nil && src.FilesAnalyzed > 0 {
+		stats += fmt.Sprintf(
+			"\n\nLines: %d code, %d comments, %d blank\nFunctions: %d (avg complexity %.1f, max %d)",
+			src.CodeLines, src.CommentLines, src.BlankLines,
+			src.Functions, src.AvgComplexity, src.MaxComplexity,
+		)
+		if src.Truncated {
+			stats += fmt.Sprintf("\nSampled %d files", src.FilesAnalyzed)
+		}
+	}
+
+	content := lipgloss.JoinHorizontal(lipgloss.Top, CardStyle.Render(scores), CardStyle.Render(stats))
+
+	if src := cq.Source; src != nil && len(src.ComplexFunctions) > 0 {
+		lines := []string{"Most complex functions:"}
+		maxShow := 5
+		if len(src.ComplexFunctions) < maxShow {
+			maxShow = len(src.ComplexFunctions)
+		}
+		for _, fn := range src.ComplexFunctions[:maxShow] {
+			lines = append(lines, fmt.Sprintf("• %s (%s:%d) — %d", fn.Name, fn.Path, fn.Line, fn.Complexity))
+		}
+		content += "\n" + CardStyle.Render(strings.Join(lines, "\n"))
+	}
+
+	if tm := cq.TestMapping; tm != nil && tm.SourceFiles > 0 {
+		lines := []string{fmt.Sprintf("Tests by convention: %d/%d source files (%.0f%%)", tm.TestedSources, tm.SourceFiles, tm.Coverage*100)}
+		maxShow := 5
+		if len(tm.Directories) < maxShow {
+			maxShow = len(tm.Directories)
+		}
+		for _, d := range tm.Directories[:maxShow] {
+			lines = append(lines, fmt.Sprintf("• %s  %d/%d (%.0f%%)", d.Dir, d.TestedFiles, d.SourceFiles, d.Coverage*100))
+		}
+		if len(tm.UntestedPackages) > 0 {
+			lines = append(lines, "", "Largest untested directories:")
+			for i, d := range tm.UntestedPackages {
+				if i == maxShow {
+					break
+				}
+				lines = append(lines, fmt.Sprintf("• %s (%d files)", d.Dir, d.SourceFiles))
+			}
+		}
+		if len(tm.OrphanedTests) > 0 {
+			lines = append(lines, "", fmt.Sprintf("Orphaned tests: %d", len(tm.OrphanedTests)))
+			for i, p := range tm.OrphanedTests {
+				if i == 3 {
+					lines = append(lines, fmt.Sprintf("... %d more", len(tm.OrphanedTests)-3))
+					break
+				}
+				lines = append(lines, "• "+p)
+			}
+		}
+		content += "\n" + CardStyle.Render(strings.Join(lines, "\n"))
+	}
+
+	if doc := cq.Documentation; doc != nil && doc.Readme != "" {
+		lines := []string{
+			fmt.Sprintf("Documentation: %s (%d files analyzed)", doc.Readme, len(doc.Files)),
+			fmt.Sprintf("Install %s  Usage %s  Examples %s  Contributing %s  License %s",
+				checkMark(doc.HasInstall), checkMark(doc.HasUsage), checkMark(doc.HasExamples),
+				checkMark(doc.HasContributing), checkMark(doc.HasLicense)),
+			fmt.Sprintf("Code blocks: %d  Badges: %d  Images without alt text: %d", doc.CodeBlocks, doc.Badges, len(doc.ImagesWithoutAlt)),
+		}
+		if doc.StaleDays >= 0 {
+			lines = append(lines, fmt.Sprintf("README lags the latest commit by %d days", doc.StaleDays))
+		}
+		if len(doc.BrokenLinks) > 0 {
+			lines = append(lines, "", fmt.Sprintf("Broken links: %d", len(doc.BrokenLinks)))
+			for i, link := range doc.BrokenLinks {
+				if i == 3 {
+					lines = append(lines, fmt.Sprintf("... %d more", len(doc.BrokenLinks)-3))
+					break
+				}
+				lines = append(lines, fmt.Sprintf("• %s (%s:%d)", link.Target, link.File, link.Line))
+			}
+		}
+		if doc.Note != "" {
+			lines = append(lines, "⚠️ "+doc.Note)
+		}
+		content += "\n" + CardStyle.Render(strings.Join(lines, "\n"))
+	}
+
+	smells := []string{"Code smells:"}
+	if len(cq.CodeSmells) == 0 {
+		smells = append(smells, "✅ None detected")
+	}
+	for _, smell := range cq.CodeSmells {
+		line := fmt.Sprintf("%s %s: %s", analyzer.GetSeverityEmoji(strings.ToUpper(smell.Severity)), smell.Type, smell.Description)
+		if smell.Location != "" {
+			line += " (" + smell.Location + ")"
+		}
+		smells = append(smells, line)
+	}
+	content += "\n" + CardStyle.Render(strings.Join(smells, "\n"))
+
+	if len(cq.Recommendations) > 0 {
+		recs := "💡 RECOMMENDATIONS\n"
+		for _, rec := range cq.Recommendations {
+			recs += fmt.Sprintf("• %s\n", rec)
+		}
+		content += "\n" + CardStyle.Render(recs)
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, content)
+}
+
+func (m DashboardModel) projectLicenseView() string {
+	header := TitleStyle.Render(" License ")
+
+	la := m.data.License
+	if la == nil {
+		return lipgloss.JoinVertical(lipgloss.Left, header, CardStyle.Render("No license data"))
+	}
+
+	var summary string
+	if lic := la.MainLicense; lic != nil {
+		summary = fmt.Sprintf(
+			"%s (%s)\nCategory:   %s\nFound in:   %s\nConfidence: %d%%\nScore:      %d/100\n\n"+
+				"%s Commercial use\n%s Modification\n%s Distribution\n%s Patent grant",
+			lic.Name, lic.SPDX, lic.Category, lic.SourceFile, lic.Confidence, la.LicenseScore,
+			checkMark(lic.Commercial), checkMark(lic.Modify), checkMark(lic.Distribute), checkMark(lic.Patent),
+		)
+	} else {
+		summary = fmt.Sprintf("❌ No license detected\nScore: %d/100", la.LicenseScore)
+	}
+
+	var lines []string
+	if len(la.DeclaredLicenses) > 0 {
+		lines = append(lines, "Declared in manifests:")
+		for _, dl := range la.DeclaredLicenses {
+			line := fmt.Sprintf("• %s: %s", dl.Source, dl.Expression)
+			if !dl.Valid {
+				line += " (not a valid SPDX expression)"
+			}
+			lines = append(lines, line)
+		}
+	}
+	if len(la.FileLicenses) > 0 {
+		expressions := make(map[string]int)
+		for _, fl := range la.FileLicenses {
+			expressions[fl.Expression]++
+		}
+		names := make([]string, 0, len(expressions))
+		for expr := range expressions {
+			names = append(names, expr)
+		}
+		sort.Strings(names)
+		lines = append(lines, "SPDX file headers:")
+		for _, expr := range names {
+			lines = append(lines, fmt.Sprintf("• %s (%d files)", expr, expressions[expr]))
+		}
+	}
+	for _, other := range la.OtherLicenses {
+		lines = append(lines, fmt.Sprintf("Also found: %s in %s", other.SPDX, other.SourceFile))
+	}
+
+	compat := "✅ Compatible"
+	switch la.Compatibility {
+	case "warning":
+		compat = "⚠️ Review needed"
+	case "conflict":
+		compat = "❌ Conflicts found"
+	}
+	lines = append(lines, "", "Compatibility: "+compat)
+	for _, w := range la.Warnings {
+		lines = append(lines, "⚠️ "+w)
+	}
+
+	content := CardStyle.Render(summary) + "\n" + CardStyle.Render(strings.TrimLeft(strings.Join(lines, "\n"), "\n"))
+	return lipgloss.JoinVertical(lipgloss.Left, header, content)
+}
+
+// joinOrNone joins values for display, or returns "none" for an empty list
+func joinOrNone(values []string) string {
+	if len(values) == 0 {
+		return "none"
+	}
+	return strings.Join(values, ", ")
+}
+
+// checkMark renders a boolean permission as a check or a cross
+func checkMark(ok bool) string {
+	if ok {
+		return "✅"
+	}
+	return "❌"
+}
+
+func (m DashboardModel) licensesView() string {
+	header := TitleStyle.Render(" Dependency Licenses ")
+
+	report := m.data.LicenseCompliance
+	if report == nil {
+		msg := "No license compliance data"
+		if m.data.LicenseError != "" {
+			msg = "⚠️ License compliance check failed: " + m.data.LicenseError
+		}
+		return lipgloss.JoinVertical(lipgloss.Left, header, CardStyle.Render(msg))
+	}
+
+	status := "✅ Compliant"
+	if !report.Compliant {
+		status = fmt.Sprintf("❌ %d dependencies use denied licenses", report.Denied)
+	}
+	summary := fmt.Sprintf(
+		"%s\nPolicy: %s\nDependencies: %d\n\n🟢 %d allowed  🟡 %d flagged  🔴 %d denied\n❔ %d with unknown license",
+		status, report.Policy, len(report.Dependencies),
+		report.Allowed, report.Flagged, report.Denied, report.Unresolved,
+	)
+
+	var lines []string
+	maxShow := 10
+	for _, dep := range report.Dependencies {
+		if dep.Status == analyzer.LicenseAllowed {
+			continue
+		}
+		if len(lines) == maxShow {
+			lines = append(lines, fmt.Sprintf("... %d more", report.Flagged+report.Denied-maxShow))
+			break
+		}
+		emoji := "🟡"
+		if dep.Status == analyzer.LicenseDenied {
+			emoji = "🔴"
+		}
+		license := dep.License
+		if license == "" {
+			license = "unknown"
+		}
+		name := dep.Package
+		if dep.Version != "" {
+			name += "@" + dep.Version
+		}
+		line := fmt.Sprintf("%s %s — %s", emoji, name, license)
+		if dep.Reason != "" {
+			line += " (" + dep.Reason + ")"
+		}
+		if !dep.Direct {
+			line += " [transitive]"
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "✅ All dependency licenses are allowed by the policy")
+	}
+
+	content := CardStyle.Render(summary) + "\n" + CardStyle.Render(strings.Join(lines, "\n"))
 	return lipgloss.JoinVertical(lipgloss.Left, header, content)
 }
 
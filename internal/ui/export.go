@@ -3,18 +3,21 @@ package ui
 import (
 	"encoding/json"
 	"fmt"
+	"html"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"time" 
+
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
 	"github.com/jung-kurt/gofpdf"
 )
 
 // ValidateExportFormat checks if the given format is supported and returns a descriptive error if not
 func ValidateExportFormat(format string) error {
-	supportedFormats := []string{"json", "markdown", "csv", "html", "pdf"}
+	supportedFormats := []string{"json", "markdown", "csv", "html", "pdf", "cyclonedx", "spdx", "spdx-tv"}
 	
 	// Convert to lowercase for case-insensitive comparison
 	formatLower := strings.ToLower(format)
@@ -31,12 +34,30 @@ func ValidateExportFormat(format string) error {
 
 // ExportData is the structure for JSON export with additional metadata
 type ExportData struct {
-	ExportedAt      string              `json:"exported_at"`
-	Repository      RepoExport          `json:"repository"`
-	Metrics         MetricsExport       `json:"metrics"`
-	Languages       map[string]int      `json:"languages"`
-	TopContributors []ContributorExport `json:"top_contributors"`
-	CommitCount     int                 `json:"commit_count_1y"`
+	ExportedAt      string                        `json:"exported_at"`
+	Repository      RepoExport                    `json:"repository"`
+	Metrics         MetricsExport                 `json:"metrics"`
+	Languages       map[string]int                `json:"languages"`
+	TopContributors []ContributorExport           `json:"top_contributors"`
+	CommitCount     int                           `json:"commit_count_1y"`
+	Security        *SecurityExport               `json:"security,omitempty"`
+	Secrets         *analyzer.SecretScanResult    `json:"secrets,omitempty"`
+	Workflows       *analyzer.WorkflowAuditResult `json:"workflows,omitempty"`
+	Monorepo        *analyzer.MonorepoAnalysis    `json:"monorepo,omitempty"`
+	Containers      *analyzer.ContainerAnalysis   `json:"containers,omitempty"`
+	CodeQuality     *analyzer.CodeQualityMetrics  `json:"code_quality,omitempty"`
+	License         *analyzer.LicenseAnalysis     `json:"license,omitempty"`
+	Scorecard       *analyzer.ScorecardResult     `json:"scorecard,omitempty"`
+	Changelog       *analyzer.ChangelogAnalysis   `json:"changelog,omitempty"`
+}
+
+// SecurityExport holds the dependency vulnerability scan results
+type SecurityExport struct {
+	Score           int                      `json:"score"`
+	Grade           string                   `json:"grade"`
+	Source          string                   `json:"source,omitempty"`
+	Vulnerabilities []analyzer.Vulnerability `json:"vulnerabilities"`
+	Upgrades        []analyzer.UpgradeAdvice `json:"upgrades,omitempty"`
 }
 
 type RepoExport struct {
@@ -147,6 +168,15 @@ func ExportJSON(data AnalysisResult, _ string) (string, error) {
 		Languages:       data.Languages,
 		TopContributors: topContribs,
 		CommitCount:     len(data.Commits),
+		Security:        buildSecurityExport(data.Security),
+		Secrets:         data.Secrets,
+		Workflows:       data.Workflows,
+		Monorepo:        data.Monorepo,
+		Containers:      data.Containers,
+		CodeQuality:     data.CodeQuality,
+		License:         data.License,
+		Scorecard:       data.Scorecard,
+		Changelog:       data.Changelog,
 	}
 
 	file, err := os.Create(filename)
@@ -218,6 +248,202 @@ func ExportMarkdown(data AnalysisResult, _ string) (string, error) {
 		md += fmt.Sprintf("%d. %s (%d commits)\n", i+1, c.Login, c.Commits)
 	}
 
+	if cc := data.CommitConventions; cc != nil && cc.TotalCommits > 0 {
+		md += "\n## Commit Conventions\n"
+		md += fmt.Sprintf("- **Conventional Commits:** %d (%.0f%%)\n", cc.Conventional, cc.ConventionalRatio*100)
+		var types []string
+		for _, tc := range cc.Types {
+			types = append(types, fmt.Sprintf("%s %d", tc.Type, tc.Count))
+		}
+		md += fmt.Sprintf("- **Types:** %s\n", joinOrNone(types))
+		md += fmt.Sprintf("- **Breaking Changes:** %d\n", cc.BreakingChanges)
+		md += fmt.Sprintf("- **Issue References:** %d (%.0f%%)\n", cc.IssueReferences, cc.IssueRefRatio*100)
+		md += fmt.Sprintf("- **Short / WIP Messages:** %d / %d\n", cc.ShortMessages, cc.WIPMessages)
+		md += fmt.Sprintf("- **Merge Commits:** %d (%.0f%%)\n", cc.MergeCommits, cc.MergeRatio*100)
+		md += fmt.Sprintf("- **Signed-off-by (DCO):** %d (%.0f%%)\n", cc.SignedOff, cc.SignedOffRatio*100)
+	}
+
+	if sec := data.Security; sec != nil {
+		md += "\n## Security\n"
+		md += fmt.Sprintf("- **Security Score:** %d/100 (Grade: %s)\n", sec.SecurityScore, analyzer.GetSecurityGrade(sec.SecurityScore))
+		md += fmt.Sprintf("- **Vulnerabilities:** %d\n\n", sec.TotalCount)
+		if len(sec.Vulnerabilities) > 0 {
+			md += "| ID | Package | Severity | CVSS | Vector | Fixed In |\n"
+			md += "|----|---------|----------|------|--------|----------|\n"
+			for _, v := range sec.Vulnerabilities {
+				md += fmt.Sprintf("| %s | %s@%s | %s | %s | %s | %s |\n",
+					v.ID, v.Package, v.Version, v.Severity, formatCVSSScore(v), orDash(v.Vector), orDash(v.FixedIn))
+			}
+		}
+	}
+
+	if sc := data.Secrets; sc != nil && len(sc.Findings) > 0 {
+		md += "\n## Leaked Secrets\n"
+		md += fmt.Sprintf("- **Findings:** %d in %d scanned files\n\n", len(sc.Findings), sc.FilesScanned)
+		md += "| File | Line | Rule | Severity | Secret |\n"
+		md += "|------|------|------|----------|--------|\n"
+		for _, f := range sc.Findings {
+			md += fmt.Sprintf("| %s | %d | %s | %s | `%s` |\n", f.Path, f.Line, f.Rule, f.Severity, f.Secret)
+		}
+	}
+
+	if wf := data.Workflows; wf != nil && len(wf.Findings) > 0 {
+		md += "\n## Workflow Audit\n"
+		md += fmt.Sprintf("- **Findings:** %d in %d workflows\n\n", len(wf.Findings), wf.Workflows)
+		md += "| Workflow | Line | Job | Rule | Severity | Description |\n"
+		md += "|----------|------|-----|------|----------|-------------|\n"
+		for _, f := range wf.Findings {
+			md += fmt.Sprintf("| %s | %d | %s | %s | %s | %s |\n", f.Workflow, f.Line, orDash(f.Job), f.Rule, f.Severity, f.Description)
+		}
+	}
+
+	if ct := data.Containers; ct != nil && len(ct.Dockerfiles) > 0 {
+		md += "\n## Containers\n"
+		md += fmt.Sprintf("- **Dockerfiles:** %d\n", len(ct.Dockerfiles))
+		for _, img := range ct.BaseImages {
+			pin := "not pinned by digest"
+			if img.Pinned {
+				pin = img.Digest
+			}
+			md += fmt.Sprintf("- **Base Image:** `%s` %s (%s)\n", img.Image, orDash(img.Tag), pin)
+		}
+		if len(ct.Findings) > 0 {
+			md += "\n| File | Line | Rule | Severity | Description |\n"
+			md += "|------|------|------|----------|-------------|\n"
+			for _, f := range ct.Findings {
+				md += fmt.Sprintf("| %s | %d | %s | %s | %s |\n", f.File, f.Line, f.Rule, f.Severity, f.Description)
+			}
+		}
+	}
+
+	if mono := data.Monorepo; mono != nil && len(mono.Packages) > 0 {
+		md += "\n## Workspace Packages\n"
+		md += fmt.Sprintf("- **Workspaces:** %s (%s)\n", strings.Join(mono.Tools, ", "), strings.Join(mono.Configs, ", "))
+		for _, p := range mono.Packages {
+			md += fmt.Sprintf("\n### %s\n", p.Name)
+			md += fmt.Sprintf("- **Path:** `%s` (%s)\n", p.Path, p.Tool)
+			md += fmt.Sprintf("- **Files:** %d\n", p.Files)
+			md += fmt.Sprintf("- **Languages:** %s\n", joinOrNone(languagesBySize(p.Languages)))
+			md += fmt.Sprintf("- **Dependencies:** %d\n", p.Dependencies)
+			md += fmt.Sprintf("- **Vulnerabilities:** %d (%d critical)\n", p.Vulnerabilities, p.CriticalVulns)
+			md += fmt.Sprintf("- **Tests:** %d test files, %d/%d sources tested (%.0f%%)\n", p.TestFiles, p.TestedSources, p.SourceFiles, p.TestCoverage*100)
+			md += fmt.Sprintf("- **Owners:** %s\n", joinOrNone(p.Owners))
+			if p.RecentCommits >= 0 {
+				md += fmt.Sprintf("- **Commits (90 days):** %d\n", p.RecentCommits)
+			}
+		}
+	}
+
+	if cl := data.Changelog; cl != nil && (cl.File != "" || cl.Releases > 0) {
+		md += "\n## Releases & Changelog\n"
+		md += fmt.Sprintf("- **Latest Release:** %s\n", orDash(cl.LatestRelease))
+		md += fmt.Sprintf("- **Releases Without Notes:** %d/%d\n", cl.ReleasesWithoutNotes, cl.Releases)
+		if cl.File != "" {
+			md += fmt.Sprintf("- **Changelog:** %s (%s, %d versions)\n", cl.File, cl.Format, len(cl.Versions))
+			md += fmt.Sprintf("- **Entries per Release:** %.1f\n", cl.AvgEntriesPerRelease)
+			md += fmt.Sprintf("- **Unreleased Entries:** %d\n", cl.UnreleasedEntries)
+			md += fmt.Sprintf("- **Missing from Changelog:** %s\n", joinOrNone(cl.MissingVersions))
+			if len(cl.UntaggedVersions) > 0 {
+				md += fmt.Sprintf("- **Never Tagged:** %s\n", strings.Join(cl.UntaggedVersions, ", "))
+			}
+		}
+		for _, rec := range cl.Recommendations {
+			md += fmt.Sprintf("- %s\n", rec)
+		}
+	}
+
+	if sc := data.Scorecard; sc != nil {
+		md += "\n## Scorecard\n"
+		md += fmt.Sprintf("- **Aggregate Score:** %.1f/10\n\n", sc.Score)
+		md += "| Check | Score | Risk | Reason |\n"
+		md += "|-------|-------|------|--------|\n"
+		for _, c := range sc.Checks {
+			score := "?"
+			if c.Score != analyzer.InconclusiveScore {
+				score = fmt.Sprintf("%d", c.Score)
+			}
+			md += fmt.Sprintf("| %s | %s | %s | %s |\n", c.Name, score, c.Risk, c.Reason)
+		}
+	}
+
+	if cq := data.CodeQuality; cq != nil {
+		md += "\n## Code Quality\n"
+		md += fmt.Sprintf("- **Overall Score:** %d/100 (Grade: %s)\n", cq.OverallScore, cq.Grade)
+		md += fmt.Sprintf("- **Documentation:** %d/100\n", cq.DocumentationScore)
+		md += fmt.Sprintf("- **Testing:** %d/100\n", cq.TestingScore)
+		md += fmt.Sprintf("- **Structure:** %d/100\n", cq.StructureScore)
+		md += fmt.Sprintf("- **Maintenance:** %d/100\n", cq.MaintenanceScore)
+		md += fmt.Sprintf("- **CI Providers:** %s\n", joinOrNone(cq.CIProviders))
+		md += fmt.Sprintf("- **Test Frameworks:** %s\n", joinOrNone(cq.TestFrameworks))
+		if co := cq.CodeOwners; co != nil && co.File != "" {
+			md += fmt.Sprintf("- **Code Owners:** %d/%d files owned (%s)\n", co.OwnedFiles, co.TotalFiles, co.File)
+			if len(co.InactiveOwners) > 0 {
+				md += fmt.Sprintf("- **Inactive Owners:** %s\n", strings.Join(co.InactiveOwners, ", "))
+			}
+		}
+		if doc := cq.Documentation; doc != nil && doc.Readme != "" {
+			md += fmt.Sprintf("- **README:** %s (install: %s, usage: %s, examples: %s, contributing: %s, license: %s)\n",
+				doc.Readme, yesNo(doc.HasInstall), yesNo(doc.HasUsage), yesNo(doc.HasExamples), yesNo(doc.HasContributing), yesNo(doc.HasLicense))
+			md += fmt.Sprintf("- **Code Blocks:** %d, **Badges:** %d, **Images Without Alt Text:** %d\n", doc.CodeBlocks, doc.Badges, len(doc.ImagesWithoutAlt))
+			if doc.StaleDays >= 0 {
+				md += fmt.Sprintf("- **README Lag:** %d days behind the latest commit\n", doc.StaleDays)
+			}
+			for _, link := range doc.BrokenLinks {
+				md += fmt.Sprintf("- **Broken Link:** `%s` (%s:%d)\n", link.Target, link.File, link.Line)
+			}
+		}
+		if src := cq.Source; src != nil && src.FilesAnalyzed > 0 {
+			md += fmt.Sprintf("- **Lines of Code:** %d (%d comments, %d blank)\n", src.CodeLines, src.CommentLines, src.BlankLines)
+			md += fmt.Sprintf("- **Cyclomatic Complexity:** avg %.1f, max %d across %d functions\n", src.AvgComplexity, src.MaxComplexity, src.Functions)
+		}
+		if tm := cq.TestMapping; tm != nil && tm.SourceFiles > 0 {
+			md += fmt.Sprintf("\n### Tests by Convention\n- **Tested Source Files:** %d/%d (%.0f%%)\n", tm.TestedSources, tm.SourceFiles, tm.Coverage*100)
+			md += fmt.Sprintf("- **Orphaned Tests:** %d\n\n", len(tm.OrphanedTests))
+			md += "| Directory | Source Files | Tested | Coverage |\n"
+			md += "|-----------|--------------|--------|----------|\n"
+			for _, d := range tm.Directories {
+				md += fmt.Sprintf("| %s | %d | %d | %.0f%% |\n", d.Dir, d.SourceFiles, d.TestedFiles, d.Coverage*100)
+			}
+			if len(tm.OrphanedTests) > 0 {
+				md += "\nOrphaned tests:\n"
+				for _, p := range tm.OrphanedTests {
+					md += fmt.Sprintf("- %s\n", p)
+				}
+			}
+		}
+		if len(cq.CodeSmells) > 0 {
+			md += "\n| Smell | Severity | Description | Location |\n"
+			md += "|-------|----------|-------------|----------|\n"
+			for _, smell := range cq.CodeSmells {
+				md += fmt.Sprintf("| %s | %s | %s | %s |\n", smell.Type, smell.Severity, smell.Description, orDash(smell.Location))
+			}
+		}
+		if len(cq.Recommendations) > 0 {
+			md += "\n"
+			for _, rec := range cq.Recommendations {
+				md += fmt.Sprintf("- %s\n", rec)
+			}
+		}
+	}
+
+	if la := data.License; la != nil {
+		md += "\n## License\n"
+		if lic := la.MainLicense; lic != nil {
+			md += fmt.Sprintf("- **License:** %s (%s)\n", lic.Name, lic.SPDX)
+			md += fmt.Sprintf("- **Category:** %s\n", lic.Category)
+			md += fmt.Sprintf("- **Source:** %s (%d%% confidence)\n", lic.SourceFile, lic.Confidence)
+			md += fmt.Sprintf("- **Permissions:** commercial use %s, modification %s, distribution %s, patent grant %s\n",
+				yesNo(lic.Commercial), yesNo(lic.Modify), yesNo(lic.Distribute), yesNo(lic.Patent))
+		} else {
+			md += "- **License:** none detected\n"
+		}
+		md += fmt.Sprintf("- **License Score:** %d/100\n", la.LicenseScore)
+		md += fmt.Sprintf("- **Compatibility:** %s\n", la.Compatibility)
+		for _, w := range la.Warnings {
+			md += fmt.Sprintf("- ⚠️ %s\n", w)
+		}
+	}
+
 	_, err = file.WriteString(md)
 	if err != nil {
 		return "", err
@@ -315,6 +541,122 @@ func ExportPDF(data AnalysisResult, _ string) (string, error) {
 		pdf.Ln(6)
 	}
 
+	if pc := data.PunchCard; pc != nil && pc.TotalCommits > 0 {
+		pdf.Ln(9)
+		pdf.SetFont("Arial", "B", 14)
+		pdf.Cell(0, 10, "Commit Punch Card")
+		pdf.Ln(10)
+
+		pdf.SetFont("Arial", "", 11)
+		pdf.Cell(0, 8, fmt.Sprintf("Peak: %s %02d:00, Weekends: %.0f%%, After hours: %.0f%%",
+			pc.PeakDay, pc.PeakHour, pc.WeekendRatio*100, pc.AfterHoursRatio*100))
+		pdf.Ln(8)
+
+		max := punchCardMax(pc)
+		pdf.SetFont("Arial", "", 7)
+		pdf.CellFormat(12, 5, "", "", 0, "L", false, 0, "")
+		for hour := 0; hour < 24; hour++ {
+			pdf.CellFormat(7, 5, fmt.Sprintf("%d", hour), "", 0, "C", false, 0, "")
+		}
+		pdf.Ln(5)
+		for day, hours := range pc.Matrix {
+			pdf.CellFormat(12, 6, time.Weekday(day).String()[:3], "", 0, "L", false, 0, "")
+			for _, n := range hours {
+				c := exportHeatColors[heatLevel(n, max, len(exportHeatColors))]
+				pdf.SetFillColor(c[0], c[1], c[2])
+				label := ""
+				if n > 0 {
+					label = fmt.Sprintf("%d", n)
+				}
+				pdf.CellFormat(7, 6, label, "1", 0, "C", true, 0, "")
+			}
+			pdf.Ln(6)
+		}
+		pdf.SetFont("Arial", "", 11)
+		if pc.KnownOffsets {
+			pdf.Ln(2)
+			var zones []string
+			for _, tz := range pc.Timezones {
+				zones = append(zones, fmt.Sprintf("%s (%d)", tz.Offset, tz.Contributors))
+			}
+			pdf.Cell(0, 8, fmt.Sprintf("Contributor timezones: %s, spread %d hours", strings.Join(zones, ", "), pc.TimezoneSpread))
+			pdf.Ln(6)
+		}
+	}
+
+	if sec := data.Security; sec != nil {
+		pdf.Ln(9)
+		pdf.SetFont("Arial", "B", 14)
+		pdf.Cell(0, 10, "Security")
+		pdf.Ln(10)
+
+		pdf.SetFont("Arial", "", 11)
+		pdf.Cell(0, 8, fmt.Sprintf("Security Score: %d/100 (Grade: %s)", sec.SecurityScore, analyzer.GetSecurityGrade(sec.SecurityScore)))
+		pdf.Ln(6)
+		for _, v := range sec.Vulnerabilities {
+			pdf.Cell(0, 8, fmt.Sprintf("- %s %s@%s: %s (CVSS %s)", v.ID, v.Package, v.Version, v.Severity, formatCVSSScore(v)))
+			pdf.Ln(6)
+		}
+	}
+
+	if cq := data.CodeQuality; cq != nil {
+		pdf.Ln(9)
+		pdf.SetFont("Arial", "B", 14)
+		pdf.Cell(0, 10, "Code Quality")
+		pdf.Ln(10)
+
+		pdf.SetFont("Arial", "", 11)
+		pdf.Cell(0, 8, fmt.Sprintf("Overall Score: %d/100 (Grade: %s)", cq.OverallScore, cq.Grade))
+		pdf.Ln(6)
+		pdf.Cell(0, 8, fmt.Sprintf("Documentation %d, Testing %d, Structure %d, Maintenance %d",
+			cq.DocumentationScore, cq.TestingScore, cq.StructureScore, cq.MaintenanceScore))
+		pdf.Ln(6)
+		pdf.Cell(0, 8, fmt.Sprintf("CI Providers: %s", joinOrNone(cq.CIProviders)))
+		pdf.Ln(6)
+		if src := cq.Source; src != nil && src.FilesAnalyzed > 0 {
+			pdf.Cell(0, 8, fmt.Sprintf("Lines of Code: %d, Complexity: avg %.1f, max %d", src.CodeLines, src.AvgComplexity, src.MaxComplexity))
+			pdf.Ln(6)
+		}
+		if tm := cq.TestMapping; tm != nil && tm.SourceFiles > 0 {
+			pdf.Cell(0, 8, fmt.Sprintf("Tests by convention: %d/%d source files (%.0f%%), %d orphaned tests",
+				tm.TestedSources, tm.SourceFiles, tm.Coverage*100, len(tm.OrphanedTests)))
+			pdf.Ln(6)
+			for _, d := range tm.UntestedPackages {
+				pdf.Cell(0, 8, fmt.Sprintf("- Untested: %s (%d files)", d.Dir, d.SourceFiles))
+				pdf.Ln(6)
+			}
+		}
+		for _, smell := range cq.CodeSmells {
+			pdf.Cell(0, 8, fmt.Sprintf("- [%s] %s: %s", smell.Severity, smell.Type, smell.Description))
+			pdf.Ln(6)
+		}
+	}
+
+	if la := data.License; la != nil {
+		pdf.Ln(9)
+		pdf.SetFont("Arial", "B", 14)
+		pdf.Cell(0, 10, "License")
+		pdf.Ln(10)
+
+		pdf.SetFont("Arial", "", 11)
+		if lic := la.MainLicense; lic != nil {
+			pdf.Cell(0, 8, fmt.Sprintf("License: %s (%s, %s)", lic.Name, lic.SPDX, lic.Category))
+			pdf.Ln(6)
+			pdf.Cell(0, 8, fmt.Sprintf("Commercial use: %s, Modification: %s, Distribution: %s, Patent grant: %s",
+				yesNo(lic.Commercial), yesNo(lic.Modify), yesNo(lic.Distribute), yesNo(lic.Patent)))
+			pdf.Ln(6)
+		} else {
+			pdf.Cell(0, 8, "License: none detected")
+			pdf.Ln(6)
+		}
+		pdf.Cell(0, 8, fmt.Sprintf("License Score: %d/100, Compatibility: %s", la.LicenseScore, la.Compatibility))
+		pdf.Ln(6)
+		for _, w := range la.Warnings {
+			pdf.Cell(0, 8, "- "+w)
+			pdf.Ln(6)
+		}
+	}
+
 	err = pdf.OutputFileAndClose(filename)
 	if err != nil {
 		return "", err
@@ -387,6 +729,65 @@ func ExportCSV(data AnalysisResult, _ string) (string, error) {
 		fmt.Fprintf(file, "%s,%d\n", c.Login, c.Commits)
 	}
 
+	// Vulnerabilities
+	if sec := data.Security; sec != nil {
+		fmt.Fprintf(file, "\nSecurity Score,%d\n", sec.SecurityScore)
+		file.WriteString("\nVulnerabilities\n")
+		file.WriteString("ID,Package,Version,Severity,CVSS Score,CVSS Vector,Fixed In\n")
+		for _, v := range sec.Vulnerabilities {
+			fmt.Fprintf(file, "%s,%s,%s,%s,%s,%s,%s\n", v.ID, v.Package, v.Version, v.Severity, formatCVSSScore(v), v.Vector, v.FixedIn)
+		}
+	}
+
+	// Code quality
+	if cq := data.CodeQuality; cq != nil {
+		fmt.Fprintf(file, "\nCode Quality Score,%d\n", cq.OverallScore)
+		fmt.Fprintf(file, "Code Quality Grade,%s\n", cq.Grade)
+		fmt.Fprintf(file, "Documentation Score,%d\n", cq.DocumentationScore)
+		fmt.Fprintf(file, "Testing Score,%d\n", cq.TestingScore)
+		fmt.Fprintf(file, "Structure Score,%d\n", cq.StructureScore)
+		fmt.Fprintf(file, "Maintenance Score,%d\n", cq.MaintenanceScore)
+		fmt.Fprintf(file, "CI Providers,%s\n", strings.Join(cq.CIProviders, ";"))
+		if src := cq.Source; src != nil && src.FilesAnalyzed > 0 {
+			fmt.Fprintf(file, "Lines of Code,%d\n", src.CodeLines)
+			fmt.Fprintf(file, "Average Complexity,%.1f\n", src.AvgComplexity)
+			fmt.Fprintf(file, "Max Complexity,%d\n", src.MaxComplexity)
+		}
+		if tm := cq.TestMapping; tm != nil && tm.SourceFiles > 0 {
+			fmt.Fprintf(file, "Tested Source Files,%d\n", tm.TestedSources)
+			fmt.Fprintf(file, "Orphaned Tests,%d\n", len(tm.OrphanedTests))
+			file.WriteString("\nTests by Convention\n")
+			file.WriteString("Directory,Source Files,Tested,Coverage\n")
+			for _, d := range tm.Directories {
+				fmt.Fprintf(file, "%s,%d,%d,%.0f%%\n", d.Dir, d.SourceFiles, d.TestedFiles, d.Coverage*100)
+			}
+		}
+		if len(cq.CodeSmells) > 0 {
+			file.WriteString("\nCode Smells\n")
+			file.WriteString("Type,Severity,Description,Location\n")
+			for _, smell := range cq.CodeSmells {
+				fmt.Fprintf(file, "%s,%s,%s,%s\n", smell.Type, smell.Severity, strings.ReplaceAll(smell.Description, ",", ";"), smell.Location)
+			}
+		}
+	}
+
+	// License
+	if la := data.License; la != nil {
+		file.WriteString("\n")
+		if lic := la.MainLicense; lic != nil {
+			fmt.Fprintf(file, "License,%s\n", lic.SPDX)
+			fmt.Fprintf(file, "License Category,%s\n", lic.Category)
+			fmt.Fprintf(file, "Commercial Use,%s\n", yesNo(lic.Commercial))
+			fmt.Fprintf(file, "Modification,%s\n", yesNo(lic.Modify))
+			fmt.Fprintf(file, "Distribution,%s\n", yesNo(lic.Distribute))
+			fmt.Fprintf(file, "Patent Grant,%s\n", yesNo(lic.Patent))
+		} else {
+			file.WriteString("License,none\n")
+		}
+		fmt.Fprintf(file, "License Score,%d\n", la.LicenseScore)
+		fmt.Fprintf(file, "License Compatibility,%s\n", la.Compatibility)
+	}
+
 	_ = openFileManager(filename)
 
 	return filename, nil
@@ -454,10 +855,10 @@ func ExportHTML(data AnalysisResult, _ string) (string, error) {
         <h2>Languages</h2>
         <table>
             <tr><th>Language</th><th>Percentage</th></tr>`,
-		data.Repo.FullName, data.Repo.FullName, time.Now().Format("2006-01-02 15:04"),
+		escapeHTML(data.Repo.FullName), escapeHTML(data.Repo.FullName), time.Now().Format("2006-01-02 15:04"),
 		data.Repo.Stars, data.Repo.Forks, data.Repo.OpenIssues,
-		data.Repo.CreatedAt.Format("2006-01-02"), data.Repo.HTMLURL, data.Repo.HTMLURL,
-		data.HealthScore, data.BusFactor, data.BusRisk, data.MaturityLevel, data.MaturityScore,
+		data.Repo.CreatedAt.Format("2006-01-02"), escapeHTML(data.Repo.HTMLURL), escapeHTML(data.Repo.HTMLURL),
+		data.HealthScore, data.BusFactor, escapeHTML(data.BusRisk), escapeHTML(data.MaturityLevel), data.MaturityScore,
 		len(data.Commits), len(data.Contributors))
 
 	// Languages
@@ -467,7 +868,7 @@ func ExportHTML(data AnalysisResult, _ string) (string, error) {
 	}
 	for lang, bytes := range data.Languages {
 		pct := float64(bytes) / float64(total) * 100
-		html += fmt.Sprintf("<tr><td>%s</td><td>%.1f%%</td></tr>", lang, pct)
+		html += fmt.Sprintf("<tr><td>%s</td><td>%.1f%%</td></tr>", escapeHTML(lang), pct)
 	}
 	html += `        </table>
     </div>
@@ -484,11 +885,174 @@ func ExportHTML(data AnalysisResult, _ string) (string, error) {
 	}
 	for i := 0; i < maxContribs; i++ {
 		c := data.Contributors[i]
-		html += fmt.Sprintf("<tr><td>%s</td><td>%d</td></tr>", c.Login, c.Commits)
+		html += fmt.Sprintf("<tr><td>%s</td><td>%d</td></tr>", escapeHTML(c.Login), c.Commits)
 	}
 
 	html += `        </table>
-    </div>
+    </div>`
+
+	// Contribution calendar
+	if cal := data.Calendar; cal != nil || len(data.Commits) > 0 {
+		if cal == nil {
+			cal = analyzer.BuildContributionCalendar(data.Commits, "", time.Now())
+		}
+		html += fmt.Sprintf(`
+
+    <div class="section">
+        <h2>Contribution Calendar</h2>
+        <p>%d commits in the last year</p>
+        %s
+    </div>`, cal.Total, calendarSVG(cal))
+	}
+
+	// Punch card
+	if pc := data.PunchCard; pc != nil && pc.TotalCommits > 0 {
+		html += fmt.Sprintf(`
+
+    <div class="section">
+        <h2>Commit Punch Card</h2>
+        <p>Peak: %s %02d:00 &middot; Weekends: %.0f%% &middot; After hours: %.0f%%</p>
+        <table>
+            <tr><th></th>`,
+			escapeHTML(pc.PeakDay), pc.PeakHour, pc.WeekendRatio*100, pc.AfterHoursRatio*100)
+		for hour := 0; hour < 24; hour++ {
+			html += fmt.Sprintf("<th>%d</th>", hour)
+		}
+		html += "</tr>"
+		max := punchCardMax(pc)
+		for day, hours := range pc.Matrix {
+			html += fmt.Sprintf("<tr><td>%s</td>", time.Weekday(day).String()[:3])
+			for _, n := range hours {
+				c := exportHeatColors[heatLevel(n, max, len(exportHeatColors))]
+				html += fmt.Sprintf(`<td style="background:#%02x%02x%02x" title="%d commits"></td>`, c[0], c[1], c[2], n)
+			}
+			html += "</tr>"
+		}
+		html += `        </table>`
+		if pc.KnownOffsets {
+			html += fmt.Sprintf(`
+        <p>Timezone spread: %d hours</p>
+        <table>
+            <tr><th>Timezone</th><th>Contributors</th><th>Commits</th></tr>`, pc.TimezoneSpread)
+			for _, tz := range pc.Timezones {
+				html += fmt.Sprintf("<tr><td>%s</td><td>%d</td><td>%d</td></tr>", escapeHTML(tz.Offset), tz.Contributors, tz.Commits)
+			}
+			html += `        </table>`
+		}
+		html += `
+    </div>`
+	}
+
+	// Vulnerabilities
+	if sec := data.Security; sec != nil {
+		html += fmt.Sprintf(`
+
+    <div class="section">
+        <h2>Security</h2>
+        <p>Security Score: %d/100 (Grade: %s)</p>
+        <table>
+            <tr><th>ID</th><th>Package</th><th>Severity</th><th>CVSS</th><th>Vector</th><th>Fixed In</th></tr>`,
+			sec.SecurityScore, analyzer.GetSecurityGrade(sec.SecurityScore))
+		for _, v := range sec.Vulnerabilities {
+			html += fmt.Sprintf("<tr><td>%s</td><td>%s@%s</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>",
+				escapeHTML(v.ID), escapeHTML(v.Package), escapeHTML(v.Version), escapeHTML(v.Severity),
+				escapeHTML(formatCVSSScore(v)), escapeHTML(orDash(v.Vector)), escapeHTML(orDash(v.FixedIn)))
+		}
+		html += `        </table>
+    </div>`
+	}
+
+	// Workspace packages
+	if mono := data.Monorepo; mono != nil && len(mono.Packages) > 0 {
+		html += fmt.Sprintf(`
+
+    <div class="section">
+        <h2>Workspace Packages</h2>
+        <p>Workspaces: %s</p>
+        <table>
+            <tr><th>Package</th><th>Path</th><th>Languages</th><th>Dependencies</th><th>Vulnerabilities</th><th>Tested</th><th>Owners</th><th>Commits (90d)</th></tr>`,
+			escapeHTML(strings.Join(mono.Tools, ", ")))
+		for _, p := range mono.Packages {
+			commits := "-"
+			if p.RecentCommits >= 0 {
+				commits = fmt.Sprintf("%d", p.RecentCommits)
+			}
+			html += fmt.Sprintf("<tr><td>%s</td><td>%s</td><td>%s</td><td>%d</td><td>%d</td><td>%.0f%%</td><td>%s</td><td>%s</td></tr>",
+				escapeHTML(p.Name), escapeHTML(p.Path), escapeHTML(joinOrNone(languagesBySize(p.Languages))),
+				p.Dependencies, p.Vulnerabilities, p.TestCoverage*100, escapeHTML(joinOrNone(p.Owners)), commits)
+		}
+		html += `        </table>
+    </div>`
+	}
+
+	// Code quality
+	if cq := data.CodeQuality; cq != nil {
+		html += fmt.Sprintf(`
+
+    <div class="section">
+        <h2>Code Quality</h2>
+        <p>Overall Score: %d/100 (Grade: %s)</p>
+        <p>Documentation %d &middot; Testing %d &middot; Structure %d &middot; Maintenance %d</p>
+        <p>CI Providers: %s</p>`,
+			cq.OverallScore, escapeHTML(cq.Grade),
+			cq.DocumentationScore, cq.TestingScore, cq.StructureScore, cq.MaintenanceScore,
+			escapeHTML(joinOrNone(cq.CIProviders)))
+		if src := cq.Source; src != nil && src.FilesAnalyzed > 0 {
+			html += fmt.Sprintf("<p>Lines of Code: %d &middot; Complexity: avg %.1f, max %d</p>", src.CodeLines, src.AvgComplexity, src.MaxComplexity)
+		}
+		if tm := cq.TestMapping; tm != nil && tm.SourceFiles > 0 {
+			html += fmt.Sprintf(`
+        <h3>Tests by Convention</h3>
+        <p>Tested source files: %d/%d (%.0f%%) &middot; Orphaned tests: %d</p>
+        <table>
+            <tr><th>Directory</th><th>Source Files</th><th>Tested</th><th>Coverage</th></tr>`,
+				tm.TestedSources, tm.SourceFiles, tm.Coverage*100, len(tm.OrphanedTests))
+			for _, d := range tm.Directories {
+				html += fmt.Sprintf("<tr><td>%s</td><td>%d</td><td>%d</td><td>%.0f%%</td></tr>", escapeHTML(d.Dir), d.SourceFiles, d.TestedFiles, d.Coverage*100)
+			}
+			html += `        </table>`
+		}
+		if len(cq.CodeSmells) > 0 {
+			html += `
+        <table>
+            <tr><th>Smell</th><th>Severity</th><th>Description</th><th>Location</th></tr>`
+			for _, smell := range cq.CodeSmells {
+				html += fmt.Sprintf("<tr><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>",
+					escapeHTML(smell.Type), escapeHTML(smell.Severity), escapeHTML(smell.Description), escapeHTML(orDash(smell.Location)))
+			}
+			html += `        </table>`
+		}
+		html += `
+    </div>`
+	}
+
+	// License
+	if la := data.License; la != nil {
+		html += `
+
+    <div class="section">
+        <h2>License</h2>`
+		if lic := la.MainLicense; lic != nil {
+			html += fmt.Sprintf(`
+        <p>%s (%s) &middot; %s</p>
+        <table>
+            <tr><th>Commercial use</th><th>Modification</th><th>Distribution</th><th>Patent grant</th></tr>
+            <tr><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>
+        </table>`,
+				escapeHTML(lic.Name), escapeHTML(lic.SPDX), escapeHTML(lic.Category),
+				yesNo(lic.Commercial), yesNo(lic.Modify), yesNo(lic.Distribute), yesNo(lic.Patent))
+		} else {
+			html += "<p>No license detected</p>"
+		}
+		html += fmt.Sprintf("<p>License Score: %d/100 &middot; Compatibility: %s</p>", la.LicenseScore, escapeHTML(la.Compatibility))
+		for _, w := range la.Warnings {
+			html += fmt.Sprintf("<p>&#9888; %s</p>", escapeHTML(w))
+		}
+		html += `
+    </div>`
+	}
+
+	html += `
 </body>
 </html>`
 
@@ -502,6 +1066,155 @@ func ExportHTML(data AnalysisResult, _ string) (string, error) {
 	return filename, nil
 }
 
+// buildSecurityExport converts scan results for the JSON exports
+func buildSecurityExport(sec *analyzer.SecurityScanResult) *SecurityExport {
+	if sec == nil {
+		return nil
+	}
+	return &SecurityExport{
+		Score:           sec.SecurityScore,
+		Grade:           analyzer.GetSecurityGrade(sec.SecurityScore),
+		Source:          sec.Source,
+		Vulnerabilities: sec.Vulnerabilities,
+		Upgrades:        sec.Upgrades,
+	}
+}
+
+// formatCVSSScore formats a vulnerability's CVSS score, or "-" when the
+// advisory had no scorable vector
+func formatCVSSScore(v analyzer.Vulnerability) string {
+	if v.Score == 0 && v.Vector == "" {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f", v.Score)
+}
+
+// exportHeatColors are the heatmap colors of the exports, from no activity
+// to the busiest cell, for a light background
+var exportHeatColors = [][3]int{{235, 237, 240}, {155, 233, 168}, {64, 196, 99}, {48, 161, 78}, {33, 110, 57}}
+
+// calendarSVG draws a contribution calendar as inline SVG, one square per
+// day with the commit count as its tooltip
+func calendarSVG(cal *analyzer.ContributionCalendar) string {
+	const cell, gap, left, top = 11, 2, 28, 16
+	width := left + len(cal.Weeks)*(cell+gap)
+	height := top + 7*(cell+gap)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="Arial, sans-serif" font-size="9" fill="#767676">`, width, height))
+	for day := 1; day < 7; day += 2 {
+		sb.WriteString(fmt.Sprintf(`<text x="0" y="%d">%s</text>`, top+day*(cell+gap)+cell-2, time.Weekday(day).String()[:3]))
+	}
+	for i, w := range cal.Weeks {
+		x := left + i*(cell+gap)
+		if i == 0 || w.Start.Month() != cal.Weeks[i-1].Start.Month() {
+			sb.WriteString(fmt.Sprintf(`<text x="%d" y="10">%s</text>`, x, w.Start.Format("Jan")))
+		}
+		for day, n := range w.Days {
+			c := exportHeatColors[heatLevel(n, cal.Max, len(exportHeatColors))]
+			sb.WriteString(fmt.Sprintf(`<rect x="%d" y="%d" width="%d" height="%d" rx="2" fill="#%02x%02x%02x"><title>%s: %d commits</title></rect>`,
+				x, top+day*(cell+gap), cell, cell, c[0], c[1], c[2], w.Start.AddDate(0, 0, day).Format("2006-01-02"), n))
+		}
+	}
+	sb.WriteString("</svg>")
+	return sb.String()
+}
+
+// yesNo renders a license permission for the exports
+func yesNo(ok bool) string {
+	if ok {
+		return "Yes"
+	}
+	return "No"
+}
+
+// escapeHTML escapes a repository-controlled value for the HTML export
+func escapeHTML(s string) string {
+	return html.EscapeString(s)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// ExportDependencyGraph exports the resolved dependency graph as Graphviz DOT
+// ("dot") or JSON ("json") to the Downloads folder
+func ExportDependencyGraph(data AnalysisResult, format string) (string, error) {
+	graph := data.DependencyGraph
+	if graph == nil || len(graph.Nodes) == 0 {
+		return "", fmt.Errorf("no dependency graph available: no supported lock files found")
+	}
+
+	var content []byte
+	switch format {
+	case "dot":
+		content = []byte(graph.DOT(data.Repo.FullName))
+	case "json":
+		var err error
+		content, err = graph.JSON()
+		if err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("unsupported dependency graph format '%s'. Supported formats are: dot, json", format)
+	}
+
+	downloadsDir, err := getDownloadsDir()
+	if err != nil {
+		return "", err
+	}
+
+	filename := filepath.Join(downloadsDir, generateFilename(data.Repo.FullName+"_dependencies", format))
+	if err := os.WriteFile(filename, content, 0644); err != nil {
+		return "", err
+	}
+
+	_ = openFileManager(filename)
+
+	return filename, nil
+}
+
+// ExportSBOM exports the dependency set as a software bill of materials in
+// CycloneDX ("cyclonedx"), SPDX JSON ("spdx") or SPDX tag-value ("spdx-tv")
+// format to the Downloads folder
+func ExportSBOM(data AnalysisResult, format string) (string, error) {
+	ext, ok := analyzer.SBOMFormats[format]
+	if !ok {
+		return "", fmt.Errorf("unsupported SBOM format '%s'. Supported formats are: cyclonedx, spdx, spdx-tv", format)
+	}
+	if data.Dependencies == nil || data.Dependencies.TotalDeps == 0 && len(data.Dependencies.LockFiles) == 0 {
+		return "", fmt.Errorf("no dependencies available: no supported manifests or lock files found")
+	}
+
+	subject := analyzer.SBOMSubject{
+		Name:    data.Repo.FullName,
+		Version: data.Repo.DefaultBranch,
+		URL:     data.Repo.HTMLURL,
+	}
+	sbom := analyzer.NewSBOM(subject, data.Dependencies, data.Security, data.LicenseCompliance)
+	content, err := sbom.Render(format)
+	if err != nil {
+		return "", err
+	}
+
+	downloadsDir, err := getDownloadsDir()
+	if err != nil {
+		return "", err
+	}
+
+	filename := filepath.Join(downloadsDir, generateFilename(data.Repo.FullName+"_sbom", ext))
+	if err := os.WriteFile(filename, content, 0644); err != nil {
+		return "", err
+	}
+
+	_ = openFileManager(filename)
+
+	return filename, nil
+}
+
 // ExportAnalysis exports analysis data in the specified format with validation
 func ExportAnalysis(data AnalysisResult, format string) (string, error) {
 	// Validate the export format
@@ -524,6 +1237,8 @@ func ExportAnalysis(data AnalysisResult, format string) (string, error) {
 		return ExportHTML(data, "")
 	case "pdf":
 		return ExportPDF(data, "")
+	case "cyclonedx", "spdx", "spdx-tv":
+		return ExportSBOM(data, format)
 	default:
 		// This should not happen due to validation, but just in case
 		return "", fmt.Errorf("unexpected format after validation: %s", format)
@@ -575,6 +1290,15 @@ func buildExportData(data AnalysisResult) ExportData {
 		Languages:       data.Languages,
 		TopContributors: topContribs,
 		CommitCount:     len(data.Commits),
+		Security:        buildSecurityExport(data.Security),
+		Secrets:         data.Secrets,
+		Workflows:       data.Workflows,
+		Monorepo:        data.Monorepo,
+		Containers:      data.Containers,
+		CodeQuality:     data.CodeQuality,
+		License:         data.License,
+		Scorecard:       data.Scorecard,
+		Changelog:       data.Changelog,
 	}
 }
 
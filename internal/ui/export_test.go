@@ -1,8 +1,14 @@
 package ui
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
+	"github.com/agnivo988/Repo-lyzer/internal/github"
 )
 
 func TestValidateExportFormat(t *testing.T) {
@@ -71,4 +77,128 @@ func TestExportAnalysis(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestExportDependencyGraph_Errors(t *testing.T) {
+	data := AnalysisResult{Repo: &github.Repo{FullName: "owner/repo"}}
+	if _, err := ExportDependencyGraph(data, "dot"); err == nil {
+		t.Error("expected error when no dependency graph is available")
+	}
+
+	data.DependencyGraph = analyzer.BuildDependencyGraph(&analyzer.DependencyAnalysis{
+		LockFiles: []analyzer.LockFile{{
+			FileType: "npm",
+			Packages: []analyzer.LockedPackage{{Name: "left-pad", Version: "1.3.0", Direct: true}},
+		}},
+	})
+	if _, err := ExportDependencyGraph(data, "svg"); err == nil || !strings.Contains(err.Error(), "dot, json") {
+		t.Errorf("expected unsupported format error listing dot and json, got %v", err)
+	}
+}
+
+func TestBuildSecurityExport(t *testing.T) {
+	if buildSecurityExport(nil) != nil {
+		t.Error("buildSecurityExport(nil) should be nil so the JSON field is omitted")
+	}
+
+	sec := &analyzer.SecurityScanResult{
+		SecurityScore: 75,
+		Source:        "osv.dev",
+		Vulnerabilities: []analyzer.Vulnerability{
+			{ID: "GHSA-1", Package: "lodash", Severity: "CRITICAL", Score: 9.8, Vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"},
+			{ID: "GHSA-2", Package: "minimist", Severity: "MEDIUM"},
+		},
+	}
+	export := buildSecurityExport(sec)
+	if export.Grade != "C" || len(export.Vulnerabilities) != 2 {
+		t.Errorf("buildSecurityExport() = %+v, want grade C with 2 vulnerabilities", export)
+	}
+	if got := formatCVSSScore(sec.Vulnerabilities[0]); got != "9.8" {
+		t.Errorf("formatCVSSScore() = %q, want 9.8", got)
+	}
+	if got := formatCVSSScore(sec.Vulnerabilities[1]); got != "-" {
+		t.Errorf("formatCVSSScore() without a vector = %q, want -", got)
+	}
+}
+
+func TestBuildExportData_QualityAndLicense(t *testing.T) {
+	data := AnalysisResult{Repo: &github.Repo{FullName: "owner/repo"}}
+	export := buildExportData(data)
+	if export.CodeQuality != nil || export.License != nil {
+		t.Error("code quality and license should be omitted when they were not analyzed")
+	}
+
+	data.CodeQuality = &analyzer.CodeQualityMetrics{OverallScore: 82, Grade: "B"}
+	data.License = &analyzer.LicenseAnalysis{MainLicense: &analyzer.LicenseInfo{Name: "MIT License", SPDX: "MIT"}}
+	export = buildExportData(data)
+	if export.CodeQuality == nil || export.CodeQuality.Grade != "B" {
+		t.Errorf("CodeQuality = %+v, want grade B", export.CodeQuality)
+	}
+	if export.License == nil || export.License.MainLicense.SPDX != "MIT" {
+		t.Errorf("License = %+v, want MIT", export.License)
+	}
+}
+
+func TestExportHTML_EscapesRepoValues(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	if err := os.Mkdir(filepath.Join(home, "Downloads"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	const payload = `<script>alert("x")</script>`
+	data := AnalysisResult{
+		Repo:      &github.Repo{FullName: "owner/repo", HTMLURL: `https://github.com/owner/repo"onmouseover="x`},
+		Languages: map[string]int{payload: 1},
+		CodeQuality: &analyzer.CodeQualityMetrics{
+			Grade:       "B",
+			CodeSmells:  []analyzer.CodeSmell{{Type: "large-file", Severity: "Low", Description: "big", Location: payload}},
+			TestMapping: &analyzer.TestMapping{SourceFiles: 1, Directories: []analyzer.DirectoryTestCoverage{{Dir: payload}}},
+		},
+		License: &analyzer.LicenseAnalysis{Warnings: []string{payload}},
+	}
+	path, err := ExportHTML(data, "")
+	if err != nil {
+		t.Fatalf("ExportHTML() error = %v", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := string(content)
+	if strings.Contains(page, "<script>") || strings.Contains(page, `"onmouseover`) {
+		t.Error("repository values should be escaped in the HTML export")
+	}
+	if strings.Count(page, "&lt;script&gt;") != 4 {
+		t.Errorf("want the payload escaped in the language, directory, smell and warning; got %d", strings.Count(page, "&lt;script&gt;"))
+	}
+}
+
+func TestHeatLevel(t *testing.T) {
+	tests := []struct{ count, max, want int }{
+		{0, 10, 0},
+		{1, 10, 1},
+		{5, 10, 2},
+		{10, 10, 4},
+		{3, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := heatLevel(tt.count, tt.max, len(exportHeatColors)); got != tt.want {
+			t.Errorf("heatLevel(%d, %d) = %d, want %d", tt.count, tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestCalendarSVG(t *testing.T) {
+	c := github.Commit{}
+	c.Commit.Author.Date = time.Date(2026, 6, 3, 12, 0, 0, 0, time.UTC)
+	cal := analyzer.BuildContributionCalendar([]github.Commit{c}, "", c.Commit.Author.Date)
+
+	svg := calendarSVG(cal)
+	if !strings.HasPrefix(svg, "<svg") || strings.Count(svg, "<rect") != 7*analyzer.CalendarWeeks {
+		t.Errorf("calendarSVG() has %d cells, want %d", strings.Count(svg, "<rect"), 7*analyzer.CalendarWeeks)
+	}
+	if !strings.Contains(svg, `fill="#216e39"><title>2026-06-03: 1 commits</title>`) {
+		t.Error("calendarSVG() should color the busiest day darkest")
+	}
+}
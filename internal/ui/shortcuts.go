@@ -55,6 +55,12 @@ func GetDashboardShortcuts() []KeyboardShortcut {
 		{Key: "c", AltKey: "", Description: "Export CSV", Category: "Actions"},
 		{Key: "x", AltKey: "", Description: "Export HTML", Category: "Actions"},
 		{Key: "p", AltKey: "", Description: "Export PDF", Category: "Actions"},
+		{Key: "d", AltKey: "", Description: "Export dependency graph (DOT)", Category: "Actions"},
+		{Key: "g", AltKey: "", Description: "Export dependency graph (JSON)", Category: "Actions"},
+		{Key: "s", AltKey: "", Description: "Export SBOM (CycloneDX)", Category: "Actions"},
+		{Key: "v", AltKey: "", Description: "Export SBOM (SPDX)", Category: "Actions"},
+		{Key: "↑/↓", AltKey: "Enter", Description: "Browse dependency tree", Category: "Navigation"},
+		{Key: "[/]", AltKey: "↑/↓", Description: "Select calendar week and contributor", Category: "Navigation"},
 		{Key: "f", AltKey: "", Description: "File tree", Category: "Actions"},
 		{Key: "r", AltKey: "F5", Description: "Refresh data", Category: "Actions"},
 		{Key: "b", AltKey: "", Description: "Toggle bookmark", Category: "Actions"},
@@ -75,6 +81,7 @@ func GetSettingsShortcuts() []KeyboardShortcut {
 		{Key: "a", AltKey: "", Description: "Toggle auto-cache", Category: "Cache"},
 		{Key: "c", AltKey: "", Description: "Clear cache", Category: "Cache"},
 		{Key: "x", AltKey: "", Description: "Clean expired", Category: "Cache"},
+		{Key: "o", AltKey: "", Description: "Toggle offline vuln scan", Category: "Cache"},
 		{Key: "ESC", AltKey: "q", Description: "Go back", Category: "System"},
 	}
 }
@@ -1,6 +1,10 @@
 package ui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+)
 
 // Theme represents a color theme for the UI
 type Theme struct {
@@ -241,3 +245,29 @@ func SetThemeByName(name string) bool {
 func GetCurrentThemeName() string {
 	return CurrentTheme.Name
 }
+
+// HeatLevels returns five heatmap colors for the theme, from an empty cell
+// just off the background up to the theme's success color
+func (t Theme) HeatLevels() []lipgloss.Color {
+	return []lipgloss.Color{
+		blendColors(t.Background, t.TextMuted, 0.35),
+		blendColors(t.Background, t.Success, 0.3),
+		blendColors(t.Background, t.Success, 0.55),
+		blendColors(t.Background, t.Success, 0.8),
+		t.Success,
+	}
+}
+
+// blendColors mixes two #rrggbb colors, ratio 0 giving a and 1 giving b.
+// Colors in any other format are returned as b.
+func blendColors(a, b lipgloss.Color, ratio float64) lipgloss.Color {
+	var ar, ag, ab, br, bg, bb int
+	if _, err := fmt.Sscanf(string(a), "#%02x%02x%02x", &ar, &ag, &ab); err != nil {
+		return b
+	}
+	if _, err := fmt.Sscanf(string(b), "#%02x%02x%02x", &br, &bg, &bb); err != nil {
+		return b
+	}
+	mix := func(x, y int) int { return x + int(float64(y-x)*ratio+0.5) }
+	return lipgloss.Color(fmt.Sprintf("#%02x%02x%02x", mix(ar, br), mix(ag, bg), mix(ab, bb)))
+}
@@ -19,13 +19,28 @@ type AnalysisResult struct {
 	MaturityScore       int
 	MaturityLevel       string
 	Dependencies        *analyzer.DependencyAnalysis
+	Monorepo            *analyzer.MonorepoAnalysis
+	DependencyGraph     *analyzer.DependencyGraph
 	ContributorInsights *analyzer.ContributorInsights
 	Security            *analyzer.SecurityScanResult
+	SecurityError       string // why the vulnerability scan did not run, e.g. no offline database
+	Secrets             *analyzer.SecretScanResult
+	Workflows           *analyzer.WorkflowAuditResult
+	Containers          *analyzer.ContainerAnalysis
 	CodeQuality         *analyzer.CodeQualityMetrics
 	License             *analyzer.LicenseAnalysis
+	LicenseCompliance   *analyzer.LicenseComplianceReport
+	LicenseError        string // why the compliance check did not run, e.g. a malformed policy file
 	ContributorActivity analyzer.ContributorActivityResult
+	CommitConventions   *analyzer.CommitConventionAnalysis
+	PunchCard           *analyzer.PunchCard
+	Calendar            *analyzer.ContributionCalendar // repository-wide, from GitHub's commit activity statistics
 	RiskAlerts          *analyzer.RiskAlertsResult
 	QualityDashboard    *analyzer.QualityDashboard
+	TruckFactor         *analyzer.TruckFactorResult
+	CodeOwners          *analyzer.CodeOwnersAnalysis
+	Scorecard           *analyzer.ScorecardResult
+	Changelog           *analyzer.ChangelogAnalysis
 }
 
 // CachedAnalysisResult wraps AnalysisResult with cache metadata